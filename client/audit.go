@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Outcome is how an audited request ended.
+//
+type Outcome string
+
+const (
+	// OutcomeSuccess is a request which got its annotations.
+	OutcomeSuccess Outcome = "success"
+	// OutcomeFailure is a request which failed.
+	OutcomeFailure Outcome = "failure"
+	// OutcomeCanceled is a request whose context was cancelled or timed out.
+	OutcomeCanceled Outcome = "canceled"
+)
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context recording the requests made with it
+// under id in the audit log, e.g. the id of the API call or of the user.
+//
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationIDFrom returns the correlation id of ctx, "" if it has none.
+//
+func CorrelationIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// AuditRecord records an annotation request. The text itself is never
+// recorded, only its hash.
+//
+type AuditRecord struct {
+	// the id of the context, see WithCorrelationID, or a random one
+	CorrelationID string
+
+	// the SHA-256 of the salted input in hex, and its size in bytes
+	InputHash string
+	InputSize int
+
+	// the annotators run, and the input file for Run
+	Annotators []string
+	Input      string
+
+	Start    time.Time
+	Duration time.Duration
+	Outcome  Outcome
+	Err      error
+}
+
+// AuditSink receives the records of an AuditClient, e.g. to write them to
+// an append-only store. Audit may be called concurrently.
+//
+type AuditSink interface {
+	Audit(record *AuditRecord) error
+}
+
+// AuditFunc adapts a function to an AuditSink.
+//
+type AuditFunc func(record *AuditRecord) error
+
+func (self AuditFunc) Audit(record *AuditRecord) error {
+	return self(record)
+}
+
+// AuditClient wraps a client to record who annotated what in an AuditSink:
+// every request, with its correlation id, the hash of its input, the
+// annotators, the duration and the outcome. It is safe for concurrent use if
+// the client and the sink are.
+//
+// For example:
+// c := NewAuditClient(client, AuditFunc(func(r *AuditRecord) error { return store.Append(r) }))
+// err := c.RunText(WithCorrelationID(ctx, requestID), text, doc)
+//
+type AuditClient struct {
+	Client Client
+	Sink   AuditSink
+
+	// the annotators recorded, by default those of the client if a *HttpClient or a *Cmd
+	Annotators []string
+
+	// prefixed to the inputs hashed, so the hashes cannot be matched against
+	// a dictionary of known texts
+	Salt string
+
+	// Strict fails the requests which could not be recorded, with the error
+	// of the sink; otherwise the error is passed to OnError, optional
+	Strict  bool
+	OnError func(error)
+}
+
+// NewAuditClient creates an instance of AuditClient around c, recording in sink.
+//
+// annotators, optional: the annotators recorded, by default those of c.
+//
+func NewAuditClient(c Client, sink AuditSink, annotators ...string) *AuditClient {
+	if len(annotators) == 0 {
+		switch t := c.(type) {
+		case *HttpClient:
+			annotators = t.Annotators
+		case *Cmd:
+			annotators = t.Annotators
+		}
+	}
+	return &AuditClient{Client: c, Sink: sink, Annotators: copyStrings(annotators)}
+}
+
+// Run runs on the input file, and gets the NLP data in msg
+//
+func (self *AuditClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	data, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	record := self.start(ctx, data)
+	record.Input = input
+	return self.finish(record, self.Client.Run(ctx, input, msg))
+}
+
+// RunText runs on the text string, and gets the NLP data in msg
+//
+func (self *AuditClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	record := self.start(ctx, text)
+	return self.finish(record, self.Client.RunText(ctx, text, msg))
+}
+
+func (self *AuditClient) start(ctx context.Context, text []byte) *AuditRecord {
+	id := CorrelationIDFrom(ctx)
+	if id == "" {
+		id = newCorrelationID()
+	}
+	h := sha256.New()
+	h.Write([]byte(self.Salt))
+	h.Write(text)
+	return &AuditRecord{
+		CorrelationID: id,
+		InputHash:     hex.EncodeToString(h.Sum(nil)),
+		InputSize:     len(text),
+		Annotators:    copyStrings(self.Annotators),
+		Start:         time.Now(),
+	}
+}
+
+// finish records the request ended with err, and returns err, or the error of
+// the sink when Strict.
+//
+func (self *AuditClient) finish(record *AuditRecord, err error) error {
+	record.Duration = time.Since(record.Start)
+	record.Err = err
+	switch {
+	case err == nil:
+		record.Outcome = OutcomeSuccess
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		record.Outcome = OutcomeCanceled
+	default:
+		record.Outcome = OutcomeFailure
+	}
+
+	if aerr := self.Sink.Audit(record); aerr != nil {
+		aerr = fmt.Errorf("audit %s: %w", record.CorrelationID, aerr)
+		if self.Strict && err == nil {
+			return aerr
+		}
+		if self.OnError != nil {
+			self.OnError(aerr)
+		}
+	}
+	return err
+}
+
+// newCorrelationID returns a random id of 16 hex digits.
+//
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
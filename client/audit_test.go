@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestAuditClient(t *testing.T) {
+	fail := errors.New("boom")
+	inner := funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+		switch string(text) {
+		case "fail":
+			return fail
+		case "slow":
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+	var records []*AuditRecord
+	c := NewAuditClient(inner, AuditFunc(func(r *AuditRecord) error {
+		records = append(records, r)
+		return nil
+	}), "tokenize", "ssplit")
+
+	ctx := WithCorrelationID(context.Background(), "req-1")
+	if err := c.RunText(ctx, []byte("John runs."), &nlp.Document{}); err != nil { t.Fatal(err) }
+	sum := sha256.Sum256([]byte("John runs."))
+	r := records[0]
+	if r.CorrelationID != "req-1" || r.InputHash != hex.EncodeToString(sum[:]) || r.InputSize != 10 || len(r.Annotators) != 2 || r.Outcome != OutcomeSuccess || r.Err != nil {
+		t.Errorf("%+v", r)
+	}
+
+	if err := c.RunText(context.Background(), []byte("fail"), &nlp.Document{}); err != fail {
+		t.Errorf("%v", err)
+	}
+	if r := records[1]; r.Outcome != OutcomeFailure || r.Err != fail || len(r.CorrelationID) != 16 {
+		t.Errorf("%+v", r)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	c.RunText(canceled, []byte("slow"), &nlp.Document{})
+	if r := records[2]; r.Outcome != OutcomeCanceled {
+		t.Errorf("%+v", r)
+	}
+
+	c.Salt = "pepper"
+	c.RunText(ctx, []byte("John runs."), &nlp.Document{})
+	if records[3].InputHash == records[0].InputHash {
+		t.Errorf("salt ignored")
+	}
+}
+
+func TestAuditClientSinkError(t *testing.T) {
+	inner := funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error { return nil })
+	down := errors.New("store down")
+	c := NewAuditClient(inner, AuditFunc(func(r *AuditRecord) error { return down }))
+	var reported error
+	c.OnError = func(err error) { reported = err }
+	if err := c.RunText(context.Background(), []byte("x"), &nlp.Document{}); err != nil || !errors.Is(reported, down) {
+		t.Errorf("%v %v", err, reported)
+	}
+	c.Strict = true
+	if err := c.RunText(context.Background(), []byte("x"), &nlp.Document{}); !errors.Is(err, down) {
+		t.Errorf("%v", err)
+	}
+
+	if c := NewAuditClient(NewHttpClient([]string{"tokenize", "pos"}), nil); len(c.Annotators) != 2 {
+		t.Errorf("%v", c.Annotators)
+	}
+}
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// RunFiles annotates all the files with a single Java process, using the -filelist
+// option, so the start-up and the model loading are paid only once.
+// It returns the documents keyed by the given paths.
+//
+// In the persistent mode (see Start), the files are sent to the private server one by one.
+//
+func (self *Cmd) RunFiles(ctx context.Context, paths []string) (map[string]*nlp.Document, error) {
+	docs := make(map[string]*nlp.Document, len(paths))
+	err := self.StreamFiles(ctx, paths, SinkFunc(func(result *Result) error {
+		if result.Err != nil {
+			return fmt.Errorf("%s: %w", result.ID, result.Err)
+		}
+		docs[result.ID] = result.Document
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// streamInterval is the time between two looks at the output directory
+// of StreamFiles.
+//
+var streamInterval = 100 * time.Millisecond
+
+// StreamFiles annotates the files like RunFiles, but sends each document to
+// sink, with the given path as ID, as soon as CoreNLP has written it, rather
+// than once the Java process exits: the output directory is watched, and a
+// file is decoded once its size stays the same over a look and it decodes.
+// The documents come in the order CoreNLP finishes them, and the files left
+// when the process exits, e.g. missing, are sent with an error. The sink is
+// flushed at the end.
+//
+// It returns the error of the process or of the sink; when the process
+// fails, the documents already sent stay valid.
+//
+func (self *Cmd) StreamFiles(ctx context.Context, paths []string, sink Sink) error {
+	if c := self.persistent(); c != nil {
+		for i, path := range paths {
+			result := &Result{ID: path}
+			doc := &nlp.Document{}
+			if result.Err = c.Run(ctx, path, doc); result.Err == nil {
+				result.Document = doc
+			}
+			if err := sink.Write(result); err != nil {
+				return err
+			}
+			if self.OnProgress != nil {
+				self.OnProgress(i+1, len(paths))
+			}
+		}
+		return sink.Flush()
+	}
+	if len(paths) == 0 {
+		return sink.Flush()
+	}
+
+	var total int64
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			total += info.Size()
+		}
+	}
+	outputDir, cleanup, err := self.tempDir(total)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	inputs, err := batchInputs(outputDir, paths)
+	if err != nil {
+		return err
+	}
+	listed := make([]string, len(inputs))
+	outputs := make([]string, len(inputs))
+	for i, input := range inputs {
+		listed[i] = longPath(input)
+		outputs[i] = filepath.Join(outputDir, filepath.Base(input)+format.Extension(self.serializer().Format()))
+	}
+	list := filepath.Join(outputDir, "filelist.txt")
+	if err = ioutil.WriteFile(list, []byte(strings.Join(listed, "\n")+"\n"), 0666); err != nil {
+		return err
+	}
+
+	var logs []io.Writer
+	if self.OnProgress != nil {
+		done := 0
+		logs = append(logs, NewLineWriter(func(line string) {
+			// CoreNLP logs "Processing file /path/input.txt ... writing to ..."
+			if strings.Contains(line, "Processing file ") {
+				done++
+				self.OnProgress(done, len(paths))
+			}
+		}))
+	}
+
+	// the sizes seen at the last look, -1 once sent
+	sizes := make([]int64, len(outputs))
+	collect := func(exited bool) error {
+		for i, output := range outputs {
+			if sizes[i] < 0 {
+				continue
+			}
+			result := &Result{ID: paths[i]}
+			data, err := ioutil.ReadFile(output)
+			if !exited {
+				// wait for the size to settle, then for the file to decode
+				size := int64(len(data))
+				if err != nil || size == 0 || size != sizes[i] {
+					sizes[i] = size
+					continue
+				}
+			}
+			if err == nil {
+				doc := &nlp.Document{}
+				if err = self.decode(data, doc); err == nil {
+					result.Document = doc
+				} else if !exited {
+					continue
+				}
+			}
+			result.Err = err
+			sizes[i] = -1
+			if err = sink.Write(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	args := self.arguments("-filelist", longPath(list), "--outputDirectory", longPath(outputDir))
+	start := time.Now()
+	exit := make(chan error, 1)
+	go func() {
+		exit <- self.execute(ctx, args, logs...)
+	}()
+	running := true
+	defer func() {
+		// on an error of the sink, kill the process before the cleanup
+		cancel()
+		if running {
+			<-exit
+		}
+	}()
+
+	ticker := time.NewTicker(streamInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-exit:
+			running = false
+			if err != nil {
+				return err
+			}
+			elapsed := time.Since(start)
+			var size int64
+			for _, input := range inputs {
+				if info, err := os.Stat(input); err == nil {
+					size += info.Size()
+				}
+			}
+			self.record(len(paths), size, elapsed)
+			if err := collect(true); err != nil {
+				return err
+			}
+			return sink.Flush()
+		case <-ticker.C:
+			if err := collect(false); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// batchInputs returns the files to list for CoreNLP. Since the outputs are named
+// after the base names, a file whose base name is taken is copied to dir under
+// a unique name.
+//
+func batchInputs(dir string, paths []string) ([]string, error) {
+	inputs := make([]string, len(paths))
+	seen := map[string]bool{"filelist.txt": true}
+	for i, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		base := filepath.Base(abs)
+		if !seen[base] {
+			seen[base] = true
+			inputs[i] = abs
+			continue
+		}
+
+		data, err := ioutil.ReadFile(abs)
+		if err != nil {
+			return nil, err
+		}
+		for n := 1; seen[base]; n++ {
+			base = fmt.Sprintf("%d-%s", n, filepath.Base(abs))
+		}
+		seen[base] = true
+		inputs[i] = filepath.Join(dir, base)
+		if err = ioutil.WriteFile(inputs[i], data, 0666); err != nil {
+			return nil, err
+		}
+	}
+	return inputs, nil
+}
+
+// Throughput is the annotation throughput of the Java processes run by a Cmd,
+// measured from process start to exit, so it includes the model loading.
+//
+type Throughput struct {
+	// documents annotated
+	Documents int
+
+	// bytes of text annotated
+	Bytes int64
+
+	// total time of the Java processes
+	Elapsed time.Duration
+}
+
+// DocumentsPerSecond returns the documents annotated per second.
+//
+func (self Throughput) DocumentsPerSecond() float64 {
+	if self.Elapsed <= 0 {
+		return 0
+	}
+	return float64(self.Documents) / self.Elapsed.Seconds()
+}
+
+// BytesPerSecond returns the bytes of text annotated per second.
+//
+func (self Throughput) BytesPerSecond() float64 {
+	if self.Elapsed <= 0 {
+		return 0
+	}
+	return float64(self.Bytes) / self.Elapsed.Seconds()
+}
+
+// Throughput returns the throughput achieved by all the runs so far,
+// not including those in the persistent mode.
+//
+func (self *Cmd) Throughput() Throughput {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.stats
+}
+
+func (self *Cmd) record(docs int, size int64, elapsed time.Duration) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.stats.Documents += docs
+	self.stats.Bytes += size
+	self.stats.Elapsed += elapsed
+}
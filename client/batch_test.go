@@ -0,0 +1,132 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRunFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	java, err := fakeJava(dir, fakeBatchJava)
+	if err != nil { t.Fatal(err) }
+	template := serialize(&nlp.Document{Text: proto.String("annotated")})
+	if err = ioutil.WriteFile(filepath.Join(dir, "template"), template, 0666); err != nil { t.Fatal(err) }
+
+	// two inputs share the base name a.txt
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "sub", "a.txt")}
+	for _, p := range paths {
+		if err = ioutil.WriteFile(p, []byte("text"), 0666); err != nil { t.Fatal(err) }
+	}
+
+	cmd := NewCmd([]string{"tokenize"}, "", "", java).With(WithThreads(4))
+	if args := strings.Join(cmd.arguments(), " "); !strings.Contains(args, "-annotators tokenize -threads 4") {
+		t.Errorf("%s", args)
+	}
+	docs, err := cmd.RunFiles(context.Background(), paths)
+	if err != nil { t.Fatal(err) }
+	if len(docs) != 3 {
+		t.Fatalf("%v", docs)
+	}
+	for _, p := range paths {
+		if docs[p].GetText() != "annotated" {
+			t.Errorf("%s: %s", p, docs[p].String())
+		}
+	}
+
+	stats := cmd.Throughput()
+	if stats.Documents != 3 || stats.Bytes != 12 || stats.Elapsed <= 0 || stats.DocumentsPerSecond() <= 0 {
+		t.Errorf("%#v", stats)
+	}
+}
+
+// fakeStreamJava writes the output of the first listed file, then waits for
+// the test to receive it before the others, leaving out missing.txt.
+//
+const fakeStreamJava = `
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -filelist) list=$2; shift;;
+    --outputDirectory) out=$2; shift;;
+  esac
+  shift
+done
+dir=$(dirname "$0")
+n=0
+while read f; do
+  case "$f" in
+    *missing.txt) ;;
+    *) cp "$dir/template" "$out/$(basename "$f").ser.gz";;
+  esac
+  if [ $n -eq 0 ]; then
+    i=0
+    while [ ! -f "$dir/received" ]; do
+      i=$((i+1))
+      if [ $i -gt 100 ]; then touch "$dir/late"; break; fi
+      sleep 0.05
+    done
+  fi
+  n=$((n+1))
+done < "$list"
+`
+
+// markingSink creates the marker file on the first result.
+//
+type markingSink struct {
+	memorySink
+	marker string
+}
+
+func (self *markingSink) Write(result *Result) error {
+	if len(self.results) == 0 {
+		ioutil.WriteFile(self.marker, nil, 0666)
+	}
+	return self.memorySink.Write(result)
+}
+
+func TestStreamFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stream")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+	defer func(d time.Duration) { streamInterval = d }(streamInterval)
+	streamInterval = 10 * time.Millisecond
+
+	java, err := fakeJava(dir, fakeStreamJava)
+	if err != nil { t.Fatal(err) }
+	template := serialize(&nlp.Document{Text: proto.String("annotated")})
+	if err = ioutil.WriteFile(filepath.Join(dir, "template"), template, 0666); err != nil { t.Fatal(err) }
+	paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), filepath.Join(dir, "missing.txt")}
+	for _, p := range paths {
+		if err = ioutil.WriteFile(p, []byte("text"), 0666); err != nil { t.Fatal(err) }
+	}
+
+	sink := &markingSink{marker: filepath.Join(dir, "received")}
+	cmd := NewCmd([]string{"tokenize"}, "", "", java)
+	if err = cmd.StreamFiles(context.Background(), paths, sink); err != nil { t.Fatal(err) }
+
+	if _, err := os.Stat(filepath.Join(dir, "late")); err == nil {
+		t.Errorf("the first document was not streamed")
+	}
+	results := sink.results
+	if len(results) != 3 || !sink.flushed || results[0].ID != paths[0] || results[0].Document.GetText() != "annotated" {
+		t.Fatalf("%v", results)
+	}
+	if results[1].ID != paths[1] || results[1].Err != nil || results[2].ID != paths[2] || results[2].Err == nil {
+		t.Errorf("%v %v", results[1], results[2])
+	}
+
+	if _, err = cmd.RunFiles(context.Background(), paths); err == nil || !strings.Contains(err.Error(), "missing.txt") {
+		t.Errorf("%v", err)
+	}
+}
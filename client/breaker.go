@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker without calling the server
+// while the circuit is open.
+//
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker.
+//
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every request fast until the cool-down passes.
+	BreakerOpen
+	// BreakerHalfOpen lets one trial request through to probe the server.
+	BreakerHalfOpen
+)
+
+func (self BreakerState) String() string {
+	switch self {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker wraps a client so that, after Threshold consecutive failures,
+// requests fail fast with ErrCircuitOpen for the CoolDown period. Then a single
+// trial request is let through: its success closes the circuit, its failure
+// opens it again.
+//
+type CircuitBreaker struct {
+	Client Client
+
+	// consecutive failures opening the circuit, default 5
+	Threshold int
+
+	// how long the circuit stays open, default 30s
+	CoolDown time.Duration
+
+	// IsFailure decides if an error counts against the server,
+	// default to IsUnavailable or a 5xx ServerError
+	IsFailure func(error) bool
+
+	// OnStateChange, optional, is called when the state changes
+	OnStateChange func(from, to BreakerState)
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trial    bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates an instance of CircuitBreaker around c.
+//
+func NewCircuitBreaker(c Client) *CircuitBreaker {
+	return &CircuitBreaker{Client: c, Threshold: 5, CoolDown: 30 * time.Second, IsFailure: isServerFailure, now: time.Now}
+}
+
+// State returns the current state of the circuit.
+//
+func (self *CircuitBreaker) State() BreakerState {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.state == BreakerOpen && self.clock().Sub(self.openedAt) >= self.CoolDown {
+		return BreakerHalfOpen
+	}
+	return self.state
+}
+
+// Run runs on the input file, and gets the NLP data in msg
+//
+func (self *CircuitBreaker) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	data, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	return self.RunText(ctx, data, msg)
+}
+
+// RunText runs on the text string unless the circuit is open, and gets the NLP data in msg
+//
+func (self *CircuitBreaker) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	if err := self.acquire(); err != nil {
+		return err
+	}
+	err := self.Client.RunText(ctx, text, msg)
+	self.record(err)
+	return err
+}
+
+func (self *CircuitBreaker) acquire() error {
+	notify := noChange
+	self.mu.Lock()
+	// the callback runs once unlocked, so that it may call State
+	defer func() {
+		self.mu.Unlock()
+		notify()
+	}()
+
+	switch self.state {
+	case BreakerOpen:
+		if self.clock().Sub(self.openedAt) < self.CoolDown {
+			return ErrCircuitOpen
+		}
+		notify = self.setState(BreakerHalfOpen)
+		self.trial = true
+	case BreakerHalfOpen:
+		if self.trial {
+			return ErrCircuitOpen
+		}
+		self.trial = true
+	}
+	return nil
+}
+
+func (self *CircuitBreaker) record(err error) {
+	notify := noChange
+	self.mu.Lock()
+	defer func() {
+		self.mu.Unlock()
+		notify()
+	}()
+
+	failed := err != nil
+	if failed {
+		is := self.IsFailure
+		if is == nil {
+			is = isServerFailure
+		}
+		failed = is(err)
+	}
+
+	if self.state == BreakerHalfOpen {
+		self.trial = false
+		if failed {
+			notify = self.open()
+		} else if err == nil {
+			self.failures = 0
+			notify = self.setState(BreakerClosed)
+		}
+		return
+	}
+
+	switch {
+	case failed:
+		self.failures++
+		threshold := self.Threshold
+		if threshold <= 0 {
+			threshold = 5
+		}
+		if self.state == BreakerClosed && self.failures >= threshold {
+			notify = self.open()
+		}
+	case err == nil:
+		self.failures = 0
+	}
+}
+
+func (self *CircuitBreaker) open() func() {
+	self.openedAt = self.clock()
+	return self.setState(BreakerOpen)
+}
+
+// setState changes the state with the lock held, and returns the call of
+// OnStateChange to make once unlocked.
+//
+func (self *CircuitBreaker) setState(state BreakerState) func() {
+	if state == self.state || self.OnStateChange == nil {
+		self.state = state
+		return noChange
+	}
+	from, callback := self.state, self.OnStateChange
+	self.state = state
+	return func() { callback(from, state) }
+}
+
+func noChange() {}
+
+func (self *CircuitBreaker) clock() time.Time {
+	if self.now == nil {
+		return time.Now()
+	}
+	return self.now()
+}
+
+// isServerFailure reports whether err shows the server itself is in trouble.
+//
+func isServerFailure(err error) bool {
+	var se *ServerError
+	if errors.As(err, &se) {
+		return se.StatusCode >= 500 || se.StatusCode == 429
+	}
+	return IsUnavailable(err)
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+
+	now := time.Now()
+	cb := NewCircuitBreaker(NewHttpClient(nil, srv.URL))
+	cb.Threshold = 2
+	cb.CoolDown = time.Minute
+	cb.now = func() time.Time { return now }
+	var changes []BreakerState
+	// the callback may look at the breaker
+	cb.OnStateChange = func(from, to BreakerState) { changes = append(changes, cb.State()) }
+
+	ctx := context.Background()
+	srv.setStatus(http.StatusServiceUnavailable)
+	for i := 0; i < 2; i++ {
+		if err := cb.RunText(ctx, []byte("x"), &nlp.Document{}); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("%d: %v", i, err)
+		}
+	}
+	if cb.State() != BreakerOpen {
+		t.Fatalf("%s", cb.State())
+	}
+	if err := cb.RunText(ctx, []byte("x"), &nlp.Document{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("%v", err)
+	}
+	if srv.count() != 2 {
+		t.Errorf("%d", srv.count())
+	}
+
+	// the trial request fails and the circuit opens again
+	now = now.Add(time.Minute)
+	if cb.State() != BreakerHalfOpen {
+		t.Errorf("%s", cb.State())
+	}
+	cb.RunText(ctx, []byte("x"), &nlp.Document{})
+	if cb.State() != BreakerOpen {
+		t.Errorf("%s", cb.State())
+	}
+
+	// the trial request succeeds and the circuit closes
+	now = now.Add(time.Minute)
+	srv.setStatus(http.StatusOK)
+	if err := cb.RunText(ctx, []byte("x"), &nlp.Document{}); err != nil { t.Fatal(err) }
+	if cb.State() != BreakerClosed {
+		t.Errorf("%s", cb.State())
+	}
+
+	want := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+	if len(changes) != len(want) {
+		t.Fatalf("%v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("%v", changes)
+		}
+	}
+}
+
+func TestCircuitBreakerClientErrors(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+	srv.setStatus(http.StatusBadRequest)
+
+	cb := NewCircuitBreaker(NewHttpClient(nil, srv.URL))
+	cb.Threshold = 1
+	cb.RunText(context.Background(), []byte("x"), &nlp.Document{})
+	if cb.State() != BreakerClosed {
+		t.Errorf("%s", cb.State())
+	}
+}
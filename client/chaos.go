@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Fault is a failure injected by ChaosClient.
+//
+type Fault int
+
+const (
+	// FaultLatency delays the request.
+	FaultLatency Fault = iota
+	// FaultError fails the request without calling the client.
+	FaultError
+	// FaultTruncated cuts the serialized response short before decoding it.
+	FaultTruncated
+	// FaultMalformed corrupts bytes of the serialized response before decoding it.
+	FaultMalformed
+)
+
+func (self Fault) String() string {
+	switch self {
+	case FaultLatency:
+		return "latency"
+	case FaultError:
+		return "error"
+	case FaultTruncated:
+		return "truncated"
+	default:
+		return "malformed"
+	}
+}
+
+// ChaosClient wraps a client to inject faults, for testing the resilience of
+// applications: latencies, errors, and truncated or malformed protobuf
+// responses, which are decoded as the clients decode those of a server, so
+// the application sees the same errors. Each fault strikes a request with its
+// rate, from 0 to 1; a request gets at most one of the error, truncation and
+// corruption, and may be delayed as well. It is safe for concurrent use.
+//
+// For example, to fail one request in ten:
+// c := NewChaosClient(client); c.ErrorRate = 0.1
+//
+type ChaosClient struct {
+	Client Client
+
+	// rate of the delayed requests, and the maximal delay, drawn uniformly
+	LatencyRate float64
+	Latency     time.Duration
+
+	// rate of the failed requests, and their error, default a 503 ServerError
+	ErrorRate float64
+	Err       error
+
+	// rate of the truncated responses
+	TruncateRate float64
+
+	// rate of the malformed responses
+	MalformedRate float64
+
+	// OnFault, optional, is called with every fault injected
+	OnFault func(Fault)
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewChaosClient creates an instance of ChaosClient around c, injecting
+// nothing until the rates are set.
+//
+// seed[0], optional: the seed of the random faults, default the current time.
+//
+func NewChaosClient(c Client, seed ...int64) *ChaosClient {
+	s := time.Now().UnixNano()
+	if len(seed) > 0 {
+		s = seed[0]
+	}
+	return &ChaosClient{Client: c, rnd: rand.New(rand.NewSource(s))}
+}
+
+// Run runs on the input file, and gets the NLP data in msg
+//
+func (self *ChaosClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	data, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	return self.RunText(ctx, data, msg)
+}
+
+// RunText runs on the text string with the faults drawn for the request,
+// and gets the NLP data in msg
+//
+func (self *ChaosClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	self.mu.Lock()
+	var delay time.Duration
+	if self.rnd == nil {
+		self.rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if self.rnd.Float64() < self.LatencyRate && self.Latency > 0 {
+		delay = time.Duration(self.rnd.Int63n(int64(self.Latency)) + 1)
+	}
+	r := self.rnd.Float64()
+	seed := self.rnd.Int63()
+	self.mu.Unlock()
+
+	if delay > 0 {
+		self.fault(FaultLatency)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	switch {
+	case r < self.ErrorRate:
+		self.fault(FaultError)
+		if self.Err != nil {
+			return self.Err
+		}
+		return &ServerError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}
+	case r < self.ErrorRate+self.TruncateRate:
+		return self.corrupt(ctx, text, msg, FaultTruncated, rand.New(rand.NewSource(seed)))
+	case r < self.ErrorRate+self.TruncateRate+self.MalformedRate:
+		return self.corrupt(ctx, text, msg, FaultMalformed, rand.New(rand.NewSource(seed)))
+	}
+	return self.Client.RunText(ctx, text, msg)
+}
+
+// corrupt runs the client, serializes the result as the server does, damages
+// it by the fault, and decodes it into msg.
+//
+func (self *ChaosClient) corrupt(ctx context.Context, text []byte, msg protoreflect.ProtoMessage, fault Fault, rnd *rand.Rand) error {
+	result := msg.ProtoReflect().New().Interface()
+	if err := self.Client.RunText(ctx, text, result); err != nil {
+		return err
+	}
+	bs, err := proto.MarshalOptions{AllowPartial: true}.Marshal(result)
+	if err != nil {
+		return err
+	}
+	data := protowire.AppendBytes(nil, bs)
+
+	self.fault(fault)
+	if fault == FaultTruncated {
+		data = data[:rnd.Intn(len(data))]
+	} else {
+		for i := 0; i < 1+len(data)/100; i++ {
+			data[rnd.Intn(len(data))] ^= byte(1 + rnd.Intn(255))
+		}
+	}
+	return BytesUnmarshal(data, msg)
+}
+
+func (self *ChaosClient) fault(f Fault) {
+	if self.OnFault != nil {
+		self.OnFault(f)
+	}
+}
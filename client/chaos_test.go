@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestChaosClient(t *testing.T) {
+	calls := 0
+	inner := funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+		calls++
+		proto.Merge(msg, completeWords(string(text), "NN"))
+		return nil
+	})
+	var faults []Fault
+	c := NewChaosClient(inner, 1)
+	c.OnFault = func(f Fault) { faults = append(faults, f) }
+
+	doc := &nlp.Document{}
+	if err := c.RunText(context.Background(), []byte("John runs."), doc); err != nil || doc.GetText() != "John runs." || len(faults) != 0 {
+		t.Errorf("%v %v %v", err, doc, faults)
+	}
+
+	c.ErrorRate = 1
+	var serr *ServerError
+	if err := c.RunText(context.Background(), []byte("John runs."), &nlp.Document{}); !errors.As(err, &serr) || serr.StatusCode != 503 || calls != 1 {
+		t.Errorf("%v %d", err, calls)
+	}
+
+	c.ErrorRate, c.TruncateRate = 0, 1
+	if err := c.RunText(context.Background(), []byte("John runs."), &nlp.Document{}); err == nil || calls != 2 {
+		t.Errorf("a truncated response should fail to decode: %v", err)
+	}
+
+	c.TruncateRate, c.MalformedRate = 0, 1
+	for i := 0; i < 20; i++ {
+		// a corrupted response decodes with an error or into garbage, never panics
+		c.RunText(context.Background(), []byte("John runs."), &nlp.Document{})
+	}
+
+	c.MalformedRate, c.LatencyRate, c.Latency = 0, 1, time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.RunText(ctx, []byte("John runs."), &nlp.Document{}); err != context.DeadlineExceeded {
+		t.Errorf("%v", err)
+	}
+	if len(faults) != 23 || faults[0] != FaultError || faults[1] != FaultTruncated || faults[2] != FaultMalformed || faults[22] != FaultLatency {
+		t.Errorf("%v", faults)
+	}
+}
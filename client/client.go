@@ -0,0 +1,18 @@
+package client
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Client is implemented by every way of running CoreNLP in this package,
+// e.g. Cmd, HttpClient and the wrappers built on top of them.
+//
+type Client interface {
+	// Run runs on the input file, and gets the NLP data in msg
+	Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error
+
+	// RunText runs on the text string, and gets the NLP data in msg
+	RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// countingTransport counts the requests it passes to http.DefaultTransport.
+//
+type countingTransport struct {
+	n int
+}
+
+func (self *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	self.n++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClone(t *testing.T) {
+	body := serialize(completeWords("John runs.", ""))
+	var annotators, formats []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var props map[string]string
+		json.Unmarshal([]byte(r.URL.Query().Get("properties")), &props)
+		annotators = append(annotators, props["annotators"])
+		formats = append(formats, props["outputFormat"])
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	transport := &countingTransport{}
+	c := NewHttpClient([]string{"tokenize", "ssplit"}, ts.URL).With(WithTransport(transport))
+	ner := c.With(WithAnnotators("tokenize", "ssplit", "ner"))
+	for _, client := range []*HttpClient{c, ner} {
+		if err := client.RunText(context.Background(), []byte("John runs."), &nlp.Document{}); err != nil { t.Fatal(err) }
+	}
+	if transport.n != 2 || len(annotators) != 2 || annotators[0] != "tokenize,ssplit" || annotators[1] != "tokenize,ssplit,ner" {
+		t.Errorf("%d %v", transport.n, annotators)
+	}
+
+	// a started command, as Start leaves it
+	cmd := NewCmd([]string{"tokenize", "ssplit"}, "", "edu.stanford.nlp.pipeline.StanfordCoreNLP", "/nonexistent/java")
+	cmd.http = NewHttpClient(cmd.Annotators, ts.URL)
+	pos := cmd.Clone(WithCmdAnnotators("tokenize", "ssplit", "pos"))
+	if err := pos.RunText(context.Background(), []byte("John runs."), &nlp.Document{}); err != nil { t.Fatal(err) }
+	if annotators[2] != "tokenize,ssplit,pos" {
+		t.Errorf("%v", annotators)
+	}
+	if err := pos.Close(); err != nil || cmd.persistent() == nil {
+		t.Errorf("closing the clone should leave the server: %v", err)
+	}
+	// the clone requests the format of its own serializer
+	cmd.Clone(WithOutputSerializer(JSONSerializer)).RunText(context.Background(), []byte("John runs."), &nlp.Document{})
+	if len(formats) != 4 || formats[2] != "serialized" || formats[3] != "json" {
+		t.Errorf("%v", formats)
+	}
+
+	cmd.http = nil
+	if err := pos.RunText(context.Background(), []byte("John runs."), &nlp.Document{}); err == nil || len(annotators) != 4 {
+		t.Errorf("the clone should run its own process once the original is closed")
+	}
+}
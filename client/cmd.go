@@ -4,11 +4,19 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/server"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -16,6 +24,10 @@ import (
 // The original Java-based CoreNLP package must be downloaded
 // and installed properly.
 //
+// A Cmd is safe for concurrent use, every run starting its own process or
+// sharing the persistent one, provided its fields are not modified once it
+// is in use; derive differently configured commands with With instead.
+//
 // see
 // https://stanfordnlp.github.io/CoreNLP/index.html
 //
@@ -33,6 +45,61 @@ type Cmd struct {
 
 // extra arguments for the Java command
 	Args        []string
+
+// CoreNLP properties passed after the class as -key value, e.g.
+// "parse.model", see CheckProperties
+	Properties  map[string]string
+
+// maximal heap size of the JVM, e.g. "4g", passed as -mx
+	Memory      string
+
+// number of threads annotating files in parallel, passed as -threads
+	Threads     int
+
+// stream the text through the standard input of the Java process, without
+// an input file, see RunText
+	Stdin       bool
+
+// when set, receives a copy of the standard error of the Java process as it
+// is written, e.g. the model loading and the files processed
+	Log         io.Writer
+
+// when set, called with every line the Java process writes to the standard error
+	OnLog       func(line string)
+
+// when set, called by RunFiles as each file starts being processed,
+// with the number of files started so far and the total
+	OnProgress  func(done, total int)
+
+// output format of the Java process, one of the format constants, passed as
+// -outputFormat. The default is the serialized protobuf; the others are
+// parsed in Go, which keeps less data, and "text" can only be read by RunRaw.
+	OutputFormat string
+
+// when set, decodes the output instead of OutputFormat, see WithOutputSerializer
+	Serializer  Serializer
+
+// environment variables added to those of the current process, e.g. "LANG=C.UTF-8"
+	Env         []string
+
+// working directory of the Java process, the current one by default
+	Dir         string
+
+// when set, the input and output files of Run are written to this directory
+// and kept, see CmdResult.Output; otherwise to a temporary one, removed after
+	OutputDir   string
+
+// when set, the temporary files are written under this root, within its quota,
+// instead of the system temporary directory
+	TempRoot    *TempRoot
+
+	mu          sync.Mutex
+	server      *server.Manager
+	http        *HttpClient
+	stats       Throughput
+
+	// the command cloned by Clone, whose persistent process is shared
+	parent      *Cmd
 }
 
 // NewCmd creates an instance of Cmd.
@@ -51,6 +118,8 @@ type Cmd struct {
 // you can create instance:
 // NewCmd([]string{"tokenize","ssplit","pos"}, "/home/user/standford/*")
 //
+// BuildCmd creates a validated Cmd from options instead.
+//
 // see
 // https://stanfordnlp.github.io/CoreNLP/cmdline.html
 //
@@ -71,7 +140,7 @@ func NewCmd(annotators []string, args ...string) *Cmd {
 		args = args[1:]
 	}
 
-	return &Cmd{annotators, cp, c, java, args}
+	return &Cmd{Annotators: annotators, ClassPath: cp, Class: c, javaCmd: java, Args: args}
 }
 
 // Runs on the input file, and gets the NLP data in msg.
@@ -88,51 +157,313 @@ func (self *Cmd) Run(ctx context.Context, input string, msg protoreflect.ProtoMe
 
 // RunText runs on the text string, and gets the NLP data in msg
 //
+// With Stdin, the text is piped into CoreNLP as /dev/stdin instead of being
+// written to a file, and annotated at once like a file; the output is read
+// from /dev/stdout, so that it runs in a read-only container.
+//
 func (self *Cmd) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
-	outputDir, err := ioutil.TempDir("", "coreNLP")
+	if c := self.persistent(); c != nil {
+		return c.RunText(ctx, text, msg)
+	}
+	if self.Stdin {
+		return self.runStdin(ctx, text, msg)
+	}
+
+	data, err := self.RunRaw(ctx, text)
 	if err != nil {
 		return err
 	}
-	defer os.RemoveAll(outputDir)
+	return self.decode(data, msg)
+}
 
-	input := filepath.Join(outputDir, "input.text")
-	if err = ioutil.WriteFile(input, text, 0666); err != nil {
-		return err
+// RunRaw runs a Java process on the text string, and returns its output as is,
+// in OutputFormat. It always starts a new process, even in the persistent mode.
+//
+func (self *Cmd) RunRaw(ctx context.Context, text []byte) ([]byte, error) {
+	data, _, err := self.runFile(ctx, text)
+	return data, err
+}
+
+// runFile runs a Java process on the text written to a file, and returns its
+// output with the result of the process. The files are removed, unless
+// OutputDir is set.
+//
+func (self *Cmd) runFile(ctx context.Context, text []byte) ([]byte, *CmdResult, error) {
+	outputDir := self.OutputDir
+	var input string
+	if outputDir == "" {
+		dir, cleanup, err := self.tempDir(int64(len(text)))
+		if err != nil {
+			return nil, nil, err
+		}
+		defer cleanup()
+		outputDir, input = dir, filepath.Join(dir, "input.text")
+		if err = ioutil.WriteFile(input, text, 0666); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		f, err := ioutil.TempFile(outputDir, "input-*.text")
+		if err != nil {
+			return nil, nil, err
+		}
+		input = f.Name()
+		_, err = f.Write(text)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	args := self.arguments("-file", longPath(input), "--outputDirectory", longPath(outputDir))
+	_, res, err := self.pipe(ctx, args, nil)
+	if err != nil {
+		return nil, res, err
+	}
+	self.record(1, int64(len(text)), res.Elapsed)
+
+	output := input + format.Extension(self.serializer().Format())
+	if self.OutputDir != "" {
+		res.Output = output
+	}
+	data, err := ioutil.ReadFile(output)
+	return data, res, err
+}
+
+// decode reads the output of the Java process into msg with its serializer.
+//
+func (self *Cmd) decode(data []byte, msg protoreflect.ProtoMessage) error {
+	return self.serializer().Unmarshal(data, msg)
+}
+
+// serializer returns Serializer, or else the serializer of OutputFormat.
+//
+func (self *Cmd) serializer() Serializer {
+	if self.Serializer != nil {
+		return self.Serializer
+	}
+	return SerializerOf(self.OutputFormat)
+}
+
+// Start switches Cmd to the persistent mode: a private CoreNLP server is
+// launched with the same Java command, classpath and arguments, the annotators
+// are loaded once, and every following Run is sent to it instead of starting
+// a new Java process. Start blocks until the models are loaded or ctx is done.
+// Close stops the server.
+//
+func (self *Cmd) Start(ctx context.Context) error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.server != nil {
+		return nil
 	}
 
 	args := self.Args
-	if self.ClassPath != "" {
-		args = append(args, "-cp", self.ClassPath)
+	if self.Memory != "" {
+		args = append([]string{"-mx" + self.Memory}, args...)
 	}
-	args = append(args, self.Class)
-	if self.Annotators != nil && len(self.Annotators) > 0 {
-		args = append(args, "-annotators", strings.Join(self.Annotators, ","))
+	java, err := self.java()
+	if err != nil {
+		return err
 	}
+	m := server.NewManager(0, append([]string{classPath(self.ClassPath), "edu.stanford.nlp.pipeline.StanfordCoreNLPServer", java}, args...)...)
+	m.Preload = self.Annotators
+	m.Log = self.logWriter()
+	// Cmd has no time limit of its own, the caller's context bounds each request
+	m.Timeout = 3600000
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+	self.server = m
+	self.http = NewHttpClient(self.Annotators, m.URL()).With(self.httpOptions()...)
+	return nil
+}
 
-	args = append(args,
-		"-file",
-		input,
-		"--outputDirectory",
-		outputDir,
+// httpOptions are the settings of the command its persistent server requests
+// use: the annotators, the properties and the serializer.
+//
+func (self *Cmd) httpOptions() []HttpOption {
+	return []HttpOption{WithAnnotators(self.Annotators...), WithProperties(self.Properties), WithSerializer(self.serializer())}
+}
+
+// Close stops the persistent process started by Start, if any.
+//
+func (self *Cmd) Close() error {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.server == nil {
+		return nil
+	}
+	err := self.server.Close()
+	self.server, self.http = nil, nil
+	return err
+}
+
+// arguments returns the Java arguments, with input the options selecting the files.
+//
+func (self *Cmd) arguments(input ...string) []string {
+	args := append(self.options(), input...)
+	if f := self.serializer().Format(); f != format.Serialized {
+		return append(args, "-outputFormat", f)
+	}
+	return append(args,
 		"-outputFormat",
 		"serialized",
 		"-outputSerializer",
-		"edu.stanford.nlp.pipeline.ProtobufAnnotationSerializer")
+		protobufSerializer)
+}
+
+// options returns the JVM arguments, the class and the pipeline options.
+//
+func (self *Cmd) options() []string {
+	var args []string
+	if self.Memory != "" {
+		args = append(args, "-mx"+self.Memory)
+	}
+	args = append(args, self.Args...)
+	if self.ClassPath != "" {
+		args = append(args, "-cp", classPath(self.ClassPath))
+	}
+	args = append(args, self.Class)
+	if len(self.Annotators) > 0 {
+		args = append(args, "-annotators", strings.Join(self.Annotators, ","))
+	}
+	if self.Threads > 0 {
+		args = append(args, "-threads", strconv.Itoa(self.Threads))
+	}
+	keys := make([]string, 0, len(self.Properties))
+	for k := range self.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "-"+k, self.Properties[k])
+	}
+	return args
+}
+
+func (self *Cmd) execute(ctx context.Context, args []string, logs ...io.Writer) error {
+	_, _, err := self.pipe(ctx, args, nil, logs...)
+	return err
+}
 
-	cmd := exec.CommandContext(ctx, self.javaCmd, args...)
+// pipe runs the Java process with stdin, and returns its standard output and
+// the result of the process, nil if it could not start. The standard error is
+// also copied to logs. When ctx is done, the process is killed with all its
+// children, see killTree.
+//
+func (self *Cmd) pipe(ctx context.Context, args []string, stdin io.Reader, logs ...io.Writer) ([]byte, *CmdResult, error) {
+	cmd, err := self.command(args)
+	if err != nil {
+		return nil, nil, err
+	}
 	stdout := &bytes.Buffer{}
 	stderr := &bytes.Buffer{}
+	cmd.Stdin = stdin
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
+	if w := self.logWriter(logs...); w != nil {
+		cmd.Stderr = io.MultiWriter(stderr, w)
+		defer flushLines(w)
+	}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("%s: %s", err.Error(), stderr.String())
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
 	}
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			killTree(cmd)
+		case <-done:
+		}
+	}()
 
-	data, err := ioutil.ReadFile(input+".ser.gz")
+	err = cmd.Wait()
+	res := newCmdResult(cmd, time.Since(start), stderr.Bytes())
 	if err != nil {
-		return err
+		if ctx.Err() != nil {
+			return nil, res, ctx.Err()
+		}
+		return nil, res, fmt.Errorf("%s: %s", err.Error(), stderr.String())
 	}
+	return stdout.Bytes(), res, nil
+}
 
-	return BytesUnmarshal(data, msg)
+// command builds the Java process run by pipe.
+//
+func (self *Cmd) command(args []string) (*exec.Cmd, error) {
+	java, err := self.java()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(java, args...)
+	cmd.Dir = self.Dir
+	if len(self.Env) > 0 {
+		cmd.Env = append(os.Environ(), self.Env...)
+	}
+	newProcessGroup(cmd)
+	return cmd, nil
+}
+
+// Command returns, without running it, the Java process Run would start on
+// the input file, writing its output next to it: the resolved Java command,
+// the arguments with the classpath and the properties rendered, the
+// environment and the working directory. It is meant for debugging a setup,
+// see also CommandLine; the returned command is killed when ctx is done.
+//
+func (self *Cmd) Command(ctx context.Context, input string) (*exec.Cmd, error) {
+	args := self.arguments("-file", longPath(input), "--outputDirectory", longPath(filepath.Dir(input)))
+	cmd, err := self.command(args)
+	if err != nil {
+		return nil, err
+	}
+	c := exec.CommandContext(ctx, cmd.Path, cmd.Args[1:]...)
+	c.Dir, c.Env, c.SysProcAttr = cmd.Dir, cmd.Env, cmd.SysProcAttr
+	return c, nil
+}
+
+// CommandLine renders the command as a line for a POSIX shell, quoting the
+// arguments that need it, e.g. the classpath wildcard.
+//
+func CommandLine(cmd *exec.Cmd) string {
+	words := make([]string, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		if i == 0 {
+			arg = cmd.Path
+		}
+		words[i] = shellQuote(arg)
+	}
+	line := strings.Join(words, " ")
+	if cmd.Dir != "" {
+		line = "cd " + shellQuote(cmd.Dir) + " && " + line
+	}
+	return line
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=,+@%", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func (self *Cmd) persistent() *HttpClient {
+	self.mu.Lock()
+	http, parent := self.http, self.parent
+	self.mu.Unlock()
+	if http == nil && parent != nil {
+		if p := parent.persistent(); p != nil {
+			return p.With(self.httpOptions()...)
+		}
+	}
+	return http
 }
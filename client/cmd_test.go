@@ -2,9 +2,15 @@ package client
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/genelet/corenlp-golang/format"
 	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestCmd(t *testing.T) {
@@ -20,3 +26,142 @@ func TestCmd(t *testing.T) {
 		t.Errorf("%s", pb.String()[:168])
     }
 }
+
+func TestCmdPersistent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cmd")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+	java := filepath.Join(dir, "java")
+	if err = ioutil.WriteFile(java, []byte("#!/bin/sh\necho \"$@\" >&2\nexit 1\n"), 0755); err != nil { t.Fatal(err) }
+
+	cmd := NewCmd([]string{"tokenize","ssplit"}, "/opt/corenlp/*", "", java, "-mx4g")
+	err = cmd.Start(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "-mx4g -cp /opt/corenlp/* edu.stanford.nlp.pipeline.StanfordCoreNLPServer") || !strings.Contains(err.Error(), "-preload tokenize,ssplit") {
+		t.Errorf("%v", err)
+	}
+
+	// once started, the texts go to the private server
+	srv := newFakeServer()
+	defer srv.Close()
+	// server.Manager.URL ends with a slash
+	cmd.http = NewHttpClient(cmd.Annotators, srv.URL+"/")
+	pb := &nlp.Document{}
+	if err = cmd.RunText(context.Background(), []byte("hello"), pb); err != nil { t.Fatal(err) }
+	if pb.GetText() != "hello" || srv.count() != 1 {
+		t.Errorf("%s", pb.String())
+	}
+	if err = cmd.Close(); err != nil { t.Fatal(err) }
+}
+
+func TestCmdWith(t *testing.T) {
+	cmd := NewCmd([]string{"tokenize"}, "/opt/corenlp/*", "edu.stanford.nlp.pipeline.StanfordCoreNLP", "java", "-Dx=y")
+	c := cmd.With(WithMemory("4g"), WithGC("-XX:+UseG1GC"), WithJava("/usr/bin/java"))
+	if len(cmd.Args) != 1 || cmd.Memory != "" || cmd.javaCmd != "java" {
+		t.Errorf("original changed: %v", cmd.Args)
+	}
+
+	args := strings.Join(c.arguments("-file", "in.txt"), " ")
+	if !strings.HasPrefix(args, "-mx4g -Dx=y -XX:+UseG1GC -cp /opt/corenlp/* edu.stanford.nlp.pipeline.StanfordCoreNLP -annotators tokenize -file in.txt") || c.javaCmd != "/usr/bin/java" {
+		t.Errorf("%s", args)
+	}
+}
+
+func TestCmdOutputFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fakejava")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	// imitates StanfordCoreNLP -file: writes template with the extension of -outputFormat
+	java, err := fakeJava(dir, `
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -file) file=$2; shift;;
+    -outputFormat) fmt=$2; shift;;
+  esac
+  shift
+done
+cp "$(dirname "$0")/template" "$file.$fmt"
+`)
+	if err != nil { t.Fatal(err) }
+	conll := "1\tJohn\tJohn\tNNP\tPERSON\t2\tnsubj\n2\truns\trun\tVBZ\tO\t0\tROOT\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "template"), []byte(conll), 0666); err != nil { t.Fatal(err) }
+
+	cmd := NewCmd([]string{"tokenize", "ssplit", "pos"}, "", "edu.stanford.nlp.pipeline.StanfordCoreNLP", java).With(WithOutputFormat(format.CoNLL))
+	if args := strings.Join(cmd.arguments(), " "); !strings.HasSuffix(args, "-outputFormat conll") {
+		t.Errorf("%s", args)
+	}
+
+	raw, err := cmd.RunRaw(context.Background(), []byte("John runs"))
+	if err != nil { t.Fatal(err) }
+	if string(raw) != conll {
+		t.Errorf("%q", raw)
+	}
+
+	doc := &nlp.Document{}
+	if err = cmd.RunText(context.Background(), []byte("John runs"), doc); err != nil { t.Fatal(err) }
+	if doc.GetText() != "John runs" || doc.Sentence[0].Token[1].GetLemma() != "run" {
+		t.Errorf("%v", doc)
+	}
+
+	if err = cmd.RunText(context.Background(), []byte("John runs"), &nlp.Sentence{}); err == nil {
+		t.Errorf("conll should need a Document")
+	}
+}
+
+func TestCmdStdin(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fakejava")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	// imitates CoreNLP on -file /dev/stdin -outputFile /dev/stdout: reads the
+	// whole text at once, and writes template, if the text is that of template
+	java, err := fakeJava(dir, `
+case "$*" in *"-file /dev/stdin -outputFile /dev/stdout"*) ;; *) exit 1;; esac
+case "$*" in *outputDirectory*) exit 1;; esac
+[ "$(cat)" = "$(cat "$(dirname "$0")/text")" ] || exit 2
+cat "$(dirname "$0")/template"
+`)
+	if err != nil { t.Fatal(err) }
+	text := "Hi\n\nBye"
+	want := &nlp.Document{Text: proto.String(text), Sentence: []*nlp.Sentence{
+		{TokenOffsetBegin: proto.Uint32(0), TokenOffsetEnd: proto.Uint32(1), Token: []*nlp.Token{{Word: proto.String("Hi")}}},
+		{TokenOffsetBegin: proto.Uint32(1), TokenOffsetEnd: proto.Uint32(2), Token: []*nlp.Token{{Word: proto.String("Bye"), BeginChar: proto.Uint32(4)}}},
+	}}
+	if err = ioutil.WriteFile(filepath.Join(dir, "text"), []byte(text), 0666); err != nil { t.Fatal(err) }
+	if err = ioutil.WriteFile(filepath.Join(dir, "template"), serialize(want), 0666); err != nil { t.Fatal(err) }
+
+	cmd := NewCmd([]string{"tokenize", "ssplit"}, "", "edu.stanford.nlp.pipeline.StanfordCoreNLP", java).With(WithStdin())
+	doc := &nlp.Document{}
+	if err = cmd.RunText(context.Background(), []byte(text), doc); err != nil { t.Fatal(err) }
+	if !proto.Equal(doc, want) {
+		t.Errorf("%v", doc)
+	}
+
+	if err = cmd.RunText(context.Background(), []byte("Hi"), doc); err == nil {
+		t.Errorf("another text should fail")
+	}
+}
+
+func TestCmdCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "java")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+	java, err := fakeJava(dir, "exit 0")
+	if err != nil { t.Fatal(err) }
+
+	c := NewCmd([]string{"tokenize", "ssplit"}, "lib/*", "edu.stanford.nlp.pipeline.StanfordCoreNLP", java).With(WithMemory("2g"), WithEnv("LANG=C.UTF-8"), WithDir(dir))
+	input := filepath.Join(dir, "input.txt")
+	cmd, err := c.Command(context.Background(), input)
+	if err != nil { t.Fatal(err) }
+	if cmd.Path != java || cmd.Dir != dir || cmd.Env[len(cmd.Env)-1] != "LANG=C.UTF-8" {
+		t.Errorf("%s %s %v", cmd.Path, cmd.Dir, cmd.Env)
+	}
+	want := "cd " + dir + " && " + java + " -mx2g -cp 'lib/*' edu.stanford.nlp.pipeline.StanfordCoreNLP -annotators tokenize,ssplit -file " + input +
+		" --outputDirectory " + dir + " -outputFormat serialized -outputSerializer edu.stanford.nlp.pipeline.ProtobufAnnotationSerializer"
+	if line := CommandLine(cmd); line != want {
+		t.Errorf("%s", line)
+	}
+	if err = cmd.Run(); err != nil {
+		t.Errorf("%v", err)
+	}
+}
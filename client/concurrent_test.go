@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// completeWords is splitWords with the required fields of the sentences set,
+// for serializing.
+//
+func completeWords(text, pos string) *nlp.Document {
+	doc := splitWords(text, pos)
+	begin := uint32(0)
+	for _, s := range doc.Sentence {
+		s.TokenOffsetBegin = proto.Uint32(begin)
+		begin += uint32(len(s.Token))
+		s.TokenOffsetEnd = proto.Uint32(begin)
+	}
+	return doc
+}
+
+// The tests below share one client among goroutines, which run and derive
+// new clients at the same time; run them with -race.
+
+func TestHttpClientConcurrent(t *testing.T) {
+	body := serialize(completeWords("John runs.", "NN"))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	shared := NewHttpClient([]string{"tokenize", "ssplit"}, ts.URL).With(WithRateLimit(1000, 100), WithMaxInFlight(4), WithProperties(map[string]string{"a": "b"}))
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := shared
+			if i%2 == 0 {
+				c = shared.With(WithProperties(map[string]string{"a": strconv.Itoa(i)}), WithDroppedLayers("pos"))
+				c.Annotators = append(c.Annotators, "pos")
+			}
+			doc := &nlp.Document{}
+			if err := c.RunText(context.Background(), []byte("John runs."), doc); err != nil {
+				errs <- err
+			} else if len(doc.Sentence) != 1 || (i%2 == 0) != (doc.Sentence[0].Token[0].Pos == nil) {
+				t.Errorf("%d: %v", i, doc)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if len(shared.Annotators) != 2 || shared.Properties["a"] != "b" {
+		t.Errorf("%v %v", shared.Annotators, shared.Properties)
+	}
+}
+
+func TestCmdConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fakejava")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	java, err := fakeJava(dir, `
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -file) file=$2; shift;;
+  esac
+  shift
+done
+cp "$(dirname "$0")/template" "$file.ser.gz"
+`)
+	if err != nil { t.Fatal(err) }
+	if err = ioutil.WriteFile(filepath.Join(dir, "template"), serialize(completeWords("John runs.", "")), 0666); err != nil { t.Fatal(err) }
+
+	shared := NewCmd([]string{"tokenize", "ssplit"}, "", "edu.stanford.nlp.pipeline.StanfordCoreNLP", java).With(WithJVMArgs("-Dx=y"))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := shared
+			if i%2 == 0 {
+				c = shared.With(WithJVMArgs("-Di=" + strconv.Itoa(i)), WithEnv("I="+strconv.Itoa(i)))
+			}
+			doc := &nlp.Document{}
+			if err := c.RunText(context.Background(), []byte("John runs."), doc); err != nil {
+				t.Error(err)
+			} else if len(doc.Sentence) != 1 {
+				t.Errorf("%d: %v", i, doc)
+			}
+			shared.Throughput()
+		}(i)
+	}
+	wg.Wait()
+	if tp := shared.Throughput(); tp.Documents != 4 || len(shared.Args) != 1 {
+		t.Errorf("%#v %v", tp, shared.Args)
+	}
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// TextRedaction selects how DebugLog shows the input texts.
+//
+type TextRedaction int
+
+const (
+	// RedactText shows the length of the text only.
+	RedactText TextRedaction = iota
+	// HashText shows a SHA-256 prefix of the text, telling equal texts apart.
+	HashText
+	// TruncateText shows the beginning of the text.
+	TruncateText
+	// ShowText shows the whole text, for test data only.
+	ShowText
+)
+
+// DebugLog logs every request of a HttpClient, with its properties and the
+// input text redacted, and the size, status and time of the response, to
+// debug server-side issues without leaking sensitive texts. The credentials
+// are never logged.
+//
+type DebugLog struct {
+	// called with every log line
+	OnLine func(line string)
+
+	// how the input texts are shown, RedactText by default
+	Text TextRedaction
+
+	// the characters kept by TruncateText, default 40
+	Truncate int
+
+	// prefixed to the texts hashed by HashText, so the hashes cannot be
+	// matched against a dictionary of known texts
+	Salt string
+}
+
+// WithDebugLog logs the requests of the client to fn, see DebugLog.
+//
+// redaction[0], optional: how the input texts are shown, RedactText by default.
+//
+func WithDebugLog(fn func(line string), redaction ...TextRedaction) HttpOption {
+	return func(self *HttpClient) {
+		self.Debug = &DebugLog{OnLine: fn}
+		if len(redaction) > 0 {
+			self.Debug.Text = redaction[0]
+		}
+	}
+}
+
+// text returns the text as shown in the log.
+//
+func (self *DebugLog) text(text []byte) string {
+	size := strconv.Itoa(len(text)) + " bytes"
+	switch self.Text {
+	case HashText:
+		sum := sha256.Sum256(append([]byte(self.Salt), text...))
+		return size + " sha256:" + hex.EncodeToString(sum[:8])
+	case TruncateText:
+		n := self.Truncate
+		if n <= 0 {
+			n = 40
+		}
+		s := string(text)
+		if utf8.RuneCountInString(s) > n {
+			s = string([]rune(s)[:n]) + "..."
+		}
+		return size + " " + strconv.Quote(s)
+	case ShowText:
+		return size + " " + strconv.Quote(string(text))
+	}
+	return size
+}
+
+// request logs a request to the server at curl with its query.
+//
+func (self *DebugLog) request(curl, query string, text []byte) {
+	values, _ := url.ParseQuery(query)
+	line := "corenlp request " + curl
+	for _, k := range []string{"properties", "pipelineLanguage", "resetDefault"} {
+		if v := values.Get(k); v != "" {
+			line += " " + k + "=" + v
+		}
+	}
+	self.OnLine(line + " text=" + self.text(text))
+}
+
+// response logs the response to a request, or its error.
+//
+func (self *DebugLog) response(status int, size int, elapsed time.Duration, err error) {
+	line := fmt.Sprintf("corenlp response status=%d bytes=%d elapsed=%s", status, size, elapsed.Round(time.Millisecond))
+	if err != nil {
+		line += " error=" + err.Error()
+	}
+	self.OnLine(line)
+}
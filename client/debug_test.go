@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestDebugLog(t *testing.T) {
+	body := serialize(completeWords("Mary Lee runs.", ""))
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("pipelineLanguage") == "german" {
+			http.Error(w, "no models", http.StatusInternalServerError)
+			return
+		}
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	var mu sync.Mutex
+	var lines []string
+	logf := func(line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, line)
+	}
+	secret := []byte("Mary Lee runs.")
+
+	c := NewHttpClient([]string{"tokenize"}, ts.URL).With(WithDebugLog(logf), WithBasicAuth("user", "password"))
+	if err := c.RunText(context.Background(), secret, &nlp.Document{}); err != nil { t.Fatal(err) }
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "corenlp request "+ts.URL+`/ properties={"annotators":"tokenize","outputFormat":"serialized"`) ||
+		!strings.HasSuffix(lines[0], " text=14 bytes") || !strings.HasPrefix(lines[1], "corenlp response status=200 bytes=") {
+		t.Errorf("%q", lines)
+	}
+
+	lines = nil
+	c = c.With(WithDebugLog(logf, TruncateText), WithLanguage("german"))
+	c.Debug.Truncate = 4
+	if err := c.RunText(context.Background(), secret, &nlp.Document{}); err == nil { t.Fatal("expected a server error") }
+	if len(lines) != 2 || !strings.Contains(lines[0], `pipelineLanguage=german text=14 bytes "Mary..."`) || !strings.Contains(lines[1], "status=500 bytes=0") || !strings.Contains(lines[1], "error=HTTP status 500") {
+		t.Errorf("%q", lines)
+	}
+
+	lines = nil
+	c = c.With(WithDebugLog(logf, HashText), WithLanguage(""))
+	c.RunText(context.Background(), secret, &nlp.Document{})
+	c.RunText(context.Background(), secret, &nlp.Document{})
+	for _, line := range lines {
+		if strings.Contains(line, "Mary") || strings.Contains(line, "password") {
+			t.Errorf("%q", line)
+		}
+	}
+	if !strings.Contains(lines[0], "sha256:") || lines[0] != lines[2] {
+		t.Errorf("%q", lines)
+	}
+}
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"time"
+)
+
+// docDateProperty is the property setting the reference date of SUTime.
+//
+const docDateProperty = "ner.docdate.useFixedDate"
+
+// WithDocDate sets the reference date of the documents, against which SUTime
+// resolves the relative expressions, e.g. "next Tuesday"; by default it is the
+// day the text is annotated. For documents of different dates, derive a client
+// per date, which is cheap:
+//
+// c.With(WithDocDate(published)).RunText(ctx, text, doc)
+//
+func WithDocDate(date time.Time) HttpOption {
+	return WithProperties(map[string]string{docDateProperty: date.Format("2006-01-02")})
+}
+
+// WithCmdDocDate sets the reference date of the documents of a command, see WithDocDate.
+//
+func WithCmdDocDate(date time.Time) CmdOption {
+	return WithCmdProperties(map[string]string{docDateProperty: date.Format("2006-01-02")})
+}
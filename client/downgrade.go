@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requires maps an annotator to those it depends on, for dropping the
+// dependents together with an annotator: each requirement is met by any of
+// its alternatives, e.g. coref needs parse or depparse, and ner.
+//
+var requires = map[string][][]string{
+	"coref":     {{"parse", "depparse"}, {"ner"}},
+	"dcoref":    {{"parse"}, {"ner"}},
+	"sentiment": {{"parse"}},
+	"natlog":    {{"depparse"}},
+	"openie":    {{"natlog"}},
+	"kbp":       {{"coref"}, {"depparse"}},
+	"quote":     {{"coref"}},
+	"relation":  {{"parse"}, {"depparse"}},
+}
+
+// DowngradeLevel is an annotator set tried by DowngradeClient.
+//
+type DowngradeLevel struct {
+	Annotators []string
+
+	// the annotators dropped compared to the full set
+	Dropped []string
+
+	Client Client
+}
+
+// Downgrade tells which level of DowngradeClient served a request.
+//
+type Downgrade struct {
+	// 0 for the full annotator set
+	Level int
+
+	// the annotators whose layers are missing from the result
+	Dropped []string
+}
+
+// DowngradeClient runs the full annotator set first and, when a request
+// times out, retries with cheaper sets, e.g. without coref, then without
+// parse, so a slow document still gets the basic layers.
+//
+type DowngradeClient struct {
+	// the annotator sets from the full to the cheapest
+	Levels []*DowngradeLevel
+
+	// time limit of each attempt; 0 leaves the limit to ctx
+	Timeout time.Duration
+
+	// IsTimeout decides if an error triggers the next level, default to IsTimeout
+	IsTimeout func(error) bool
+
+	// OnDowngrade, optional, is called after each request
+	OnDowngrade func(Downgrade, error)
+}
+
+// NewDowngradeClient creates an instance of DowngradeClient. build creates
+// the client for an annotator set, and drops lists the annotators dropped
+// in turn, default to coref then parse. Dropping an annotator also drops
+// those depending on it, e.g. sentiment with parse.
+//
+// For example:
+// NewDowngradeClient(annotators, func(a []string) Client { return NewHttpClient(a, url) })
+//
+func NewDowngradeClient(annotators []string, build func([]string) Client, drops ...string) *DowngradeClient {
+	if len(drops) == 0 {
+		drops = []string{"coref", "parse"}
+	}
+	c := &DowngradeClient{IsTimeout: IsTimeout}
+	c.Levels = append(c.Levels, &DowngradeLevel{Annotators: annotators, Client: build(annotators)})
+
+	current := annotators
+	var dropped []string
+	for _, drop := range drops {
+		removed := dependents(current, drop)
+		if len(removed) == 0 {
+			continue
+		}
+		var kept []string
+		for _, a := range current {
+			if !removed[a] {
+				kept = append(kept, a)
+			} else {
+				dropped = append(dropped, a)
+			}
+		}
+		current = kept
+		c.Levels = append(c.Levels, &DowngradeLevel{Annotators: kept, Dropped: append([]string{}, dropped...), Client: build(kept)})
+	}
+	return c
+}
+
+// dependents returns drop and the annotators of set depending on it, directly or not:
+// those with a requirement whose alternatives in set are all removed.
+//
+func dependents(set []string, drop string) map[string]bool {
+	removed := make(map[string]bool)
+	for _, a := range set {
+		if a == drop {
+			removed[a] = true
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+	present := make(map[string]bool, len(set))
+	for _, a := range set {
+		present[a] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, a := range set {
+			if removed[a] || !unmet(requires[a], present, removed) {
+				continue
+			}
+			removed[a] = true
+			changed = true
+		}
+	}
+	return removed
+}
+
+// unmet tells if one of the requirements has an alternative removed, and
+// none left in the set.
+//
+func unmet(requirements [][]string, present, removed map[string]bool) bool {
+	for _, alternatives := range requirements {
+		lost, left := false, false
+		for _, r := range alternatives {
+			lost = lost || removed[r]
+			left = left || (present[r] && !removed[r])
+		}
+		if lost && !left {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTimeout reports whether err means the request ran out of time: a deadline
+// exceeded, a network timeout, or the server answering 500, as CoreNLP does
+// when its own -timeout expires, or 504.
+//
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	var se *ServerError
+	return errors.As(err, &se) && (se.StatusCode == http.StatusInternalServerError || se.StatusCode == http.StatusGatewayTimeout)
+}
+
+// Run runs on the input file, and gets the NLP data in msg
+//
+func (self *DowngradeClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	data, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	return self.RunText(ctx, data, msg)
+}
+
+// RunText runs on the text string, and gets the NLP data in msg
+//
+func (self *DowngradeClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	_, err := self.RunDowngrade(ctx, text, msg)
+	return err
+}
+
+// RunDowngrade is RunText also telling which layers were downgraded.
+//
+func (self *DowngradeClient) RunDowngrade(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) (Downgrade, error) {
+	isTimeout := self.IsTimeout
+	if isTimeout == nil {
+		isTimeout = IsTimeout
+	}
+
+	var d Downgrade
+	var err error
+	for i, level := range self.Levels {
+		d = Downgrade{Level: i, Dropped: level.Dropped}
+		err = self.attempt(ctx, level.Client, text, msg)
+		if err == nil || !isTimeout(err) || ctx.Err() != nil {
+			break
+		}
+	}
+	if self.OnDowngrade != nil {
+		self.OnDowngrade(d, err)
+	}
+	return d, err
+}
+
+func (self *DowngradeClient) attempt(ctx context.Context, c Client, text []byte, msg protoreflect.ProtoMessage) error {
+	if self.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, self.Timeout)
+		defer cancel()
+	}
+	return c.RunText(ctx, text, msg)
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestDowngradeClient(t *testing.T) {
+	var tried []string
+	build := func(annotators []string) Client {
+		set := strings.Join(annotators, ",")
+		return funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+			tried = append(tried, set)
+			switch {
+			case strings.Contains(set, "coref"):
+				<-ctx.Done()
+				return ctx.Err()
+			case strings.Contains(set+",", ",parse,") && string(text) == "long":
+				return &ServerError{StatusCode: http.StatusInternalServerError, Status: "500 Internal Server Error"}
+			case string(text) == "bad":
+				return &ServerError{StatusCode: http.StatusBadRequest, Status: "400 Bad Request"}
+			}
+			msg.(*nlp.Document).Text = proto.String(set)
+			return nil
+		})
+	}
+
+	c := NewDowngradeClient([]string{"tokenize", "ssplit", "pos", "parse", "sentiment", "depparse", "coref"}, build)
+	c.Timeout = 10 * time.Millisecond
+	if len(c.Levels) != 3 || strings.Join(c.Levels[2].Dropped, ",") != "coref,parse,sentiment" {
+		t.Fatalf("%v", c.Levels)
+	}
+
+	var seen []Downgrade
+	c.OnDowngrade = func(d Downgrade, err error) { seen = append(seen, d) }
+	doc := &nlp.Document{}
+	d, err := c.RunDowngrade(context.Background(), []byte("short"), doc)
+	if err != nil || d.Level != 1 || doc.GetText() != "tokenize,ssplit,pos,parse,sentiment,depparse" {
+		t.Errorf("%v %v %s", d, err, doc.GetText())
+	}
+	if err = c.RunText(context.Background(), []byte("long"), doc); err != nil || doc.GetText() != "tokenize,ssplit,pos,depparse" {
+		t.Errorf("%v %s", err, doc.GetText())
+	}
+	if len(seen) != 2 || seen[1].Level != 2 || len(seen[1].Dropped) != 3 {
+		t.Errorf("%v", seen)
+	}
+
+	tried = nil
+	if err = c.RunText(context.Background(), []byte("bad"), doc); err == nil || len(tried) != 2 {
+		t.Errorf("%v %v", err, tried)
+	}
+
+	// coref keeps with depparse when parse is dropped, not without it
+	c = NewDowngradeClient([]string{"tokenize", "ssplit", "pos", "ner", "parse", "depparse", "coref"}, build, "parse")
+	if len(c.Levels) != 2 || strings.Join(c.Levels[1].Dropped, ",") != "parse" {
+		t.Errorf("%v", c.Levels[1])
+	}
+	c = NewDowngradeClient([]string{"tokenize", "ssplit", "pos", "ner", "parse", "coref"}, build, "parse")
+	if len(c.Levels) != 2 || strings.Join(c.Levels[1].Dropped, ",") != "parse,coref" {
+		t.Errorf("%v", c.Levels[1])
+	}
+}
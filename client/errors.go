@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ServerError is returned when the CoreNLP server answers with a non-2xx
+// status, or when an HTML page comes instead of the output of CoreNLP, e.g.
+// the error page of a reverse proxy, whatever its status.
+//
+type ServerError struct {
+	StatusCode int
+	Status     string
+
+	// the beginning of the response body, with the markup of an HTML page removed
+	Body string
+
+	// the response is an HTML page, which CoreNLP never sends, so it comes
+	// from a proxy or another server in between
+	HTML bool
+}
+
+func (self *ServerError) Error() string {
+	msg := "HTTP status " + self.Status
+	if self.HTML {
+		msg += " with an HTML page, likely from a proxy"
+	}
+	if self.Body != "" {
+		msg += ": " + self.Body
+	}
+	return msg
+}
+
+// maxSnippet is the number of characters of the body kept in ServerError.
+//
+const maxSnippet = 200
+
+var (
+	htmlMarkup = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)>|<!--.*?-->|<[^>]*>`)
+	spaces     = regexp.MustCompile(`\s+`)
+)
+
+// newServerError returns the error of a response, with a snippet of its body.
+//
+func newServerError(res *http.Response, body []byte) *ServerError {
+	se := &ServerError{StatusCode: res.StatusCode, Status: res.Status, HTML: isHTML(res.Header.Get("Content-Type"), body)}
+	text := string(body)
+	if se.HTML {
+		text = htmlMarkup.ReplaceAllString(text, " ")
+	}
+	text = strings.TrimSpace(spaces.ReplaceAllString(strings.ToValidUTF8(text, ""), " "))
+	if utf8.RuneCountInString(text) > maxSnippet {
+		text = string([]rune(text)[:maxSnippet]) + "..."
+	}
+	se.Body = text
+	return se
+}
+
+// isHTML reports whether a response of contentType with body is an HTML page.
+//
+func isHTML(contentType string, body []byte) bool {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil && (mt == "text/html" || mt == "application/xhtml+xml") {
+		return true
+	}
+	n := len(body)
+	if n > 512 {
+		n = 512
+	}
+	head := strings.ToLower(strings.TrimSpace(string(body[:n])))
+	return strings.HasPrefix(head, "<!doctype html") || strings.HasPrefix(head, "<html")
+}
+
+// IsUnavailable reports whether err means the server could not be reached
+// or is overloaded, i.e. the same request may succeed elsewhere or later.
+//
+func IsUnavailable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var se *ServerError
+	if errors.As(err, &se) {
+		// a proxy failing, or its maintenance page
+		if se.HTML && (se.StatusCode < 300 || se.StatusCode >= 500) {
+			return true
+		}
+		switch se.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return true
+	}
+	var oe *net.OpError
+	return errors.As(err, &oe) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// CommandError is returned when the Java command cannot be found or started.
+//
+type CommandError struct {
+	// the command looked for, e.g. "java"
+	Java string
+	Err  error
+}
+
+func (self *CommandError) Error() string {
+	return "java command " + self.Java + ": " + self.Err.Error() +
+		" (install Java 8 or later, set JAVA_HOME, or give the command to NewCmd or WithJava)"
+}
+
+func (self *CommandError) Unwrap() error {
+	return self.Err
+}
+
+// JavaVersionError is returned when the Java runtime is too old for CoreNLP,
+// see Cmd.CheckJava.
+//
+type JavaVersionError struct {
+	// the Java command and its major version
+	Java    string
+	Version int
+
+	// the CoreNLP release, "" if unknown, and the Java version it requires
+	CoreNLP  string
+	Required int
+}
+
+func (self *JavaVersionError) Error() string {
+	release := "CoreNLP"
+	if self.CoreNLP != "" {
+		release += " " + self.CoreNLP
+	}
+	return fmt.Sprintf("java command %s is Java %d, %s requires Java %d or later (install a JDK %d+, e.g. from https://adoptium.net, and set JAVA_HOME or use WithJava)",
+		self.Java, self.Version, release, self.Required, self.Required)
+}
+
+// ConfigError is returned when a Cmd is configured wrongly, see Cmd.Validate.
+//
+type ConfigError struct {
+	// the setting at fault, e.g. "Annotators", "ClassPath"
+	Field string
+	Err   error
+}
+
+func (self *ConfigError) Error() string {
+	return "invalid " + self.Field + ": " + self.Err.Error()
+}
+
+func (self *ConfigError) Unwrap() error {
+	return self.Err
+}
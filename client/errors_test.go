@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+const proxyPage = `<!DOCTYPE html>
+<html><head><title>502 Bad Gateway</title><style>body { color: red }</style></head>
+<body><h1>Bad Gateway</h1>
+<p>The upstream server   is unavailable.</p></body></html>`
+
+func TestProxyErrorPage(t *testing.T) {
+	status, contentType, body := 0, "", ""
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer ts.Close()
+	c := NewHttpClient([]string{"tokenize"}, ts.URL)
+
+	for _, tc := range []struct {
+		status            int
+		contentType, body string
+		html, unavailable bool
+		snippet           string
+	}{
+		{http.StatusBadGateway, "text/html", proxyPage, true, true, "502 Bad Gateway Bad Gateway The upstream server is unavailable."},
+		// a maintenance page served with 200
+		{http.StatusOK, "", proxyPage, true, true, "502 Bad Gateway"},
+		{http.StatusForbidden, "text/html; charset=utf-8", "<p>Access denied</p>", true, false, "Access denied"},
+		{http.StatusInternalServerError, "text/plain", "java.lang.OutOfMemoryError " + strings.Repeat("x", 300), false, false, "java.lang.OutOfMemoryError x"},
+	} {
+		status, contentType, body = tc.status, tc.contentType, tc.body
+		err := c.RunText(context.Background(), []byte("x"), &nlp.Document{})
+		var se *ServerError
+		if !errors.As(err, &se) {
+			t.Fatalf("%d: %v", tc.status, err)
+		}
+		if se.StatusCode != tc.status || se.HTML != tc.html || IsUnavailable(err) != tc.unavailable || !strings.HasPrefix(se.Body, tc.snippet) || len([]rune(se.Body)) > maxSnippet+3 {
+			t.Errorf("%d: %+v %v", tc.status, se, IsUnavailable(err))
+		}
+		if !strings.Contains(err.Error(), tc.snippet) {
+			t.Errorf("%v", err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Path tells which client of FailoverClient served a request.
+//
+type Path int
+
+const (
+	// PathPrimary means the primary client, usually the HTTP server, was used.
+	PathPrimary Path = iota
+	// PathFallback means the request fell back to the secondary client, usually Cmd.
+	PathFallback
+)
+
+func (self Path) String() string {
+	if self == PathFallback {
+		return "fallback"
+	}
+	return "primary"
+}
+
+// FailoverClient tries the primary client first, and transparently falls back
+// to the secondary one when the primary fails with an error accepted by ShouldFallback.
+// The typical use is a HttpClient backed up by a local Cmd pipeline.
+//
+type FailoverClient struct {
+	Primary  Client
+	Fallback Client
+
+	// ShouldFallback decides if the primary's error triggers the fallback,
+	// default to IsUnavailable
+	ShouldFallback func(error) bool
+
+	// OnRun, optional, is called after each request with the path taken
+	// and the error returned on that path
+	OnRun func(path Path, err error)
+}
+
+// NewFailoverClient creates an instance of FailoverClient.
+//
+// For example, to fall back to the command line when the server is down:
+// NewFailoverClient(NewHttpClient(annotators), NewCmd(annotators, "/home/user/standford/*"))
+//
+func NewFailoverClient(primary, fallback Client) *FailoverClient {
+	return &FailoverClient{Primary: primary, Fallback: fallback, ShouldFallback: IsUnavailable}
+}
+
+// Run runs on the input file, and gets the NLP data in msg
+//
+func (self *FailoverClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	data, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	return self.RunText(ctx, data, msg)
+}
+
+// RunText runs on the text string, and gets the NLP data in msg
+//
+func (self *FailoverClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	err := self.Primary.RunText(ctx, text, msg)
+	should := self.ShouldFallback
+	if should == nil {
+		should = IsUnavailable
+	}
+	if err == nil || ctx.Err() != nil || self.Fallback == nil || !should(err) {
+		self.observe(PathPrimary, err)
+		return err
+	}
+
+	err = self.Fallback.RunText(ctx, text, msg)
+	self.observe(PathFallback, err)
+	return err
+}
+
+func (self *FailoverClient) observe(path Path, err error) {
+	if self.OnRun != nil {
+		self.OnRun(path, err)
+	}
+}
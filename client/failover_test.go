@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type funcClient func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error
+
+func (self funcClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	return self(ctx, []byte(input), msg)
+}
+
+func (self funcClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	return self(ctx, text, msg)
+}
+
+func TestFailoverClient(t *testing.T) {
+	srv := newFakeServer()
+	defer srv.Close()
+
+	local := funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+		msg.(*nlp.Document).Text = proto.String("local")
+		return nil
+	})
+	c := NewFailoverClient(NewHttpClient(nil, srv.URL), local)
+	var paths []Path
+	c.OnRun = func(path Path, err error) { paths = append(paths, path) }
+
+	ctx := context.Background()
+	pb := &nlp.Document{}
+	if err := c.RunText(ctx, []byte("server"), pb); err != nil { t.Fatal(err) }
+	if pb.GetText() != "server" {
+		t.Errorf("%s", pb.String())
+	}
+
+	srv.setStatus(http.StatusServiceUnavailable)
+	pb = &nlp.Document{}
+	if err := c.RunText(ctx, []byte("server"), pb); err != nil { t.Fatal(err) }
+	if pb.GetText() != "local" {
+		t.Errorf("%s", pb.String())
+	}
+
+	// a bad request is not retried locally
+	srv.setStatus(http.StatusBadRequest)
+	err := c.RunText(ctx, []byte("server"), &nlp.Document{})
+	var se *ServerError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest {
+		t.Errorf("%v", err)
+	}
+
+	srv.Close()
+	if err = c.RunText(ctx, []byte("server"), &nlp.Document{}); err != nil { t.Fatal(err) }
+
+	if len(paths) != 4 || paths[0] != PathPrimary || paths[1] != PathFallback || paths[2] != PathPrimary || paths[3] != PathFallback {
+		t.Errorf("%v", paths)
+	}
+}
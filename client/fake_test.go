@@ -0,0 +1,83 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeServer imitates a CoreNLP server: it answers /ready, and returns
+// a serialized Document holding the posted text for any other path.
+// status, when not 200, is returned for annotation requests instead.
+//
+type fakeServer struct {
+	*httptest.Server
+	status int32
+	calls  int32
+}
+
+func newFakeServer() *fakeServer {
+	f := &fakeServer{status: http.StatusOK}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := int(atomic.LoadInt32(&f.status))
+		if r.URL.Path == "/ready" {
+			w.WriteHeader(status)
+			return
+		}
+		atomic.AddInt32(&f.calls, 1)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		text, _ := ioutil.ReadAll(r.Body)
+		w.Write(serialize(&nlp.Document{Text: proto.String(string(text))}))
+	}))
+	return f
+}
+
+func (self *fakeServer) setStatus(status int) {
+	atomic.StoreInt32(&self.status, int32(status))
+}
+
+func (self *fakeServer) count() int {
+	return int(atomic.LoadInt32(&self.calls))
+}
+
+// serialize encodes doc the way ProtobufAnnotationSerializer does.
+//
+func serialize(doc *nlp.Document) []byte {
+	bs, _ := proto.Marshal(doc)
+	return protowire.AppendBytes(nil, bs)
+}
+
+// fakeJava writes an executable shell script standing in for the java command.
+//
+func fakeJava(dir, script string) (string, error) {
+	java := filepath.Join(dir, "java")
+	return java, ioutil.WriteFile(java, []byte("#!/bin/sh\n"+script), 0755)
+}
+
+// fakeBatchJava imitates StanfordCoreNLP -filelist: it writes template as
+// the output of every listed file, logging each to the standard error.
+//
+const fakeBatchJava = `
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -filelist) list=$2; shift;;
+    --outputDirectory) out=$2; shift;;
+  esac
+  shift
+done
+echo "Loading models" >&2
+while read f; do
+  echo "Processing file $f ... writing to $out/$(basename "$f").ser.gz" >&2
+  cp "$(dirname "$0")/template" "$out/$(basename "$f").ser.gz"
+done < "$list"
+printf "Annotation pipeline timing information" >&2
+`
@@ -0,0 +1,24 @@
+//go:build go1.18
+// +build go1.18
+
+package client
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func FuzzBytesUnmarshal(f *testing.F) {
+	f.Add(serialize(completeWords("John runs .", "NNP VBZ .")))
+	f.Add(serialize(completeWords("He left . She stayed .", "PRP VBD . PRP VBD .")))
+	f.Add([]byte{})
+	f.Add([]byte{0x80})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		doc := &nlp.Document{}
+		if err := BytesUnmarshal(data, doc); err == nil && len(data) == 0 {
+			t.Fatal("empty data decoded")
+		}
+		LenientUnmarshal(data, &nlp.Document{}, []string{"pos", "depparse"})
+	})
+}
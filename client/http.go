@@ -3,18 +3,29 @@ package client
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // HttpClient runs Stanford CoreNLP as a HTTP client
 // The CoreNLP server must be actively running.
 //
+// A HttpClient is safe for concurrent use, so one instance can be shared by
+// all the handlers of a server, provided its fields are not modified once it
+// is in use; derive differently configured clients with With instead.
+//
 // see
 // https://stanfordnlp.github.io/CoreNLP/index.html
 //
@@ -24,8 +35,54 @@ type HttpClient struct {
 
 // server's URL 
 	URL        string
+
+// optional limit on the request rate, see WithRateLimit
+	Limiter    *RateLimiter
+
+// optional cap on the requests in flight, see WithMaxInFlight
+	Queue      *Queue
+
+// salvage partial documents, see WithLenient
+	Lenient    bool
+
+// decodes the responses, the serialized protobuf by default, see WithSerializer
+	Serializer Serializer
+
+// extra properties sent with every request, e.g. "ner.applyFineGrained": "false"
+	Properties map[string]string
+
+// language of the pipeline, e.g. "chinese", sent as pipelineLanguage, see WithLanguage
+	Language   string
+
+// ignore the default properties of the server, sent as resetDefault
+	ResetDefault bool
+
+// credentials of a server started with -username and -password, see WithBasicAuth
+	Username   string
+	Password   string
+
+// the transport, with its pool of connections, http.DefaultTransport if nil, see WithTransport
+	Transport  http.RoundTripper
+
+// optional log of the requests and responses, see WithDebugLog
+	Debug      *DebugLog
+
+// the Content-Type of the texts sent, DefaultContentType if empty, see WithContentType
+	ContentType string
+
+// optional, called with the warnings about the capabilities of the server, see WithWarnings
+	OnWarning  func(warning string)
+
+// set to 1 once the serialized output failed to decode and json worked, so that
+// the following requests go in json; shared by the clients derived on the same URL
+	fallback   *int32
 }
 
+// DefaultContentType is the Content-Type of the texts sent by a HttpClient.
+// The CoreNLP server decodes the text with its charset.
+//
+const DefaultContentType = "text/plain; charset=utf-8"
+
 // NewHttpClient creates an instance of HttpClient
 //
 // annotators: the list of annotators;
@@ -38,10 +95,10 @@ func NewHttpClient(annotators []string, args ...string) *HttpClient {
 		curl = args[0]
 	}
 
-	if curl[len(curl)-2:] != `/` {
+	if !strings.HasSuffix(curl, `/`) {
 		curl += `/`
 	}
-	return &HttpClient{annotators, curl}
+	return &HttpClient{Annotators: annotators, URL: curl, fallback: new(int32)}
 }
 
 // Runs on the input file, and gets the NLP data in msg
@@ -59,30 +116,184 @@ func (self *HttpClient) Run(ctx context.Context, input string, msg protoreflect.
 // RunText runs on the text string, and gets the NLP data in msg
 //
 func (self *HttpClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
-	str := ``
-	if self.Annotators != nil {
-		str = `"annotators":"` + strings.Join(self.Annotators, ",") + `",`
+	serializer := self.Serializer
+	if serializer == nil {
+		serializer = ProtobufSerializer
+	}
+	_, isDoc := msg.(*nlp.Document)
+	if isDoc && serializer.Format() == format.Serialized && self.fallback != nil && atomic.LoadInt32(self.fallback) == 1 {
+		return self.runJSON(ctx, text, msg, serializer)
 	}
-	curl := self.URL + `?properties=`+ url.QueryEscape(`{`+str+`"outputFormat":"serialized","serializer":"edu.stanford.nlp.pipeline.ProtobufAnnotationSerializer"}`)
 
-	req, err := http.NewRequestWithContext(ctx, "POST", curl, bytes.NewReader(text))
+	body, contentType, err := self.post(ctx, text, serializer)
+	requested := serializer
+	serializer = negotiate(serializer, contentType)
+	if self.Lenient && serializer.Format() == format.Serialized {
+		if doc, ok := msg.(*nlp.Document); ok && len(body) > 0 {
+			perr := LenientUnmarshal(body, doc, self.Annotators)
+			if ls, ok := serializer.(*layerSerializer); ok {
+				DropLayers(doc, ls.annotators...)
+			}
+			if perr == nil && err != nil {
+				return &PartialError{Err: err}
+			}
+			return perr
+		}
+	}
 	if err != nil {
 		return err
 	}
 
-	defaultClient := &http.Client{Transport: http.DefaultTransport}
-	res, err := defaultClient.Do(req)
+	err = serializer.Unmarshal(body, msg)
+	if err != nil && isDoc && serializer.Format() == format.Serialized {
+		return self.retryJSON(ctx, text, msg, requested, err)
+	}
+	return err
+}
+
+// retryJSON runs the text again in the json output format, after the
+// serialized response of s failed to decode with perr, as when the server or
+// a proxy ignores the serializer property. If json works, it warns about the
+// server, and the client keeps to json from then on.
+//
+func (self *HttpClient) retryJSON(ctx context.Context, text []byte, msg protoreflect.ProtoMessage, s Serializer, perr error) error {
+	if err := self.runJSON(ctx, text, msg, s); err != nil {
+		return fmt.Errorf("%w; retry in json: %v", perr, err)
+	}
+	if self.fallback != nil && !atomic.CompareAndSwapInt32(self.fallback, 0, 1) {
+		return nil
+	}
+	if self.OnWarning != nil {
+		self.OnWarning(fmt.Sprintf("%s: the serialized output failed to decode (%v), fell back to json; consider WithSerializer(JSONSerializer) for this server", self.URL, perr))
+	}
+	return nil
+}
+
+// runJSON runs the text in the json output format in place of the
+// serialized one of s, dropping the same layers as s.
+//
+func (self *HttpClient) runJSON(ctx context.Context, text []byte, msg protoreflect.ProtoMessage, s Serializer) error {
+	body, _, err := self.post(ctx, text, JSONSerializer)
 	if err != nil {
 		return err
-	} else if res.StatusCode < 200 || res.StatusCode >= 300 {
-		return fmt.Errorf("HTTP status %s\n", res.Status)
 	}
+	if err = JSONSerializer.Unmarshal(body, msg); err != nil {
+		return err
+	}
+	if ls, ok := s.(*layerSerializer); ok {
+		DropLayers(msg.(*nlp.Document), ls.annotators...)
+	}
+	return nil
+}
 
-	body, err := ioutil.ReadAll(res.Body)
-	res.Body.Close()
+// RunTextJSON runs on the text string with the json output format, for servers
+// or proxies that cannot pass the binary protobuf. The result can be converted
+// by its Document method.
+//
+func (self *HttpClient) RunTextJSON(ctx context.Context, text []byte) (*format.JSONDocument, error) {
+	body, _, err := self.post(ctx, text, JSONSerializer)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	return format.ParseJSON(body)
+}
+
+// post sends the text requesting the format of s, and returns the response
+// body, which may be partial if reading it failed, and its Content-Type.
+//
+func (self *HttpClient) post(ctx context.Context, text []byte, s Serializer) (body []byte, contentType string, err error) {
+	if self.Limiter != nil {
+		if err := self.Limiter.Wait(ctx); err != nil {
+			return nil, "", err
+		}
+	}
+	if self.Queue != nil {
+		if err := self.Queue.Acquire(ctx); err != nil {
+			return nil, "", err
+		}
+		defer self.Queue.Release()
+	}
+
+	query := self.query(outputProperties(s))
+	status := 0
+	if self.Debug != nil {
+		self.Debug.request(self.URL, query, text)
+		start := time.Now()
+		defer func() { self.Debug.response(status, len(body), time.Since(start), err) }()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", self.URL+`?`+query, bytes.NewReader(text))
+	if err != nil {
+		return nil, "", err
+	}
+	if self.Username != "" || self.Password != "" {
+		req.SetBasicAuth(self.Username, self.Password)
+	}
+	req.Header.Set("Content-Type", DefaultContentType)
+	if self.ContentType != "" {
+		req.Header.Set("Content-Type", self.ContentType)
+	}
+	if mt, ok := mediaTypes[s.Format()]; ok {
+		req.Header.Set("Accept", mt)
+	}
+
+	transport := self.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	defaultClient := &http.Client{Transport: transport}
+	res, err := defaultClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	status = res.StatusCode
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		page, _ := ioutil.ReadAll(io.LimitReader(res.Body, 4096))
+		res.Body.Close()
+		return nil, "", newServerError(res, page)
+	}
+
+	contentType = res.Header.Get("Content-Type")
+	body, err = ioutil.ReadAll(io.LimitReader(res.Body, format.MaxSize+1))
+	res.Body.Close()
+	if err == nil && int64(len(body)) > format.MaxSize {
+		return nil, "", fmt.Errorf("%s: response over the limit of %d bytes", self.URL, format.MaxSize)
+	}
+	if isHTML(contentType, body) {
+		return nil, "", newServerError(res, body)
+	}
+	return body, contentType, err
+}
+
+// query returns the query string of a request: the properties, with the
+// annotators first, then output, then Properties by key, and the other parameters.
+//
+func (self *HttpClient) query(output string) string {
+	str := ``
+	if self.Annotators != nil {
+		str = `"annotators":"` + strings.Join(self.Annotators, ",") + `",`
+	}
+	str += output
+	keys := make([]string, 0, len(self.Properties))
+	for k := range self.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key, _ := json.Marshal(k)
+		value, _ := json.Marshal(self.Properties[k])
+		if str != `` && !strings.HasSuffix(str, `,`) {
+			str += `,`
+		}
+		str += string(key) + `:` + string(value)
 	}
 
-	return BytesUnmarshal(body, msg)
+	query := `properties=` + url.QueryEscape(`{`+str+`}`)
+	if self.Language != "" {
+		query += `&pipelineLanguage=` + url.QueryEscape(self.Language)
+	}
+	if self.ResetDefault {
+		query += `&resetDefault=true`
+	}
+	return query
 }
@@ -2,9 +2,12 @@ package client
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestCoreNLP(t *testing.T) {
@@ -18,3 +21,44 @@ func TestCoreNLP(t *testing.T) {
 		t.Errorf("%s", pb.String()[:168])
 	}
 }
+
+func TestNewHttpClientURL(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write(serialize(&nlp.Document{Text: proto.String("x")}))
+	}))
+	defer ts.Close()
+
+	// with and without the slash, as given by server.Manager.URL and by hand
+	for _, u := range []string{ts.URL, ts.URL + "/"} {
+		c := NewHttpClient([]string{"tokenize"}, u)
+		if err := c.RunText(context.Background(), []byte("x"), &nlp.Document{}); err != nil { t.Fatal(err) }
+	}
+	if len(paths) != 2 || paths[0] != "/" || paths[1] != "/" {
+		t.Errorf("%q", paths)
+	}
+}
+
+func TestRunTextJSON(t *testing.T) {
+	var properties string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		properties = r.URL.Query().Get("properties")
+		w.Write([]byte(`{"sentences":[{"index":0,"tokens":[{"index":1,"word":"Hi","originalText":"Hi","lemma":"hi","characterOffsetBegin":0,"characterOffsetEnd":2,"pos":"UH"}],
+"basicDependencies":[{"dep":"ROOT","governor":0,"governorGloss":"ROOT","dependent":1,"dependentGloss":"Hi"}]}]}`))
+	}))
+	defer ts.Close()
+
+	c := NewHttpClient([]string{"tokenize", "ssplit", "pos"}, ts.URL)
+	doc, err := c.RunTextJSON(context.Background(), []byte("Hi"))
+	if err != nil { t.Fatal(err) }
+	if properties != `{"annotators":"tokenize,ssplit,pos","outputFormat":"json"}` {
+		t.Errorf("%s", properties)
+	}
+	if len(doc.Sentences) != 1 || doc.Sentences[0].Tokens[0].POS != "UH" || doc.Sentences[0].BasicDependencies[0].Dependent != 1 {
+		t.Errorf("%#v", doc)
+	}
+	if pb, err := doc.Document(); err != nil || pb.GetText() != "Hi" || pb.Sentence[0].Token[0].GetLemma() != "hi" {
+		t.Errorf("%v %v", pb, err)
+	}
+}
@@ -0,0 +1,222 @@
+package client
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// javaLocations are the glob patterns of the common Java installations.
+//
+var javaLocations = defaultJavaLocations()
+
+func defaultJavaLocations() []string {
+	switch runtime.GOOS {
+	case "windows":
+		var patterns []string
+		for _, env := range []string{"ProgramFiles", "ProgramFiles(x86)"} {
+			dir := os.Getenv(env)
+			if dir == "" {
+				continue
+			}
+			for _, vendor := range []string{"Java", "Eclipse Adoptium", "Microsoft", "Zulu", "Amazon Corretto"} {
+				patterns = append(patterns, filepath.Join(dir, vendor, "*", "bin", "java.exe"))
+			}
+		}
+		return patterns
+	case "darwin":
+		return []string{"/Library/Java/JavaVirtualMachines/*/Contents/Home/bin/java", "/opt/homebrew/opt/openjdk/bin/java", "/usr/local/opt/openjdk/bin/java"}
+	}
+	return []string{"/usr/lib/jvm/*/bin/java", "/usr/java/*/bin/java", "/opt/java/*/bin/java", "/usr/local/java/*/bin/java"}
+}
+
+func javaExecutable() string {
+	if runtime.GOOS == "windows" {
+		return "java.exe"
+	}
+	return "java"
+}
+
+// DetectJava locates the Java executable: first $JAVA_HOME/bin/java, then
+// java in PATH, then the common install locations of the platform, the
+// latest version first. It returns *CommandError if none is found.
+//
+func DetectJava() (string, error) {
+	if candidates := javaCandidates(); len(candidates) > 0 {
+		return candidates[0], nil
+	}
+	return "", &CommandError{Java: javaExecutable(), Err: errors.New("not found in JAVA_HOME, PATH or the common locations")}
+}
+
+// javaCandidates returns the Java executables found, in the order of DetectJava.
+//
+func javaCandidates() []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(java string) {
+		if !seen[java] && executable(java) {
+			seen[java] = true
+			candidates = append(candidates, java)
+		}
+	}
+	if home := os.Getenv("JAVA_HOME"); home != "" {
+		add(filepath.Join(home, "bin", javaExecutable()))
+	}
+	if java, err := exec.LookPath(javaExecutable()); err == nil {
+		add(java)
+	}
+	for _, pattern := range javaLocations {
+		matches, _ := filepath.Glob(pattern)
+		sortByInstallVersion(pattern, matches)
+		for _, java := range matches {
+			add(java)
+		}
+	}
+	return candidates
+}
+
+var installVersion = regexp.MustCompile(`\d+(?:\.\d+)*(?:_\d+)?`)
+
+// sortByInstallVersion sorts the Java executables matching pattern by the
+// version in the name of their installation directory, the part matching
+// "*", the latest first; e.g. jdk-17.0.2 before jdk1.8.0_202 before jdk-8.
+// Names without a version come last, in reverse lexical order.
+//
+func sortByInstallVersion(pattern string, matches []string) {
+	star := strings.Index(pattern, "*")
+	if star < 0 {
+		return
+	}
+	below := strings.Count(pattern[star:], string(filepath.Separator))
+	versions := make(map[string][]int, len(matches))
+	for _, java := range matches {
+		dir := java
+		for i := 0; i < below; i++ {
+			dir = filepath.Dir(dir)
+		}
+		var numbers []int
+		for _, part := range strings.FieldsFunc(installVersion.FindString(filepath.Base(dir)), func(r rune) bool { return r == '.' || r == '_' }) {
+			n, _ := strconv.Atoi(part)
+			numbers = append(numbers, n)
+		}
+		// up to Java 8, the versions read 1.x
+		if len(numbers) > 1 && numbers[0] == 1 {
+			numbers = numbers[1:]
+		}
+		versions[java] = numbers
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := versions[matches[i]], versions[matches[j]]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] > b[k]
+			}
+		}
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return matches[i] > matches[j]
+	})
+}
+
+func executable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0111 != 0
+}
+
+// java returns the Java command of the Cmd: the one given, looked up in PATH
+// if it is a bare name, or the detected one by default.
+//
+func (self *Cmd) java() (string, error) {
+	if self.javaCmd == "" || self.javaCmd == "java" {
+		return DetectJava()
+	}
+	java, err := exec.LookPath(self.javaCmd)
+	if err != nil {
+		return "", &CommandError{Java: self.javaCmd, Err: err}
+	}
+	return java, nil
+}
+
+// JoinClassPath joins classpath entries with the separator of the platform,
+// ";" on Windows and ":" elsewhere.
+//
+func JoinClassPath(entries ...string) string {
+	return strings.Join(entries, string(filepath.ListSeparator))
+}
+
+// ExpandClassPath replaces each "dir/*" entry of the classpath with the jar
+// files in dir, as the JVM does, for the places the wildcard is not expanded,
+// e.g. a classpath in a manifest or a shell that globs it away.
+//
+func ExpandClassPath(cp string) (string, error) {
+	var entries []string
+	for _, entry := range splitClassPath(cp) {
+		if filepath.Base(entry) != "*" {
+			entries = append(entries, entry)
+			continue
+		}
+		dir := filepath.Dir(entry)
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return "", err
+		}
+		for _, info := range infos {
+			if !info.IsDir() && strings.EqualFold(filepath.Ext(info.Name()), ".jar") {
+				entries = append(entries, filepath.Join(dir, info.Name()))
+			}
+		}
+	}
+	return JoinClassPath(entries...), nil
+}
+
+// splitClassPath splits the classpath on the separator of the platform.
+// On Windows, a classpath written with ":" as on Unix is accepted too,
+// see splitColons.
+//
+func splitClassPath(cp string) []string {
+	if runtime.GOOS != "windows" || strings.Contains(cp, ";") {
+		return filepath.SplitList(cp)
+	}
+	return splitColons(cp)
+}
+
+// splitColons splits a Windows classpath on ":", keeping the colons of the
+// drive letters, e.g. "C:/nlp/*:extra.jar".
+//
+func splitColons(cp string) []string {
+	var entries []string
+	start := 0
+	for i := 0; i < len(cp); i++ {
+		if cp[i] != ':' {
+			continue
+		}
+		if i == start+1 && i+1 < len(cp) && (cp[i+1] == '\\' || cp[i+1] == '/') {
+			continue
+		}
+		entries = append(entries, cp[start:i])
+		start = i + 1
+	}
+	return append(entries, cp[start:])
+}
+
+// classPath returns cp in the form of the platform: the entries joined by
+// its separator, with its path separator.
+//
+func classPath(cp string) string {
+	entries := splitClassPath(cp)
+	for i, entry := range entries {
+		entries[i] = filepath.FromSlash(entry)
+	}
+	return JoinClassPath(entries...)
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestDetectJava(t *testing.T) {
+	dir, err := ioutil.TempDir("", "java")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	home := filepath.Join(dir, "jdk")
+	os.MkdirAll(filepath.Join(home, "bin"), 0755)
+	java, err := fakeJava(filepath.Join(home, "bin"), "exit 0")
+	if err != nil { t.Fatal(err) }
+
+	saved := javaLocations
+	defer func() { javaLocations = saved }()
+	javaLocations = nil
+
+	t.Setenv("JAVA_HOME", home)
+	t.Setenv("PATH", dir)
+	if found, err := DetectJava(); err != nil || found != java {
+		t.Errorf("%s %v", found, err)
+	}
+
+	t.Setenv("JAVA_HOME", "")
+	if _, err := DetectJava(); err == nil {
+		t.Errorf("java should not be found")
+	}
+	var ce *CommandError
+	if err = NewCmd([]string{"tokenize"}).RunText(context.Background(), []byte("text"), &nlp.Document{}); !errors.As(err, &ce) || !strings.Contains(err.Error(), "JAVA_HOME") {
+		t.Errorf("%v", err)
+	}
+
+	javaLocations = []string{filepath.Join(dir, "*", "bin", "java")}
+	if found, err := DetectJava(); err != nil || found != java {
+		t.Errorf("%s %v", found, err)
+	}
+
+	t.Setenv("PATH", filepath.Join(home, "bin"))
+	if found, err := DetectJava(); err != nil || found != java {
+		t.Errorf("%s %v", found, err)
+	}
+
+	err = NewCmd([]string{"tokenize"}, "*", "Main", filepath.Join(dir, "missing")).RunText(context.Background(), []byte("text"), &nlp.Document{})
+	if !errors.As(err, &ce) || ce.Java != filepath.Join(dir, "missing") {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestJavaCandidatesOrder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "java")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	names := []string{"default-java", "jdk-8", "jdk-17.0.2", "jdk1.8.0_202", "jdk-11"}
+	for _, name := range names {
+		os.MkdirAll(filepath.Join(dir, name, "bin"), 0755)
+		if _, err := fakeJava(filepath.Join(dir, name, "bin"), "exit 0"); err != nil { t.Fatal(err) }
+	}
+	saved := javaLocations
+	defer func() { javaLocations = saved }()
+	javaLocations = []string{filepath.Join(dir, "*", "bin", "java")}
+	t.Setenv("JAVA_HOME", "")
+	t.Setenv("PATH", "")
+
+	var got []string
+	for _, java := range javaCandidates() {
+		got = append(got, filepath.Base(filepath.Dir(filepath.Dir(java))))
+	}
+	if strings.Join(got, " ") != "jdk-17.0.2 jdk-11 jdk1.8.0_202 jdk-8 default-java" {
+		t.Errorf("%v", got)
+	}
+}
+
+func TestExpandClassPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cp")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"b.jar", "a.JAR", "readme.txt"} {
+		ioutil.WriteFile(filepath.Join(dir, name), nil, 0666)
+	}
+
+	cp, err := ExpandClassPath(JoinClassPath("classes", filepath.Join(dir, "*")))
+	if err != nil { t.Fatal(err) }
+	want := JoinClassPath("classes", filepath.Join(dir, "a.JAR"), filepath.Join(dir, "b.jar"))
+	if cp != want {
+		t.Errorf("%s", cp)
+	}
+
+	if _, err = ExpandClassPath(filepath.Join(dir, "missing", "*")); err == nil {
+		t.Errorf("missing directory should fail")
+	}
+}
+
+func TestSplitColons(t *testing.T) {
+	entries := splitColons(`C:\nlp\*:lib/extra.jar:D:/models.jar`)
+	if len(entries) != 3 || entries[0] != `C:\nlp\*` || entries[1] != "lib/extra.jar" || entries[2] != "D:/models.jar" {
+		t.Errorf("%q", entries)
+	}
+	if cp := classPath("a.jar" + string(os.PathListSeparator) + "lib/*"); cp != JoinClassPath("a.jar", filepath.FromSlash("lib/*")) {
+		t.Errorf("%s", cp)
+	}
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// JavaRuntime is a Java installation with its version.
+//
+type JavaRuntime struct {
+	Path string
+
+	// the major version, e.g. 8 for "1.8.0_292" and 17 for "17.0.2"
+	Version int
+
+	// the full version as printed by java -version
+	Release string
+}
+
+// JavaRuntimes returns the Java installations found in JAVA_HOME, PATH and
+// the common locations, in the order DetectJava tries them. Those whose
+// version cannot be read are left out.
+//
+func JavaRuntimes(ctx context.Context) []*JavaRuntime {
+	var runtimes []*JavaRuntime
+	for _, java := range javaCandidates() {
+		if r, err := JavaVersion(ctx, java); err == nil {
+			runtimes = append(runtimes, r)
+		}
+	}
+	return runtimes
+}
+
+var javaVersionLine = regexp.MustCompile(`version "([^"]+)"`)
+
+// JavaVersion runs java -version and returns the version of the runtime.
+//
+func JavaVersion(ctx context.Context, java string) (*JavaRuntime, error) {
+	// java -version prints to the standard error
+	out, err := exec.CommandContext(ctx, java, "-version").CombinedOutput()
+	if err != nil {
+		return nil, &CommandError{Java: java, Err: err}
+	}
+	release, major, err := parseJavaVersion(out)
+	if err != nil {
+		return nil, &CommandError{Java: java, Err: err}
+	}
+	return &JavaRuntime{Path: java, Version: major, Release: release}, nil
+}
+
+// parseJavaVersion reads the output of java -version, e.g.
+// `openjdk version "17.0.2" 2022-01-18` or `java version "1.8.0_292"`.
+//
+func parseJavaVersion(out []byte) (string, int, error) {
+	m := javaVersionLine.FindSubmatch(out)
+	if m == nil {
+		return "", 0, fmt.Errorf("no version in %q", bytes.TrimSpace(out))
+	}
+	release := string(m[1])
+	parts := strings.FieldsFunc(release, func(r rune) bool {
+		return r == '.' || r == '_' || r == '-' || r == '+'
+	})
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", 0, fmt.Errorf("bad version %q", release)
+	}
+	// up to Java 8, the versions read 1.x
+	if major == 1 && len(parts) > 1 {
+		if major, err = strconv.Atoi(parts[1]); err != nil {
+			return "", 0, fmt.Errorf("bad version %q", release)
+		}
+	}
+	return release, major, nil
+}
+
+// requiredJava lists the minimum Java version of the CoreNLP releases,
+// the latest first.
+//
+var requiredJava = []struct {
+	release string
+	java    int
+}{
+	{"3.5.0", 8},
+	{"3.3.0", 7},
+	{"0", 6},
+}
+
+// RequiredJava returns the minimum Java major version of a CoreNLP release,
+// e.g. 8 for "4.5.4". An unknown release requires what the latest does.
+//
+func RequiredJava(release string) int {
+	if release == "" {
+		return requiredJava[0].java
+	}
+	for _, r := range requiredJava {
+		if compareVersions(release, r.release) >= 0 {
+			return r.java
+		}
+	}
+	return requiredJava[0].java
+}
+
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+var corenlpJar = regexp.MustCompile(`^stanford-corenlp-(\d+(?:\.\d+)*)\.jar$`)
+
+// CoreNLPVersion returns the CoreNLP release of the classpath, read from the
+// name of its stanford-corenlp jar, e.g. "4.5.4" for stanford-corenlp-4.5.4.jar,
+// or "" if there is none.
+//
+func CoreNLPVersion(cp string) string {
+	for _, entry := range splitClassPath(cp) {
+		names := []string{filepath.Base(entry)}
+		if filepath.Base(entry) == "*" {
+			jars, _ := filepath.Glob(filepath.Join(filepath.Dir(entry), "*.jar"))
+			names = names[:0]
+			for _, jar := range jars {
+				names = append(names, filepath.Base(jar))
+			}
+		}
+		for _, name := range names {
+			if m := corenlpJar.FindStringSubmatch(name); m != nil {
+				return m[1]
+			}
+		}
+	}
+	return ""
+}
+
+// CheckJava runs the Java command of the Cmd to read its version, and returns
+// *JavaVersionError if it is older than the CoreNLP release of the classpath
+// requires, see RequiredJava, or *CommandError if it cannot be run.
+//
+func (self *Cmd) CheckJava(ctx context.Context) error {
+	java, err := self.java()
+	if err != nil {
+		return err
+	}
+	r, err := JavaVersion(ctx, java)
+	if err != nil {
+		return err
+	}
+	release := CoreNLPVersion(self.ClassPath)
+	if required := RequiredJava(release); r.Version < required {
+		return &JavaVersionError{Java: java, Version: r.Version, CoreNLP: release, Required: required}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseJavaVersion(t *testing.T) {
+	for out, major := range map[string]int{
+		`java version "1.8.0_292"`:                         8,
+		`openjdk version "17.0.2" 2022-01-18`:              17,
+		`openjdk version "11" 2018-09-25`:                  11,
+		`openjdk version "21-ea" 2023-09-19` + "\nOpenJDK": 21,
+	} {
+		_, v, err := parseJavaVersion([]byte(out))
+		if err != nil || v != major {
+			t.Errorf("%s: %d %v", out, v, err)
+		}
+	}
+	if _, _, err := parseJavaVersion([]byte("command not found")); err == nil {
+		t.Errorf("no error")
+	}
+}
+
+func TestCheckJava(t *testing.T) {
+	dir, err := ioutil.TempDir("", "java")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "stanford-corenlp-4.5.4.jar"), nil, 0644)
+	ioutil.WriteFile(filepath.Join(dir, "stanford-corenlp-4.5.4-models.jar"), nil, 0644)
+
+	if v := CoreNLPVersion(filepath.Join(dir, "*")); v != "4.5.4" {
+		t.Errorf("%s", v)
+	}
+	if RequiredJava("4.5.4") != 8 || RequiredJava("3.4.1") != 7 || RequiredJava("") != 8 {
+		t.Errorf("%d %d", RequiredJava("4.5.4"), RequiredJava("3.4.1"))
+	}
+
+	java, err := fakeJava(dir, `echo 'java version "1.7.0_80"' >&2`)
+	if err != nil { t.Fatal(err) }
+	c := NewCmd([]string{"tokenize"}, filepath.Join(dir, "*"), "", java)
+	err = c.CheckJava(context.Background())
+	var ve *JavaVersionError
+	if !errors.As(err, &ve) || ve.Version != 7 || ve.Required != 8 || ve.CoreNLP != "4.5.4" {
+		t.Fatalf("%v", err)
+	}
+
+	java, err = fakeJava(dir, `echo 'openjdk version "17.0.2" 2022-01-18' >&2`)
+	if err != nil { t.Fatal(err) }
+	if err = c.CheckJava(context.Background()); err != nil {
+		t.Errorf("%v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"strconv"
+)
+
+// kBestProperty is the property making the parser keep its k best parses.
+//
+const kBestProperty = "parse.kbest"
+
+// WithKBest makes the "parse" annotator keep its k best constituency parses
+// of every sentence with their scores, in Sentence.KBestParseTrees; see
+// extract.KBestParses.
+//
+func WithKBest(k int) HttpOption {
+	return WithProperties(map[string]string{kBestProperty: strconv.Itoa(k)})
+}
+
+// WithCmdKBest makes the parser of a command keep its k best parses, see WithKBest.
+//
+func WithCmdKBest(k int) CmdOption {
+	return WithCmdProperties(map[string]string{kBestProperty: strconv.Itoa(k)})
+}
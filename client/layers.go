@@ -0,0 +1,152 @@
+package client
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DropLayers clears the output of annotators from doc, e.g. "parse" for the
+// constituency trees, to keep only what a service needs in memory. The
+// annotators are those of CoreNLP: pos, lemma, ner, entitymentions, parse,
+// depparse, sentiment, natlog, openie, kbp, coref, quote and relation;
+// the others are ignored. Tokens and sentences are always kept.
+//
+func DropLayers(doc *nlp.Document, annotators ...string) {
+	drop := make(map[string]bool, len(annotators))
+	for _, a := range annotators {
+		drop[a] = true
+	}
+	if drop["ner"] || drop["entitymentions"] {
+		doc.Mentions = nil
+		doc.HasEntityMentionsAnnotation = nil
+		doc.EntityMentionToCorefMentionMappings = nil
+		doc.CorefMentionToEntityMentionMappings = nil
+	}
+	if drop["coref"] {
+		doc.CorefChain = nil
+		doc.MentionsForCoref = nil
+		doc.HasCorefAnnotation = nil
+		doc.HasCorefMentionAnnotation = nil
+		doc.CorefMentionToEntityMentionMappings = nil
+		doc.EntityMentionToCorefMentionMappings = nil
+	}
+	if drop["quote"] {
+		doc.Quote = nil
+	}
+
+	for _, s := range doc.GetSentence() {
+		dropSentenceLayers(s, drop)
+	}
+}
+
+func dropSentenceLayers(s *nlp.Sentence, drop map[string]bool) {
+	if drop["ner"] || drop["entitymentions"] {
+		s.Mentions = nil
+		s.HasEntityMentionsAnnotation = nil
+	}
+	if drop["parse"] {
+		s.ParseTree = nil
+		s.BinarizedParseTree = nil
+		s.KBestParseTrees = nil
+	}
+	if drop["depparse"] {
+		s.BasicDependencies = nil
+		s.CollapsedDependencies = nil
+		s.CollapsedCCProcessedDependencies = nil
+		s.AlternativeDependencies = nil
+		s.EnhancedDependencies = nil
+		s.EnhancedPlusPlusDependencies = nil
+	}
+	if drop["sentiment"] {
+		s.Sentiment = nil
+		s.AnnotatedParseTree = nil
+	}
+	if drop["openie"] {
+		s.OpenieTriple = nil
+		s.EntailedSentence = nil
+		s.EntailedClause = nil
+		s.HasOpenieTriplesAnnotation = nil
+	}
+	if drop["kbp"] {
+		s.KbpTriple = nil
+		s.HasKBPTriplesAnnotation = nil
+	}
+	if drop["coref"] {
+		s.MentionsForCoref = nil
+		s.HasCorefMentionsAnnotation = nil
+	}
+	if drop["relation"] {
+		s.Entity = nil
+		s.Relation = nil
+		s.HasRelationAnnotations = nil
+	}
+
+	for _, t := range s.Token {
+		if drop["pos"] {
+			t.Pos = nil
+		}
+		if drop["lemma"] {
+			t.Lemma = nil
+		}
+		if drop["ner"] {
+			t.Ner = nil
+			t.CoarseNER = nil
+			t.FineGrainedNER = nil
+			t.NerLabelProbs = nil
+			t.NormalizedNER = nil
+			t.TimexValue = nil
+			t.WikipediaEntity = nil
+		}
+		if drop["ner"] || drop["entitymentions"] {
+			t.EntityMentionIndex = nil
+		}
+		if drop["sentiment"] {
+			t.Sentiment = nil
+		}
+		if drop["natlog"] {
+			t.Operator = nil
+			t.Polarity = nil
+			t.PolarityDir = nil
+		}
+		if drop["coref"] {
+			t.CorefClusterID = nil
+			t.CorefMentionIndex = nil
+		}
+	}
+}
+
+// WithoutLayers returns a Serializer decoding as s, then dropping the layers
+// of annotators from the documents, see DropLayers. Other messages are left
+// as decoded.
+//
+func WithoutLayers(s Serializer, annotators ...string) Serializer {
+	return &layerSerializer{Serializer: s, annotators: annotators}
+}
+
+type layerSerializer struct {
+	Serializer
+	annotators []string
+}
+
+func (self *layerSerializer) Unmarshal(data []byte, msg protoreflect.ProtoMessage) error {
+	if err := self.Serializer.Unmarshal(data, msg); err != nil {
+		return err
+	}
+	if doc, ok := msg.(*nlp.Document); ok {
+		DropLayers(doc, self.annotators...)
+	}
+	return nil
+}
+
+// WithDroppedLayers makes the client drop the layers of annotators from the
+// documents it decodes, see DropLayers. The annotators still run on the server.
+//
+func WithDroppedLayers(annotators ...string) HttpOption {
+	return func(self *HttpClient) {
+		s := self.Serializer
+		if s == nil {
+			s = ProtobufSerializer
+		}
+		self.Serializer = WithoutLayers(s, annotators...)
+	}
+}
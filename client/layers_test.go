@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDroppedLayers(t *testing.T) {
+	doc := &nlp.Document{Text: proto.String("John runs"), Sentence: []*nlp.Sentence{{
+		TokenOffsetBegin:  proto.Uint32(0),
+		TokenOffsetEnd:    proto.Uint32(2),
+		Token:             []*nlp.Token{{Word: proto.String("John"), Pos: proto.String("NNP"), Ner: proto.String("PERSON")}, {Word: proto.String("runs"), Pos: proto.String("VBZ"), Ner: proto.String("O")}},
+		ParseTree:         &nlp.ParseTree{Value: proto.String("ROOT")},
+		BasicDependencies: &nlp.DependencyGraph{Root: []uint32{2}},
+		Mentions:          []*nlp.NERMention{{Ner: proto.String("PERSON"), TokenStartInSentenceInclusive: proto.Uint32(0), TokenEndInSentenceExclusive: proto.Uint32(1)}},
+	}}, CorefChain: []*nlp.CorefChain{{ChainID: proto.Int32(1), Representative: proto.Uint32(0)}}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(serialize(doc))
+	}))
+	defer ts.Close()
+
+	c := NewHttpClient([]string{"tokenize", "ssplit", "pos", "ner", "parse", "depparse", "coref"}, ts.URL).With(WithDroppedLayers("parse", "coref", "ner"))
+	got := &nlp.Document{}
+	if err := c.RunText(context.Background(), []byte("John runs"), got); err != nil { t.Fatal(err) }
+	s := got.Sentence[0]
+	if s.ParseTree != nil || got.CorefChain != nil || s.Mentions != nil || s.Token[0].Ner != nil {
+		t.Errorf("%v", got)
+	}
+	if s.BasicDependencies == nil || s.Token[0].GetPos() != "NNP" || got.GetText() != "John runs" {
+		t.Errorf("%v", got)
+	}
+}
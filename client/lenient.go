@@ -0,0 +1,153 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// MissingLayers lists the annotation layers missing from a sentence.
+//
+type MissingLayers struct {
+	// 0-based sentence index in the document
+	Sentence int
+
+	// the annotators whose output is missing, e.g. "pos", "depparse"
+	Layers []string
+}
+
+// PartialError is returned by LenientUnmarshal when the document is incomplete.
+// The document holds what could be salvaged.
+//
+type PartialError struct {
+	// the error that truncated the data, nil if it decoded completely
+	Err error
+
+	// number of sentences dropped because they could not be decoded
+	Dropped int
+
+	// the sentences kept but missing some layers
+	Missing []MissingLayers
+}
+
+func (self *PartialError) Error() string {
+	msg := fmt.Sprintf("partial document: %d sentences dropped, %d incomplete", self.Dropped, len(self.Missing))
+	if self.Err != nil {
+		msg += ": " + self.Err.Error()
+	}
+	return msg
+}
+
+func (self *PartialError) Unwrap() error {
+	return self.Err
+}
+
+// LenientUnmarshal unmarshals CoreNLP protobuf data like BytesUnmarshal, but
+// salvages what it can from a truncated or partly corrupted document: the
+// fields are decoded one by one, the sentences that fail are dropped, and the
+// decoding stops at the first field that cannot be delimited. Every kept
+// sentence is then checked for the layers of annotators.
+//
+// It returns nil if the document is complete, or *PartialError.
+//
+func LenientUnmarshal(data []byte, doc *nlp.Document, annotators []string) error {
+	proto.Reset(doc)
+	partial := &PartialError{}
+
+	bs, n := protowire.ConsumeBytes(data)
+	if n < 0 {
+		// a truncated length prefix: use what arrived
+		_, m := protowire.ConsumeVarint(data)
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		bs = data[m:]
+		partial.Err = protowire.ParseError(n)
+	}
+
+	sentence := doc.ProtoReflect().Descriptor().Fields().ByName("sentence").Number()
+	for len(bs) > 0 {
+		num, typ, tn := protowire.ConsumeTag(bs)
+		if tn < 0 {
+			partial.Err = protowire.ParseError(tn)
+			break
+		}
+		vn := protowire.ConsumeFieldValue(num, typ, bs[tn:])
+		if vn < 0 {
+			if num == sentence {
+				partial.Dropped++
+			}
+			partial.Err = protowire.ParseError(vn)
+			break
+		}
+		field := bs[:tn+vn]
+		bs = bs[tn+vn:]
+
+		// required fields are checked by the document as a whole, not per field
+		one := &nlp.Document{}
+		if err := (proto.UnmarshalOptions{AllowPartial: true}).Unmarshal(field, one); err != nil {
+			if num == sentence {
+				partial.Dropped++
+			}
+			if partial.Err == nil {
+				partial.Err = err
+			}
+			continue
+		}
+		proto.Merge(doc, one)
+	}
+
+	for i, s := range doc.Sentence {
+		if layers := missingLayers(s, annotators); len(layers) > 0 {
+			partial.Missing = append(partial.Missing, MissingLayers{Sentence: i, Layers: layers})
+		}
+	}
+	if partial.Err == nil && partial.Dropped == 0 && len(partial.Missing) == 0 {
+		return nil
+	}
+	return partial
+}
+
+// missingLayers returns the annotators whose output the sentence lacks.
+// Annotators without a per-sentence layer, e.g. coref, are not checked.
+//
+func missingLayers(s *nlp.Sentence, annotators []string) []string {
+	var missing []string
+	for _, a := range annotators {
+		ok := true
+		switch a {
+		case "tokenize", "ssplit":
+			ok = len(s.Token) > 0
+		case "pos":
+			ok = allTokens(s, func(t *nlp.Token) bool { return t.Pos != nil })
+		case "lemma":
+			ok = allTokens(s, func(t *nlp.Token) bool { return t.Lemma != nil })
+		case "ner":
+			ok = allTokens(s, func(t *nlp.Token) bool { return t.Ner != nil })
+		case "parse":
+			ok = s.ParseTree != nil
+		case "depparse":
+			ok = s.BasicDependencies != nil
+		case "sentiment":
+			ok = s.Sentiment != nil
+		}
+		if !ok {
+			missing = append(missing, a)
+		}
+	}
+	return missing
+}
+
+func allTokens(s *nlp.Sentence, has func(*nlp.Token) bool) bool {
+	if len(s.Token) == 0 {
+		return false
+	}
+	for _, t := range s.Token {
+		if !has(t) {
+			return false
+		}
+	}
+	return true
+}
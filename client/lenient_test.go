@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func lenientDoc() *nlp.Document {
+	tagged := func(words ...string) *nlp.Sentence {
+		s := &nlp.Sentence{BasicDependencies: &nlp.DependencyGraph{}}
+		for _, w := range words {
+			s.Token = append(s.Token, &nlp.Token{Word: proto.String(w), Pos: proto.String("NN")})
+		}
+		return s
+	}
+	doc := &nlp.Document{Text: proto.String("a b. c. d e f.")}
+	doc.Sentence = []*nlp.Sentence{tagged("a", "b"), tagged("c"), tagged("d", "e", "f")}
+	doc.Sentence[1].Token[0].Pos = nil
+	return doc
+}
+
+func TestLenientUnmarshal(t *testing.T) {
+	data := serialize(lenientDoc())
+
+	doc := &nlp.Document{}
+	err := LenientUnmarshal(data, doc, []string{"tokenize", "pos", "depparse"})
+	var perr *PartialError
+	if !errors.As(err, &perr) || perr.Err != nil || perr.Dropped != 0 {
+		t.Fatalf("%v", err)
+	}
+	if len(perr.Missing) != 1 || perr.Missing[0].Sentence != 1 || len(perr.Missing[0].Layers) != 1 || perr.Missing[0].Layers[0] != "pos" {
+		t.Errorf("%v", perr.Missing)
+	}
+	if err = LenientUnmarshal(data, doc, []string{"tokenize"}); err != nil || len(doc.Sentence) != 3 {
+		t.Errorf("%v %d", err, len(doc.Sentence))
+	}
+
+	// the last sentence is cut in the middle
+	err = LenientUnmarshal(data[:len(data)-5], doc, []string{"tokenize"})
+	if !errors.As(err, &perr) || perr.Err == nil || perr.Dropped != 1 {
+		t.Fatalf("%v", err)
+	}
+	if len(doc.Sentence) != 2 || doc.GetText() != "a b. c. d e f." {
+		t.Errorf("%v", doc)
+	}
+
+	if err = LenientUnmarshal([]byte{0xff}, doc, nil); err == nil {
+		t.Errorf("a broken length should fail")
+	}
+}
+
+func TestHttpClientLenient(t *testing.T) {
+	data := serialize(lenientDoc())
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(data[:len(data)-5])
+	}))
+	defer ts.Close()
+
+	doc := &nlp.Document{}
+	c := NewHttpClient([]string{"tokenize", "pos"}, ts.URL)
+	if err := c.RunText(context.Background(), []byte("a b. c. d e f."), doc); err == nil {
+		t.Errorf("strict decoding should fail")
+	}
+
+	err := c.With(WithLenient()).RunText(context.Background(), []byte("a b. c. d e f."), doc)
+	var perr *PartialError
+	if !errors.As(err, &perr) || perr.Dropped != 1 || len(perr.Missing) != 1 || len(doc.Sentence) != 2 {
+		t.Errorf("%v %v", err, doc)
+	}
+}
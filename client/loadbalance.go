@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Strategy decides which backend of LoadBalancedClient gets the next request.
+//
+type Strategy int
+
+const (
+	// RoundRobin cycles through the healthy backends in order.
+	RoundRobin Strategy = iota
+	// LeastLoaded picks the healthy backend with the fewest in-flight requests.
+	LeastLoaded
+)
+
+// ErrNoBackend is returned when LoadBalancedClient has no backend to use.
+//
+var ErrNoBackend = errors.New("no CoreNLP backend available")
+
+// LoadBalancedClient distributes requests over a pool of CoreNLP servers.
+// A backend failing MaxFailures times in a row, with errors for which
+// IsUnavailable is true, is evicted, and is re-admitted once its /ready
+// endpoint answers again; other errors, e.g. of bad properties, tell that the
+// backend is up. A failed request is retried on another backend.
+//
+type LoadBalancedClient struct {
+	// how the next backend is selected
+	Strategy Strategy
+
+	// consecutive failures before a backend is evicted, default 3
+	MaxFailures int
+
+	// minimal interval between health checks of an evicted backend, default 10s
+	HealthInterval time.Duration
+
+	// extra attempts on other backends after a failure, default 1
+	Retries int
+
+	mu       sync.Mutex
+	backends []*backend
+	next     int
+}
+
+type backend struct {
+	client    *HttpClient
+	inflight  int
+	failures  int
+	evicted   bool
+	checking  bool
+	lastCheck time.Time
+}
+
+// NewLoadBalancedClient creates an instance of LoadBalancedClient.
+//
+// annotators: the list of annotators;
+//
+// urls: the addresses of the servers.
+//
+func NewLoadBalancedClient(annotators []string, urls ...string) *LoadBalancedClient {
+	clients := make([]*HttpClient, len(urls))
+	for i, u := range urls {
+		clients[i] = NewHttpClient(annotators, u)
+	}
+	return NewLoadBalancedClientFrom(clients...)
+}
+
+// NewLoadBalancedClientFrom creates an instance of LoadBalancedClient from configured clients.
+//
+func NewLoadBalancedClientFrom(clients ...*HttpClient) *LoadBalancedClient {
+	self := &LoadBalancedClient{MaxFailures: 3, HealthInterval: 10 * time.Second, Retries: 1}
+	for _, c := range clients {
+		self.backends = append(self.backends, &backend{client: c})
+	}
+	return self
+}
+
+// With returns a new LoadBalancedClient over copies of the backends with opts applied.
+// Health state is not carried over.
+//
+// For example, to limit every server to 5 requests per second:
+// NewLoadBalancedClient(annotators, urls...).With(WithRateLimit(5, 5))
+//
+func (self *LoadBalancedClient) With(opts ...HttpOption) *LoadBalancedClient {
+	self.mu.Lock()
+	clients := make([]*HttpClient, len(self.backends))
+	for i, b := range self.backends {
+		clients[i] = b.client.With(opts...)
+	}
+	self.mu.Unlock()
+
+	c := NewLoadBalancedClientFrom(clients...)
+	c.Strategy, c.MaxFailures, c.HealthInterval, c.Retries = self.Strategy, self.MaxFailures, self.HealthInterval, self.Retries
+	return c
+}
+
+// Run runs on the input file, and gets the NLP data in msg
+//
+func (self *LoadBalancedClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	data, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	return self.RunText(ctx, data, msg)
+}
+
+// RunText runs on the text string using one of the backends, and gets the NLP data in msg
+//
+func (self *LoadBalancedClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	tried := make(map[*backend]bool)
+	var err error
+	for i := 0; i <= self.Retries; i++ {
+		b := self.pick(ctx, tried)
+		if b == nil {
+			break
+		}
+		tried[b] = true
+
+		err = b.client.RunText(ctx, text, msg)
+		self.release(b, err, ctx.Err() != nil)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+	if err == nil {
+		err = ErrNoBackend
+	}
+	return err
+}
+
+// Healthy returns the URLs of the backends currently in rotation.
+//
+func (self *LoadBalancedClient) Healthy() []string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	var urls []string
+	for _, b := range self.backends {
+		if !b.evicted {
+			urls = append(urls, b.client.URL)
+		}
+	}
+	return urls
+}
+
+// CheckHealth probes every backend now, evicting those not ready and
+// re-admitting those ready again.
+//
+func (self *LoadBalancedClient) CheckHealth(ctx context.Context) {
+	self.mu.Lock()
+	backends := append([]*backend{}, self.backends...)
+	self.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, b := range backends {
+		wg.Add(1)
+		go func(b *backend) {
+			defer wg.Done()
+			err := ready(ctx, b.client)
+			self.mu.Lock()
+			b.lastCheck = time.Now()
+			if err == nil {
+				b.evicted, b.failures = false, 0
+			} else {
+				b.evicted = true
+			}
+			self.mu.Unlock()
+		}(b)
+	}
+	wg.Wait()
+}
+
+// pick selects a backend not in tried, and counts the request as in flight.
+// If every backend is evicted, the evicted ones are used as a last resort.
+//
+func (self *LoadBalancedClient) pick(ctx context.Context, tried map[*backend]bool) *backend {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	var healthy, evicted []*backend
+	for _, b := range self.backends {
+		if tried[b] {
+			continue
+		}
+		if !b.evicted {
+			healthy = append(healthy, b)
+			continue
+		}
+		evicted = append(evicted, b)
+		if !b.checking && time.Since(b.lastCheck) >= self.HealthInterval {
+			b.checking = true
+			go self.recheck(b)
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = evicted
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	var b *backend
+	switch self.Strategy {
+	case LeastLoaded:
+		b = healthy[0]
+		for _, c := range healthy[1:] {
+			if c.inflight < b.inflight {
+				b = c
+			}
+		}
+	default:
+		b = healthy[self.next%len(healthy)]
+		self.next++
+	}
+	b.inflight++
+	return b
+}
+
+// release records the outcome of a request on b.
+// Requests aborted by the caller do not count against the backend, nor
+// do the errors of a backend that answered.
+//
+func (self *LoadBalancedClient) release(b *backend, err error, aborted bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	b.inflight--
+	switch {
+	case aborted:
+	case !IsUnavailable(err):
+		b.failures = 0
+	default:
+		b.failures++
+		if b.failures >= self.MaxFailures && !b.evicted {
+			b.evicted = true
+			b.lastCheck = time.Now()
+		}
+	}
+}
+
+func (self *LoadBalancedClient) recheck(b *backend) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := ready(ctx, b.client)
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	b.checking = false
+	b.lastCheck = time.Now()
+	if err == nil {
+		b.evicted, b.failures = false, 0
+	}
+}
+
+// ready checks the /ready endpoint of the server, through the transport and
+// with the credentials of c.
+//
+func ready(ctx context.Context, c *HttpClient) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.URL+"ready", nil)
+	if err != nil {
+		return err
+	}
+	if c.Username != "" || c.Password != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errors.New("HTTP status " + res.Status)
+	}
+	return nil
+}
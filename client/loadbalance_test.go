@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestLoadBalancedClient(t *testing.T) {
+	a, b := newFakeServer(), newFakeServer()
+	defer a.Close()
+	defer b.Close()
+
+	lb := NewLoadBalancedClient([]string{"tokenize"}, a.URL, b.URL)
+	lb.MaxFailures = 1
+	lb.HealthInterval = time.Hour
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		pb := &nlp.Document{}
+		if err := lb.RunText(ctx, []byte("hello"), pb); err != nil { t.Fatal(err) }
+		if pb.GetText() != "hello" {
+			t.Errorf("%s", pb.String())
+		}
+	}
+	if a.count() != 2 || b.count() != 2 {
+		t.Errorf("%d %d", a.count(), b.count())
+	}
+
+	// a fails, the request is retried on b and a is evicted
+	a.setStatus(http.StatusServiceUnavailable)
+	if err := lb.RunText(ctx, []byte("hello"), &nlp.Document{}); err != nil { t.Fatal(err) }
+	if healthy := lb.Healthy(); len(healthy) != 1 || healthy[0] != b.URL+"/" {
+		t.Errorf("%v", healthy)
+	}
+	for i := 0; i < 2; i++ {
+		lb.RunText(ctx, []byte("hello"), &nlp.Document{})
+	}
+	if a.count() != 3 || b.count() != 5 {
+		t.Errorf("%d %d", a.count(), b.count())
+	}
+
+	a.setStatus(http.StatusOK)
+	lb.CheckHealth(ctx)
+	if healthy := lb.Healthy(); len(healthy) != 2 {
+		t.Errorf("%v", healthy)
+	}
+}
+
+func TestLoadBalancedLeastLoaded(t *testing.T) {
+	a, b := newFakeServer(), newFakeServer()
+	defer a.Close()
+	defer b.Close()
+
+	lb := NewLoadBalancedClient(nil, a.URL, b.URL)
+	lb.Strategy = LeastLoaded
+	first := lb.pick(context.Background(), nil)
+	second := lb.pick(context.Background(), nil)
+	if first == second {
+		t.Errorf("same backend picked while busy")
+	}
+	lb.release(first, nil, false)
+	if lb.pick(context.Background(), nil) != first {
+		t.Errorf("idle backend not picked")
+	}
+}
+
+func TestLoadBalancedHealth(t *testing.T) {
+	// a server wanting credentials, also on /ready, and failing on the
+	// properties of every annotation request
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "u" || password != "p" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/ready" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	transport := &countingTransport{}
+	lb := NewLoadBalancedClientFrom(NewHttpClient(nil, ts.URL).With(WithTransport(transport), WithBasicAuth("u", "p")))
+	lb.MaxFailures = 1
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := lb.RunText(ctx, []byte("hello"), &nlp.Document{}); err == nil {
+			t.Fatal("the server should fail")
+		}
+	}
+	if healthy := lb.Healthy(); len(healthy) != 1 {
+		t.Errorf("%v", healthy)
+	}
+
+	lb.CheckHealth(ctx)
+	if healthy := lb.Healthy(); len(healthy) != 1 || transport.n != 4 {
+		t.Errorf("%v %d", healthy, transport.n)
+	}
+}
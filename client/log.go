@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// logWriter returns the writer receiving the standard error of the Java
+// process: Log, OnLog and logs combined, or nil if there is none.
+//
+func (self *Cmd) logWriter(logs ...io.Writer) io.Writer {
+	var ws []io.Writer
+	if self.Log != nil {
+		ws = append(ws, &quietWriter{w: self.Log})
+	}
+	if self.OnLog != nil {
+		ws = append(ws, NewLineWriter(self.OnLog))
+	}
+	ws = append(ws, logs...)
+	switch len(ws) {
+	case 0:
+		return nil
+	case 1:
+		return ws[0]
+	}
+	return &multiWriter{ws}
+}
+
+// multiWriter is io.MultiWriter that can be flushed.
+//
+type multiWriter struct {
+	ws []io.Writer
+}
+
+func (self *multiWriter) Write(p []byte) (int, error) {
+	for _, w := range self.ws {
+		w.Write(p)
+	}
+	return len(p), nil
+}
+
+func (self *multiWriter) Flush() {
+	for _, w := range self.ws {
+		flushLines(w)
+	}
+}
+
+// quietWriter ignores the errors of w, so a failing log does not fail the process.
+//
+type quietWriter struct {
+	w io.Writer
+}
+
+func (self *quietWriter) Write(p []byte) (int, error) {
+	self.w.Write(p)
+	return len(p), nil
+}
+
+// LineWriter is an io.Writer calling a function with every complete line
+// written to it, without the line ending. It is safe for concurrent use.
+//
+type LineWriter struct {
+	fn  func(line string)
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewLineWriter creates an instance of LineWriter calling fn.
+//
+// For example, to print the progress of CoreNLP as it runs:
+// cmd.Log = NewLineWriter(func(line string) { log.Print(line) })
+//
+func NewLineWriter(fn func(line string)) *LineWriter {
+	return &LineWriter{fn: fn}
+}
+
+// Write calls the function for the lines completed by p.
+//
+func (self *LineWriter) Write(p []byte) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.buf.Write(p)
+	for {
+		i := bytes.IndexByte(self.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(self.buf.Next(i + 1))
+		self.fn(strings.TrimRight(line, "\r\n"))
+	}
+}
+
+// Flush calls the function with the last line if it has no line ending.
+//
+func (self *LineWriter) Flush() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.buf.Len() > 0 {
+		line := self.buf.String()
+		self.buf.Reset()
+		self.fn(strings.TrimRight(line, "\r"))
+	}
+}
+
+func flushLines(w io.Writer) {
+	if f, ok := w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+}
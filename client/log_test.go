@@ -0,0 +1,68 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := NewLineWriter(func(line string) { lines = append(lines, line) })
+	w.Write([]byte("one\r\ntw"))
+	w.Write([]byte("o\nthr"))
+	if len(lines) != 2 || lines[0] != "one" || lines[1] != "two" {
+		t.Errorf("%q", lines)
+	}
+	w.Flush()
+	w.Flush()
+	if len(lines) != 3 || lines[2] != "thr" {
+		t.Errorf("%q", lines)
+	}
+}
+
+func TestCmdLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	java, err := fakeJava(dir, fakeBatchJava)
+	if err != nil { t.Fatal(err) }
+	template := serialize(&nlp.Document{Text: proto.String("annotated")})
+	if err = ioutil.WriteFile(filepath.Join(dir, "template"), template, 0666); err != nil { t.Fatal(err) }
+	paths := []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt")}
+	for _, p := range paths {
+		if err = ioutil.WriteFile(p, []byte("text"), 0666); err != nil { t.Fatal(err) }
+	}
+
+	var log bytes.Buffer
+	var lines []string
+	var progress []int
+	cmd := NewCmd([]string{"tokenize"}, "", "edu.stanford.nlp.pipeline.StanfordCoreNLP", java)
+	cmd.Log = &log
+	cmd.OnLog = func(line string) { lines = append(lines, line) }
+	cmd.OnProgress = func(done, total int) {
+		if total != 2 {
+			t.Errorf("total %d", total)
+		}
+		progress = append(progress, done)
+	}
+	if _, err = cmd.RunFiles(context.Background(), paths); err != nil { t.Fatal(err) }
+
+	if len(lines) != 4 || lines[0] != "Loading models" || !strings.HasPrefix(lines[1], "Processing file ") || lines[3] != "Annotation pipeline timing information" {
+		t.Errorf("%q", lines)
+	}
+	if !strings.HasPrefix(log.String(), "Loading models\n") {
+		t.Errorf("%q", log.String())
+	}
+	if len(progress) != 2 || progress[1] != 2 {
+		t.Errorf("%v", progress)
+	}
+}
@@ -0,0 +1,48 @@
+package client
+
+// MentionDetection is how the coref annotators detect the mentions, the
+// property coref.md.type.
+//
+type MentionDetection string
+
+const (
+	// RuleMentions detects the mentions with rules over the constituency parse.
+	RuleMentions MentionDetection = "RULE"
+	// DependencyMentions detects the mentions over the dependency parse,
+	// which is faster and needs no constituency parse.
+	DependencyMentions MentionDetection = "DEPENDENCY"
+	// HybridMentions combines the rules with a statistical model, the
+	// default of the statistical and neural coref.
+	HybridMentions MentionDetection = "HYBRID"
+)
+
+// mentionDetectionProperty is the property selecting the mention detection.
+//
+const mentionDetectionProperty = "coref.md.type"
+
+// WithMentionDetection sets how the mentions of "coref" and "coref.mention" are detected.
+//
+func WithMentionDetection(md MentionDetection) HttpOption {
+	return WithProperties(map[string]string{mentionDetectionProperty: string(md)})
+}
+
+// WithCmdMentionDetection sets the mention detection of a command, see WithMentionDetection.
+//
+func WithCmdMentionDetection(md MentionDetection) CmdOption {
+	return WithCmdProperties(map[string]string{mentionDetectionProperty: string(md)})
+}
+
+// MentionAnnotators returns the annotators detecting the coref mentions
+// with md without resolving them, i.e. "coref.mention" instead of "coref",
+// with the parse md needs; see extract.ExtractCorefMentions.
+//
+// For example:
+// NewHttpClient(MentionAnnotators(DependencyMentions)).With(WithMentionDetection(DependencyMentions))
+//
+func MentionAnnotators(md MentionDetection) []string {
+	parse := "parse"
+	if md == DependencyMentions {
+		parse = "depparse"
+	}
+	return []string{"tokenize", "ssplit", "pos", "lemma", "ner", parse, "coref.mention"}
+}
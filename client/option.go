@@ -0,0 +1,292 @@
+package client
+
+import (
+	"net/http"
+)
+
+// HttpOption configures a HttpClient, see HttpClient.With.
+//
+type HttpOption func(*HttpClient)
+
+// WithRateLimit limits the client to rps requests per second, with bursts of burst.
+// Each client the option is applied to gets its own limit, so applying it to
+// the backends of a LoadBalancedClient limits every server separately.
+//
+func WithRateLimit(rps float64, burst int) HttpOption {
+	return func(self *HttpClient) {
+		self.Limiter = NewRateLimiter(rps, burst)
+	}
+}
+
+// WithLimiter shares limiter among all the clients the option is applied to.
+//
+func WithLimiter(limiter *RateLimiter) HttpOption {
+	return func(self *HttpClient) {
+		self.Limiter = limiter
+	}
+}
+
+// WithLenient makes RunText salvage the intact sentences of a partial document,
+// e.g. when an annotator crashed mid-document or the connection dropped, and
+// return a *PartialError telling what is missing. It applies to *nlp.Document only.
+//
+func WithLenient() HttpOption {
+	return func(self *HttpClient) {
+		self.Lenient = true
+	}
+}
+
+// WithSerializer makes the client request the format of s, which decodes the
+// responses, e.g. JSONSerializer through a proxy mangling binary bodies.
+//
+func WithSerializer(s Serializer) HttpOption {
+	return func(self *HttpClient) {
+		self.Serializer = s
+	}
+}
+
+// WithAnnotators replaces the annotators, e.g. in a client cloned for another endpoint.
+//
+func WithAnnotators(annotators ...string) HttpOption {
+	return func(self *HttpClient) {
+		self.Annotators = copyStrings(annotators)
+	}
+}
+
+// WithTransport sets the transport of the requests, e.g. a http.Transport
+// with a larger pool of idle connections per host. Copies of the client
+// share it, and so its connections.
+//
+func WithTransport(transport http.RoundTripper) HttpOption {
+	return func(self *HttpClient) {
+		self.Transport = transport
+	}
+}
+
+// WithContentType sets the Content-Type of the texts sent, e.g.
+// "text/plain; charset=iso-8859-1" for texts in Latin-1.
+//
+func WithContentType(contentType string) HttpOption {
+	return func(self *HttpClient) {
+		self.ContentType = contentType
+	}
+}
+
+// WithWarnings calls fn with the warnings about the capabilities of the
+// server, e.g. when its serialized output cannot be decoded and the client
+// falls back to json.
+//
+func WithWarnings(fn func(warning string)) HttpOption {
+	return func(self *HttpClient) {
+		self.OnWarning = fn
+	}
+}
+
+// With returns a copy of the client with opts applied.
+// The original client is left unchanged: the copy gets its own Annotators
+// and Properties, so it can be configured while the original is in use. It
+// shares the Transport, so the connection pool, and the Limiter and Queue of
+// the original, which bound the load of all the copies together, e.g. for a
+// service exposing several NLP endpoints over one CoreNLP server.
+//
+// For example, to send at most 10 requests per second:
+// NewHttpClient(annotators).With(WithRateLimit(10, 1))
+//
+func (self *HttpClient) With(opts ...HttpOption) *HttpClient {
+	c := *self
+	c.Annotators = copyStrings(self.Annotators)
+	c.Properties = copyProperties(self.Properties)
+	for _, opt := range opts {
+		opt(&c)
+	}
+	// the json fallback is that of the server
+	if c.URL != self.URL {
+		c.fallback = new(int32)
+	}
+	return &c
+}
+
+// CmdOption configures a Cmd, see Cmd.With.
+//
+type CmdOption func(*Cmd)
+
+// WithMemory sets the maximal heap size of the JVM, e.g. "4g" for -mx4g.
+//
+func WithMemory(size string) CmdOption {
+	return func(self *Cmd) {
+		self.Memory = size
+	}
+}
+
+// WithGC adds garbage collector flags, e.g. "-XX:+UseG1GC".
+//
+func WithGC(flags ...string) CmdOption {
+	return WithJVMArgs(flags...)
+}
+
+// WithJVMArgs adds arguments for the JVM, placed before the classpath,
+// e.g. "-Dfile.encoding=UTF-8".
+//
+func WithJVMArgs(args ...string) CmdOption {
+	return func(self *Cmd) {
+		self.Args = append(append([]string{}, self.Args...), args...)
+	}
+}
+
+// WithClassPath sets the Java classpath, e.g. "/home/user/standford/*".
+//
+func WithClassPath(cp string) CmdOption {
+	return func(self *Cmd) {
+		self.ClassPath = cp
+	}
+}
+
+// WithThreads annotates the files of RunFiles with n threads inside the JVM, via -threads.
+//
+func WithThreads(n int) CmdOption {
+	return func(self *Cmd) {
+		self.Threads = n
+	}
+}
+
+// WithJava sets the Java command, e.g. "/usr/lib/jvm/java-17/bin/java".
+//
+func WithJava(java string) CmdOption {
+	return func(self *Cmd) {
+		self.javaCmd = java
+	}
+}
+
+// WithOutputFormat sets the output format of the Java process, e.g. format.JSON.
+//
+func WithOutputFormat(f string) CmdOption {
+	return func(self *Cmd) {
+		self.OutputFormat = f
+	}
+}
+
+// WithOutputSerializer makes the Java process write the format of s, which
+// decodes it, e.g. a Serializer of one's own reading format.JSON.
+//
+func WithOutputSerializer(s Serializer) CmdOption {
+	return func(self *Cmd) {
+		self.Serializer = s
+	}
+}
+
+// WithStdin streams the text through the standard input of the Java process.
+//
+func WithStdin() CmdOption {
+	return func(self *Cmd) {
+		self.Stdin = true
+	}
+}
+
+// WithEnv adds environment variables of the Java process, e.g. "JAVA_TOOL_OPTIONS=-Xss4m".
+//
+func WithEnv(vars ...string) CmdOption {
+	return func(self *Cmd) {
+		self.Env = append(append([]string{}, self.Env...), vars...)
+	}
+}
+
+// WithDir sets the working directory of the Java process.
+//
+func WithDir(dir string) CmdOption {
+	return func(self *Cmd) {
+		self.Dir = dir
+	}
+}
+
+// WithOutputDir keeps the input and output files of Run in dir.
+//
+func WithOutputDir(dir string) CmdOption {
+	return func(self *Cmd) {
+		self.OutputDir = dir
+	}
+}
+
+// WithTempRoot writes the temporary files under root, see TempRoot.
+//
+func WithTempRoot(root *TempRoot) CmdOption {
+	return func(self *Cmd) {
+		self.TempRoot = root
+	}
+}
+
+// With returns a copy of the command with opts applied.
+// The original command is left unchanged, and the copy is not started
+// in the persistent mode even if the original is. The copy gets its own
+// Annotators, Args, Properties and Env, so it can be configured while the original is in use.
+//
+// For example:
+// NewCmd(annotators, "/home/user/standford/*").With(WithMemory("4g"), WithGC("-XX:+UseG1GC"))
+//
+func (self *Cmd) With(opts ...CmdOption) *Cmd {
+	c := &Cmd{Annotators: copyStrings(self.Annotators), ClassPath: self.ClassPath, Class: self.Class, javaCmd: self.javaCmd, Args: copyStrings(self.Args), Properties: copyProperties(self.Properties), Memory: self.Memory, Threads: self.Threads, Stdin: self.Stdin, Log: self.Log, OnLog: self.OnLog, OnProgress: self.OnProgress, OutputFormat: self.OutputFormat, Serializer: self.Serializer, Env: copyStrings(self.Env), Dir: self.Dir, OutputDir: self.OutputDir, TempRoot: self.TempRoot}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithCmdAnnotators replaces the annotators of a command.
+//
+func WithCmdAnnotators(annotators ...string) CmdOption {
+	return func(self *Cmd) {
+		self.Annotators = copyStrings(annotators)
+	}
+}
+
+// WithCmdProperties adds CoreNLP properties passed to the pipeline, e.g.
+// "parse.maxlen": "80", replacing those of the same keys.
+//
+func WithCmdProperties(props map[string]string) CmdOption {
+	return func(self *Cmd) {
+		merged := copyProperties(self.Properties)
+		if merged == nil {
+			merged = make(map[string]string, len(props))
+		}
+		for k, v := range props {
+			merged[k] = v
+		}
+		self.Properties = merged
+	}
+}
+
+// Clone returns a command derived with opts, as With, which shares the
+// persistent process of the original: while the original is started, the
+// clone sends its texts to the same CoreNLP server with its own annotators,
+// loading the models once for all the clones. Closing the clone does not stop
+// the server; once the original is closed, the clone starts its own processes.
+//
+// For example:
+// ner := cmd.Clone(WithCmdAnnotators("tokenize", "ssplit", "ner"))
+//
+func (self *Cmd) Clone(opts ...CmdOption) *Cmd {
+	c := self.With(opts...)
+	c.parent = self
+	return c
+}
+
+// copyStrings returns a copy of s, nil if s is nil.
+//
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
+
+// copyProperties returns a copy of m, nil if m is nil.
+//
+func copyProperties(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
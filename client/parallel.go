@@ -0,0 +1,256 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ParallelClient annotates the sentences of a document in parallel: Splitter,
+// running cheap annotators such as tokenize and ssplit, finds the sentences,
+// and Client annotates each of them on its own. A sentence that fails does
+// not fail the document: it keeps the annotation of Splitter, and the failure
+// is recorded in SentenceErrors.
+//
+// Since every sentence is a separate document for Client, coreference and
+// other cross-sentence annotations are resolved within a sentence only.
+//
+type ParallelClient struct {
+	Splitter Client
+	Client   Client
+
+	// number of sentences annotated at the same time, default runtime.NumCPU()
+	Workers int
+}
+
+// NewParallelClient creates an instance of ParallelClient.
+//
+// For example:
+// NewParallelClient(NewHttpClient([]string{"tokenize","ssplit"}), NewHttpClient([]string{"tokenize","ssplit","pos","parse"}))
+//
+func NewParallelClient(splitter, client Client) *ParallelClient {
+	return &ParallelClient{Splitter: splitter, Client: client, Workers: runtime.NumCPU()}
+}
+
+// SentenceError records the failure of one sentence.
+//
+type SentenceError struct {
+	// 0-based sentence index in the merged document
+	Sentence int
+
+	// character offsets of the sentence
+	Begin uint32
+	End   uint32
+
+	Err error
+}
+
+func (self *SentenceError) Error() string {
+	return fmt.Sprintf("sentence %d: %s", self.Sentence, self.Err.Error())
+}
+
+func (self *SentenceError) Unwrap() error {
+	return self.Err
+}
+
+// SentenceErrors is returned by ParallelClient when some sentences failed.
+// The document is still filled in.
+//
+type SentenceErrors []*SentenceError
+
+func (self SentenceErrors) Error() string {
+	if len(self) == 1 {
+		return self[0].Error()
+	}
+	return fmt.Sprintf("%d sentences failed, first %s", len(self), self[0].Error())
+}
+
+// ParallelResult is the merged document and the per-sentence failures.
+//
+type ParallelResult struct {
+	Document *nlp.Document
+	Errors   []*SentenceError
+}
+
+// Run runs on the input file, and gets the NLP data in msg, see RunText.
+//
+func (self *ParallelClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	data, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	return self.RunText(ctx, data, msg)
+}
+
+// RunText annotates the text, and gets the merged document in msg, which must
+// be *nlp.Document. It returns SentenceErrors if some sentences failed.
+//
+func (self *ParallelClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	doc, ok := msg.(*nlp.Document)
+	if !ok {
+		return fmt.Errorf("parallel mode needs *nlp.Document, got %T", msg)
+	}
+	result, err := self.RunParallel(ctx, text)
+	if err != nil {
+		return err
+	}
+	proto.Reset(doc)
+	proto.Merge(doc, result.Document)
+	if len(result.Errors) > 0 {
+		return SentenceErrors(result.Errors)
+	}
+	return nil
+}
+
+// RunParallel annotates the text, and returns the merged document with the
+// per-sentence failures. It fails as a whole only if the splitting fails or
+// ctx is done.
+//
+func (self *ParallelClient) RunParallel(ctx context.Context, text []byte) (*ParallelResult, error) {
+	split := &nlp.Document{}
+	if err := self.Splitter.RunText(ctx, text, split); err != nil {
+		return nil, err
+	}
+	units := utf16.Encode([]rune(split.GetText()))
+
+	n := len(split.Sentence)
+	docs := make([]*nlp.Document, n)
+	errs := make([]error, n)
+	workers := self.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, s := range split.Sentence {
+		begin, end := s.GetCharacterOffsetBegin(), s.GetCharacterOffsetEnd()
+		if int(end) > len(units) || begin > end {
+			errs[i] = fmt.Errorf("offsets %d-%d out of the text", begin, end)
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, sentence string) {
+			defer func() { <-sem; wg.Done() }()
+			doc := &nlp.Document{}
+			if err := self.Client.RunText(ctx, []byte(sentence), doc); err != nil {
+				errs[i] = err
+				return
+			}
+			if len(doc.Sentence) == 0 {
+				errs[i] = errors.New("no sentence annotated")
+				return
+			}
+			docs[i] = doc
+		}(i, string(utf16.Decode(units[begin:end])))
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &ParallelResult{Document: &nlp.Document{DocID: split.DocID, DocDate: split.DocDate, Text: split.Text}}
+	m := &merger{doc: result.Document, units: units}
+	for i, s := range split.Sentence {
+		if errs[i] != nil {
+			result.Errors = append(result.Errors, &SentenceError{Sentence: len(result.Document.Sentence), Begin: s.GetCharacterOffsetBegin(), End: s.GetCharacterOffsetEnd(), Err: errs[i]})
+			m.add(&nlp.Document{Sentence: []*nlp.Sentence{s}}, 0)
+			continue
+		}
+		m.add(docs[i], s.GetCharacterOffsetBegin())
+	}
+	return result, nil
+}
+
+// merger appends annotated documents to doc, shifting their offsets and indexes.
+//
+type merger struct {
+	doc    *nlp.Document
+	units  []uint16
+	tokens uint32
+	// the largest coreference mention or chain ID so far; CoreNLP numbers a
+	// chain by one of its mentions, so both are shifted by it
+	ids int32
+}
+
+// add appends the sentences of part, whose text starts at the character offset
+// begin of the document; begin is 0 if part already has document offsets.
+//
+func (self *merger) add(part *nlp.Document, begin uint32) {
+	codepoints := uint32(len([]rune(string(utf16.Decode(self.units[:begin])))))
+	first := uint32(len(self.doc.Sentence))
+	mentions := uint32(len(self.doc.Mentions))
+
+	for _, s := range part.Sentence {
+		index := uint32(len(self.doc.Sentence))
+		s.SentenceIndex = proto.Uint32(index)
+		s.TokenOffsetBegin = proto.Uint32(self.tokens)
+		if begin > 0 {
+			s.CharacterOffsetBegin = proto.Uint32(s.GetCharacterOffsetBegin() + begin)
+			s.CharacterOffsetEnd = proto.Uint32(s.GetCharacterOffsetEnd() + begin)
+		}
+		for _, t := range s.Token {
+			if begin > 0 {
+				t.BeginChar = proto.Uint32(t.GetBeginChar() + begin)
+				t.EndChar = proto.Uint32(t.GetEndChar() + begin)
+				if t.CodepointOffsetBegin != nil {
+					t.CodepointOffsetBegin = proto.Uint32(t.GetCodepointOffsetBegin() + codepoints)
+					t.CodepointOffsetEnd = proto.Uint32(t.GetCodepointOffsetEnd() + codepoints)
+				}
+			}
+			t.TokenBeginIndex = proto.Uint32(self.tokens)
+			t.TokenEndIndex = proto.Uint32(self.tokens + 1)
+			self.tokens++
+		}
+		s.TokenOffsetEnd = proto.Uint32(self.tokens)
+		for _, g := range []*nlp.DependencyGraph{s.BasicDependencies, s.CollapsedDependencies, s.CollapsedCCProcessedDependencies,
+			s.AlternativeDependencies, s.EnhancedDependencies, s.EnhancedPlusPlusDependencies} {
+			for _, node := range g.GetNode() {
+				node.SentenceIndex = proto.Uint32(index)
+			}
+		}
+		for _, m := range s.Mentions {
+			shiftMention(m, first, mentions)
+		}
+		self.doc.Sentence = append(self.doc.Sentence, s)
+	}
+	for _, m := range part.Mentions {
+		shiftMention(m, first, mentions)
+		self.doc.Mentions = append(self.doc.Mentions, m)
+	}
+
+	last := self.ids
+	for _, chain := range part.CorefChain {
+		chain.ChainID = proto.Int32(chain.GetChainID() + self.ids)
+		if chain.GetChainID() > last {
+			last = chain.GetChainID()
+		}
+		for _, m := range chain.Mention {
+			m.SentenceIndex = proto.Uint32(m.GetSentenceIndex() + first)
+			m.MentionID = proto.Int32(m.GetMentionID() + self.ids)
+			if m.GetMentionID() > last {
+				last = m.GetMentionID()
+			}
+		}
+		self.doc.CorefChain = append(self.doc.CorefChain, chain)
+	}
+	self.ids = last
+}
+
+func shiftMention(m *nlp.NERMention, sentences, mentions uint32) {
+	m.SentenceIndex = proto.Uint32(m.GetSentenceIndex() + sentences)
+	if m.EntityMentionIndex != nil {
+		m.EntityMentionIndex = proto.Uint32(m.GetEntityMentionIndex() + mentions)
+	}
+	if m.CanonicalEntityMentionIndex != nil {
+		m.CanonicalEntityMentionIndex = proto.Uint32(m.GetCanonicalEntityMentionIndex() + mentions)
+	}
+}
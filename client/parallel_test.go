@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// splitWords annotates ASCII text: tokens are separated by spaces, and a token
+// ending with "." ends a sentence. pos, if not empty, tags every token.
+//
+func splitWords(text, pos string) *nlp.Document {
+	doc := &nlp.Document{Text: proto.String(text)}
+	var s *nlp.Sentence
+	offset, index := 0, uint32(0)
+	for _, w := range strings.Split(text, " ") {
+		if w == "" {
+			offset++
+			continue
+		}
+		if s == nil {
+			s = &nlp.Sentence{SentenceIndex: proto.Uint32(uint32(len(doc.Sentence))), CharacterOffsetBegin: proto.Uint32(uint32(offset))}
+			doc.Sentence = append(doc.Sentence, s)
+		}
+		t := &nlp.Token{Word: proto.String(w), BeginChar: proto.Uint32(uint32(offset)), EndChar: proto.Uint32(uint32(offset + len(w))), TokenBeginIndex: proto.Uint32(index)}
+		if pos != "" {
+			t.Pos = proto.String(pos)
+		}
+		s.Token = append(s.Token, t)
+		s.CharacterOffsetEnd = t.EndChar
+		offset += len(w) + 1
+		index++
+		if strings.HasSuffix(w, ".") {
+			s = nil
+		}
+	}
+	return doc
+}
+
+func TestParallelClient(t *testing.T) {
+	splitter := funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+		proto.Merge(msg, splitWords(string(text), ""))
+		return nil
+	})
+	annotator := funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+		if strings.Contains(string(text), "bad") {
+			return errors.New("annotator crashed")
+		}
+		doc := splitWords(string(text), "NN")
+		doc.Sentence[0].BasicDependencies = &nlp.DependencyGraph{Node: []*nlp.DependencyGraph_Node{{SentenceIndex: proto.Uint32(0), Index: proto.Uint32(1)}}}
+		doc.Mentions = []*nlp.NERMention{{SentenceIndex: proto.Uint32(0), EntityMentionIndex: proto.Uint32(0)}}
+		proto.Merge(msg, doc)
+		return nil
+	})
+
+	text := "One two. A bad one.  Three four five."
+	c := NewParallelClient(splitter, annotator)
+	c.Workers = 2
+	doc := &nlp.Document{}
+	err := c.RunText(context.Background(), []byte(text), doc)
+
+	var errs SentenceErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Sentence != 1 || errs[0].Begin != 9 || errs[0].End != 19 {
+		t.Fatalf("%v", err)
+	}
+	if doc.GetText() != text || len(doc.Sentence) != 3 {
+		t.Fatalf("%v", doc)
+	}
+	last := doc.Sentence[2]
+	if last.GetSentenceIndex() != 2 || last.GetTokenOffsetBegin() != 5 || last.GetCharacterOffsetBegin() != 21 {
+		t.Errorf("%v", last)
+	}
+	if tk := last.Token[1]; tk.GetBeginChar() != 27 || tk.GetTokenBeginIndex() != 6 || tk.GetPos() != "NN" {
+		t.Errorf("%v", tk)
+	}
+	if last.BasicDependencies.Node[0].GetSentenceIndex() != 2 {
+		t.Errorf("%v", last.BasicDependencies)
+	}
+	// the failed sentence keeps the splitter tokens
+	if failed := doc.Sentence[1]; len(failed.Token) != 3 || failed.Token[0].Pos != nil {
+		t.Errorf("%v", failed)
+	}
+	if len(doc.Mentions) != 2 || doc.Mentions[1].GetSentenceIndex() != 2 || doc.Mentions[1].GetEntityMentionIndex() != 1 {
+		t.Errorf("%v", doc.Mentions)
+	}
+
+	result, err := c.RunParallel(context.Background(), []byte("All fine."))
+	if err != nil || len(result.Errors) != 0 || len(result.Document.Sentence) != 1 {
+		t.Errorf("%v %v", result, err)
+	}
+}
+
+func TestMergerCoref(t *testing.T) {
+	chain := func(id int32, mentions ...int32) *nlp.CorefChain {
+		c := &nlp.CorefChain{ChainID: proto.Int32(id), Representative: proto.Uint32(0)}
+		for _, m := range mentions {
+			c.Mention = append(c.Mention, &nlp.CorefChain_CorefMention{MentionID: proto.Int32(m), SentenceIndex: proto.Uint32(0)})
+		}
+		return c
+	}
+
+	m := &merger{doc: &nlp.Document{}}
+	// the mention IDs of a chain may exceed the chain IDs
+	first := splitWords("He said she left.", "")
+	first.CorefChain = []*nlp.CorefChain{chain(1, 1, 7), chain(2, 2)}
+	second := splitWords("It rains.", "")
+	second.CorefChain = []*nlp.CorefChain{chain(1, 1, 3)}
+	m.add(first, 0)
+	m.add(second, 0)
+
+	var ids []int32
+	for _, c := range m.doc.CorefChain {
+		ids = append(ids, c.GetChainID())
+		for _, mention := range c.Mention {
+			ids = append(ids, mention.GetMentionID())
+		}
+	}
+	want := []int32{1, 1, 7, 2, 2, 8, 8, 10}
+	if len(ids) != len(want) {
+		t.Fatalf("%v", ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("%v", ids)
+		}
+	}
+	if s := m.doc.CorefChain[2].Mention[0].GetSentenceIndex(); s != 1 {
+		t.Errorf("%d", s)
+	}
+}
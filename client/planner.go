@@ -0,0 +1,166 @@
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// timingClasses maps the annotator classes of CmdResult.Timing to the
+// annotator names.
+//
+var timingClasses = map[string]string{
+	"TokenizerAnnotator":          "tokenize",
+	"WordsToSentencesAnnotator":   "ssplit",
+	"CleanXmlAnnotator":           "cleanxml",
+	"TrueCaseAnnotator":           "truecase",
+	"POSTaggerAnnotator":          "pos",
+	"MorphaAnnotator":             "lemma",
+	"NERCombinerAnnotator":        "ner",
+	"TokensRegexNERAnnotator":     "regexner",
+	"EntityMentionsAnnotator":     "entitymentions",
+	"ParserAnnotator":             "parse",
+	"DependencyParseAnnotator":    "depparse",
+	"CorefAnnotator":              "coref",
+	"CorefMentionAnnotator":       "coref.mention",
+	"DeterministicCorefAnnotator": "dcoref",
+	"SentimentAnnotator":          "sentiment",
+	"NaturalLogicAnnotator":       "natlog",
+	"OpenIE":                      "openie",
+	"KBPAnnotator":                "kbp",
+	"QuoteAnnotator":              "quote",
+	"RelationExtractorAnnotator":  "relation",
+}
+
+// Timings accumulates the time the annotators take per character of text,
+// from measured runs, see RecordResult. Measure each Preset apart, as the models in its
+// properties change the times. It is safe for concurrent use.
+//
+type Timings struct {
+	mu      sync.Mutex
+	elapsed map[string]time.Duration
+	chars   map[string]int64
+}
+
+// NewTimings creates an instance of Timings.
+//
+func NewTimings() *Timings {
+	return &Timings{elapsed: make(map[string]time.Duration), chars: make(map[string]int64)}
+}
+
+// Record adds a measure of annotator taking elapsed over chars characters.
+//
+func (self *Timings) Record(annotator string, elapsed time.Duration, chars int64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.elapsed[annotator] += elapsed
+	self.chars[annotator] += chars
+}
+
+// RecordResult adds the time of every annotator reported in the Timing of
+// a run, see Cmd.RunResult, over a text of chars characters. CoreNLP reports
+// times to a tenth of a second, so measure runs of a few seconds.
+//
+// For example:
+// res, err := cmd.RunResult(ctx, text, doc)
+// timings.RecordResult(res, int64(len(text)))
+//
+func (self *Timings) RecordResult(res *CmdResult, chars int64) {
+	for class, elapsed := range res.Timing {
+		if annotator, ok := timingClasses[class]; ok {
+			self.Record(annotator, elapsed, chars)
+		}
+	}
+}
+
+// Estimate returns the time the annotators are expected to take on chars
+// characters, and false if one of them was never measured.
+//
+func (self *Timings) Estimate(annotators []string, chars int) (time.Duration, bool) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	var total float64
+	for _, a := range annotators {
+		measured := self.chars[a]
+		if measured <= 0 {
+			return 0, false
+		}
+		total += float64(self.elapsed[a]) * float64(chars) / float64(measured)
+	}
+	return time.Duration(total), true
+}
+
+// Profile is a preset with the timings measured for it.
+//
+type Profile struct {
+	Preset  *Preset
+	Timings *Timings
+}
+
+// Plan is the pipeline PlanBudget recommends.
+//
+type Plan struct {
+	// the preset the plan starts from, and its annotators and properties kept
+	Preset     *Preset
+	Annotators []string
+	Properties map[string]string
+
+	// the annotators of the preset left out to meet the budget, in order
+	Dropped []string
+
+	// the estimated time of the plan, and whether it is within the budget
+	Estimated time.Duration
+	Fits      bool
+}
+
+// minimalAnnotators are never dropped by PlanBudget.
+//
+var minimalAnnotators = map[string]bool{"tokenize": true, "ssplit": true}
+
+// PlanBudget recommends the pipeline annotating chars characters of text
+// within budget: the first profile, from the highest quality to the lowest,
+// e.g. MaxQualityPreset, BalancedPreset and FastPreset, whose estimate fits.
+// If none does, the annotators of the last profile are dropped from the end,
+// as the later ones depend on the earlier, together with their properties,
+// until it fits or only tokenize and ssplit are left. Profiles with
+// annotators never measured are skipped. It returns nil without a usable profile.
+//
+func PlanBudget(budget time.Duration, chars int, profiles ...*Profile) *Plan {
+	var last *Profile
+	for _, p := range profiles {
+		estimated, ok := p.Timings.Estimate(p.Preset.Annotators, chars)
+		if !ok {
+			continue
+		}
+		if estimated <= budget {
+			return &Plan{Preset: p.Preset, Annotators: copyStrings(p.Preset.Annotators), Properties: copyProperties(p.Preset.Properties), Estimated: estimated, Fits: true}
+		}
+		last = p
+	}
+	if last == nil {
+		return nil
+	}
+
+	annotators := copyStrings(last.Preset.Annotators)
+	var dropped []string
+	estimated, _ := last.Timings.Estimate(annotators, chars)
+	for estimated > budget && len(annotators) > 0 && !minimalAnnotators[annotators[len(annotators)-1]] {
+		dropped = append([]string{annotators[len(annotators)-1]}, dropped...)
+		annotators = annotators[:len(annotators)-1]
+		estimated, _ = last.Timings.Estimate(annotators, chars)
+	}
+
+	props := make(map[string]string)
+	for k, v := range last.Preset.Properties {
+		keep := true
+		for _, a := range dropped {
+			if strings.HasPrefix(k, a+".") {
+				keep = false
+			}
+		}
+		if keep {
+			props[k] = v
+		}
+	}
+	return &Plan{Preset: last.Preset, Annotators: annotators, Properties: props, Dropped: dropped, Estimated: estimated, Fits: estimated <= budget}
+}
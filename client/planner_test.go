@@ -0,0 +1,66 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const timingLog = `[main] INFO edu.stanford.nlp.pipeline.StanfordCoreNLP - Processing file a.txt ... writing to a.txt.ser.gz
+Annotation pipeline timing information:
+TokenizerAnnotator: 0.1 sec.
+WordsToSentencesAnnotator: 0.1 sec.
+POSTaggerAnnotator: 0.8 sec.
+MorphaAnnotator: 0.2 sec.
+NERCombinerAnnotator: 1.8 sec.
+ParserAnnotator: 6.0 sec.
+CorefAnnotator: 3.0 sec.
+TOTAL: 12.0 sec. for 2000 tokens at 166.7 tokens/sec.
+Pipeline setup: 9.1 sec.
+`
+
+func TestTimingsRecordResult(t *testing.T) {
+	timings := NewTimings()
+	timings.RecordResult(&CmdResult{Timing: parseTiming([]byte(timingLog))}, 10000)
+	if d, ok := timings.Estimate([]string{"pos", "parse"}, 1000); !ok || d != 680*time.Millisecond {
+		t.Errorf("%v %v", d, ok)
+	}
+	if _, ok := timings.Estimate([]string{"TOTAL"}, 1000); ok {
+		t.Error("TOTAL recorded")
+	}
+}
+
+func TestPlanBudget(t *testing.T) {
+	// balanced measured over 10,000 characters: 12s, i.e. 1.2ms a character
+	res := &CmdResult{Timing: parseTiming([]byte(timingLog))}
+	balanced := NewTimings()
+	balanced.RecordResult(res, 10000)
+	// fast has no parse nor coref, and a cheap depparse
+	fast := NewTimings()
+	fast.RecordResult(res, 10000)
+	fast.Record("depparse", time.Second, 10000)
+
+	profiles := []*Profile{{MaxQualityPreset, NewTimings()}, {BalancedPreset, balanced}, {FastPreset, fast}}
+
+	plan := PlanBudget(2*time.Second, 1000, profiles...)
+	if plan == nil || plan.Preset != BalancedPreset || !plan.Fits || plan.Estimated != 1200*time.Millisecond {
+		t.Fatalf("%+v", plan)
+	}
+
+	// fast takes 0.4s on 1000 characters, 0.12s without ner and depparse
+	plan = PlanBudget(250*time.Millisecond, 1000, profiles...)
+	if plan.Preset != FastPreset || !plan.Fits || strings.Join(plan.Dropped, ",") != "ner,depparse" || strings.Join(plan.Annotators, ",") != "tokenize,ssplit,pos,lemma" {
+		t.Errorf("%+v", plan)
+	}
+	if _, ok := plan.Properties["ner.applyFineGrained"]; ok || plan.Properties["pos.maxlen"] != "100" {
+		t.Errorf("%v", plan.Properties)
+	}
+
+	plan = PlanBudget(time.Millisecond, 1000, profiles...)
+	if plan.Fits || strings.Join(plan.Annotators, ",") != "tokenize,ssplit" {
+		t.Errorf("%+v", plan)
+	}
+	if plan := PlanBudget(time.Second, 1000, profiles[0]); plan != nil {
+		t.Errorf("%+v", plan)
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"fmt"
+)
+
+// Preset is a named pipeline trading latency for quality: the annotators,
+// the models and the limits on long sentences.
+//
+type Preset struct {
+	Name       string
+	Annotators []string
+	Properties map[string]string
+}
+
+var (
+	// FastPreset is for interactive use: tagging, coarse named entities and
+	// the neural dependency parser, without constituency parse nor coref.
+	FastPreset = &Preset{
+		Name:       "fast",
+		Annotators: []string{"tokenize", "ssplit", "pos", "lemma", "ner", "depparse"},
+		Properties: map[string]string{
+			"ner.applyFineGrained": "false",
+			"ner.useSUTime":        "false",
+			"pos.maxlen":           "100",
+		},
+	}
+
+	// BalancedPreset adds the PCFG constituency parse, from which the
+	// dependencies are built, and the statistical coref, skipping the parse
+	// of sentences over 60 tokens.
+	BalancedPreset = &Preset{
+		Name:       "balanced",
+		Annotators: []string{"tokenize", "ssplit", "pos", "lemma", "ner", "parse", "coref"},
+		Properties: map[string]string{
+			"parse.model":     "edu/stanford/nlp/models/lexparser/englishPCFG.ser.gz",
+			"parse.maxlen":    "60",
+			"coref.algorithm": "statistical",
+		},
+	}
+
+	// MaxQualityPreset is for offline workloads: the beam shift-reduce
+	// parser on every sentence and the neural coref. Both need the English
+	// models jar besides the default models.
+	MaxQualityPreset = &Preset{
+		Name:       "max-quality",
+		Annotators: []string{"tokenize", "ssplit", "pos", "lemma", "ner", "parse", "coref"},
+		Properties: map[string]string{
+			"parse.model":     "edu/stanford/nlp/models/srparser/englishSR.beam.ser.gz",
+			"coref.algorithm": "neural",
+		},
+	}
+)
+
+// Presets are the presets by name.
+//
+var Presets = map[string]*Preset{
+	FastPreset.Name:       FastPreset,
+	BalancedPreset.Name:   BalancedPreset,
+	MaxQualityPreset.Name: MaxQualityPreset,
+}
+
+// PresetOf returns the preset of the name, e.g. "balanced", or *ConfigError.
+//
+func PresetOf(name string) (*Preset, error) {
+	if p, ok := Presets[name]; ok {
+		return p, nil
+	}
+	return nil, &ConfigError{Field: "Preset", Err: fmt.Errorf("unknown preset %q", name)}
+}
+
+// HttpOption returns the option setting the annotators of the preset and
+// adding its properties to those of the client.
+//
+// For example:
+// c := NewHttpClient(nil, url).With(FastPreset.HttpOption())
+//
+func (self *Preset) HttpOption() HttpOption {
+	return func(c *HttpClient) {
+		c.Annotators = copyStrings(self.Annotators)
+		WithProperties(self.Properties)(c)
+	}
+}
+
+// CmdOption returns the option setting the annotators of the preset and
+// adding its properties to those of the command.
+//
+func (self *Preset) CmdOption() CmdOption {
+	return func(c *Cmd) {
+		c.Annotators = copyStrings(self.Annotators)
+		WithCmdProperties(self.Properties)(c)
+	}
+}
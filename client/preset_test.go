@@ -0,0 +1,76 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPresets(t *testing.T) {
+	for name, p := range Presets {
+		if warnings := CheckProperties(p.Properties); warnings != nil {
+			t.Errorf("%s: %v", name, warnings)
+		}
+	}
+	if _, err := PresetOf("slow"); err == nil {
+		t.Error("unknown preset")
+	}
+
+	p, err := PresetOf("balanced")
+	if err != nil { t.Fatal(err) }
+	c := NewHttpClient(nil, "http://localhost:9000").With(WithProperties(map[string]string{"parse.maxlen": "40", "tokenize.language": "en"}), p.HttpOption())
+	if strings.Join(c.Annotators, ",") != "tokenize,ssplit,pos,lemma,ner,parse,coref" || c.Properties["parse.maxlen"] != "60" || c.Properties["tokenize.language"] != "en" {
+		t.Errorf("%v %v", c.Annotators, c.Properties)
+	}
+	c.Annotators[0] = "x"
+	if p.Annotators[0] != "tokenize" {
+		t.Error("preset modified")
+	}
+
+	cmd := NewCmd(nil, "/tmp/*").With(FastPreset.CmdOption())
+	args := strings.Join(cmd.options(), " ")
+	if !strings.Contains(args, "-annotators tokenize,ssplit,pos,lemma,ner,depparse -ner.applyFineGrained false -ner.useSUTime false -pos.maxlen 100") {
+		t.Errorf("%s", args)
+	}
+	derived := cmd.With(WithCmdProperties(map[string]string{"pos.maxlen": "50"}))
+	if cmd.Properties["pos.maxlen"] != "100" || derived.Properties["pos.maxlen"] != "50" || FastPreset.Properties["pos.maxlen"] != "100" {
+		t.Errorf("%v %v", cmd.Properties, derived.Properties)
+	}
+}
+
+func TestWithDocDate(t *testing.T) {
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := NewHttpClient([]string{"tokenize", "ssplit", "ner"}, "http://localhost:9000").With(WithDocDate(date))
+	if c.Properties["ner.docdate.useFixedDate"] != "2024-05-01" {
+		t.Errorf("%v", c.Properties)
+	}
+	cmd := NewCmd(nil, "/tmp/*").With(WithCmdDocDate(date))
+	if !strings.Contains(strings.Join(cmd.options(), " "), "-ner.docdate.useFixedDate 2024-05-01") {
+		t.Errorf("%v", cmd.options())
+	}
+}
+
+func TestWithKBest(t *testing.T) {
+	c := NewHttpClient([]string{"tokenize", "ssplit", "parse"}).With(WithKBest(5))
+	if c.Properties["parse.kbest"] != "5" {
+		t.Errorf("%v", c.Properties)
+	}
+	cmd := NewCmd(nil, "/tmp/*").With(WithCmdKBest(3))
+	if !strings.Contains(strings.Join(cmd.options(), " "), "-parse.kbest 3") {
+		t.Errorf("%v", cmd.options())
+	}
+}
+
+func TestMentionDetection(t *testing.T) {
+	c := NewHttpClient(MentionAnnotators(DependencyMentions)).With(WithMentionDetection(DependencyMentions))
+	if c.Properties["coref.md.type"] != "DEPENDENCY" || c.Annotators[5] != "depparse" || c.Annotators[6] != "coref.mention" {
+		t.Errorf("%v %v", c.Annotators, c.Properties)
+	}
+	if MentionAnnotators(RuleMentions)[5] != "parse" {
+		t.Errorf("%v", MentionAnnotators(RuleMentions))
+	}
+	cmd := NewCmd(nil, "/tmp/*").With(WithCmdMentionDetection(HybridMentions))
+	if !strings.Contains(strings.Join(cmd.options(), " "), "-coref.md.type HYBRID") {
+		t.Errorf("%v", cmd.options())
+	}
+}
@@ -0,0 +1,65 @@
+//go:build !windows
+// +build !windows
+
+package client
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// newProcessGroup makes the command lead a process group of its own,
+// so that killTree reaches the processes it spawns.
+//
+func newProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killTree kills the process group of the started command.
+//
+func killTree(cmd *exec.Cmd) error {
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
+
+// longPath returns path as is: only Windows limits the path length.
+//
+func longPath(path string) string {
+	return path
+}
+
+// processAlive reports whether the process pid exists.
+//
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// lockOwner creates the file at path and holds an exclusive lock on it until
+// the file is closed, or the process dies.
+//
+func lockOwner(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// ownerLocked reports whether the lock of lockOwner on path is held.
+//
+func ownerLocked(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_SH|syscall.LOCK_NB) == syscall.EWOULDBLOCK
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+// +build !windows
+
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestKillTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "java")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	// the child keeps the standard output open, so the command only
+	// returns early if the whole tree is killed
+	java, err := fakeJava(dir, "sleep 30 &\nwait\n")
+	if err != nil { t.Fatal(err) }
+
+	c := NewCmd([]string{"tokenize"}, dir, "", java)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := c.RunRaw(ctx, []byte("Hi")); err != context.DeadlineExceeded {
+		t.Errorf("%v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("%v", elapsed)
+	}
+}
@@ -0,0 +1,90 @@
+//go:build windows
+// +build windows
+
+package client
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// newProcessGroup starts the command in a new process group, so that
+// interrupting the parent console does not reach it directly.
+//
+func newProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// killTree kills the started command and all its descendants with taskkill,
+// since Process.Kill only terminates the command itself.
+//
+func killTree(cmd *exec.Cmd) error {
+	pid := strconv.Itoa(cmd.Process.Pid)
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", pid).Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
+
+// longPath returns path in the extended-length form \\?\C:\... when it
+// exceeds MAX_PATH, which the Java process accepts as a file name.
+//
+func longPath(path string) string {
+	if len(path) < 260 || strings.HasPrefix(path, `\\`) {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return `\\?\` + abs
+}
+
+// processAlive reports whether the process pid exists.
+//
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
+
+// errorSharingViolation is ERROR_SHARING_VIOLATION, which syscall lacks.
+//
+const errorSharingViolation syscall.Errno = 32
+
+// lockOwner creates the file at path and opens it without sharing until the
+// file is closed, or the process dies.
+//
+func lockOwner(path string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "lock", Path: path, Err: err}
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
+
+// ownerLocked reports whether the lock of lockOwner on path is held.
+//
+func ownerLocked(path string) bool {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false
+	}
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ, 0, nil, syscall.OPEN_EXISTING, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return err == errorSharingViolation
+	}
+	syscall.CloseHandle(h)
+	return false
+}
@@ -0,0 +1,165 @@
+package client
+
+import (
+	"sort"
+	"strings"
+)
+
+// KnownProperties is the registry of CoreNLP property names CheckProperties
+// accepts: the pipeline settings and the documented options of the bundled
+// annotators. Add to it the properties of other releases or annotators.
+//
+var KnownProperties = map[string]bool{}
+
+func init() {
+	for _, group := range [][]string{
+		{"annotators", "inputFormat", "outputFormat", "serializer", "outputSerializer", "inputSerializer",
+			"outputDirectory", "outputExtension", "replaceExtension", "noClobber", "file", "filelist", "textFile",
+			"encoding", "inputEncoding", "outputEncoding", "threads", "enforceRequirements", "pipelineLanguage", "resetDefault",
+			"output.prettyPrint", "output.columns", "output.includeText"},
+		{"tokenize.language", "tokenize.class", "tokenize.whitespace", "tokenize.keepeol", "tokenize.options",
+			"tokenize.verbose", "tokenize.codepoint"},
+		{"ssplit.eolonly", "ssplit.isOneSentence", "ssplit.newlineIsSentenceBreak", "ssplit.boundaryTokenRegex",
+			"ssplit.boundaryMultiTokenRegex", "ssplit.boundariesToDiscard", "ssplit.htmlBoundariesToDiscard",
+			"ssplit.tokenPatternsToDiscard", "ssplit.boundaryFollowersRegex"},
+		{"clean.xmltags", "clean.sentenceendingtags", "clean.singlesentencetags", "clean.allowflawedxml",
+			"clean.datetags", "clean.docIdtags", "clean.docTypetags", "clean.utterancetags", "clean.speakertags",
+			"clean.docAnnotations", "clean.tokenAnnotations", "clean.sectiontags", "clean.sectionAnnotations",
+			"clean.ssplitDiscardTokens"},
+		{"truecase.model", "truecase.bias", "truecase.mixedcasefile", "truecase.overwriteText"},
+		{"pos.model", "pos.maxlen"},
+		{"ner.model", "ner.applyNumericClassifiers", "ner.applyFineGrained", "ner.buildEntityMentions",
+			"ner.combinationMode", "ner.useSUTime", "ner.language", "ner.maxlen", "ner.nthreads",
+			"ner.statisticalOnly", "ner.rulesOnly", "ner.fine.regexner.mapping", "ner.fine.regexner.ignorecase",
+			"ner.additional.regexner.mapping", "ner.additional.regexner.ignorecase", "ner.additional.tokensregex.rules",
+			"ner.docdate.useFixedDate", "ner.docdate.usePresent", "ner.docdate.useMappingFile", "ner.docdate.useRegex"},
+		{"sutime.rules", "sutime.markTimeRanges", "sutime.includeRange", "sutime.includeNested", "sutime.binders",
+			"sutime.teRelHeurLevel"},
+		{"regexner.mapping", "regexner.ignorecase", "regexner.validpospattern", "regexner.backgroundSymbol",
+			"regexner.verbose", "tokensregex.rules", "entitymentions.acronyms"},
+		{"parse.model", "parse.maxlen", "parse.flags", "parse.originalDependencies", "parse.kbest",
+			"parse.binaryTrees", "parse.buildgraphs", "parse.nthreads", "parse.debug", "parse.treemap",
+			"parse.maxtime", "parse.keepPunct", "parse.extradependencies"},
+		{"depparse.model", "depparse.language", "depparse.extradependencies"},
+		{"coref.algorithm", "coref.language", "coref.md.type", "coref.maxMentionDistance",
+			"coref.maxMentionDistanceWithStringMatch", "coref.neural.greedyness", "dcoref.score",
+			"dcoref.postprocessing", "dcoref.maxdist"},
+		{"sentiment.model"},
+		{"openie.format", "openie.filelist", "openie.threads", "openie.max_entailments_per_clause",
+			"openie.resolve_coref", "openie.ignore_affinity", "openie.affinity_probability_cap",
+			"openie.affinity_models", "openie.triple.strict", "openie.triple.all_nominals",
+			"openie.splitter.model", "openie.splitter.nomodel", "openie.splitter.threshold", "openie.splitter.disable"},
+		{"quote.singleQuotes", "quote.maxLength", "quote.asciiQuotes", "quote.allowEmbeddedSame",
+			"quote.extractUnclosedQuotes", "quote.attributeQuotes", "quote.attribution.charactersPath",
+			"quote.attribution.booknlpCoref", "quote.attribution.familyWordsFile",
+			"quote.attribution.animacyWordsFile", "quote.attribution.genderNamesFile", "quote.attribution.modelPath"},
+		{"kbp.model", "kbp.semgrex", "kbp.tokensregex", "kbp.language"},
+	} {
+		for _, name := range group {
+			KnownProperties[name] = true
+		}
+	}
+}
+
+// customAnnotator is the prefix of the properties declaring custom
+// annotators, e.g. "customAnnotatorClass.stopword", whose own properties
+// then start with their name, e.g. "stopword.file".
+//
+const customAnnotator = "customAnnotatorClass."
+
+// PropertyWarning is a property that CoreNLP would ignore silently.
+//
+type PropertyWarning struct {
+	Key string
+
+	// the known property nearest to Key, "" if none is close
+	Suggestion string
+}
+
+func (self *PropertyWarning) String() string {
+	msg := "unknown property " + `"` + self.Key + `"`
+	if self.Suggestion != "" {
+		msg += `, did you mean "` + self.Suggestion + `"?`
+	}
+	return msg
+}
+
+// CheckProperties returns a warning for each key of props that is not in
+// KnownProperties, in the order of the keys, with the nearest known name when
+// it looks like a typo, e.g. "ner.applyFineGrained" for "ner.applyFineGrain".
+// The declarations of custom annotators, and the properties under their
+// names, are accepted.
+//
+func CheckProperties(props map[string]string) []*PropertyWarning {
+	custom := make(map[string]bool)
+	for k := range props {
+		if strings.HasPrefix(k, customAnnotator) {
+			custom[strings.TrimPrefix(k, customAnnotator)] = true
+		}
+	}
+
+	var warnings []*PropertyWarning
+	for k := range props {
+		if KnownProperties[k] || strings.HasPrefix(k, customAnnotator) {
+			continue
+		}
+		if i := strings.Index(k, "."); i > 0 && custom[k[:i]] {
+			continue
+		}
+		warnings = append(warnings, &PropertyWarning{Key: k, Suggestion: suggestProperty(k)})
+	}
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Key < warnings[j].Key })
+	return warnings
+}
+
+// CheckProperties checks the Properties of the client, see CheckProperties.
+//
+func (self *HttpClient) CheckProperties() []*PropertyWarning {
+	return CheckProperties(self.Properties)
+}
+
+// suggestProperty returns the known property nearest to key, ignoring case,
+// if it is a few edits away, or "".
+//
+func suggestProperty(key string) string {
+	best, distance := "", len(key)/4+1
+	lower := strings.ToLower(key)
+	for name := range KnownProperties {
+		d := editDistance(lower, strings.ToLower(name))
+		if d < distance || d == distance && best != "" && name < best {
+			best, distance = name, d
+		}
+	}
+	if best == "" || distance > 3 {
+		return ""
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b, in bytes.
+//
+func editDistance(a, b string) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			next := row[j-1] + 1
+			if row[j]+1 < next {
+				next = row[j] + 1
+			}
+			if prev+cost < next {
+				next = prev + cost
+			}
+			prev, row[j] = row[j], next
+		}
+	}
+	return row[len(b)]
+}
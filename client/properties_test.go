@@ -0,0 +1,45 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestCheckProperties(t *testing.T) {
+	c := NewHttpClient([]string{"tokenize", "ssplit", "ner"}, "http://localhost:9000").With(WithProperties(map[string]string{
+		"ner.applyFineGrain":                "false",
+		"tokenize.language":                 "de",
+		"Parse.Model":                       "x.ser.gz",
+		"customAnnotatorClass.stopword":     "org.example.Stopword",
+		"stopword.file":                     "stop.txt",
+		"frobnicate.everything.immediately": "yes",
+	}))
+	warnings := c.CheckProperties()
+	if len(warnings) != 3 {
+		t.Fatalf("%v", warnings)
+	}
+	if w := warnings[0]; w.Key != "Parse.Model" || w.Suggestion != "parse.model" {
+		t.Errorf("%v", w)
+	}
+	if w := warnings[1]; w.Key != "frobnicate.everything.immediately" || w.Suggestion != "" || w.String() != `unknown property "frobnicate.everything.immediately"` {
+		t.Errorf("%v", w)
+	}
+	if w := warnings[2]; w.String() != `unknown property "ner.applyFineGrain", did you mean "ner.applyFineGrained"?` {
+		t.Errorf("%v", w)
+	}
+	if warnings := CheckProperties(nil); warnings != nil {
+		t.Errorf("%v", warnings)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		d    int
+	}{
+		{"", "abc", 3}, {"kitten", "sitting", 3}, {"pos.model", "pos.model", 0}, {"pos.modle", "pos.model", 2},
+	} {
+		if d := editDistance(c.a, c.b); d != c.d {
+			t.Errorf("%q %q: %d", c.a, c.b, d)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority is the priority class of a request waiting in a Queue.
+// Higher classes are served first, and in arrival order within a class.
+//
+type Priority int
+
+const (
+	// PriorityBatch is for background work, e.g. corpus jobs.
+	PriorityBatch Priority = -1
+	// PriorityNormal is the priority of requests without one.
+	PriorityNormal Priority = 0
+	// PriorityInteractive is for latency-sensitive traffic, e.g. API calls.
+	PriorityInteractive Priority = 1
+)
+
+type priorityKey struct{}
+
+// WithPriority returns a context giving the requests made with it priority p.
+//
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the priority of ctx, PriorityNormal if it has none.
+//
+func PriorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityNormal
+}
+
+// Queue caps the number of requests in flight, and queues the excess
+// by priority, see WithPriority, then in arrival order. A CoreNLP server
+// chokes when it gets many more requests than its -threads setting, so it
+// is better to wait on the client side. Queue is safe for concurrent use.
+//
+// The priorities are strict: as long as interactive requests wait, no batch
+// request starts, so batch jobs only get the capacity interactive traffic leaves.
+//
+type Queue struct {
+	mu       sync.Mutex
+	limit    int
+	inflight int
+	waiting  []*waiter
+}
+
+type waiter struct {
+	ready    chan struct{}
+	priority Priority
+}
+
+// NewQueue creates a Queue allowing limit requests in flight, at least 1.
+//
+func NewQueue(limit int) *Queue {
+	if limit < 1 {
+		limit = 1
+	}
+	return &Queue{limit: limit}
+}
+
+// Acquire blocks until a request may start or ctx is done. The request
+// waits with the priority of ctx. Each successful Acquire must be followed by Release.
+//
+func (self *Queue) Acquire(ctx context.Context) error {
+	self.mu.Lock()
+	if self.inflight < self.limit && len(self.waiting) == 0 {
+		self.inflight++
+		self.mu.Unlock()
+		return nil
+	}
+	w := &waiter{ready: make(chan struct{}), priority: PriorityFrom(ctx)}
+	self.waiting = append(self.waiting, w)
+	self.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	select {
+	case <-w.ready:
+		// granted while giving up, pass the slot on
+		self.release()
+	default:
+		for i, other := range self.waiting {
+			if other == w {
+				self.waiting = append(self.waiting[:i], self.waiting[i+1:]...)
+				break
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// Release ends a request started by Acquire.
+//
+func (self *Queue) Release() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.release()
+}
+
+func (self *Queue) release() {
+	if len(self.waiting) > 0 {
+		next := 0
+		for i, w := range self.waiting {
+			if w.priority > self.waiting[next].priority {
+				next = i
+			}
+		}
+		ready := self.waiting[next].ready
+		self.waiting = append(self.waiting[:next], self.waiting[next+1:]...)
+		close(ready)
+		return
+	}
+	self.inflight--
+}
+
+// Depth returns the number of requests waiting.
+//
+func (self *Queue) Depth() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return len(self.waiting)
+}
+
+// DepthOf returns the number of requests waiting with priority p.
+//
+func (self *Queue) DepthOf(p Priority) int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	n := 0
+	for _, w := range self.waiting {
+		if w.priority == p {
+			n++
+		}
+	}
+	return n
+}
+
+// InFlight returns the number of requests running.
+//
+func (self *Queue) InFlight() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.inflight
+}
+
+// WithMaxInFlight caps the requests in flight of the client at n, queueing the rest.
+// Each client the option is applied to gets its own queue, so applying it to
+// the backends of a LoadBalancedClient caps every server separately.
+//
+func WithMaxInFlight(n int) HttpOption {
+	return func(self *HttpClient) {
+		self.Queue = NewQueue(n)
+	}
+}
+
+// WithQueue shares queue among all the clients the option is applied to, e.g.
+// an interactive and a batch client of the same server.
+//
+func WithQueue(queue *Queue) HttpOption {
+	return func(self *HttpClient) {
+		self.Queue = queue
+	}
+}
+
+// QueueDepth returns the number of requests waiting for the server,
+// 0 if the client has no queue.
+//
+func (self *HttpClient) QueueDepth() int {
+	if self.Queue == nil {
+		return 0
+	}
+	return self.Queue.Depth()
+}
+
+// QueueDepth returns the number of requests waiting, summed over all the backends.
+//
+func (self *LoadBalancedClient) QueueDepth() int {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	n := 0
+	for _, b := range self.backends {
+		n += b.client.QueueDepth()
+	}
+	return n
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestQueue(t *testing.T) {
+	q := NewQueue(2)
+	ctx := context.Background()
+	q.Acquire(ctx)
+	q.Acquire(ctx)
+
+	var order []int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := q.Acquire(ctx); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			q.Release()
+		}(i)
+		// let the goroutine queue up in order
+		for q.Depth() != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := q.Acquire(short); err != context.DeadlineExceeded {
+		t.Errorf("%v", err)
+	}
+	if q.Depth() != 3 || q.InFlight() != 2 {
+		t.Errorf("%d %d", q.Depth(), q.InFlight())
+	}
+
+	// the slot is handed down the queue one waiter at a time
+	q.Release()
+	wg.Wait()
+	q.Release()
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Errorf("%v", order)
+	}
+	if q.Depth() != 0 || q.InFlight() != 0 {
+		t.Errorf("%d %d", q.Depth(), q.InFlight())
+	}
+}
+
+func TestQueuePriority(t *testing.T) {
+	q := NewQueue(1)
+	ctx := context.Background()
+	q.Acquire(ctx)
+
+	var order []Priority
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	priorities := []Priority{PriorityBatch, PriorityNormal, PriorityBatch, PriorityInteractive}
+	for i, p := range priorities {
+		wg.Add(1)
+		go func(p Priority) {
+			defer wg.Done()
+			if err := q.Acquire(WithPriority(ctx, p)); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+			q.Release()
+		}(p)
+		for q.Depth() != i+1 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if q.DepthOf(PriorityBatch) != 2 || q.DepthOf(PriorityInteractive) != 1 {
+		t.Errorf("%d %d", q.DepthOf(PriorityBatch), q.DepthOf(PriorityInteractive))
+	}
+
+	q.Release()
+	wg.Wait()
+	expected := []Priority{PriorityInteractive, PriorityNormal, PriorityBatch, PriorityBatch}
+	for i := range expected {
+		if i >= len(order) || order[i] != expected[i] {
+			t.Fatalf("%v", order)
+		}
+	}
+	if PriorityFrom(ctx) != PriorityNormal {
+		t.Errorf("%d", PriorityFrom(ctx))
+	}
+}
+
+func TestWithMaxInFlight(t *testing.T) {
+	a, b := newFakeServer(), newFakeServer()
+	defer a.Close()
+	defer b.Close()
+
+	lb := NewLoadBalancedClient(nil, a.URL, b.URL).With(WithMaxInFlight(1))
+	lb.backends[0].client.Queue.Acquire(context.Background())
+
+	done := make(chan error)
+	go func() { done <- lb.backends[0].client.RunText(context.Background(), []byte("x"), &nlp.Document{}) }()
+	for lb.QueueDepth() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	lb.backends[0].client.Queue.Release()
+	<-done
+	if lb.QueueDepth() != 0 || a.count() != 1 {
+		t.Errorf("%d %d", lb.QueueDepth(), a.count())
+	}
+}
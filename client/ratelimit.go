@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket allowing Rate requests per second on average,
+// with bursts of up to Burst requests. It is safe for concurrent use.
+//
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates an instance of RateLimiter.
+//
+// rps: the requests per second;
+//
+// burst: the maximal number of requests at once, at least 1.
+//
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{rate: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait blocks until a request is allowed or ctx is done.
+//
+func (self *RateLimiter) Wait(ctx context.Context) error {
+	if self.rate <= 0 {
+		return nil
+	}
+
+	self.mu.Lock()
+	now := time.Now()
+	self.tokens += now.Sub(self.last).Seconds() * self.rate
+	if self.tokens > self.burst {
+		self.tokens = self.burst
+	}
+	self.last = now
+
+	// reserve a token, possibly going into debt
+	self.tokens--
+	if self.tokens >= 0 {
+		self.mu.Unlock()
+		return nil
+	}
+	delay := time.Duration(-self.tokens / self.rate * float64(time.Second))
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		self.tokens++
+		self.mu.Unlock()
+		return context.DeadlineExceeded
+	}
+	self.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		self.mu.Lock()
+		self.tokens++
+		self.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := NewRateLimiter(50, 2)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil { t.Fatal(err) }
+	}
+	// 2 at once, then 2 more at 20ms each
+	if d := time.Since(start); d < 30*time.Millisecond {
+		t.Errorf("%s", d)
+	}
+
+	short, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	l.Wait(ctx)
+	if err := l.Wait(short); err != context.DeadlineExceeded {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	a, b := newFakeServer(), newFakeServer()
+	defer a.Close()
+	defer b.Close()
+
+	c := NewHttpClient(nil, a.URL)
+	limited := c.With(WithRateLimit(1000, 1))
+	if c.Limiter != nil || limited.Limiter == nil {
+		t.Fatalf("option applied to the original client")
+	}
+	if err := limited.RunText(context.Background(), []byte("x"), &nlp.Document{}); err != nil { t.Fatal(err) }
+
+	lb := NewLoadBalancedClient(nil, a.URL, b.URL).With(WithRateLimit(1000, 1))
+	if lb.backends[0].client.Limiter == lb.backends[1].client.Limiter {
+		t.Errorf("limiter shared among hosts")
+	}
+}
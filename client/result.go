@@ -0,0 +1,95 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// stderrTail is the size of the end of the standard error kept in CmdResult.
+//
+const stderrTail = 4096
+
+// CmdResult describes a run of the Java process, see Cmd.RunResult.
+//
+type CmdResult struct {
+	// exit code of the process, -1 if it was killed
+	ExitCode int
+
+	// wall time from the start to the exit of the process
+	Elapsed time.Duration
+
+	// the last lines of the standard error, up to 4 KB
+	Stderr string
+
+	// path of the output file when Cmd.OutputDir is set, "" otherwise
+	Output string
+
+	// time spent by every annotator, e.g. "TokenizerAnnotator", as reported
+	// by CoreNLP at exit, with "TOTAL" and "Pipeline setup" when reported
+	Timing map[string]time.Duration
+}
+
+func newCmdResult(cmd *exec.Cmd, elapsed time.Duration, stderr []byte) *CmdResult {
+	res := &CmdResult{ExitCode: -1, Elapsed: elapsed, Timing: parseTiming(stderr)}
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	if len(stderr) > stderrTail {
+		stderr = stderr[len(stderr)-stderrTail:]
+		if i := bytes.IndexByte(stderr, '\n'); i >= 0 {
+			stderr = stderr[i+1:]
+		}
+	}
+	res.Stderr = string(stderr)
+	return res
+}
+
+var timingLine = regexp.MustCompile(`([A-Za-z][\w ]*?): (\d+(?:\.\d+)?) sec\.`)
+
+// parseTiming reads the timing CoreNLP logs after "Annotation pipeline timing
+// information:", e.g. "POSTaggerAnnotator: 0.1 sec.", and "Pipeline setup: 1.2 sec.".
+//
+func parseTiming(stderr []byte) map[string]time.Duration {
+	timing := make(map[string]time.Duration)
+	started := false
+	for _, line := range strings.Split(string(stderr), "\n") {
+		// skip the prefix of the logger, e.g. "[main] INFO edu.stanford.nlp.pipeline.StanfordCoreNLP - "
+		if i := strings.Index(line, " - "); i >= 0 {
+			line = line[i+3:]
+		}
+		if strings.Contains(line, "Annotation pipeline timing information") {
+			started = true
+			continue
+		}
+		m := timingLine.FindStringSubmatch(line)
+		if m == nil || !started && m[1] != "Pipeline setup" {
+			continue
+		}
+		sec, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			continue
+		}
+		timing[strings.TrimSpace(m[1])] = time.Duration(sec * float64(time.Second))
+	}
+	return timing
+}
+
+// RunResult runs a Java process on the text string as RunRaw, decodes its
+// output into msg as RunText, and returns the result of the process. On
+// failure, the result is also returned when the process ran, e.g. with the
+// exit code and the end of the standard error.
+//
+func (self *Cmd) RunResult(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) (*CmdResult, error) {
+	data, res, err := self.runFile(ctx, text)
+	if err != nil {
+		return res, err
+	}
+	return res, self.decode(data, msg)
+}
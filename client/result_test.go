@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestCmdRunResult(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fakejava")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	// imitates StanfordCoreNLP -file, logging its timing, and failing on an empty text
+	java, err := fakeJava(dir, `
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -file) file=$2; shift;;
+  esac
+  shift
+done
+if [ ! -s "$file" ]; then echo "no text" >&2; exit 3; fi
+cp "$(dirname "$0")/template" "$file.conll"
+echo "[main] INFO edu.stanford.nlp.pipeline.StanfordCoreNLP - Annotation pipeline timing information:" >&2
+echo "[main] INFO edu.stanford.nlp.pipeline.StanfordCoreNLP - TokenizerAnnotator: 0.1 sec." >&2
+echo "[main] INFO edu.stanford.nlp.pipeline.StanfordCoreNLP - TOTAL: 0.3 sec. for 2 tokens at 6.7 tokens/sec." >&2
+echo "[main] INFO edu.stanford.nlp.pipeline.StanfordCoreNLP - Pipeline setup: 1.5 sec." >&2
+`)
+	if err != nil { t.Fatal(err) }
+	conll := "1\tJohn\tJohn\tNNP\tPERSON\t2\tnsubj\n2\truns\trun\tVBZ\tO\t0\tROOT\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "template"), []byte(conll), 0666); err != nil { t.Fatal(err) }
+
+	out := filepath.Join(dir, "out")
+	os.Mkdir(out, 0755)
+	cmd := NewCmd([]string{"tokenize"}, "", "edu.stanford.nlp.pipeline.StanfordCoreNLP", java).With(WithOutputFormat(format.CoNLL), WithOutputDir(out))
+	doc := &nlp.Document{}
+	res, err := cmd.RunResult(context.Background(), []byte("John runs"), doc)
+	if err != nil { t.Fatal(err) }
+	if doc.Sentence[0].Token[1].GetLemma() != "run" || res.ExitCode != 0 || res.Elapsed <= 0 {
+		t.Errorf("%v %#v", doc, res)
+	}
+	if res.Timing["TokenizerAnnotator"] != 100*time.Millisecond || res.Timing["TOTAL"] != 300*time.Millisecond || res.Timing["Pipeline setup"] != 1500*time.Millisecond {
+		t.Errorf("%v", res.Timing)
+	}
+	if data, err := ioutil.ReadFile(res.Output); err != nil || string(data) != conll || filepath.Dir(res.Output) != out {
+		t.Errorf("%s %v", res.Output, err)
+	}
+
+	res, err = cmd.RunResult(context.Background(), nil, doc)
+	if err == nil || res == nil || res.ExitCode != 3 || !strings.Contains(res.Stderr, "no text") {
+		t.Errorf("%#v %v", res, err)
+	}
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// ErrDraining is returned by Submit once the Runner is draining or closed.
+//
+var ErrDraining = errors.New("runner is draining")
+
+// Item is a text to annotate by a Runner.
+//
+type Item struct {
+	// identifies the item in the Result, e.g. a file name
+	ID   string
+	Text []byte
+}
+
+// Result is the outcome of an Item.
+//
+type Result struct {
+	ID       string
+	Document *nlp.Document
+	Err      error
+}
+
+// Sink receives the results of a Runner. Write is never called concurrently,
+// and Flush is called once the Runner is drained.
+//
+type Sink interface {
+	Write(result *Result) error
+	Flush() error
+}
+
+// SinkFunc adapts a function to a Sink with nothing to flush.
+//
+type SinkFunc func(result *Result) error
+
+func (self SinkFunc) Write(result *Result) error {
+	return self(result)
+}
+
+func (self SinkFunc) Flush() error {
+	return nil
+}
+
+// Runner annotates the submitted items with a pool of workers and writes
+// the results to a sink, for batch jobs and streams running inside a service.
+//
+// Drain stops accepting items, finishes the queued and in-flight ones, and
+// flushes the sink; if its context is done first, the unfinished items are
+// cancelled and returned, so they can be checkpointed and resubmitted later.
+// It fits shutdown hooks such as http.Server.RegisterOnShutdown.
+//
+type Runner struct {
+	Client  Client
+	Sink    Sink
+	Workers int
+
+	once     sync.Once
+	mu       sync.RWMutex
+	closed   bool
+	items    chan *Item
+	stop     chan struct{}
+	draining sync.Once
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	// guards the sink, the errors and the unfinished items
+	smu        sync.Mutex
+	err        error
+	unfinished []*Item
+}
+
+// NewRunner creates a Runner sending the items to client and the results to sink.
+//
+// workers[0], optional: the number of concurrent requests, default 4.
+//
+func NewRunner(client Client, sink Sink, workers ...int) *Runner {
+	n := 4
+	if len(workers) > 0 && workers[0] > 0 {
+		n = workers[0]
+	}
+	return &Runner{Client: client, Sink: sink, Workers: n}
+}
+
+func (self *Runner) start() {
+	self.once.Do(func() {
+		n := self.Workers
+		if n < 1 {
+			n = 1
+		}
+		self.items = make(chan *Item, n)
+		self.stop = make(chan struct{})
+		self.ctx, self.cancel = context.WithCancel(context.Background())
+		for i := 0; i < n; i++ {
+			self.wg.Add(1)
+			go self.work()
+		}
+	})
+}
+
+// Submit queues the item, blocking while all the workers are busy.
+// It returns ErrDraining once Drain or Close was called.
+//
+func (self *Runner) Submit(ctx context.Context, item *Item) error {
+	self.start()
+	self.mu.RLock()
+	defer self.mu.RUnlock()
+	if self.closed {
+		return ErrDraining
+	}
+	select {
+	case self.items <- item:
+		return nil
+	case <-self.stop:
+		return ErrDraining
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (self *Runner) work() {
+	defer self.wg.Done()
+	for item := range self.items {
+		if self.ctx.Err() != nil {
+			self.checkpoint(item)
+			continue
+		}
+		doc := &nlp.Document{}
+		err := self.Client.RunText(self.ctx, item.Text, doc)
+		if err != nil && self.ctx.Err() != nil {
+			self.checkpoint(item)
+			continue
+		}
+		result := &Result{ID: item.ID, Err: err}
+		if err == nil {
+			result.Document = doc
+		}
+		self.write(result)
+	}
+}
+
+func (self *Runner) checkpoint(item *Item) {
+	self.smu.Lock()
+	defer self.smu.Unlock()
+	self.unfinished = append(self.unfinished, item)
+}
+
+func (self *Runner) write(result *Result) {
+	self.smu.Lock()
+	defer self.smu.Unlock()
+	if self.Sink == nil {
+		return
+	}
+	if err := self.Sink.Write(result); err != nil && self.err == nil {
+		self.err = err
+	}
+}
+
+// Drain stops accepting items and waits for the queued and in-flight ones,
+// then flushes the sink. If ctx is done first, the remaining items are
+// cancelled and returned unfinished, along with ctx.Err(). Otherwise the
+// error is the first one of the sink. Calling Drain again returns the same.
+//
+func (self *Runner) Drain(ctx context.Context) ([]*Item, error) {
+	self.start()
+	self.draining.Do(func() {
+		close(self.stop)
+		self.mu.Lock()
+		self.closed = true
+		close(self.items)
+		self.mu.Unlock()
+
+		done := make(chan struct{})
+		go func() {
+			self.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			self.cancel()
+			<-done
+			self.err = ctx.Err()
+		}
+		self.cancel()
+
+		self.smu.Lock()
+		defer self.smu.Unlock()
+		if self.Sink != nil {
+			if err := self.Sink.Flush(); err != nil && self.err == nil {
+				self.err = err
+			}
+		}
+	})
+	self.smu.Lock()
+	defer self.smu.Unlock()
+	return self.unfinished, self.err
+}
+
+// Close drains the Runner without a time limit.
+//
+func (self *Runner) Close() error {
+	_, err := self.Drain(context.Background())
+	return err
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type memorySink struct {
+	results []*Result
+	flushed bool
+}
+
+func (self *memorySink) Write(result *Result) error {
+	self.results = append(self.results, result)
+	return nil
+}
+
+func (self *memorySink) Flush() error {
+	self.flushed = true
+	return nil
+}
+
+func TestRunnerDrain(t *testing.T) {
+	c := funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+		if string(text) == "bad" {
+			return errors.New("failed")
+		}
+		msg.(*nlp.Document).Text = proto.String(string(text))
+		return nil
+	})
+	sink := &memorySink{}
+	r := NewRunner(c, sink, 2)
+	for _, text := range []string{"a", "bad", "c"} {
+		if err := r.Submit(context.Background(), &Item{ID: text, Text: []byte(text)}); err != nil { t.Fatal(err) }
+	}
+	unfinished, err := r.Drain(context.Background())
+	if err != nil || len(unfinished) != 0 { t.Fatal(unfinished, err) }
+	if len(sink.results) != 3 || !sink.flushed {
+		t.Fatalf("%d %v", len(sink.results), sink.flushed)
+	}
+	for _, result := range sink.results {
+		if (result.ID == "bad") != (result.Err != nil) || (result.Err == nil && result.Document.GetText() != result.ID) {
+			t.Errorf("%#v", result)
+		}
+	}
+	if err := r.Submit(context.Background(), &Item{ID: "late"}); err != ErrDraining {
+		t.Errorf("%v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestRunnerCheckpoint(t *testing.T) {
+	c := funcClient(func(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	sink := &memorySink{}
+	r := NewRunner(c, sink, 1)
+	for _, id := range []string{"a", "b"} {
+		if err := r.Submit(context.Background(), &Item{ID: id}); err != nil { t.Fatal(err) }
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	unfinished, err := r.Drain(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("%v", err)
+	}
+	if len(unfinished) != 2 || len(sink.results) != 0 || !sink.flushed {
+		t.Errorf("%d %d %v", len(unfinished), len(sink.results), sink.flushed)
+	}
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"fmt"
+	"mime"
+
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// protobufSerializer is the Java class writing the serialized protobuf.
+//
+const protobufSerializer = "edu.stanford.nlp.pipeline.ProtobufAnnotationSerializer"
+
+// Serializer decodes an output format of CoreNLP. Cmd and HttpClient ask
+// CoreNLP for its Format and pass the output to Unmarshal, so a new format,
+// or a decoder of one's own, needs no new client; see WithSerializer.
+//
+type Serializer interface {
+	// Format returns the output format requested from CoreNLP, one of the
+	// format constants, e.g. format.JSON
+	Format() string
+
+	// Unmarshal decodes the output of CoreNLP into msg
+	Unmarshal(data []byte, msg protoreflect.ProtoMessage) error
+}
+
+// The serializers of the output formats of CoreNLP. All but ProtobufSerializer
+// are parsed in Go, keep less data, and only decode into *nlp.Document.
+//
+var (
+	ProtobufSerializer Serializer = formatSerializer(format.Serialized)
+	JSONSerializer     Serializer = formatSerializer(format.JSON)
+	XMLSerializer      Serializer = formatSerializer(format.XML)
+	CoNLLSerializer    Serializer = formatSerializer(format.CoNLL)
+	CoNLLUSerializer   Serializer = formatSerializer(format.CoNLLU)
+)
+
+// SerializerOf returns the serializer of an output format, the protobuf
+// one for "". Formats that cannot be decoded, e.g. format.Text, get a
+// serializer whose Unmarshal fails.
+//
+func SerializerOf(f string) Serializer {
+	if f == "" {
+		return ProtobufSerializer
+	}
+	return formatSerializer(f)
+}
+
+type formatSerializer string
+
+func (self formatSerializer) Format() string {
+	return string(self)
+}
+
+func (self formatSerializer) Unmarshal(data []byte, msg protoreflect.ProtoMessage) error {
+	if self == format.Serialized {
+		return BytesUnmarshal(data, msg)
+	}
+	if _, ok := msg.(*nlp.Document); !ok {
+		return fmt.Errorf("output format %s needs *nlp.Document, got %T", string(self), msg)
+	}
+	doc, err := format.Parse(string(self), data)
+	if err != nil {
+		return err
+	}
+	proto.Reset(msg)
+	proto.Merge(msg, doc)
+	return nil
+}
+
+// outputProperties returns the server properties requesting the format of s.
+//
+func outputProperties(s Serializer) string {
+	if s.Format() == format.Serialized {
+		return `"outputFormat":"serialized","serializer":"` + protobufSerializer + `"`
+	}
+	return `"outputFormat":"` + s.Format() + `"`
+}
+
+// mediaTypes are the Content-Types of the output formats, as the CoreNLP
+// server sends them.
+//
+var mediaTypes = map[string]string{
+	format.Serialized: "application/x-protobuf",
+	format.JSON:       "application/json",
+	format.XML:        "application/xml",
+	format.CoNLL:      "text/plain",
+	format.CoNLLU:     "text/plain",
+	format.Text:       "text/plain",
+}
+
+// negotiate returns the serializer decoding a response of contentType to a
+// request for the format of s: s if the response is in that format or its
+// type does not tell, e.g. text/plain, otherwise the serializer of the format
+// the server returned, e.g. JSON from a server or proxy ignoring outputFormat.
+//
+func negotiate(s Serializer, contentType string) Serializer {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return s
+	}
+	var f string
+	switch mt {
+	case "application/x-protobuf", "application/octet-stream":
+		f = format.Serialized
+	case "application/json", "text/json":
+		f = format.JSON
+	case "application/xml", "text/xml":
+		f = format.XML
+	default:
+		return s
+	}
+	if f == s.Format() {
+		return s
+	}
+	return SerializerOf(f)
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// upperSerializer reads the text format as the text of a document, upper-cased.
+//
+type upperSerializer struct{}
+
+func (upperSerializer) Format() string { return format.Text }
+
+func (upperSerializer) Unmarshal(data []byte, msg protoreflect.ProtoMessage) error {
+	msg.(*nlp.Document).Text = proto.String(strings.ToUpper(string(data)))
+	return nil
+}
+
+func TestSerializer(t *testing.T) {
+	var properties string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		properties = r.URL.Query().Get("properties")
+		if strings.Contains(properties, `"json"`) {
+			w.Write([]byte(`{"sentences":[{"index":0,"tokens":[{"index":1,"word":"Hi","originalText":"Hi","characterOffsetBegin":0,"characterOffsetEnd":2,"pos":"UH"}]}]}`))
+			return
+		}
+		w.Write([]byte("hi"))
+	}))
+	defer ts.Close()
+
+	c := NewHttpClient([]string{"tokenize"}, ts.URL).With(WithSerializer(JSONSerializer))
+	doc := &nlp.Document{}
+	if err := c.RunText(context.Background(), []byte("Hi"), doc); err != nil { t.Fatal(err) }
+	if properties != `{"annotators":"tokenize","outputFormat":"json"}` || doc.GetText() != "Hi" || len(doc.Sentence) != 1 {
+		t.Errorf("%s %v", properties, doc)
+	}
+
+	c = c.With(WithSerializer(upperSerializer{}))
+	if err := c.RunText(context.Background(), []byte("hi"), doc); err != nil { t.Fatal(err) }
+	if properties != `{"annotators":"tokenize","outputFormat":"text"}` || doc.GetText() != "HI" {
+		t.Errorf("%s %v", properties, doc)
+	}
+
+	cmd := NewCmd([]string{"tokenize"}, "", "edu.stanford.nlp.pipeline.StanfordCoreNLP", "java").With(WithOutputSerializer(CoNLLUSerializer))
+	if args := strings.Join(cmd.arguments(), " "); !strings.HasSuffix(args, "-outputFormat conllu") {
+		t.Errorf("%s", args)
+	}
+	if SerializerOf("").Format() != format.Serialized || SerializerOf(format.Text).Unmarshal(nil, &nlp.Document{}) == nil {
+		t.Errorf("text should not decode")
+	}
+}
+
+func TestContentNegotiation(t *testing.T) {
+	var contentType, accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType, accept = r.Header.Get("Content-Type"), r.Header.Get("Accept")
+		// a server ignoring outputFormat
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(`{"sentences":[{"index":0,"tokens":[{"index":1,"word":"Hi","originalText":"Hi","characterOffsetBegin":0,"characterOffsetEnd":2,"pos":"UH"}]}]}`))
+	}))
+	defer ts.Close()
+
+	c := NewHttpClient([]string{"tokenize"}, ts.URL)
+	doc := &nlp.Document{}
+	if err := c.RunText(context.Background(), []byte("Hi"), doc); err != nil { t.Fatal(err) }
+	if contentType != DefaultContentType || accept != "application/x-protobuf" || len(doc.Sentence) != 1 {
+		t.Errorf("%s %s %v", contentType, accept, doc)
+	}
+
+	c = c.With(WithContentType("text/plain; charset=iso-8859-1"), WithSerializer(JSONSerializer))
+	if err := c.RunText(context.Background(), []byte("Hi"), doc); err != nil { t.Fatal(err) }
+	if contentType != "text/plain; charset=iso-8859-1" || accept != "application/json" {
+		t.Errorf("%s %s", contentType, accept)
+	}
+
+	for _, c := range []struct {
+		contentType string
+		want        string
+	}{
+		{"application/x-protobuf", format.Serialized},
+		{"text/xml; charset=utf-8", format.XML},
+		{"text/plain", format.CoNLLU},
+		{"", format.CoNLLU},
+	} {
+		if got := negotiate(CoNLLUSerializer, c.contentType).Format(); got != c.want {
+			t.Errorf("%s: %s", c.contentType, got)
+		}
+	}
+}
+
+func TestJSONRetry(t *testing.T) {
+	var outputs []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		props := r.URL.Query().Get("properties")
+		outputs = append(outputs, props)
+		// a proxy labelling every response as protobuf, passing garbage
+		// for the serialized output
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		if strings.Contains(props, `"outputFormat":"json"`) {
+			w.Write([]byte(`{"sentences":[{"index":0,"tokens":[{"index":1,"word":"Hi","originalText":"Hi","characterOffsetBegin":0,"characterOffsetEnd":2,"pos":"UH"}]}]}`))
+			return
+		}
+		w.Write([]byte{0xff, 0xff, 0xff})
+	}))
+	defer ts.Close()
+
+	var warnings []string
+	c := NewHttpClient([]string{"tokenize"}, ts.URL).With(WithWarnings(func(w string) { warnings = append(warnings, w) }))
+	doc := &nlp.Document{}
+	if err := c.RunText(context.Background(), []byte("Hi"), doc); err != nil { t.Fatal(err) }
+	if len(outputs) != 2 || len(doc.Sentence) != 1 || len(warnings) != 1 || !strings.Contains(warnings[0], "fell back to json") {
+		t.Errorf("%v %v %v", outputs, doc, warnings)
+	}
+
+	// no retry for other messages than a Document
+	outputs = nil
+	if err := c.RunText(context.Background(), []byte("Hi"), &nlp.Sentence{}); err == nil || len(outputs) != 1 {
+		t.Errorf("%v %v", err, outputs)
+	}
+
+	// the client, and those derived from it, keep to json
+	outputs = nil
+	for _, client := range []*HttpClient{c, c.With(WithAnnotators("tokenize", "pos"))} {
+		if err := client.RunText(context.Background(), []byte("Hi"), &nlp.Document{}); err != nil { t.Fatal(err) }
+	}
+	if len(outputs) != 2 || !strings.Contains(outputs[0], `"outputFormat":"json"`) || !strings.Contains(outputs[1], `"outputFormat":"json"`) || len(warnings) != 1 {
+		t.Errorf("%v %v", outputs, warnings)
+	}
+
+	// the retry drops the layers of the serializer
+	outputs, warnings = nil, nil
+	c = NewHttpClient([]string{"tokenize", "pos"}, ts.URL).With(WithSerializer(WithoutLayers(ProtobufSerializer, "pos")), WithWarnings(func(w string) { warnings = append(warnings, w) }))
+	doc = &nlp.Document{}
+	if err := c.RunText(context.Background(), []byte("Hi"), doc); err != nil { t.Fatal(err) }
+	if len(outputs) != 2 || len(warnings) != 1 || len(doc.Sentence) != 1 || doc.Sentence[0].Token[0].Pos != nil {
+		t.Errorf("%v %v %v", outputs, doc, warnings)
+	}
+}
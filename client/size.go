@@ -0,0 +1,100 @@
+package client
+
+import (
+	"reflect"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// LayerAnnotators are the annotators whose layers DropLayers can remove, by
+// which DocumentSize breaks the memory down.
+//
+var LayerAnnotators = []string{"pos", "lemma", "ner", "parse", "depparse", "sentiment", "natlog", "openie", "kbp", "coref", "quote", "relation"}
+
+// DocumentSize estimates the memory a decoded document retains.
+//
+type DocumentSize struct {
+	// estimated bytes of the whole document
+	Total int64
+
+	// estimated bytes DropLayers would free for each of LayerAnnotators,
+	// e.g. Layers["parse"]; the layers absent from the document are left out.
+	// "ner" and "coref" both count the mappings between their mentions, so
+	// the sum can exceed what dropping all the layers frees.
+	Layers map[string]int64
+}
+
+// SizeOf estimates the memory retained by doc, in total and by layer, from
+// the Go structs of its messages, the contents of its strings and lists, and
+// the boxes of the optional scalars. Allocator overhead is not counted. It
+// copies the document once per layer, so it is meant for capacity planning
+// on samples, not for every document.
+//
+func SizeOf(doc *nlp.Document) *DocumentSize {
+	size := &DocumentSize{Total: messageSize(doc.ProtoReflect()), Layers: make(map[string]int64)}
+	for _, a := range LayerAnnotators {
+		trimmed := proto.Clone(doc).(*nlp.Document)
+		DropLayers(trimmed, a)
+		if freed := size.Total - messageSize(trimmed.ProtoReflect()); freed > 0 {
+			size.Layers[a] = freed
+		}
+	}
+	return size
+}
+
+// TrimLayers drops the layers of annotators from doc, in order, until its
+// estimated size is at most limit bytes, and returns the annotators dropped.
+// It stops at the end of annotators even if doc is still over the limit.
+//
+func TrimLayers(doc *nlp.Document, limit int64, annotators ...string) []string {
+	var dropped []string
+	for _, a := range annotators {
+		if messageSize(doc.ProtoReflect()) <= limit {
+			break
+		}
+		DropLayers(doc, a)
+		dropped = append(dropped, a)
+	}
+	return dropped
+}
+
+// messageSize estimates the bytes retained by m and its fields.
+//
+func messageSize(m protoreflect.Message) int64 {
+	size := int64(reflect.TypeOf(m.Interface()).Elem().Size())
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				size += valueSize(fd, list.Get(i))
+			}
+		case fd.IsMap():
+			v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				size += valueSize(fd.MapKey(), k.Value()) + valueSize(fd.MapValue(), v)
+				return true
+			})
+		default:
+			// an optional scalar is boxed behind the pointer of its field
+			size += valueSize(fd, v)
+		}
+		return true
+	})
+	return size
+}
+
+// valueSize estimates the bytes of a value outside its field or list slot.
+//
+func valueSize(fd protoreflect.FieldDescriptor, v protoreflect.Value) int64 {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return 8 + messageSize(v.Message())
+	case protoreflect.StringKind:
+		return 16 + int64(len(v.String()))
+	case protoreflect.BytesKind:
+		return 24 + int64(len(v.Bytes()))
+	}
+	return 8
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSizeOf(t *testing.T) {
+	doc := &nlp.Document{Text: proto.String("John runs"), Sentence: []*nlp.Sentence{{
+		Token:             []*nlp.Token{{Word: proto.String("John"), Pos: proto.String("NNP")}, {Word: proto.String("runs"), Pos: proto.String("VBZ")}},
+		ParseTree:         &nlp.ParseTree{Value: proto.String("ROOT"), Child: []*nlp.ParseTree{{Value: proto.String("S")}}},
+		BasicDependencies: &nlp.DependencyGraph{Root: []uint32{2}},
+	}}}
+
+	size := SizeOf(doc)
+	if size.Total <= 0 || size.Layers["parse"] <= 0 || size.Layers["pos"] != 2*(16+3) || size.Layers["ner"] != 0 {
+		t.Errorf("%#v", size)
+	}
+	if len(size.Layers) != 3 {
+		t.Errorf("%v", size.Layers)
+	}
+
+	dropped := TrimLayers(doc, size.Total-size.Layers["parse"], "parse", "depparse", "pos")
+	if !reflect.DeepEqual(dropped, []string{"parse"}) || doc.Sentence[0].ParseTree != nil || SizeOf(doc).Total != size.Total-size.Layers["parse"] {
+		t.Errorf("%v", dropped)
+	}
+}
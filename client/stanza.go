@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+)
+
+// StanzaAnnotators are the default annotators of the CoreNLPClient of Stanza.
+//
+var StanzaAnnotators = []string{"tokenize", "ssplit", "lemma", "pos", "ner", "depparse"}
+
+// ProbeCandidates are the annotators ProbeAnnotators tries by default, in
+// pipeline order.
+//
+var ProbeCandidates = []string{"tokenize", "ssplit", "pos", "lemma", "ner", "parse", "depparse", "sentiment", "natlog", "openie", "coref", "kbp", "quote"}
+
+// probeText is the text posted by ProbeAnnotators.
+//
+const probeText = "Stanford University is in California."
+
+// NewStanzaClient creates a HttpClient for a CoreNLP server started by the
+// CoreNLPClient of Stanza, with its defaults, see WithStanza.
+//
+// annotators: the annotators, nil for StanzaAnnotators
+//
+// args[0], optional: the URL of the server, default http://127.0.0.1:9000
+//
+// args[1] and args[2], optional: the username and password of the server
+//
+func NewStanzaClient(annotators []string, args ...string) *HttpClient {
+	if annotators == nil {
+		annotators = StanzaAnnotators
+	}
+	client := NewHttpClient(annotators, args...).With(WithStanza())
+	if len(args) > 2 {
+		client.Username, client.Password = args[1], args[2]
+	}
+	return client
+}
+
+// WithStanza makes the requests as the CoreNLPClient of Stanza does: the
+// text is declared with inputFormat, and the default properties of the
+// server, which Stanza sets at its start, e.g. with a language, are kept.
+//
+func WithStanza() HttpOption {
+	return func(self *HttpClient) {
+		props := make(map[string]string, len(self.Properties)+1)
+		for k, v := range self.Properties {
+			props[k] = v
+		}
+		props["inputFormat"] = "text"
+		self.Properties = props
+		self.ResetDefault = false
+	}
+}
+
+// WithProperties adds props to the properties sent with every request,
+// e.g. "ner.applyFineGrained": "false". The client keeps its own copy.
+// CoreNLP ignores the keys it does not know; see CheckProperties.
+//
+func WithProperties(props map[string]string) HttpOption {
+	return func(self *HttpClient) {
+		merged := make(map[string]string, len(self.Properties)+len(props))
+		for k, v := range self.Properties {
+			merged[k] = v
+		}
+		for k, v := range props {
+			merged[k] = v
+		}
+		self.Properties = merged
+	}
+}
+
+// WithLanguage sets the language of the pipeline, e.g. "chinese", on a
+// server holding the models of several languages.
+//
+func WithLanguage(language string) HttpOption {
+	return func(self *HttpClient) {
+		self.Language = language
+	}
+}
+
+// WithBasicAuth sets the credentials of a server protected by a username and
+// a password, as Stanza can start it.
+//
+func WithBasicAuth(username, password string) HttpOption {
+	return func(self *HttpClient) {
+		self.Username = username
+		self.Password = password
+	}
+}
+
+// ProbeAnnotators asks the server which of candidates it supports, by default
+// ProbeCandidates, and returns them in order. The candidates are tried one
+// by one on a short text, each added to those already supported, so they
+// must come in pipeline order; an annotator whose models are missing, or
+// which needs an unsupported one, makes the server fail and is left out.
+// It returns an error when the server cannot be reached.
+//
+func (self *HttpClient) ProbeAnnotators(ctx context.Context, candidates ...string) ([]string, error) {
+	if len(candidates) == 0 {
+		candidates = ProbeCandidates
+	}
+	var supported []string
+	for _, candidate := range candidates {
+		probe := self.With()
+		probe.Annotators = append(append([]string{}, supported...), candidate)
+		_, _, err := probe.post(ctx, []byte(probeText), JSONSerializer)
+		var serverErr *ServerError
+		if errors.As(err, &serverErr) {
+			continue
+		} else if err != nil {
+			return supported, err
+		}
+		supported = append(supported, candidate)
+	}
+	return supported, nil
+}
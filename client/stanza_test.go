@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStanzaClient(t *testing.T) {
+	var query map[string][]string
+	var user, pass string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		user, pass, _ = r.BasicAuth()
+		w.Write([]byte(`{"sentences":[]}`))
+	}))
+	defer ts.Close()
+
+	c := NewStanzaClient(nil, ts.URL, "alice", "secret").With(WithLanguage("german"), WithProperties(map[string]string{"ner.applyFineGrained": "false"}))
+	if _, err := c.RunTextJSON(context.Background(), []byte("Hallo")); err != nil { t.Fatal(err) }
+	if got := query["properties"][0]; got != `{"annotators":"tokenize,ssplit,lemma,pos,ner,depparse","outputFormat":"json","inputFormat":"text","ner.applyFineGrained":"false"}` {
+		t.Errorf("%s", got)
+	}
+	if query["pipelineLanguage"][0] != "german" || query["resetDefault"] != nil {
+		t.Errorf("%v", query)
+	}
+	if user != "alice" || pass != "secret" {
+		t.Errorf("%s %s", user, pass)
+	}
+}
+
+func TestProbeAnnotators(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var props map[string]string
+		json.Unmarshal([]byte(r.URL.Query().Get("properties")), &props)
+		annotators := "," + props["annotators"] + ","
+		if strings.Contains(annotators, ",parse,") || strings.Contains(annotators, ",coref,") {
+			http.Error(w, "no models", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"sentences":[]}`))
+	}))
+	defer ts.Close()
+
+	c := NewStanzaClient(nil, ts.URL)
+	got, err := c.ProbeAnnotators(context.Background(), "tokenize", "ssplit", "pos", "parse", "depparse", "coref")
+	if err != nil { t.Fatal(err) }
+	if !reflect.DeepEqual(got, []string{"tokenize", "ssplit", "pos", "depparse"}) {
+		t.Errorf("%v", got)
+	}
+}
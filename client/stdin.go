@@ -0,0 +1,36 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// stdinFile and stdoutFile are the input and the output files of CoreNLP in
+// the stdin mode.
+//
+const (
+	stdinFile  = "/dev/stdin"
+	stdoutFile = "/dev/stdout"
+)
+
+// runStdin pipes text into a single run of CoreNLP on /dev/stdin, whose
+// serializer writes to /dev/stdout, and decodes the output captured by pipe
+// into msg, so that nothing is written to the filesystem.
+//
+func (self *Cmd) runStdin(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	if runtime.GOOS == "windows" {
+		return errors.New("stdin mode needs " + stdinFile)
+	}
+
+	args := self.arguments("-file", stdinFile, "-outputFile", stdoutFile)
+	data, res, err := self.pipe(ctx, args, bytes.NewReader(text))
+	if err != nil {
+		return err
+	}
+	self.record(1, int64(len(text)), res.Elapsed)
+	return self.decode(data, msg)
+}
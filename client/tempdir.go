@@ -0,0 +1,200 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTempQuota is returned when a job would exceed the quota of its TempRoot.
+//
+var ErrTempQuota = errors.New("temporary directory quota exceeded")
+
+// tempPrefix starts the names of the directories of TempRoot, followed by the
+// pid and the start time of their owner, e.g. "coreNLP-1234-1650000000000-567890".
+// The time tells a previous process with the same pid apart, as in containers.
+//
+const tempPrefix = "coreNLP-"
+
+// ownerLock is the file in every directory of TempRoot whose lock the owner
+// holds while the directory is in use, see Sweep.
+//
+const ownerLock = ".owner"
+
+var processStart = strconv.FormatInt(time.Now().UnixNano(), 10)
+
+// TempRoot holds the working directories of the Cmd runs under a directory
+// chosen by the caller, with a quota on their total size, and removes those
+// left behind by crashed processes. It is safe for concurrent use, and meant
+// to be shared by the commands of a server, see WithTempRoot.
+//
+// The quota is advisory: it bounds the sizes the jobs reserve, estimated from
+// their input by Expansion, not the bytes CoreNLP actually writes.
+//
+type TempRoot struct {
+	Dir string
+
+	// maximal total size in bytes reserved by the directories in use, 0 for
+	// no limit
+	Quota int64
+
+	// the size reserved by a job is its input times Expansion, as the output
+	// is larger than the text, by up to 20 times for the serialized protobuf
+	Expansion int64
+
+	// directories older than MaxAge are stale even if their owner process
+	// still runs, e.g. after the pid was reused, unless a job still holds
+	// them; 0 for no limit
+	MaxAge time.Duration
+
+	mu   sync.Mutex
+	used int64
+}
+
+// NewTempRoot creates the root directory if needed, and sweeps the directories
+// left by dead processes.
+//
+// quota[0], optional: the quota in bytes, default no limit.
+//
+func NewTempRoot(dir string, quota ...int64) (*TempRoot, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	root := &TempRoot{Dir: dir, Expansion: 20, MaxAge: 24 * time.Hour}
+	if len(quota) > 0 {
+		root.Quota = quota[0]
+	}
+	if _, err := root.Sweep(); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// TempDir is a working directory created by TempRoot.Create.
+//
+type TempDir struct {
+	Path string
+
+	root *TempRoot
+	size int64
+	lock *os.File
+	once sync.Once
+}
+
+// Create reserves size bytes of the quota, times Expansion, and creates a
+// directory for them, locked against Sweep. It returns ErrTempQuota if the
+// reservation does not fit. The directory must be removed with Remove.
+//
+func (self *TempRoot) Create(size int64) (*TempDir, error) {
+	if self.Expansion > 0 {
+		size *= self.Expansion
+	}
+	self.mu.Lock()
+	if self.Quota > 0 && self.used+size > self.Quota {
+		self.mu.Unlock()
+		return nil, fmt.Errorf("%w: %d bytes in use, %d needed, quota %d", ErrTempQuota, self.used, size, self.Quota)
+	}
+	self.used += size
+	self.mu.Unlock()
+
+	dir, err := ioutil.TempDir(self.Dir, tempPrefix+strconv.Itoa(os.Getpid())+"-"+processStart+"-")
+	if err != nil {
+		self.release(size)
+		return nil, err
+	}
+	lock, err := lockOwner(filepath.Join(dir, ownerLock))
+	if err != nil {
+		os.RemoveAll(dir)
+		self.release(size)
+		return nil, err
+	}
+	return &TempDir{Path: dir, root: self, size: size, lock: lock}, nil
+}
+
+func (self *TempRoot) release(size int64) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.used -= size
+}
+
+// Used returns the bytes reserved by the directories in use.
+//
+func (self *TempRoot) Used() int64 {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.used
+}
+
+// Remove removes the directory and gives its reservation back.
+// Calling it again does nothing.
+//
+func (self *TempDir) Remove() error {
+	var err error
+	self.once.Do(func() {
+		self.lock.Close()
+		err = os.RemoveAll(self.Path)
+		self.root.release(self.size)
+	})
+	return err
+}
+
+// Sweep removes the directories whose owner process is dead, or which are
+// older than MaxAge, and returns how many it removed. A directory whose owner
+// lock is held, by a job of any process, is kept. Running it periodically
+// cleans up after crashes of the processes sharing the root.
+//
+func (self *TempRoot) Sweep() (int, error) {
+	infos, err := ioutil.ReadDir(self.Dir)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, info := range infos {
+		if !info.IsDir() || !strings.HasPrefix(info.Name(), tempPrefix) {
+			continue
+		}
+		fields := strings.SplitN(strings.TrimPrefix(info.Name(), tempPrefix), "-", 3)
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil || len(fields) < 3 {
+			continue
+		}
+		stale := self.MaxAge > 0 && time.Since(info.ModTime()) > self.MaxAge
+		if pid == os.Getpid() {
+			stale = stale || fields[1] != processStart
+		} else {
+			stale = stale || !processAlive(pid)
+		}
+		if stale && !ownerLocked(filepath.Join(self.Dir, info.Name(), ownerLock)) {
+			if err := os.RemoveAll(filepath.Join(self.Dir, info.Name())); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// tempDir creates a working directory for a job of size bytes, under
+// TempRoot if set, or the system temporary directory otherwise. The returned
+// function removes it.
+//
+func (self *Cmd) tempDir(size int64) (string, func(), error) {
+	if self.TempRoot != nil {
+		d, err := self.TempRoot.Create(size)
+		if err != nil {
+			return "", nil, err
+		}
+		return d.Path, func() { d.Remove() }, nil
+	}
+	dir, err := ioutil.TempDir("", "coreNLP")
+	if err != nil {
+		return "", nil, err
+	}
+	return dir, func() { os.RemoveAll(dir) }, nil
+}
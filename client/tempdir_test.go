@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTempRoot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "root")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	pid := strconv.Itoa(os.Getpid())
+	for _, name := range []string{"coreNLP-999999999-1-1", "coreNLP-" + pid + "-1-1", "keep", "coreNLP-" + pid + "-" + processStart + "-1"} {
+		os.Mkdir(filepath.Join(dir, name), 0700)
+	}
+	root, err := NewTempRoot(dir, 1000)
+	if err != nil { t.Fatal(err) }
+	infos, _ := ioutil.ReadDir(dir)
+	if len(infos) != 2 || infos[0].Name() != "coreNLP-"+pid+"-"+processStart+"-1" || infos[1].Name() != "keep" {
+		t.Errorf("%v", infos)
+	}
+
+	d, err := root.Create(30)
+	if err != nil { t.Fatal(err) }
+	if root.Used() != 600 {
+		t.Errorf("%d", root.Used())
+	}
+	if _, err = root.Create(30); !errors.Is(err, ErrTempQuota) {
+		t.Errorf("%v", err)
+	}
+	d.Remove()
+	d.Remove()
+	if _, err := os.Stat(d.Path); !os.IsNotExist(err) || root.Used() != 0 {
+		t.Errorf("%v %d", err, root.Used())
+	}
+
+	// a Cmd run takes its directory from the root, and gives it back
+	java, err := fakeJava(filepath.Join(dir, "keep"), `
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -file) file=$2; shift;;
+  esac
+  shift
+done
+cp "$file" "$file.ser.gz"
+`)
+	if err != nil { t.Fatal(err) }
+	cmd := NewCmd([]string{"tokenize"}, "", "edu.stanford.nlp.pipeline.StanfordCoreNLP", java).With(WithTempRoot(root))
+	out, err := cmd.RunRaw(context.Background(), []byte("Hi"))
+	if err != nil || string(out) != "Hi" { t.Fatal(string(out), err) }
+	if _, err = cmd.RunRaw(context.Background(), make([]byte, 100)); !errors.Is(err, ErrTempQuota) {
+		t.Errorf("%v", err)
+	}
+	infos, _ = ioutil.ReadDir(dir)
+	if len(infos) != 2 || root.Used() != 0 {
+		t.Errorf("%v %d", infos, root.Used())
+	}
+}
+
+func TestTempRootLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "root")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+	root, err := NewTempRoot(dir)
+	if err != nil { t.Fatal(err) }
+
+	live, err := root.Create(1)
+	if err != nil { t.Fatal(err) }
+	defer live.Remove()
+	done, err := root.Create(1)
+	if err != nil { t.Fatal(err) }
+	// a directory left with its lock released, as by a job whose process died
+	done.lock.Close()
+
+	old := time.Now().Add(-time.Hour)
+	for _, d := range []*TempDir{live, done} {
+		os.Chtimes(d.Path, old, old)
+	}
+	root.MaxAge = time.Minute
+	if n, err := root.Sweep(); n != 1 || err != nil {
+		t.Errorf("%d %v", n, err)
+	}
+	if _, err := os.Stat(live.Path); err != nil {
+		t.Errorf("%v", err)
+	}
+	if _, err := os.Stat(done.Path); !os.IsNotExist(err) {
+		t.Errorf("%v", err)
+	}
+}
@@ -1,17 +1,37 @@
 package client
 
 import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/genelet/corenlp-golang/format"
+	"google.golang.org/protobuf/encoding/protowire"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/encoding/protowire"
 )
 
-// BytesUnmarshal unmarshals coreNLP protobuf data
+// BytesUnmarshal unmarshals coreNLP protobuf data, a message delimited by
+// its varint length as ProtobufAnnotationSerializer writes it. Data that is
+// empty, over format.MaxSize bytes, truncated or malformed fails with
+// *format.ParseError; a truncated message also matches io.ErrUnexpectedEOF.
 //
 func BytesUnmarshal(data []byte, msg protoreflect.ProtoMessage) error {
-	bs, n := protowire.ConsumeBytes(data)
+	if len(data) == 0 {
+		return &format.ParseError{Format: format.Serialized, Err: errors.New("empty data")}
+	}
+	if int64(len(data)) > format.MaxSize {
+		return &format.ParseError{Format: format.Serialized, Err: fmt.Errorf("%d bytes, over the limit of %d", len(data), format.MaxSize)}
+	}
+	size, n := protowire.ConsumeVarint(data)
 	if n < 0 {
-		return protowire.ParseError(n)
+		return &format.ParseError{Format: format.Serialized, Err: fmt.Errorf("length prefix: %w", protowire.ParseError(n))}
+	}
+	if size > uint64(len(data)-n) {
+		return &format.ParseError{Format: format.Serialized, Err: fmt.Errorf("message of %d bytes, %d present: %w", size, len(data)-n, io.ErrUnexpectedEOF)}
+	}
+	if err := proto.Unmarshal(data[n:n+int(size)], msg); err != nil {
+		return &format.ParseError{Format: format.Serialized, Err: err}
 	}
-	return proto.Unmarshal(bs, msg)
+	return nil
 }
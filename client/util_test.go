@@ -0,0 +1,41 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestBytesUnmarshal(t *testing.T) {
+	data := serialize(completeWords("John runs .", "NNP VBZ ."))
+	doc := &nlp.Document{}
+	if err := BytesUnmarshal(data, doc); err != nil { t.Fatal(err) }
+	if len(doc.Sentence) != 1 || len(doc.Sentence[0].Token) != 3 {
+		t.Errorf("%v", doc)
+	}
+
+	for name, bad := range map[string][]byte{
+		"empty":     nil,
+		"prefix":    {0xff, 0xff, 0xff},
+		"truncated": data[:len(data)-2],
+		"malformed": {3, 0x0a, 0xff, 0xff},
+	} {
+		err := BytesUnmarshal(bad, &nlp.Document{})
+		var pe *format.ParseError
+		if !errors.As(err, &pe) || pe.Format != format.Serialized {
+			t.Errorf("%s: %v", name, err)
+		}
+		if name == "truncated" && !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	defer func(max int64) { format.MaxSize = max }(format.MaxSize)
+	format.MaxSize = int64(len(data) - 1)
+	if err := BytesUnmarshal(data, doc); err == nil {
+		t.Errorf("over the limit: decoded")
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BuildCmd creates a Cmd from options instead of positional arguments, and
+// validates it, so a wrong setup fails here rather than at the first Run.
+// Without options, the classpath is "*", the class StanfordCoreNLP and the
+// Java command the one found by DetectJava.
+//
+// For example:
+// cmd, err := BuildCmd([]string{"tokenize","ssplit","pos"}, WithClassPath("/home/user/standford/*"), WithMemory("4g"))
+//
+func BuildCmd(annotators []string, opts ...CmdOption) (*Cmd, error) {
+	c := NewCmd(annotators).With(opts...)
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate checks that the annotator list is not empty, that every entry of
+// the classpath exists, a "dir/*" entry holding jar files, and that the Java
+// command is executable. It returns *ConfigError or *CommandError.
+// CheckJava also checks the version of the Java command.
+//
+func (self *Cmd) Validate() error {
+	if len(self.Annotators) == 0 {
+		return &ConfigError{Field: "Annotators", Err: errors.New("no annotator")}
+	}
+	for _, a := range self.Annotators {
+		if strings.TrimSpace(a) == "" || strings.Contains(a, ",") {
+			return &ConfigError{Field: "Annotators", Err: fmt.Errorf("bad annotator %q", a)}
+		}
+	}
+	if self.Class == "" {
+		return &ConfigError{Field: "Class", Err: errors.New("no class")}
+	}
+	if err := checkClassPath(self.ClassPath); err != nil {
+		return &ConfigError{Field: "ClassPath", Err: err}
+	}
+	_, err := self.java()
+	return err
+}
+
+func checkClassPath(cp string) error {
+	if cp == "" {
+		return errors.New("empty classpath")
+	}
+	for _, entry := range splitClassPath(cp) {
+		if filepath.Base(entry) != "*" {
+			if _, err := os.Stat(entry); err != nil {
+				return err
+			}
+			continue
+		}
+		jars, err := filepath.Glob(filepath.Join(filepath.Dir(entry), "*.jar"))
+		if err != nil {
+			return err
+		}
+		if len(jars) == 0 {
+			return fmt.Errorf("no jar file in %s", filepath.Dir(entry))
+		}
+	}
+	return nil
+}
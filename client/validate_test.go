@@ -0,0 +1,44 @@
+package client
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "build")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+	java, err := fakeJava(dir, "exit 0")
+	if err != nil { t.Fatal(err) }
+	ioutil.WriteFile(filepath.Join(dir, "corenlp.jar"), nil, 0666)
+	cp := filepath.Join(dir, "*")
+
+	c, err := BuildCmd([]string{"tokenize", "ssplit"}, WithClassPath(cp), WithJava(java), WithMemory("2g"))
+	if err != nil { t.Fatal(err) }
+	if c.ClassPath != cp || c.Class != "edu.stanford.nlp.pipeline.StanfordCoreNLP" || c.Memory != "2g" {
+		t.Errorf("%#v", c)
+	}
+
+	var ce *ConfigError
+	if _, err = BuildCmd(nil, WithClassPath(cp), WithJava(java)); !errors.As(err, &ce) || ce.Field != "Annotators" {
+		t.Errorf("%v", err)
+	}
+	if _, err = BuildCmd([]string{"tokenize,ssplit"}, WithClassPath(cp), WithJava(java)); !errors.As(err, &ce) || ce.Field != "Annotators" {
+		t.Errorf("%v", err)
+	}
+	if _, err = BuildCmd([]string{"tokenize"}, WithClassPath(filepath.Join(dir, "missing", "*")), WithJava(java)); !errors.As(err, &ce) || ce.Field != "ClassPath" {
+		t.Errorf("%v", err)
+	}
+	if _, err = BuildCmd([]string{"tokenize"}, WithClassPath(JoinClassPath(cp, filepath.Join(dir, "missing.jar"))), WithJava(java)); !errors.As(err, &ce) || ce.Field != "ClassPath" {
+		t.Errorf("%v", err)
+	}
+
+	var cmdErr *CommandError
+	if _, err = BuildCmd([]string{"tokenize"}, WithClassPath(cp), WithJava(filepath.Join(dir, "corenlp.jar"))); !errors.As(err, &cmdErr) {
+		t.Errorf("%v", err)
+	}
+}
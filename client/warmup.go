@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// WarmupText is the tiny document sent by Warmup. It should exercise every
+// annotator, so it has a named entity, a pronoun and two sentences.
+//
+var WarmupText = []byte("Stanford University is located in California. It is a great university, founded in 1891.")
+
+// Warmup sends WarmupText through c, so the server loads all the models
+// of the configured annotators before real traffic arrives.
+//
+func Warmup(ctx context.Context, c Client) error {
+	return c.RunText(ctx, WarmupText, &nlp.Document{})
+}
+
+// Warmup sends a tiny document through the configured annotators.
+//
+func (self *HttpClient) Warmup(ctx context.Context) error {
+	return Warmup(ctx, self)
+}
+
+// Warmup sends a tiny document through the configured annotators. In the
+// persistent mode, see Start, it goes to the server, whose models Start has
+// loaded already, and warms what the annotators load lazily; otherwise it
+// mostly validates the configuration, since every Run starts a new process.
+//
+func (self *Cmd) Warmup(ctx context.Context) error {
+	return Warmup(ctx, self)
+}
+
+// Warmup warms all the backends in parallel, including evicted ones.
+// It returns an error listing the backends that failed.
+//
+func (self *LoadBalancedClient) Warmup(ctx context.Context) error {
+	self.mu.Lock()
+	backends := append([]*backend{}, self.backends...)
+	self.mu.Unlock()
+
+	errs := make([]error, len(backends))
+	var wg sync.WaitGroup
+	for i, b := range backends {
+		wg.Add(1)
+		go func(i int, b *backend) {
+			defer wg.Done()
+			errs[i] = b.client.Warmup(ctx)
+		}(i, b)
+	}
+	wg.Wait()
+
+	var msgs []string
+	for i, err := range errs {
+		if err != nil {
+			msgs = append(msgs, backends[i].client.URL+": "+err.Error())
+		}
+	}
+	if msgs != nil {
+		return fmt.Errorf("warmup failed on %d of %d backends: %s", len(msgs), len(backends), strings.Join(msgs, "; "))
+	}
+	return nil
+}
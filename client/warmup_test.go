@@ -0,0 +1,26 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWarmup(t *testing.T) {
+	a, b := newFakeServer(), newFakeServer()
+	defer a.Close()
+	defer b.Close()
+
+	lb := NewLoadBalancedClient([]string{"tokenize", "ner"}, a.URL, b.URL)
+	if err := lb.Warmup(context.Background()); err != nil { t.Fatal(err) }
+	if a.count() != 1 || b.count() != 1 {
+		t.Errorf("%d %d", a.count(), b.count())
+	}
+
+	b.setStatus(http.StatusInternalServerError)
+	err := lb.Warmup(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "1 of 2") || !strings.Contains(err.Error(), b.URL) {
+		t.Errorf("%v", err)
+	}
+}
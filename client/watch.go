@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Watcher monitors a directory where CoreNLP, run by someone else, e.g. a cron
+// job, writes its serialized outputs, and sends the documents to a sink as the
+// files appear. The directory is polled, and a file is read once its size and
+// modification time stay the same over a poll, as CoreNLP may still be writing it.
+//
+type Watcher struct {
+	Dir  string
+	Sink Sink
+
+	// the files to read, as a filepath.Match pattern, default "*.ser.gz"
+	Pattern string
+
+	// time between two polls, default a second
+	Interval time.Duration
+
+	// remove the files once decoded
+	Remove bool
+
+	seen map[string]*watched
+}
+
+type watched struct {
+	size int64
+	mod  time.Time
+	done bool
+}
+
+// NewWatcher creates a Watcher of dir sending the documents to sink.
+//
+// interval[0], optional: the time between two polls, default a second.
+//
+func NewWatcher(dir string, sink Sink, interval ...time.Duration) *Watcher {
+	w := &Watcher{Dir: dir, Sink: sink, Pattern: "*.ser.gz", Interval: time.Second}
+	if len(interval) > 0 && interval[0] > 0 {
+		w.Interval = interval[0]
+	}
+	return w
+}
+
+// Watch polls the directory until ctx is done, then flushes the sink and
+// returns ctx.Err(), or the first error of the directory or the sink.
+//
+func (self *Watcher) Watch(ctx context.Context) error {
+	ticker := time.NewTicker(self.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := self.Poll(); err != nil {
+			self.Sink.Flush()
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			if err := self.Sink.Flush(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll scans the directory once, sends the files found complete to the sink,
+// in name order, and returns how many it sent. A file that cannot be decoded
+// is sent as a Result with Err; its ID is the path of the file.
+//
+func (self *Watcher) Poll() (int, error) {
+	if self.seen == nil {
+		self.seen = make(map[string]*watched)
+	}
+	pattern := self.Pattern
+	if pattern == "" {
+		pattern = "*.ser.gz"
+	}
+	paths, err := filepath.Glob(filepath.Join(self.Dir, pattern))
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(paths)
+
+	present := make(map[string]bool, len(paths))
+	sent := 0
+	for _, path := range paths {
+		present[path] = true
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		w := self.seen[path]
+		if w == nil || w.size != info.Size() || !w.mod.Equal(info.ModTime()) {
+			// new or still growing, read at the next poll
+			self.seen[path] = &watched{size: info.Size(), mod: info.ModTime()}
+			continue
+		}
+		if w.done {
+			continue
+		}
+		w.done = true
+
+		result := &Result{ID: path}
+		doc := &nlp.Document{}
+		if result.Err = readSerialized(path, doc); result.Err == nil {
+			result.Document = doc
+		}
+		if err := self.Sink.Write(result); err != nil {
+			return sent, err
+		}
+		sent++
+		if self.Remove && result.Err == nil {
+			os.Remove(path)
+		}
+	}
+	for path := range self.seen {
+		if !present[path] {
+			delete(self.seen, path)
+		}
+	}
+	return sent, nil
+}
+
+// readSerialized decodes a file written by ProtobufAnnotationSerializer,
+// gunzipping it first if it is compressed, within format.MaxSize bytes.
+//
+func readSerialized(path string, doc *nlp.Document) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if format.IsGzip(data) {
+		if data, err = format.Gunzip(data); err != nil {
+			return err
+		}
+	}
+	return BytesUnmarshal(data, doc)
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	sink := &memorySink{}
+	w := NewWatcher(dir, sink)
+	w.Remove = true
+
+	data := serialize(&nlp.Document{Text: proto.String("Hi")})
+	ioutil.WriteFile(filepath.Join(dir, "a.txt.ser.gz"), data, 0644)
+	var zipped bytes.Buffer
+	zw := gzip.NewWriter(&zipped)
+	zw.Write(data)
+	zw.Close()
+	ioutil.WriteFile(filepath.Join(dir, "b.txt.ser.gz"), zipped.Bytes(), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "c.txt.ser.gz"), []byte("garbage"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "notes.txt"), []byte("skip"), 0644)
+
+	// the files are read once seen unchanged over a poll
+	if n, err := w.Poll(); n != 0 || err != nil {
+		t.Fatal(n, err)
+	}
+	if n, err := w.Poll(); n != 3 || err != nil {
+		t.Fatal(n, err)
+	}
+	if sink.results[0].Document.GetText() != "Hi" || sink.results[1].Document.GetText() != "Hi" || sink.results[2].Err == nil {
+		t.Errorf("%v", sink.results)
+	}
+	if n, err := w.Poll(); n != 0 || err != nil {
+		t.Fatal(n, err)
+	}
+	infos, _ := ioutil.ReadDir(dir)
+	if len(infos) != 2 {
+		t.Errorf("%v", infos)
+	}
+}
@@ -0,0 +1,181 @@
+// Package dockerserver starts Stanford CoreNLP in a docker container,
+// so integration tests can run without a local Java installation.
+//
+package dockerserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/genelet/corenlp-golang/client"
+	"github.com/genelet/corenlp-golang/server"
+)
+
+// DefaultImage is the docker image used when Container.Image is empty, the
+// one of the Stanford NLP group at the release this package is tested with.
+// Another image must run the CoreNLP server on port 9000.
+//
+const DefaultImage = "stanfordnlp/corenlp:4.5.4"
+
+// ErrNotStarted is returned by Container.Client before Start.
+//
+var ErrNotStarted = errors.New("container not started")
+
+// Container is a CoreNLP server running in docker.
+//
+type Container struct {
+	// docker image, default to DefaultImage
+	Image string
+
+	// pull the image before running it
+	Pull bool
+
+	// extra arguments for "docker run", e.g. []string{"-e", "JAVA_XMX=4g"}
+	Args []string
+
+	// the docker command
+	Docker string
+
+	id  string
+	url string
+}
+
+// Start runs the container and waits until the server is ready or ctx is done.
+//
+func (self *Container) Start(ctx context.Context) error {
+	if self.id != "" {
+		return errors.New("container already started")
+	}
+	image := self.Image
+	if image == "" {
+		image = DefaultImage
+	}
+
+	if self.Pull {
+		if _, err := self.docker(ctx, "pull", image); err != nil {
+			return err
+		}
+	}
+
+	args := append([]string{"run", "-d", "--rm", "-p", "127.0.0.1::9000"}, self.Args...)
+	out, err := self.docker(ctx, append(args, image)...)
+	if err != nil {
+		return err
+	}
+	self.id = strings.TrimSpace(out)
+
+	out, err = self.docker(ctx, "port", self.id, "9000/tcp")
+	if err != nil {
+		self.Close()
+		return err
+	}
+	addr, err := hostAddress(out)
+	if err != nil {
+		self.Close()
+		return err
+	}
+	self.url = "http://" + addr + "/"
+
+	if err = server.WaitReady(ctx, self.url); err != nil {
+		self.Close()
+		return err
+	}
+	return nil
+}
+
+// URL returns the address of the server, e.g. http://127.0.0.1:32768/
+//
+func (self *Container) URL() string {
+	return self.url
+}
+
+// Client returns a HttpClient using the server in the container, or
+// ErrNotStarted if the container is not running.
+//
+func (self *Container) Client(annotators []string) (*client.HttpClient, error) {
+	if self.url == "" {
+		return nil, ErrNotStarted
+	}
+	return client.NewHttpClient(annotators, self.url), nil
+}
+
+// Close removes the container.
+//
+func (self *Container) Close() error {
+	if self.id == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	_, err := self.docker(ctx, "rm", "-f", self.id)
+	self.id, self.url = "", ""
+	return err
+}
+
+func (self *Container) docker(ctx context.Context, args ...string) (string, error) {
+	docker := self.Docker
+	if docker == "" {
+		docker = "docker"
+	}
+	cmd := exec.CommandContext(ctx, docker, args...)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("docker %s: %s: %s", args[0], err.Error(), stderr.String())
+	}
+	return stdout.String(), nil
+}
+
+// hostAddress picks the first host:port mapping printed by "docker port".
+//
+func hostAddress(out string) (string, error) {
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "[") {
+			return line, nil
+		}
+	}
+	return "", fmt.Errorf("no port mapping in %q", out)
+}
+
+// Run starts a container for the test, and returns a HttpClient using it.
+// The test is skipped if docker is not installed or not running, and the container is
+// removed when the test finishes.
+//
+// For example:
+//
+//     func TestParse(t *testing.T) {
+//         c := dockerserver.Run(t, []string{"tokenize","ssplit","pos"})
+//         ...
+//     }
+//
+func Run(t testing.TB, annotators []string) *client.HttpClient {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("docker not found")
+	}
+	if err := exec.Command("docker", "info").Run(); err != nil {
+		t.Skip("docker not running")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	c := &Container{}
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	hc, err := c.Client(annotators)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hc
+}
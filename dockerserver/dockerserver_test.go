@@ -0,0 +1,38 @@
+package dockerserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestHostAddress(t *testing.T) {
+	addr, err := hostAddress("127.0.0.1:32768\n[::1]:32768\n")
+	if err != nil || addr != "127.0.0.1:32768" {
+		t.Errorf("%s %v", addr, err)
+	}
+	if _, err = hostAddress(""); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestClientNotStarted(t *testing.T) {
+	c := &Container{}
+	if _, err := c.Client([]string{"tokenize"}); err != ErrNotStarted {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("integration test")
+	}
+	c := Run(t, []string{"tokenize", "ssplit"})
+
+	pb := &nlp.Document{}
+	if err := c.RunText(context.Background(), []byte("Hello world."), pb); err != nil { t.Fatal(err) }
+	if len(pb.Sentence) != 1 {
+		t.Errorf("%s", pb.String())
+	}
+}
@@ -0,0 +1,15 @@
+package examples
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/redact"
+)
+
+// RewriteCoref returns the text of doc, annotated with CorefAnnotators, with
+// the mentions of every entity replaced by its representative mention, e.g.
+// "Anna met her brother" as "Anna met Anna's brother", so that the sentences
+// can be read, or indexed, one by one. It is redact.ResolveCoreferences.
+//
+func RewriteCoref(doc *nlp.Document) string {
+	return redact.ResolveCoreferences(doc)
+}
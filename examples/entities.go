@@ -0,0 +1,71 @@
+package examples
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/genelet/corenlp-golang/client"
+	"github.com/genelet/corenlp-golang/extract"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// maxText is the largest text EntityService accepts, in bytes.
+//
+const maxText = 1 << 20
+
+// Entity is a named entity found by EntityService.
+//
+type Entity struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+
+	// character offsets [BeginChar, EndChar) in the text, in UTF-16 units
+	BeginChar uint32 `json:"begin"`
+	EndChar   uint32 `json:"end"`
+
+	// the text of the canonical mention, e.g. "Ada Lovelace" for "Lovelace"
+	Canonical string `json:"canonical,omitempty"`
+}
+
+// EntityService returns a handler extracting the named entities of the text
+// posted, answered as a JSON array of Entity. c runs EntityAnnotators.
+//
+// For example:
+// curl --data 'Ada Lovelace was born in London.' localhost:8080/entities
+//
+func EntityService(c client.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "post the text", http.StatusMethodNotAllowed)
+			return
+		}
+		text, err := ioutil.ReadAll(io.LimitReader(r.Body, maxText+1))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(text) > maxText {
+			http.Error(w, "text too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		doc := &nlp.Document{}
+		if err := c.RunText(r.Context(), text, doc); err != nil {
+			status := http.StatusInternalServerError
+			if client.IsUnavailable(err) {
+				status = http.StatusServiceUnavailable
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		entities := []*Entity{}
+		for _, m := range extract.ExtractEntityMentions(doc) {
+			entities = append(entities, &Entity{Text: m.Text, Type: m.Type, BeginChar: m.BeginChar, EndChar: m.EndChar, Canonical: m.Canonical})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entities)
+	})
+}
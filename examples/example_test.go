@@ -0,0 +1,45 @@
+package examples_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/genelet/corenlp-golang/client"
+	"github.com/genelet/corenlp-golang/examples"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// An entity extraction service in front of a CoreNLP server.
+//
+func ExampleEntityService() {
+	c := client.NewHttpClient(examples.EntityAnnotators, "http://127.0.0.1:9000")
+	http.Handle("/entities", examples.EntityService(c))
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
+
+// The sentiment of the sample corpus, one line per sample.
+//
+func ExampleSentimentBatch() {
+	c := client.NewHttpClient(examples.SentimentAnnotators, "http://127.0.0.1:9000")
+	if err := examples.SentimentBatch(context.Background(), c, examples.Corpus(), os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// The story sample with its coreferences resolved.
+//
+func ExampleRewriteCoref() {
+	text, err := examples.SampleText("story")
+	if err != nil {
+		log.Fatal(err)
+	}
+	c := client.NewHttpClient(examples.CorefAnnotators, "http://127.0.0.1:9000")
+	doc := &nlp.Document{}
+	if err := c.RunText(context.Background(), []byte(text), doc); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(examples.RewriteCoref(doc))
+}
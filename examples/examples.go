@@ -0,0 +1,64 @@
+// Package examples ships a small sample corpus and example pipelines built on
+// the client and extract packages, to get started: an entity extraction
+// service, a sentiment batch job and a coreference rewriter. They need a
+// CoreNLP server, or the Java package, with the annotators they list.
+//
+// For example:
+// c := client.NewHttpClient(examples.EntityAnnotators)
+// http.Handle("/entities", examples.EntityService(c))
+//
+package examples
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed corpus/*.txt
+var corpus embed.FS
+
+// The annotators the example pipelines need.
+//
+var (
+	EntityAnnotators    = []string{"tokenize", "ssplit", "pos", "lemma", "ner"}
+	SentimentAnnotators = []string{"tokenize", "ssplit", "pos", "parse", "sentiment"}
+	CorefAnnotators     = []string{"tokenize", "ssplit", "pos", "lemma", "ner", "parse", "coref"}
+)
+
+// Sample is a text of the sample corpus.
+//
+type Sample struct {
+	// the file name without extension, e.g. "news"
+	Name string
+	Text string
+}
+
+// Corpus returns the samples of the corpus, in name order: short English
+// texts with entities, opinions and pronouns.
+//
+func Corpus() []*Sample {
+	entries, _ := corpus.ReadDir("corpus")
+	samples := make([]*Sample, 0, len(entries))
+	for _, e := range entries {
+		data, err := corpus.ReadFile(path.Join("corpus", e.Name()))
+		if err != nil {
+			continue
+		}
+		samples = append(samples, &Sample{Name: strings.TrimSuffix(e.Name(), ".txt"), Text: string(data)})
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Name < samples[j].Name })
+	return samples
+}
+
+// SampleText returns the text of the sample name, e.g. "news".
+//
+func SampleText(name string) (string, error) {
+	data, err := corpus.ReadFile(path.Join("corpus", name+".txt"))
+	if err != nil {
+		return "", fmt.Errorf("sample %s: %w", name, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,106 @@
+package examples
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fakeClient annotates the texts with annotate.
+//
+type fakeClient func(text string, doc *nlp.Document) error
+
+func (self fakeClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	return errors.New("not implemented")
+}
+
+func (self fakeClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	return self(string(text), msg.(*nlp.Document))
+}
+
+func TestCorpus(t *testing.T) {
+	samples := Corpus()
+	if len(samples) != 4 || samples[0].Name != "biography" || !strings.HasPrefix(samples[0].Text, "Ada Lovelace") {
+		t.Errorf("%v", samples)
+	}
+	if text, err := SampleText("story"); err != nil || !strings.Contains(text, "Anna") {
+		t.Errorf("%s %v", text, err)
+	}
+	if _, err := SampleText("missing"); err == nil {
+		t.Errorf("no error")
+	}
+}
+
+func TestRewriteCoref(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("Anna|NNP||PERSON met|VBD||O her|PRP$||O brother|NN||O Tom|NNP||PERSON .|.||O"),
+		testdoc.Sentence("He|PRP||O missed|VBD||O the|DT||O train|NN||O .|.||O"),
+	)
+	mention := func(typ string, sentence, begin, end uint32) *nlp.CorefChain_CorefMention {
+		return &nlp.CorefChain_CorefMention{MentionType: proto.String(typ), SentenceIndex: proto.Uint32(sentence), BeginIndex: proto.Uint32(begin), EndIndex: proto.Uint32(end)}
+	}
+	doc.CorefChain = []*nlp.CorefChain{
+		{ChainID: proto.Int32(1), Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{mention("PROPER", 0, 0, 1), mention("PRONOMINAL", 0, 2, 3)}},
+		{ChainID: proto.Int32(2), Representative: proto.Uint32(1), Mention: []*nlp.CorefChain_CorefMention{mention("PRONOMINAL", 1, 0, 1), mention("PROPER", 0, 2, 5)}},
+	}
+	if got := RewriteCoref(doc); got != "Anna met Anna's brother Tom . Her brother Tom missed the train ." {
+		t.Errorf("%q", got)
+	}
+}
+
+func TestEntityService(t *testing.T) {
+	c := fakeClient(func(text string, doc *nlp.Document) error {
+		if text == "fail" {
+			return errors.New("boom")
+		}
+		doc.Sentence = []*nlp.Sentence{testdoc.Sentence("Ada|NNP||PERSON Lovelace|NNP||PERSON was|VBD||O born|VBN||O in|IN||O London|NNP||CITY .|.||O")}
+		return nil
+	})
+	ts := httptest.NewServer(EntityService(c))
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL, "text/plain", strings.NewReader("Ada Lovelace was born in London."))
+	if err != nil { t.Fatal(err) }
+	defer res.Body.Close()
+	var entities []*Entity
+	if err := json.NewDecoder(res.Body).Decode(&entities); err != nil { t.Fatal(err) }
+	if len(entities) != 2 || entities[0].Text != "Ada Lovelace" || entities[0].Type != "PERSON" || entities[1].Text != "London" {
+		t.Errorf("%+v", entities)
+	}
+
+	if res, err := http.Post(ts.URL, "text/plain", strings.NewReader("fail")); err != nil || res.StatusCode != http.StatusInternalServerError {
+		t.Errorf("%v %v", res, err)
+	}
+	if res, err := http.Get(ts.URL); err != nil || res.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("%v %v", res, err)
+	}
+}
+
+func TestSentimentBatch(t *testing.T) {
+	c := fakeClient(func(text string, doc *nlp.Document) error {
+		if strings.Contains(text, "robot") {
+			return errors.New("boom")
+		}
+		doc.Sentence = []*nlp.Sentence{{Sentiment: proto.String("Positive")}}
+		return nil
+	})
+	var out bytes.Buffer
+	samples := []*Sample{{Name: "a", Text: "nice"}, {Name: "b", Text: "a robot"}}
+	if err := SentimentBatch(context.Background(), c, samples, &out); err != nil { t.Fatal(err) }
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	sort.Strings(lines)
+	if len(lines) != 2 || lines[0] != "a\tPositive\t3.00" || lines[1] != "b\terror\tboom" {
+		t.Errorf("%q", lines)
+	}
+}
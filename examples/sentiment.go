@@ -0,0 +1,44 @@
+package examples
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/genelet/corenlp-golang/client"
+	"github.com/genelet/corenlp-golang/extract"
+)
+
+// SentimentBatch annotates the samples with c, running SentimentAnnotators
+// with a client.Runner, and writes a line per sample to w as it is done:
+// the name, the sentiment label and its score from 0 to 4, separated by
+// tabs, or the error of the sample. It returns the first error writing to w.
+//
+// For example, over the sample corpus:
+// SentimentBatch(ctx, client.NewHttpClient(examples.SentimentAnnotators), examples.Corpus(), os.Stdout)
+//
+func SentimentBatch(ctx context.Context, c client.Client, samples []*Sample, w io.Writer) error {
+	sink := client.SinkFunc(func(result *client.Result) error {
+		if result.Err != nil {
+			_, err := fmt.Fprintf(w, "%s\terror\t%v\n", result.ID, result.Err)
+			return err
+		}
+		s := extract.ExtractSentiment(result.Document)
+		label := s.Label
+		if s.Class < 0 {
+			label = "none"
+		}
+		_, err := fmt.Fprintf(w, "%s\t%s\t%.2f\n", result.ID, label, s.Score)
+		return err
+	})
+
+	runner := client.NewRunner(c, sink)
+	for _, s := range samples {
+		if err := runner.Submit(ctx, &client.Item{ID: s.Name, Text: []byte(s.Text)}); err != nil {
+			runner.Drain(ctx)
+			return err
+		}
+	}
+	_, err := runner.Drain(ctx)
+	return err
+}
@@ -0,0 +1,98 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tree"
+)
+
+// ChunkMode selects the phrases ExtractChunks returns when phrases of the
+// same category nest.
+//
+type ChunkMode int
+
+const (
+	// MaximalChunks returns the outermost phrases, e.g. the NP "the man
+	// with the hat" whole.
+	MaximalChunks ChunkMode = iota
+	// MinimalChunks returns the phrases holding no phrase of the same
+	// category, e.g. the NPs "the man" and "the hat".
+	MinimalChunks
+)
+
+// ChunkLabels are the phrase categories ExtractChunks returns by default.
+//
+var ChunkLabels = []string{"NP", "VP", "PP"}
+
+// Chunk is a phrase of the constituency parse of a sentence.
+//
+type Chunk struct {
+	// 0-based sentence index in the document
+	Sentence int
+
+	// the category of the phrase, e.g. "NP", without function tags
+	Label string
+
+	// the 0-based token span of the phrase in the sentence
+	Span TokenSpan
+
+	// character offsets of the phrase, and its text
+	BeginChar uint32
+	EndChar   uint32
+	Text      string
+}
+
+// ExtractChunks returns the phrases of the categories labels, ChunkLabels by
+// default, from the parse trees of doc, by sentence and in preorder, i.e. by
+// first token with the longer phrases first. The phrases of different
+// categories may nest, e.g. an NP in a PP. The text is cut from the document
+// text when the tokens have character offsets, and detokenized otherwise.
+// Sentences without a parse tree, or whose tree does not match the tokens,
+// give no chunks.
+//
+func ExtractChunks(doc *nlp.Document, mode ChunkMode, labels ...string) []*Chunk {
+	if len(labels) == 0 {
+		labels = ChunkLabels
+	}
+	text := newDocText(doc)
+	var chunks []*Chunk
+	for i, s := range doc.GetSentence() {
+		root := tree.New(s.GetParseTree())
+		if root == nil || root.End != len(s.Token) {
+			continue
+		}
+		for _, n := range root.Find(labels...) {
+			if mode == MinimalChunks && len(n.Find(n.Category())) > 1 {
+				continue
+			}
+			if mode == MaximalChunks && hasAncestor(n, n.Category()) {
+				continue
+			}
+			chunks = append(chunks, newChunk(text, i, n, n.Tokens(s)))
+		}
+	}
+	return chunks
+}
+
+// hasAncestor reports whether an ancestor of n has the category.
+//
+func hasAncestor(n *tree.Node, category string) bool {
+	for _, a := range n.Ancestors() {
+		if a.Category() == category {
+			return true
+		}
+	}
+	return false
+}
+
+func newChunk(text *docText, sentence int, n *tree.Node, tokens []*nlp.Token) *Chunk {
+	c := &Chunk{Sentence: sentence, Label: n.Category(), Span: TokenSpan{n.Begin, n.End}}
+	first, last := tokens[0], tokens[len(tokens)-1]
+	c.BeginChar, c.EndChar = first.GetBeginChar(), last.GetEndChar()
+	if first.BeginChar != nil && last.EndChar != nil {
+		c.Text, _ = text.slice(c.BeginChar, c.EndChar)
+	}
+	if c.Text == "" {
+		c.Text = DetokenizeTokens(tokens)
+	}
+	return c
+}
@@ -0,0 +1,46 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/tree"
+)
+
+func TestExtractChunks(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("the|DT man|NN with|IN the|DT hat|NN has|VBZ left|VBN"),
+		testdoc.Sentence("Hi|UH"),
+	)
+	parse, err := tree.Parse("(ROOT (S (NP (NP (DT the) (NN man)) (PP (IN with) (NP (DT the) (NN hat)))) (VP (VBZ has) (VP (VBN left)))))")
+	if err != nil { t.Fatal(err) }
+	doc.Sentence[0].ParseTree = parse
+
+	chunks := ExtractChunks(doc, MaximalChunks)
+	if len(chunks) != 3 {
+		t.Fatalf("%d chunks", len(chunks))
+	}
+	if c := chunks[0]; c.Label != "NP" || c.Text != "the man with the hat" || c.Span != (TokenSpan{0, 5}) || c.BeginChar != 0 || c.EndChar != 20 {
+		t.Errorf("%#v", c)
+	}
+	if c := chunks[1]; c.Label != "PP" || c.Text != "with the hat" || c.Span != (TokenSpan{2, 5}) {
+		t.Errorf("%#v", c)
+	}
+	if c := chunks[2]; c.Label != "VP" || c.Text != "has left" {
+		t.Errorf("%#v", c)
+	}
+
+	var texts []string
+	for _, c := range ExtractChunks(doc, MinimalChunks, "NP", "VP") {
+		texts = append(texts, c.Label+":"+c.Text)
+	}
+	if len(texts) != 3 || texts[0] != "NP:the man" || texts[1] != "NP:the hat" || texts[2] != "VP:left" {
+		t.Errorf("%q", texts)
+	}
+
+	// a tree over other tokens is skipped
+	doc.Sentence[0].Token = doc.Sentence[0].Token[:3]
+	if chunks := ExtractChunks(doc, MaximalChunks); chunks != nil {
+		t.Errorf("%v", chunks)
+	}
+}
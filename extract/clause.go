@@ -0,0 +1,229 @@
+package extract
+
+import (
+	"sort"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// ClauseMode selects the clauses SplitClauses returns.
+//
+type ClauseMode int
+
+const (
+	// IndependentClauses splits at coordinated and paratactic clauses; the
+	// subordinate clauses stay inside the clauses they belong to.
+	IndependentClauses ClauseMode = iota
+	// AllClauses also splits off the subordinate clauses: adverbial,
+	// complement, subject and relative clauses. Open complements (xcomp),
+	// which share their subject, are not split.
+	AllClauses
+)
+
+// Clause is a clause of a sentence. Its tokens need not be contiguous,
+// e.g. a main clause around a relative clause split off by AllClauses.
+//
+type Clause struct {
+	// 0-based sentence index in the document
+	Sentence int
+
+	// 0-based token index of the head, e.g. the main verb
+	Head int
+
+	// relation attaching the head: root, conj, parataxis, advcl, ccomp, ...
+	Relation string
+
+	// false for subordinate clauses
+	Independent bool
+
+	// 0-based token indexes in the sentence, in order, without the
+	// coordinating conjunction and the punctuation at the edges
+	Tokens []int
+
+	// span of the clause: character offsets, and 0-based token range [TokenBegin, TokenEnd)
+	BeginChar  uint32
+	EndChar    uint32
+	TokenBegin int
+	TokenEnd   int
+
+	Text string
+}
+
+// SplitClauses splits the sentences of doc into clauses, using the basic
+// dependencies, in the order of their first token. A sentence without
+// dependencies is returned as one clause. The mode is IndependentClauses by default.
+//
+func SplitClauses(doc *nlp.Document, mode ...ClauseMode) []*Clause {
+	m := IndependentClauses
+	if len(mode) > 0 {
+		m = mode[0]
+	}
+	var clauses []*Clause
+	for i, sentence := range doc.GetSentence() {
+		clauses = append(clauses, splitClauses(i, sentence, m)...)
+	}
+	return clauses
+}
+
+func splitClauses(index int, sentence *nlp.Sentence, mode ClauseMode) []*Clause {
+	tokens := sentence.Token
+	g := basicGraph(sentence)
+
+	// the clause heads, 1-based, with their relations
+	heads := make(map[uint32]string)
+	children := make(map[uint32][]*nlp.DependencyGraph_Edge)
+	var roots []uint32
+	if g != nil {
+		roots = g.Root
+		for _, r := range roots {
+			heads[r] = tags.Root
+		}
+		for _, edge := range g.Edge {
+			children[edge.GetSource()] = append(children[edge.GetSource()], edge)
+		}
+	}
+	var walk func(uint32, bool)
+	walk = func(n uint32, clause bool) {
+		for _, edge := range children[n] {
+			t := edge.GetTarget()
+			if _, ok := heads[t]; ok {
+				continue
+			}
+			rel := edge.GetDep()
+			if clausal(rel, mode) || clause && tags.BaseRelation(rel) == tags.Conj && predicate(tokens, t, children[t]) {
+				heads[t] = rel
+			}
+			_, isHead := heads[t]
+			walk(t, isHead)
+		}
+	}
+	for _, r := range roots {
+		walk(r, true)
+	}
+	if len(heads) == 0 {
+		all := make([]int, len(tokens))
+		for i := range all {
+			all[i] = i
+		}
+		if c := newClause(index, sentence, -1, tags.Root, all); c != nil {
+			return []*Clause{c}
+		}
+		return nil
+	}
+
+	// each token belongs to the clause of its nearest head above it
+	owner := make(map[uint32]uint32)
+	var assign func(uint32, uint32)
+	assign = func(n, head uint32) {
+		if _, ok := owner[n]; ok {
+			return
+		}
+		if _, ok := heads[n]; ok {
+			head = n
+		}
+		owner[n] = head
+		for _, edge := range children[n] {
+			// the coordinating conjunction of a coordinated clause is left out
+			if _, ok := heads[n]; ok && tags.BaseRelation(edge.GetDep()) == tags.Cc && heads[n] != tags.Root {
+				owner[edge.GetTarget()] = 0
+				continue
+			}
+			assign(edge.GetTarget(), head)
+		}
+	}
+	for _, r := range roots {
+		assign(r, r)
+	}
+
+	members := make(map[uint32][]int)
+	for n, head := range owner {
+		if head > 0 && int(n) <= len(tokens) {
+			members[head] = append(members[head], int(n)-1)
+		}
+	}
+	var clauses []*Clause
+	for head, ts := range members {
+		sort.Ints(ts)
+		if c := newClause(index, sentence, int(head)-1, heads[head], ts); c != nil {
+			clauses = append(clauses, c)
+		}
+	}
+	sort.Slice(clauses, func(i, j int) bool { return clauses[i].TokenBegin < clauses[j].TokenBegin })
+	return clauses
+}
+
+// clausal reports whether rel attaches a clause that is split off in mode.
+//
+func clausal(rel string, mode ClauseMode) bool {
+	if rel == tags.Parataxis {
+		return true
+	}
+	if mode != AllClauses {
+		return false
+	}
+	// rcmod is the relative clause of the Stanford Dependencies
+	return rel == "rcmod" || tags.IsClausal(rel) && tags.BaseRelation(rel) != tags.Xcomp
+}
+
+// predicate reports whether the 1-based node heads a clause of its own: a verb,
+// or a word with a subject or a copula.
+//
+func predicate(tokens []*nlp.Token, n uint32, edges []*nlp.DependencyGraph_Edge) bool {
+	if int(n) <= len(tokens) && tags.IsVerb(tokens[n-1].GetPos()) {
+		return true
+	}
+	for _, edge := range edges {
+		if tags.IsSubject(edge.GetDep()) || edge.GetDep() == tags.Cop {
+			return true
+		}
+	}
+	return false
+}
+
+// newClause trims the punctuation at the edges of the clause, and around the
+// clauses split off from it, and returns nil if nothing is left.
+//
+func newClause(index int, sentence *nlp.Sentence, head int, rel string, ts []int) *Clause {
+	tokens := sentence.Token
+	var kept []int
+	for i, t := range ts {
+		gap := i > 0 && ts[i-1] != t-1 || i < len(ts)-1 && ts[i+1] != t+1
+		if !gap || !punctuation(tokens[t]) {
+			kept = append(kept, t)
+		}
+	}
+	ts = kept
+	for len(ts) > 0 && punctuation(tokens[ts[0]]) {
+		ts = ts[1:]
+	}
+	for len(ts) > 0 && punctuation(tokens[ts[len(ts)-1]]) {
+		ts = ts[:len(ts)-1]
+	}
+	if len(ts) == 0 {
+		return nil
+	}
+
+	c := &Clause{Sentence: index, Head: head, Relation: rel, Independent: !clausal(rel, AllClauses) || rel == tags.Parataxis, Tokens: ts}
+	first, last := tokens[ts[0]], tokens[ts[len(ts)-1]]
+	c.BeginChar, c.EndChar = first.GetBeginChar(), last.GetEndChar()
+	c.TokenBegin, c.TokenEnd = ts[0], ts[len(ts)-1]+1
+
+	words := make([]*nlp.Token, len(ts))
+	for i, t := range ts {
+		words[i] = tokens[t]
+	}
+	c.Text = DetokenizeTokens(words)
+	return c
+}
+
+func punctuation(token *nlp.Token) bool {
+	if token.Pos != nil {
+		return tags.IsPunctuation(token.GetPos())
+	}
+	switch token.GetWord() {
+	case ",", ".", ";", ":", "!", "?", "--":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+)
+
+func TestSplitClauses(t *testing.T) {
+	// John left because he was tired , and Mary , who stayed , sang .
+	s := testdoc.Sentence("John|NNP left|VBD because|IN he|PRP was|VBD tired|JJ ,|, and|CC Mary|NNP ,|, who|WP stayed|VBD ,|, sang|VBD .|.",
+		"0>2:root", "2>1:nsubj", "6>3:mark", "6>4:nsubj", "6>5:cop", "2>6:advcl", "14>7:punct", "14>8:cc",
+		"14>9:nsubj", "9>10:punct", "12>11:nsubj", "9>12:acl:relcl", "9>13:punct", "2>14:conj", "2>15:punct")
+	doc := testdoc.Doc(s, testdoc.Sentence("Hello|UH world|NN !|."))
+
+	clauses := SplitClauses(doc)
+	if len(clauses) != 3 {
+		t.Fatalf("%v", clauses)
+	}
+	if c := clauses[0]; c.Text != "John left because he was tired" || c.Relation != "root" || !c.Independent || c.Head != 1 {
+		t.Errorf("%#v", c)
+	}
+	if c := clauses[1]; c.Text != "Mary, who stayed, sang" || c.Relation != "conj" || c.TokenBegin != 8 || c.TokenEnd != 14 {
+		t.Errorf("%#v", c)
+	}
+	if c := clauses[2]; c.Text != "Hello world" || c.Sentence != 1 || c.Head != -1 || c.EndChar != c.BeginChar+11 {
+		t.Errorf("%#v", c)
+	}
+
+	all := SplitClauses(doc, AllClauses)
+	var texts []string
+	for _, c := range all[:4] {
+		texts = append(texts, c.Text)
+	}
+	want := []string{"John left", "because he was tired", "Mary sang", "who stayed"}
+	for i := range want {
+		if texts[i] != want[i] {
+			t.Errorf("%d: %q", i, texts[i])
+		}
+	}
+	if all[1].Independent || all[1].Relation != "advcl" || all[3].Relation != "acl:relcl" {
+		t.Errorf("%#v %#v", all[1], all[3])
+	}
+}
@@ -0,0 +1,82 @@
+package extract
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// NERProbabilities returns the probabilities CoreNLP gives the NER labels of
+// the token, from Token.NerLabelProbs, e.g. {"PERSON": 0.98}; nil if none.
+//
+func NERProbabilities(token *nlp.Token) map[string]float64 {
+	var probs map[string]float64
+	for _, item := range token.GetNerLabelProbs() {
+		i := strings.LastIndex(item, "=")
+		if i <= 0 {
+			continue
+		}
+		p, err := strconv.ParseFloat(item[i+1:], 64)
+		if err != nil {
+			continue
+		}
+		if probs == nil {
+			probs = make(map[string]float64)
+		}
+		probs[item[:i]] = p
+	}
+	return probs
+}
+
+// NERConfidence returns the probability of the NER label of the token, and
+// false if CoreNLP gave none.
+//
+func NERConfidence(token *nlp.Token) (float64, bool) {
+	p, ok := NERProbabilities(token)[token.GetNer()]
+	return p, ok
+}
+
+// mentionConfidence returns the lowest probability of the NER labels of the
+// tokens, -1 if one of them has none.
+//
+func mentionConfidence(tokens []*nlp.Token) float64 {
+	confidence := 1.0
+	for _, t := range tokens {
+		p, ok := NERConfidence(t)
+		if !ok {
+			return -1
+		}
+		if p < confidence {
+			confidence = p
+		}
+	}
+	return confidence
+}
+
+// LowConfidenceEntities returns the entity mentions of doc, see
+// ExtractEntityMentions, whose Confidence is below threshold, least
+// confident first, for triage. Mentions without probabilities are left out.
+//
+func LowConfidenceEntities(doc *nlp.Document, threshold float64) []*EntityMention {
+	var low []*EntityMention
+	for _, m := range ExtractEntityMentions(doc) {
+		if m.Confidence >= 0 && m.Confidence < threshold {
+			low = append(low, m)
+		}
+	}
+	sort.SliceStable(low, func(i, j int) bool { return low[i].Confidence < low[j].Confidence })
+	return low
+}
+
+// ParseScore returns the score of the constituency parse of the sentence,
+// the log probability the parser gives it, and false if it has none.
+//
+func ParseScore(sentence *nlp.Sentence) (float64, bool) {
+	t := sentence.GetParseTree()
+	if t == nil || t.Score == nil {
+		return 0, false
+	}
+	return t.GetScore(), true
+}
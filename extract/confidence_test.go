@@ -0,0 +1,55 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestConfidence(t *testing.T) {
+	doc := testdoc.Doc(testdoc.Sentence("Jordan|NNP|Jordan|PERSON Smith|NNP|Smith|PERSON visited|VBD|visit|O Jordan|NNP|Jordan|COUNTRY and|CC|and|O Paris|NNP|Paris|CITY"))
+	tokens := doc.Sentence[0].Token
+	tokens[0].NerLabelProbs = []string{"PERSON=0.61"}
+	tokens[1].NerLabelProbs = []string{"PERSON=0.99"}
+	tokens[3].NerLabelProbs = []string{"COUNTRY=0.42", "bad", "CITY=x"}
+	tokens[5].NerLabelProbs = []string{"CITY=0.97"}
+
+	if probs := NERProbabilities(tokens[3]); len(probs) != 1 || probs["COUNTRY"] != 0.42 {
+		t.Errorf("%v", probs)
+	}
+	if p, ok := NERConfidence(tokens[0]); !ok || p != 0.61 {
+		t.Errorf("%v %v", p, ok)
+	}
+	if _, ok := NERConfidence(tokens[2]); ok {
+		t.Errorf("no probabilities")
+	}
+
+	mentions := ExtractEntityMentions(doc)
+	if len(mentions) != 3 || mentions[0].Confidence != 0.61 || mentions[2].Confidence != 0.97 {
+		t.Fatalf("%+v", mentions)
+	}
+	low := LowConfidenceEntities(doc, 0.9)
+	if len(low) != 2 || low[0].Text != "Jordan" || low[0].Type != "COUNTRY" || low[1].Text != "Jordan Smith" {
+		t.Errorf("%+v", low)
+	}
+	tokens[4].NerLabelProbs = nil
+	tokens[5].NerLabelProbs = nil
+	if m := ExtractEntityMentions(doc); m[2].Confidence != -1 {
+		t.Errorf("%v", m[2].Confidence)
+	}
+
+	if meta := ExtractTokenMetadata(doc, TokenNERProbs); meta[1].NERProbs["PERSON"] != 0.99 || meta[2].NERProbs != nil {
+		t.Errorf("%v", meta[1].NERProbs)
+	}
+
+	s := &nlp.Sentence{}
+	if _, ok := ParseScore(s); ok {
+		t.Errorf("no parse")
+	}
+	s.ParseTree = &nlp.ParseTree{Value: proto.String("ROOT"), Score: proto.Float64(-42.5)}
+	if score, ok := ParseScore(s); !ok || score != -42.5 {
+		t.Errorf("%v", score)
+	}
+}
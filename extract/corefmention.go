@@ -0,0 +1,93 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// CorefMention is a mention detected by the coref annotators, "coref" or
+// "coref.mention", whether resolved or not.
+//
+type CorefMention struct {
+	// 0-based sentence index in the document
+	Sentence int
+
+	// the text of the mention
+	Text string
+
+	// PROPER, NOMINAL, PRONOMINAL or LIST
+	Type string
+
+	// 0-based token span [TokenBegin, TokenEnd) in the sentence, and the
+	// index of the head token, -1 if unknown
+	TokenBegin int
+	TokenEnd   int
+	Head       int
+
+	// e.g. SINGULAR, FEMALE and ANIMATE, UNKNOWN if undecided
+	Number  string
+	Gender  string
+	Animacy string
+
+	// the id of the entity the mention was resolved to by "coref", -1 if
+	// the mention was not resolved
+	Cluster int
+}
+
+// ExtractCorefMentions returns the coref mentions of doc in document order,
+// from Sentence.MentionsForCoref, or else Document.MentionsForCoref. Unlike
+// Document.CorefChain, they are kept by "coref.mention" without "coref", see
+// client.MentionAnnotators.
+//
+func ExtractCorefMentions(doc *nlp.Document) []*CorefMention {
+	text := newDocText(doc)
+	sentences := doc.GetSentence()
+	var mentions []*CorefMention
+	add := func(i int, m *nlp.Mention) {
+		if i < 0 || i >= len(sentences) {
+			return
+		}
+		s := sentences[i]
+		begin, end := int(m.GetStartIndex()), int(m.GetEndIndex())
+		if begin >= end || end > len(s.Token) {
+			return
+		}
+		cm := &CorefMention{
+			Sentence:   i,
+			Type:       m.GetMentionType(),
+			TokenBegin: begin,
+			TokenEnd:   end,
+			Head:       -1,
+			Number:     m.GetNumber(),
+			Gender:     m.GetGender(),
+			Animacy:    m.GetAnimacy(),
+			Cluster:    -1,
+		}
+		if m.HeadIndex != nil {
+			cm.Head = int(m.GetHeadIndex())
+		}
+		if m.CorefClusterID != nil {
+			cm.Cluster = int(m.GetCorefClusterID())
+		}
+		first, last := s.Token[begin], s.Token[end-1]
+		if t, ok := text.slice(first.GetBeginChar(), last.GetEndChar()); ok && first.BeginChar != nil && last.EndChar != nil {
+			cm.Text = t
+		} else {
+			cm.Text = DetokenizeTokens(s.Token[begin:end])
+		}
+		mentions = append(mentions, cm)
+	}
+
+	found := false
+	for i, s := range sentences {
+		for _, m := range s.MentionsForCoref {
+			found = true
+			add(i, m)
+		}
+	}
+	if !found {
+		for _, m := range doc.GetMentionsForCoref() {
+			add(int(m.GetSentNum()), m)
+		}
+	}
+	return mentions
+}
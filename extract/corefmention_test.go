@@ -0,0 +1,41 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractCorefMentions(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("The|DT|the|O old|JJ|old|O queen|NN|queen|O smiled|VBD|smile|O"),
+		testdoc.Sentence("She|PRP|she|O waved|VBD|wave|O"),
+	)
+	doc.Sentence[0].MentionsForCoref = []*nlp.Mention{{MentionType: proto.String("NOMINAL"), StartIndex: proto.Uint32(0), EndIndex: proto.Uint32(3), HeadIndex: proto.Int32(2),
+		Number: proto.String("SINGULAR"), Gender: proto.String("FEMALE"), Animacy: proto.String("ANIMATE")}}
+	doc.Sentence[1].MentionsForCoref = []*nlp.Mention{
+		{MentionType: proto.String("PRONOMINAL"), StartIndex: proto.Uint32(0), EndIndex: proto.Uint32(1), CorefClusterID: proto.Int32(4)},
+		{MentionType: proto.String("PROPER"), StartIndex: proto.Uint32(1), EndIndex: proto.Uint32(5)},
+	}
+
+	mentions := ExtractCorefMentions(doc)
+	if len(mentions) != 2 {
+		t.Fatalf("%d mentions", len(mentions))
+	}
+	queen, she := mentions[0], mentions[1]
+	if queen.Text != "The old queen" || queen.Type != "NOMINAL" || queen.Head != 2 || queen.Gender != "FEMALE" || queen.Cluster != -1 {
+		t.Errorf("%+v", queen)
+	}
+	if she.Sentence != 1 || she.Text != "She" || she.Head != -1 || she.Cluster != 4 {
+		t.Errorf("%+v", she)
+	}
+
+	// the document-level mentions of dcoref
+	doc.Sentence[0].MentionsForCoref, doc.Sentence[1].MentionsForCoref = nil, nil
+	doc.MentionsForCoref = []*nlp.Mention{{SentNum: proto.Int32(1), StartIndex: proto.Uint32(0), EndIndex: proto.Uint32(1)}, {SentNum: proto.Int32(7)}}
+	if m := ExtractCorefMentions(doc); len(m) != 1 || m[0].Text != "She" {
+		t.Errorf("%v", m)
+	}
+}
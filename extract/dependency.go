@@ -0,0 +1,112 @@
+package extract
+
+import (
+	"sort"
+
+	"github.com/genelet/corenlp-golang/graph"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// DependencyVariant selects the dependency graph ExtractDependencies reads,
+// see graph.Variant.
+//
+type DependencyVariant = graph.Variant
+
+const (
+	// DependencyBasic reads Sentence.BasicDependencies, a tree.
+	DependencyBasic = graph.Basic
+	// DependencyEnhanced reads Sentence.EnhancedDependencies.
+	DependencyEnhanced = graph.Enhanced
+	// DependencyEnhancedPlusPlus reads Sentence.EnhancedPlusPlusDependencies.
+	DependencyEnhancedPlusPlus = graph.EnhancedPlusPlus
+	// DependencyCollapsed reads Sentence.CollapsedDependencies, of the older
+	// Stanford Dependencies.
+	DependencyCollapsed = graph.Collapsed
+	// DependencyCollapsedCCProcessed reads Sentence.CollapsedCCProcessedDependencies.
+	DependencyCollapsedCCProcessed = graph.CollapsedCCProcessed
+)
+
+// MissingDependenciesError is returned by Dependencies when the sentence has
+// no graph of the variant.
+//
+type MissingDependenciesError = graph.MissingDependenciesError
+
+// Dependencies returns the dependency graph of the variant in the sentence,
+// or that of the closest variant present with a *MissingDependenciesError,
+// see graph.Dependencies.
+//
+func Dependencies(sentence *nlp.Sentence, variant DependencyVariant) (*nlp.DependencyGraph, error) {
+	return graph.Dependencies(sentence, variant)
+}
+
+// Dependency is a typed dependency between two tokens of a sentence.
+//
+type Dependency struct {
+	// the relation, e.g. "nsubj" or "obl:in"; "root" for the root of the sentence
+	Relation string
+
+	// 1-based index of the governor in the sentence, 0 for the root, and its word, "ROOT" for the root
+	Governor     int
+	GovernorWord string
+
+	// 1-based index of the dependent in the sentence, and its word
+	Dependent     int
+	DependentWord string
+
+	// the edge is an extra one of the enhanced graphs, which makes them no tree
+	Extra bool
+}
+
+// ExtractDependencies returns the dependencies of every sentence of doc, from
+// the graph selected by variant, DependencyBasic by default, ordered by
+// dependent. A sentence without the graph gets no dependencies; copy nodes of
+// the enhanced graphs, e.g. for ellipsis, are left out.
+//
+func ExtractDependencies(doc *nlp.Document, variant ...DependencyVariant) [][]*Dependency {
+	v := DependencyBasic
+	if len(variant) > 0 {
+		v = variant[0]
+	}
+	deps := make([][]*Dependency, len(doc.GetSentence()))
+	for i, s := range doc.GetSentence() {
+		deps[i] = sentenceDependencies(s, v.Graph(s))
+	}
+	return deps
+}
+
+func sentenceDependencies(s *nlp.Sentence, g *nlp.DependencyGraph) []*Dependency {
+	if g == nil {
+		return nil
+	}
+	word := func(index uint32) string {
+		if index == 0 {
+			return "ROOT"
+		}
+		if int(index) > len(s.Token) {
+			return ""
+		}
+		return s.Token[index-1].GetWord()
+	}
+
+	var deps []*Dependency
+	for _, root := range g.Root {
+		if root > 0 && int(root) <= len(s.Token) {
+			deps = append(deps, &Dependency{Relation: "root", GovernorWord: "ROOT", Dependent: int(root), DependentWord: word(root)})
+		}
+	}
+	for _, e := range g.Edge {
+		if e.GetSourceCopy() > 0 || e.GetTargetCopy() > 0 || int(e.GetSource()) > len(s.Token) || int(e.GetTarget()) > len(s.Token) || e.GetTarget() == 0 {
+			continue
+		}
+		deps = append(deps, &Dependency{
+			Relation:      e.GetDep(),
+			Governor:      int(e.GetSource()),
+			GovernorWord:  word(e.GetSource()),
+			Dependent:     int(e.GetTarget()),
+			DependentWord: word(e.GetTarget()),
+			Extra:         e.GetIsExtra(),
+		})
+	}
+	sort.SliceStable(deps, func(i, j int) bool { return deps[i].Dependent < deps[j].Dependent })
+	return deps
+}
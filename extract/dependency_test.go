@@ -0,0 +1,39 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractDependencies(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("John|NNP runs|VBZ fast|RB", "0>2:root", "2>1:nsubj", "2>3:advmod"),
+		testdoc.Sentence("Hi|UH"),
+	)
+	s := doc.Sentence[0]
+	s.EnhancedPlusPlusDependencies = proto.Clone(s.BasicDependencies).(*nlp.DependencyGraph)
+	s.EnhancedPlusPlusDependencies.Edge = append(s.EnhancedPlusPlusDependencies.Edge,
+		&nlp.DependencyGraph_Edge{Source: proto.Uint32(3), Target: proto.Uint32(1), Dep: proto.String("nsubj:xsubj"), IsExtra: proto.Bool(true)})
+
+	deps := ExtractDependencies(doc)
+	if len(deps) != 2 || len(deps[0]) != 3 || deps[1] != nil {
+		t.Fatalf("%v", deps)
+	}
+	if d := deps[0][0]; d.Relation != "nsubj" || d.Governor != 2 || d.GovernorWord != "runs" || d.Dependent != 1 || d.DependentWord != "John" {
+		t.Errorf("%#v", d)
+	}
+	if d := deps[0][1]; d.Relation != "root" || d.Governor != 0 || d.GovernorWord != "ROOT" || d.DependentWord != "runs" {
+		t.Errorf("%#v", d)
+	}
+
+	deps = ExtractDependencies(doc, DependencyEnhancedPlusPlus)
+	if len(deps[0]) != 4 || deps[0][1].Relation != "nsubj:xsubj" || !deps[0][1].Extra || deps[0][1].GovernorWord != "fast" {
+		t.Errorf("%v", deps[0])
+	}
+	if deps = ExtractDependencies(doc, DependencyEnhanced); deps[0] != nil {
+		t.Errorf("%v", deps[0])
+	}
+}
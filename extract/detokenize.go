@@ -0,0 +1,168 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// ptbEscapes maps Penn Treebank escapes to their surface forms.
+//
+var ptbEscapes = map[string]string{
+	"-LRB-": "(", "-RRB-": ")", "-LSB-": "[", "-RSB-": "]", "-LCB-": "{", "-RCB-": "}",
+	"``": "\"", "''": "\"", "`": "'",
+}
+
+// Detokenize converts a token sequence back to natural text: punctuation
+// and contractions are attached to their neighbours, Penn Treebank escapes
+// such as -LRB- are restored, and quotes are attached inside the quoted span.
+// It is meant for documents without character offsets, e.g. CoNLL-U imports.
+//
+func Detokenize(words []string) string {
+	var b strings.Builder
+	open := false  // inside a pair of " quotes
+	attach := true // the next word attaches to the previous one
+	for _, w := range words {
+		if w == "" {
+			continue
+		}
+		surface := w
+		if s, ok := ptbEscapes[w]; ok {
+			surface = s
+		}
+
+		left, right := false, false
+		switch {
+		case w == "``" || w == "`":
+			left, right = false, true
+		case w == "''":
+			left = true
+		case w == "\"":
+			if open {
+				left = true
+			} else {
+				right = true
+			}
+			open = !open
+		default:
+			left = noSpaceBefore(w) || isContraction(w)
+			right = noSpaceAfter(w)
+		}
+
+		if b.Len() > 0 && !left && !attach {
+			b.WriteByte(' ')
+		}
+		b.WriteString(surface)
+		attach = right
+	}
+	return b.String()
+}
+
+// DetokenizeTokens detokenizes the words of the tokens.
+//
+func DetokenizeTokens(tokens []*nlp.Token) string {
+	words := make([]string, len(tokens))
+	for i, token := range tokens {
+		words[i] = token.GetWord()
+	}
+	return Detokenize(words)
+}
+
+// isContraction reports whether w is the second half of a split contraction,
+// e.g. n't in "do n't", 's in "John 's".
+//
+func isContraction(w string) bool {
+	l := strings.ToLower(w)
+	switch l {
+	case "n't", "'s", "'re", "'ve", "'ll", "'d", "'m", "'", "’s", "n’t":
+		return true
+	}
+	return false
+}
+
+func noSpaceBefore(w string) bool {
+	switch w {
+	case ".", ",", ";", ":", "!", "?", "%", ")", "]", "}", "-RRB-", "-RSB-", "-RCB-", "...", "…":
+		return true
+	}
+	return false
+}
+
+func noSpaceAfter(w string) bool {
+	switch w {
+	case "(", "[", "{", "$", "#", "-LRB-", "-LSB-", "-LCB-":
+		return true
+	}
+	return false
+}
+
+// SentenceText returns the text of the sentence as it was written, from its
+// tokens: the original text of each token, then the whitespace after it, e.g.
+// "Hello, world." or "Hello,  world." with two spaces. The whitespace before
+// the first token and after the last is left out. See tokenGap for tokens
+// without whitespace fields.
+//
+func SentenceText(sentence *nlp.Sentence) string {
+	var b strings.Builder
+	writeTokens(&b, sentence.GetToken())
+	return b.String()
+}
+
+// ReconstructText returns the text of doc as it was written, rebuilt from its
+// tokens as SentenceText does, with the whitespace before the first token,
+// between the sentences and after the last token. It matches Document.Text
+// when the tokenizer kept the whitespace, the default of CoreNLP, and is
+// meant for the documents whose text was not kept.
+//
+func ReconstructText(doc *nlp.Document) string {
+	var tokens []*nlp.Token
+	for _, s := range doc.GetSentence() {
+		tokens = append(tokens, s.Token...)
+	}
+	if len(tokens) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(tokens[0].GetBefore())
+	writeTokens(&b, tokens)
+	b.WriteString(tokens[len(tokens)-1].GetAfter())
+	return b.String()
+}
+
+func writeTokens(b *strings.Builder, tokens []*nlp.Token) {
+	for i, t := range tokens {
+		if i > 0 {
+			b.WriteString(tokenGap(tokens[i-1], t))
+		}
+		b.WriteString(originalText(t))
+	}
+}
+
+// tokenGap returns the whitespace between two tokens: the After of the first,
+// or else the Before of the second, or else as many spaces as the character
+// offsets leave between them, up to format.MaxGap, or else one space.
+//
+func tokenGap(t, next *nlp.Token) string {
+	switch {
+	case t.After != nil:
+		return t.GetAfter()
+	case next.Before != nil:
+		return next.GetBefore()
+	case t.EndChar != nil && next.BeginChar != nil && t.GetEndChar() <= next.GetBeginChar() && next.GetBeginChar()-t.GetEndChar() <= format.MaxGap:
+		return strings.Repeat(" ", int(next.GetBeginChar()-t.GetEndChar()))
+	}
+	return " "
+}
+
+// originalText returns the token as written, e.g. "(" for the word "-LRB-".
+//
+func originalText(t *nlp.Token) string {
+	if t.OriginalText != nil {
+		return t.GetOriginalText()
+	}
+	if t.Value != nil {
+		return t.GetValue()
+	}
+	return t.GetWord()
+}
@@ -0,0 +1,64 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDetokenize(t *testing.T) {
+	for _, c := range [][2]string{
+		{"He said , `` I ca n't go . ''", `He said, "I can't go."`},
+		{"She said \" yes \" and left .", `She said "yes" and left.`},
+		{"John 's car cost $ 5 -LRB- approx. -RRB- !", "John's car cost $5 (approx.)!"},
+		{"They 're here ; we 'll see ...", "They're here; we'll see..."},
+		{"", ""},
+	} {
+		if got := Detokenize(strings.Fields(c[0])); got != c[1] {
+			t.Errorf("%q: %q", c[0], got)
+		}
+	}
+}
+
+func TestReconstructText(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("He|PRP is|VBZ n't|RB here|RB .|."),
+		testdoc.Sentence("Hello|UH ,|, -LRB-|-LRB- world|NN -RRB-|-RRB-"),
+	)
+	// "  He isn't here.\nHello,  (world)\n" as the tokenizer sees it
+	afters := [][]string{{" ", "", " ", "", "\n"}, {"", "  ", "", "", "\n"}}
+	for i, s := range doc.Sentence {
+		for j, tok := range s.Token {
+			tok.After = proto.String(afters[i][j])
+			tok.Before = nil
+		}
+	}
+	doc.Sentence[0].Token[0].Before = proto.String("  ")
+	doc.Sentence[1].Token[2].OriginalText = proto.String("(")
+	doc.Sentence[1].Token[4].OriginalText = proto.String(")")
+
+	if got := ReconstructText(doc); got != "  He isn't here.\nHello,  (world)\n" {
+		t.Errorf("%q", got)
+	}
+	if got := SentenceText(doc.Sentence[1]); got != "Hello,  (world)" {
+		t.Errorf("%q", got)
+	}
+	if got := ExtractSentences(doc, JoinOriginal); got[0] != "He isn't here." {
+		t.Errorf("%q", got)
+	}
+
+	// without whitespace fields, the offsets tell the gaps
+	for _, tok := range doc.Sentence[0].Token {
+		tok.After = nil
+	}
+	doc.Sentence[0].Token[2].BeginChar = proto.Uint32(doc.Sentence[0].Token[1].GetEndChar())
+	if got := SentenceText(doc.Sentence[0]); got != "He isn't here ." {
+		t.Errorf("%q", got)
+	}
+	if got := ReconstructText(&nlp.Document{}); got != "" {
+		t.Errorf("%q", got)
+	}
+}
@@ -0,0 +1,23 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// ExtractNamedEntities returns the named entities of doc by NER type,
+// e.g. map[string][]string{"PERSON": {"Barack Obama"}, "CITY": {"Honolulu"}}.
+// The types are listed in package tags, e.g. entities[tags.Person].
+// Consecutive tokens of the same type are joined into one entity, see
+// tags.EntityRuns.
+// ExtractEntityMentions keeps the offsets and the normalized values.
+//
+func ExtractNamedEntities(doc *nlp.Document) map[string][]string {
+	entities := make(map[string][]string)
+	for _, sentence := range doc.GetSentence() {
+		for _, run := range tags.EntityRuns(sentence.Token) {
+			entities[run.NER] = append(entities[run.NER], DetokenizeTokens(sentence.Token[run.Begin:run.End]))
+		}
+	}
+	return entities
+}
@@ -0,0 +1,18 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+)
+
+func TestExtractNamedEntities(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("Barack|NNP|Barack|PERSON Obama|NNP|Obama|PERSON was|VBD|be born|VBN|bear in|IN|in Honolulu|NNP|Honolulu|CITY .|.|.|O"),
+		testdoc.Sentence("Obama|NNP|Obama|PERSON left|VBD|leave"),
+	)
+	got := ExtractNamedEntities(doc)
+	if len(got) != 2 || len(got["PERSON"]) != 2 || got["PERSON"][0] != "Barack Obama" || got["PERSON"][1] != "Obama" || got["CITY"][0] != "Honolulu" {
+		t.Errorf("%v", got)
+	}
+}
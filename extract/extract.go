@@ -0,0 +1,61 @@
+// Package extract turns annotated nlp.Document values into plain GO structures,
+// so callers do not need to walk the protobuf messages by hand.
+//
+package extract
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"github.com/genelet/corenlp-golang/graph"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// lower returns the lower-cased lemma of the token, computed by Lemma
+// if the "lemma" annotator did not run.
+//
+func lower(token *nlp.Token) string {
+	return strings.ToLower(LemmaOf(token))
+}
+
+// basicGraph returns the most basic dependency graph available in the sentence.
+//
+func basicGraph(sentence *nlp.Sentence) *nlp.DependencyGraph {
+	g, _ := graph.Dependencies(sentence, graph.Basic)
+	return g
+}
+
+// governors maps each 1-based token index to its governor and relation in g.
+//
+func governors(g *nlp.DependencyGraph) map[uint32]*nlp.DependencyGraph_Edge {
+	heads := make(map[uint32]*nlp.DependencyGraph_Edge)
+	if g == nil {
+		return heads
+	}
+	for _, edge := range g.Edge {
+		if _, ok := heads[edge.GetTarget()]; !ok {
+			heads[edge.GetTarget()] = edge
+		}
+	}
+	return heads
+}
+
+// docText gives access to Document.Text by CoreNLP character offsets,
+// which count UTF-16 code units as Java does.
+//
+type docText struct {
+	units []uint16
+}
+
+func newDocText(doc *nlp.Document) *docText {
+	return &docText{units: utf16.Encode([]rune(doc.GetText()))}
+}
+
+// slice returns the text between the character offsets, and false if they are out of range.
+//
+func (self *docText) slice(begin, end uint32) (string, bool) {
+	if begin > end || int(end) > len(self.units) {
+		return "", false
+	}
+	return string(utf16.Decode(self.units[begin:end])), true
+}
@@ -0,0 +1,126 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// TokenPredicate selects tokens for FilterTokens. Predicates compose with
+// And, Or and Not.
+//
+// For example, the content words which are not named entities:
+// FilterTokens(doc, And(HasPOS(tags.IsContentWord), Not(HasNER())))
+//
+type TokenPredicate func(token *nlp.Token) bool
+
+// FilterTokens returns the tokens of doc satisfying pred, in document order,
+// with their positions as ExtractTokenSpans gives them.
+//
+func FilterTokens(doc *nlp.Document, pred TokenPredicate) []*OffsetToken {
+	text := newDocText(doc)
+	var tokens []*OffsetToken
+	for i, s := range doc.GetSentence() {
+		for j, t := range s.Token {
+			if pred(t) {
+				tokens = append(tokens, newOffsetToken(text, i, j, t))
+			}
+		}
+	}
+	return tokens
+}
+
+// IsNoun reports whether the token is a common or proper noun.
+//
+func IsNoun(token *nlp.Token) bool {
+	return tags.IsNoun(token.GetPos())
+}
+
+// IsProperNoun reports whether the token is a proper noun.
+//
+func IsProperNoun(token *nlp.Token) bool {
+	return tags.IsProperNoun(token.GetPos())
+}
+
+// IsVerb reports whether the token is a verb in any form, modals aside.
+//
+func IsVerb(token *nlp.Token) bool {
+	return tags.IsVerb(token.GetPos())
+}
+
+// HasPOS returns a predicate testing the POS tag of the tokens with one of
+// the tags predicates, e.g. tags.IsAdjective.
+//
+func HasPOS(pred func(tag string) bool) TokenPredicate {
+	return func(token *nlp.Token) bool {
+		return pred(token.GetPos())
+	}
+}
+
+// HasNER returns a predicate selecting the tokens of the NER types, or of
+// any type but O if none is given.
+//
+func HasNER(types ...string) TokenPredicate {
+	return func(token *nlp.Token) bool {
+		ner := token.GetNer()
+		if len(types) == 0 {
+			return ner != "" && ner != tags.O
+		}
+		for _, t := range types {
+			if ner == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchesLemma returns a predicate selecting the tokens of the lemmas,
+// compared without case, see LemmaOf.
+//
+func MatchesLemma(lemmas ...string) TokenPredicate {
+	return func(token *nlp.Token) bool {
+		lemma := LemmaOf(token)
+		for _, l := range lemmas {
+			if strings.EqualFold(lemma, l) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// And returns a predicate satisfied when all the preds are.
+//
+func And(preds ...TokenPredicate) TokenPredicate {
+	return func(token *nlp.Token) bool {
+		for _, p := range preds {
+			if !p(token) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate satisfied when one of the preds is.
+//
+func Or(preds ...TokenPredicate) TokenPredicate {
+	return func(token *nlp.Token) bool {
+		for _, p := range preds {
+			if p(token) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a predicate satisfied when pred is not.
+//
+func Not(pred TokenPredicate) TokenPredicate {
+	return func(token *nlp.Token) bool {
+		return !pred(token)
+	}
+}
@@ -0,0 +1,45 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+func TestFilterTokens(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("John|NNP|John|PERSON bought|VBD|buy|O new|JJ|new|O shoes|NNS|shoe|O"),
+		testdoc.Sentence("He|PRP|he|O buys|VBZ|buy|O them|PRP|they|O in|IN|in|O Paris|NNP|Paris|CITY"),
+	)
+	words := func(tokens []*OffsetToken) string {
+		s := ""
+		for _, t := range tokens {
+			s += t.Token.GetWord() + " "
+		}
+		return s
+	}
+
+	for _, c := range []struct {
+		pred TokenPredicate
+		want string
+	}{
+		{IsNoun, "John shoes Paris "},
+		{IsProperNoun, "John Paris "},
+		{IsVerb, "bought buys "},
+		{HasNER(), "John Paris "},
+		{HasNER("CITY"), "Paris "},
+		{MatchesLemma("BUY"), "bought buys "},
+		{And(HasPOS(tags.IsContentWord), Not(HasNER())), "bought new shoes buys "},
+		{Or(HasPOS(tags.IsAdjective), HasPOS(tags.IsPronoun)), "new He them "},
+	} {
+		if got := words(FilterTokens(doc, c.pred)); got != c.want {
+			t.Errorf("%q, want %q", got, c.want)
+		}
+	}
+
+	paris := FilterTokens(doc, HasNER("CITY"))
+	if len(paris) != 1 || paris[0].Sentence != 1 || paris[0].Index != 4 || paris[0].BeginChar != 38 || paris[0].Text != "Paris" {
+		t.Errorf("%+v", paris)
+	}
+}
@@ -0,0 +1,54 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// GenderAnnotator is the annotator guessing the gender of the PERSON
+// mentions from the first names, after "ner".
+//
+const GenderAnnotator = "gender"
+
+// The genders of the "gender" annotator.
+//
+const (
+	Male   = "MALE"
+	Female = "FEMALE"
+)
+
+// ExtractGenders returns the genders the "gender" annotator guessed for the
+// PERSON entities of doc, by their canonical mention, see ExtractEntityMentions:
+// "Marie Curie" and "Curie" give one entry when coref or "ner" linked them.
+// An entity takes the gender of most of its mentions, and is left out on a
+// tie or without guesses.
+//
+func ExtractGenders(doc *nlp.Document) map[string]string {
+	votes := make(map[string]map[string]int)
+	for _, m := range ExtractEntityMentions(doc) {
+		if m.Type != tags.Person || m.Gender == "" {
+			continue
+		}
+		if votes[m.Canonical] == nil {
+			votes[m.Canonical] = make(map[string]int)
+		}
+		votes[m.Canonical][m.Gender]++
+	}
+
+	genders := make(map[string]string, len(votes))
+	for entity, counts := range votes {
+		best, tie := "", false
+		for g, n := range counts {
+			switch {
+			case best == "" || n > counts[best]:
+				best, tie = g, false
+			case n == counts[best]:
+				tie = true
+			}
+		}
+		if !tie {
+			genders[entity] = best
+		}
+	}
+	return genders
+}
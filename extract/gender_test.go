@@ -0,0 +1,25 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractGenders(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("Marie|NNP|Marie|PERSON Curie|NNP|Curie|PERSON met|VBD|meet|O John|NNP|John|PERSON ,|,|,|O Pat|NNP|Pat|PERSON and|CC|and|O Paris|NNP|Paris|CITY"),
+	)
+	tokens := doc.Sentence[0].Token
+	for _, i := range []int{0, 1} {
+		tokens[i].Gender = proto.String(Female)
+	}
+	tokens[3].Gender = proto.String(Male)
+	tokens[7].Gender = proto.String(Male)
+
+	genders := ExtractGenders(doc)
+	if len(genders) != 2 || genders["Marie Curie"] != Female || genders["John"] != Male {
+		t.Errorf("%#v", genders)
+	}
+}
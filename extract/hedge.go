@@ -0,0 +1,139 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// HedgeLexicon lists the lemmas that trigger hedging or modality.
+//
+type HedgeLexicon struct {
+	// modal auxiliaries, e.g. might, could
+	Modals map[string]bool
+
+	// hedging adverbs and adjectives, e.g. reportedly, likely
+	Adverbs map[string]bool
+
+	// verbs that hedge when they take an open clausal complement,
+	// e.g. appear in "appears to", seem in "seems to"
+	Verbs map[string]bool
+}
+
+// DefaultHedgeLexicon is the lexicon used when none is given.
+//
+var DefaultHedgeLexicon = &HedgeLexicon{
+	Modals: set("may", "might", "could", "would", "should", "can", "must", "shall"),
+	Adverbs: set("perhaps", "possibly", "probably", "likely", "unlikely", "maybe",
+		"reportedly", "allegedly", "apparently", "presumably", "seemingly",
+		"arguably", "supposedly", "purportedly", "roughly", "approximately"),
+	Verbs: set("appear", "seem", "tend", "suggest", "indicate", "believe",
+		"suspect", "assume", "estimate", "speculate"),
+}
+
+// Hedge is a flagged hedging or modal construction in a sentence.
+//
+type Hedge struct {
+	// index of the sentence in the document
+	Sentence int
+
+	// 0-based token span [Begin, End) covering the trigger and the word it modifies
+	Begin int
+	End   int
+
+	// the trigger words, e.g. "appears to"
+	Trigger string
+
+	// "modal", "adverb" or "verb"
+	Kind string
+}
+
+// DetectHedges flags modal and hedging constructions in doc.
+// lexicon is optional; DefaultHedgeLexicon is used if it is nil.
+//
+// The dependency graph is used when present to extend each span to the
+// word the trigger modifies; otherwise only the trigger itself is flagged.
+//
+func DetectHedges(doc *nlp.Document, lexicon ...*HedgeLexicon) []*Hedge {
+	lex := DefaultHedgeLexicon
+	if len(lexicon) > 0 && lexicon[0] != nil {
+		lex = lexicon[0]
+	}
+
+	var hedges []*Hedge
+	for i, sentence := range doc.GetSentence() {
+		heads := governors(basicGraph(sentence))
+		tokens := sentence.Token
+		for j, token := range tokens {
+			lemma := lower(token)
+			hedge := &Hedge{Sentence: i, Begin: j, End: j + 1, Trigger: token.GetWord()}
+			switch {
+			case lex.Modals[lemma] && (token.GetPos() == "" || token.GetPos() == tags.MD):
+				hedge.Kind = "modal"
+			case lex.Adverbs[lemma]:
+				hedge.Kind = "adverb"
+			case lex.Verbs[lemma] && tags.IsVerb(token.GetPos()):
+				to := complementMarker(tokens, j, heads)
+				if to < 0 {
+					continue
+				}
+				hedge.Kind = "verb"
+				hedge.End = to + 1
+				words := make([]string, 0, to-j+1)
+				for _, t := range tokens[j : to+1] {
+					words = append(words, t.GetWord())
+				}
+				hedge.Trigger = strings.Join(words, " ")
+			default:
+				continue
+			}
+
+			if edge, ok := heads[uint32(j+1)]; ok && hedge.Kind != "verb" {
+				head := int(edge.GetSource()) - 1
+				if head >= 0 && head < len(tokens) {
+					if head < hedge.Begin {
+						hedge.Begin = head
+					} else if head >= hedge.End {
+						hedge.End = head + 1
+					}
+				}
+			}
+			hedges = append(hedges, hedge)
+		}
+	}
+	return hedges
+}
+
+// complementMarker returns the index of the "to" or "that" introducing the
+// complement of the verb at j, or -1 if the verb takes no such complement.
+//
+func complementMarker(tokens []*nlp.Token, j int, heads map[uint32]*nlp.DependencyGraph_Edge) int {
+	for k := j + 1; k < len(tokens) && k <= j+3; k++ {
+		w := strings.ToLower(tokens[k].GetWord())
+		if w != "to" && w != "that" {
+			continue
+		}
+		if len(heads) == 0 {
+			return k
+		}
+		// the marker must attach to a complement of the verb
+		mark, ok := heads[uint32(k+1)]
+		if !ok {
+			continue
+		}
+		comp, ok := heads[mark.GetSource()]
+		if ok && int(comp.GetSource()) == j+1 {
+			return k
+		}
+	}
+	return -1
+}
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
@@ -0,0 +1,41 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+)
+
+func TestDetectHedges(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("He|PRP|he might|MD|might leave|VB|leave .|.|.",
+			"0>3:root", "3>1:nsubj", "3>2:aux", "3>4:punct"),
+		testdoc.Sentence("She|PRP|she appears|VBZ|appear to|TO|to agree|VB|agree .|.|.",
+			"0>2:root", "2>1:nsubj", "2>4:xcomp", "4>3:mark", "2>5:punct"),
+		testdoc.Sentence("It|PRP|it seems|VBZ|seem fine|JJ|fine .|.|.",
+			"0>2:root", "2>1:nsubj", "2>3:xcomp", "2>4:punct"),
+		testdoc.Sentence("Reportedly|RB|reportedly ,|,|, he|PRP|he left|VBD|leave"),
+	)
+
+	hedges := DetectHedges(doc)
+	if len(hedges) != 3 {
+		t.Fatalf("%#v", hedges)
+	}
+	h := hedges[0]
+	if h.Sentence != 0 || h.Begin != 1 || h.End != 3 || h.Trigger != "might" || h.Kind != "modal" {
+		t.Errorf("%#v", h)
+	}
+	h = hedges[1]
+	if h.Sentence != 1 || h.Begin != 1 || h.End != 3 || h.Trigger != "appears to" || h.Kind != "verb" {
+		t.Errorf("%#v", h)
+	}
+	h = hedges[2]
+	if h.Sentence != 3 || h.Begin != 0 || h.End != 1 || h.Trigger != "Reportedly" || h.Kind != "adverb" {
+		t.Errorf("%#v", h)
+	}
+
+	custom := &HedgeLexicon{Adverbs: set("fine")}
+	if hedges = DetectHedges(doc, custom); len(hedges) != 1 || hedges[0].Trigger != "fine" {
+		t.Errorf("%#v", hedges)
+	}
+}
@@ -0,0 +1,82 @@
+package extract
+
+import (
+	"sort"
+
+	"github.com/genelet/corenlp-golang/graph"
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tree"
+)
+
+// ParseAlternative is one of the k best parses of a sentence: a
+// constituency tree and the dependency analysis derived from it.
+//
+type ParseAlternative struct {
+	// 0 for the best parse
+	Rank int
+
+	// the log probability the parser gives the parse, 0 if none
+	Score float64
+
+	Tree *nlp.ParseTree
+
+	// the dependencies of Tree by the head rules, see tree.Node.Dependencies
+	Dependencies *nlp.DependencyGraph
+}
+
+// KBestParses returns the alternative parses of the sentence, best first,
+// from Sentence.KBestParseTrees as kept with client.WithKBest, or else the
+// parse of the sentence alone; nil if it has none. The dependency parser of
+// CoreNLP gives one analysis only, so the dependency analysis of every
+// alternative is derived from its tree, by the head rules, with the phrases
+// of the dependents for relations; compare them with CompareAnalyses.
+//
+func KBestParses(sentence *nlp.Sentence) []*ParseAlternative {
+	trees := sentence.GetKBestParseTrees()
+	if len(trees) == 0 && sentence.GetParseTree() != nil {
+		trees = []*nlp.ParseTree{sentence.GetParseTree()}
+	}
+	alternatives := make([]*ParseAlternative, 0, len(trees))
+	for _, t := range trees {
+		alternatives = append(alternatives, &ParseAlternative{Score: t.GetScore(), Tree: t, Dependencies: tree.New(t).Dependencies(sentence.GetSentenceIndex())})
+	}
+	sort.SliceStable(alternatives, func(i, j int) bool { return alternatives[i].Score > alternatives[j].Score })
+	for i, a := range alternatives {
+		a.Rank = i
+	}
+	if len(alternatives) == 0 {
+		return nil
+	}
+	return alternatives
+}
+
+// Bracket is a labeled constituent: the category of a phrase, without
+// function tags, over the 0-based token span [Begin, End).
+//
+type Bracket = tree.Span
+
+// ParseComparison compares two parses of a sentence by their brackets, the
+// phrases above the part-of-speech tags, as evalb does.
+//
+type ParseComparison = tree.SpanComparison
+
+// CompareParses compares the brackets of the parses a and b, e.g. two
+// alternatives of KBestParses, to see where the parser hesitates, see
+// tree.CompareSpans.
+//
+func CompareParses(a, b *nlp.ParseTree) *ParseComparison {
+	return tree.CompareSpans(tree.New(a), tree.New(b))
+}
+
+// AnalysisComparison compares two dependency analyses of a sentence token
+// by token.
+//
+type AnalysisComparison = graph.GraphDiff
+
+// CompareAnalyses compares the dependency analyses of two alternatives of
+// KBestParses of the sentence by their attachments and relations, e.g. a
+// prepositional phrase attached to the verb or to the noun, see graph.Diff.
+//
+func CompareAnalyses(sentence *nlp.Sentence, a, b *ParseAlternative) *AnalysisComparison {
+	return graph.Diff(graph.New(a.Dependencies, sentence.GetToken()), graph.New(b.Dependencies, sentence.GetToken()))
+}
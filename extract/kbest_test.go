@@ -0,0 +1,58 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/graph"
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tree"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestKBestParses(t *testing.T) {
+	parse := func(s string, score float64) *nlp.ParseTree {
+		p, err := tree.Parse(s)
+		if err != nil { t.Fatal(err) }
+		p.Score = proto.Float64(score)
+		return p
+	}
+	// PP attachment to the verb, or to the noun
+	verb := parse("(ROOT (S (NP (PRP I)) (VP (VBD saw) (NP (DT the) (NN man)) (PP (IN with) (NP (DT a) (NN telescope))))))", -40.5)
+	noun := parse("(ROOT (S (NP (PRP I)) (VP (VBD saw) (NP (NP (DT the) (NN man)) (PP (IN with) (NP (DT a) (NN telescope)))))))", -39.2)
+
+	s := &nlp.Sentence{ParseTree: noun, KBestParseTrees: []*nlp.ParseTree{verb, noun}}
+	alternatives := KBestParses(s)
+	if len(alternatives) != 2 || alternatives[0].Tree != noun || alternatives[0].Rank != 0 || alternatives[1].Score != -40.5 {
+		t.Fatalf("%+v", alternatives)
+	}
+	if one := KBestParses(&nlp.Sentence{ParseTree: verb}); len(one) != 1 || one[0].Tree != verb {
+		t.Errorf("%v", one)
+	}
+	if KBestParses(&nlp.Sentence{}) != nil {
+		t.Errorf("no parse")
+	}
+
+	c := CompareParses(noun, verb)
+	// S, NP I, VP, NP the man, PP, NP a telescope in common; the NP the man with a telescope in noun only
+	if len(c.Common) != 6 || len(c.OnlyA) != 1 || c.OnlyA[0] != (Bracket{Label: "NP", Begin: 2, End: 7}) || len(c.OnlyB) != 0 {
+		t.Errorf("%+v", c)
+	}
+	if c.F1 < 0.92 || c.F1 > 0.93 {
+		t.Errorf("%v", c.F1)
+	}
+	if c := CompareParses(verb, verb); c.F1 != 1 || len(c.OnlyA)+len(c.OnlyB) != 0 {
+		t.Errorf("%+v", c)
+	}
+
+	// "with" depends on "man" in the best analysis, on "saw" in the other
+	d := CompareAnalyses(s, alternatives[0], alternatives[1])
+	if d.Tokens != 7 || len(d.Diffs) != 1 || d.Diffs[0].Index != 5 || !d.Diffs[0].Attachment() {
+		t.Fatalf("%+v", d)
+	}
+	if a, b := d.Diffs[0].A, d.Diffs[0].B; len(a) != 1 || a[0] != (graph.Arc{Head: 4, Relation: "PP"}) || len(b) != 1 || b[0] != (graph.Arc{Head: 2, Relation: "PP"}) {
+		t.Errorf("%v %v", a, b)
+	}
+	if d.UAS < 0.85 || d.UAS > 0.86 || d.LAS != d.UAS {
+		t.Errorf("%v %v", d.UAS, d.LAS)
+	}
+}
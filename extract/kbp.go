@@ -0,0 +1,109 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// KBPTriple is a relation of the "kbp" annotator, e.g. per:city_of_birth.
+//
+type KBPTriple struct {
+	// index of the sentence of the first occurrence in the document
+	Sentence int
+
+	// surface forms of the first occurrence
+	Subject string
+	Object  string
+
+	// the KBP relation, lower-cased, e.g. "per:city_of_birth"
+	Relation string
+
+	// the representative mentions of the coreference chains of the subject
+	// and the object, e.g. "Barack Obama" for "He"; the surface forms otherwise
+	SubjectEntity string
+	ObjectEntity  string
+
+	// the highest confidence among the occurrences, 1 if none was given
+	Confidence float64
+
+	// token spans of the first occurrence
+	SubjectSpan TokenSpan
+	ObjectSpan  TokenSpan
+
+	// number of occurrences merged into the triple
+	Count int
+}
+
+// ExtractKBPTriples returns the KBP relations of doc, in document order.
+// The relations between the same canonical entities are merged into the
+// first occurrence, keeping the highest confidence, so "Obama was born in
+// Hawaii" and "He was born in Hawaii" give one triple when the "coref"
+// annotator linked the two subjects.
+//
+func ExtractKBPTriples(doc *nlp.Document) []*KBPTriple {
+	var triples []*KBPTriple
+	seen := make(map[string]*KBPTriple)
+	for i, s := range doc.GetSentence() {
+		for _, t := range s.GetKbpTriple() {
+			triple := &KBPTriple{
+				Sentence:    i,
+				Relation:    strings.ToLower(strings.TrimSpace(t.GetRelation())),
+				SubjectSpan: tokenSpan(t.SubjectTokens),
+				ObjectSpan:  tokenSpan(t.ObjectTokens),
+				Confidence:  1,
+				Count:       1,
+			}
+			triple.Subject = tripleText(t.Subject, s, triple.SubjectSpan)
+			triple.Object = tripleText(t.Object, s, triple.ObjectSpan)
+			triple.SubjectEntity = canonical(doc, i, triple.SubjectSpan, triple.Subject)
+			triple.ObjectEntity = canonical(doc, i, triple.ObjectSpan, triple.Object)
+			if t.Confidence != nil {
+				triple.Confidence = t.GetConfidence()
+			}
+
+			key := tripleKey(triple.SubjectEntity, triple.Relation, triple.ObjectEntity)
+			if first, ok := seen[key]; ok {
+				first.Count++
+				if triple.Confidence > first.Confidence {
+					first.Confidence = triple.Confidence
+				}
+				continue
+			}
+			seen[key] = triple
+			triples = append(triples, triple)
+		}
+	}
+	return triples
+}
+
+// canonical returns the text of the representative mention of the chain
+// whose smallest mention overlaps the span of sentence, or surface if none does.
+//
+func canonical(doc *nlp.Document, sentence int, span TokenSpan, surface string) string {
+	if span.Begin < 0 {
+		return surface
+	}
+	var best *nlp.CorefChain
+	size := 0
+	for _, chain := range doc.GetCorefChain() {
+		for _, m := range chain.Mention {
+			begin, end := int(m.GetBeginIndex()), int(m.GetEndIndex())
+			if int(m.GetSentenceIndex()) != sentence || begin >= span.End || span.Begin >= end {
+				continue
+			}
+			if best == nil || end-begin < size {
+				best, size = chain, end-begin
+			}
+		}
+	}
+	if best == nil {
+		return surface
+	}
+	if rep := representative(doc, best); rep != nil {
+		if text := mentionText(doc, rep); text != "" {
+			return text
+		}
+	}
+	return surface
+}
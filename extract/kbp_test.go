@@ -0,0 +1,44 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractKBPTriples(t *testing.T) {
+	loc := func(i uint32) *nlp.TokenLocation {
+		return &nlp.TokenLocation{TokenIndex: proto.Uint32(i)}
+	}
+	doc := testdoc.Doc(
+		testdoc.Sentence("Barack|NNP Obama|NNP was|VBD born|VBN in|IN Hawaii|NNP"),
+		testdoc.Sentence("He|PRP was|VBD born|VBN in|IN Hawaii|NNP"),
+	)
+	doc.Sentence[0].KbpTriple = []*nlp.RelationTriple{{Relation: proto.String("per:stateorprovince_of_birth"), Confidence: proto.Float64(0.7),
+		SubjectTokens: []*nlp.TokenLocation{loc(0), loc(1)}, ObjectTokens: []*nlp.TokenLocation{loc(5)}}}
+	doc.Sentence[1].KbpTriple = []*nlp.RelationTriple{{Relation: proto.String("PER:stateorprovince_of_birth"), Confidence: proto.Float64(0.9),
+		SubjectTokens: []*nlp.TokenLocation{loc(0)}, ObjectTokens: []*nlp.TokenLocation{loc(4)}}}
+	doc.CorefChain = []*nlp.CorefChain{{ChainID: proto.Int32(1), Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+		{SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(0), EndIndex: proto.Uint32(2)},
+		{SentenceIndex: proto.Uint32(1), BeginIndex: proto.Uint32(0), EndIndex: proto.Uint32(1)},
+	}}}
+
+	triples := ExtractKBPTriples(doc)
+	if len(triples) != 1 {
+		t.Fatalf("%#v", triples)
+	}
+	tr := triples[0]
+	if tr.Subject != "Barack Obama" || tr.Object != "Hawaii" || tr.Relation != "per:stateorprovince_of_birth" || tr.Confidence != 0.9 || tr.Count != 2 {
+		t.Errorf("%#v", tr)
+	}
+	if tr.SubjectEntity != "Barack Obama" || tr.ObjectEntity != "Hawaii" || tr.SubjectSpan != (TokenSpan{0, 2}) {
+		t.Errorf("%#v", tr)
+	}
+
+	doc.CorefChain = nil
+	if triples = ExtractKBPTriples(doc); len(triples) != 2 || triples[1].SubjectEntity != "He" {
+		t.Errorf("%#v", triples)
+	}
+}
@@ -0,0 +1,165 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// lemmaExceptions lists irregular English forms and their lemmas.
+//
+var lemmaExceptions = map[string]string{
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be", "'s": "be", "'re": "be", "'m": "be",
+	"has": "have", "had": "have", "having": "have", "'ve": "have", "'d": "have",
+	"does": "do", "did": "do", "done": "do", "n't": "not", "'ll": "will", "wo": "will", "ca": "can",
+	"went": "go", "gone": "go", "goes": "go", "made": "make", "said": "say", "says": "say",
+	"took": "take", "taken": "take", "came": "come", "saw": "see", "seen": "see",
+	"knew": "know", "known": "know", "got": "get", "gotten": "get", "gave": "give", "given": "give",
+	"found": "find", "thought": "think", "told": "tell", "became": "become", "left": "leave",
+	"felt": "feel", "brought": "bring", "began": "begin", "begun": "begin", "kept": "keep",
+	"held": "hold", "wrote": "write", "written": "write", "stood": "stand", "heard": "hear",
+	"meant": "mean", "met": "meet", "ran": "run", "paid": "pay", "sat": "sit", "spoke": "speak",
+	"spoken": "speak", "led": "lead", "grew": "grow", "grown": "grow", "lost": "lose",
+	"fell": "fall", "fallen": "fall", "sent": "send", "built": "build", "understood": "understand",
+	"drew": "draw", "drawn": "draw", "broke": "break", "broken": "break", "spent": "spend",
+	"rose": "rise", "risen": "rise", "drove": "drive", "driven": "drive", "bought": "buy",
+	"wore": "wear", "worn": "wear", "chose": "choose", "chosen": "choose", "sold": "sell",
+	"caught": "catch", "taught": "teach", "fought": "fight", "ate": "eat", "eaten": "eat",
+	"flew": "fly", "flown": "fly", "forgot": "forget", "forgotten": "forget", "hid": "hide",
+	"hidden": "hide", "won": "win", "shot": "shoot", "slept": "sleep", "stole": "steal",
+	"stolen": "steal", "threw": "throw", "thrown": "throw", "woke": "wake", "woken": "wake",
+	"lay": "lie", "lain": "lie", "laid": "lay", "dying": "die", "lying": "lie", "tying": "tie",
+	"men": "man", "women": "woman", "children": "child", "feet": "foot", "teeth": "tooth",
+	"mice": "mouse", "geese": "goose", "oxen": "ox", "lives": "life", "wives": "wife",
+	"knives": "knife", "leaves": "leaf", "wolves": "wolf", "halves": "half", "selves": "self",
+	"better": "good", "best": "good", "worse": "bad", "worst": "bad",
+	"this": "this", "his": "he", "its": "it", "us": "we", "as": "as",
+	"news": "news", "series": "series", "species": "species", "data": "datum",
+}
+
+// Lemma returns an approximate lemma of the English word, for documents
+// annotated without the "lemma" annotator. pos, the Penn Treebank tag, is
+// optional; with it the verb, noun and adjective rules are applied only to the
+// matching words, without it every rule is tried.
+//
+func Lemma(word, pos string) string {
+	if tags.IsProperNoun(pos) || pos == tags.POS {
+		return word
+	}
+	w := strings.ToLower(word)
+	if lemma, ok := lemmaExceptions[w]; ok {
+		return lemma
+	}
+	if len(w) < 4 || tags.IsPunctuation(pos) {
+		return w
+	}
+
+	switch {
+	case pos == "" || pos == tags.NNS || pos == tags.VBZ:
+		if lemma, ok := plural(w); ok {
+			return lemma
+		}
+	}
+	switch {
+	case pos == "" || pos == tags.VBD || pos == tags.VBN:
+		if strings.HasSuffix(w, "ied") {
+			return w[:len(w)-3] + "y"
+		}
+		if strings.HasSuffix(w, "ed") {
+			if lemma, ok := stem(w[:len(w)-2]); ok {
+				return lemma
+			}
+		}
+	}
+	switch {
+	case pos == "" || pos == tags.VBG:
+		if strings.HasSuffix(w, "ing") {
+			if lemma, ok := stem(w[:len(w)-3]); ok {
+				return lemma
+			}
+		}
+	}
+	switch pos {
+	case tags.JJR, tags.RBR:
+		return comparative(w, "er")
+	case tags.JJS, tags.RBS:
+		return comparative(w, "est")
+	}
+	return w
+}
+
+// LemmaOf returns the lemma of the token given by the "lemma" annotator,
+// or the one computed by Lemma from its POS tag if the annotator did not run.
+// A token without a POS tag gets its lower-cased word, as guessing the suffix
+// to strip would break words such as "nothing".
+//
+func LemmaOf(token *nlp.Token) string {
+	if token.Lemma != nil {
+		return token.GetLemma()
+	}
+	if token.GetPos() == "" {
+		return strings.ToLower(token.GetWord())
+	}
+	return Lemma(token.GetWord(), token.GetPos())
+}
+
+func plural(w string) (string, bool) {
+	switch {
+	case strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y", true
+	case strings.HasSuffix(w, "sses"), strings.HasSuffix(w, "ches"), strings.HasSuffix(w, "shes"),
+		strings.HasSuffix(w, "xes"), strings.HasSuffix(w, "zes"), strings.HasSuffix(w, "oes"):
+		return w[:len(w)-2], true
+	case strings.HasSuffix(w, "ss"), strings.HasSuffix(w, "us"), strings.HasSuffix(w, "is"):
+		return w, false
+	case strings.HasSuffix(w, "s"):
+		return w[:len(w)-1], true
+	}
+	return w, false
+}
+
+// stem restores the base form of a verb stem with -ed or -ing removed:
+// doubled consonants are undoubled (stopp to stop), and the silent e
+// is restored after v, z, c and u (lov to love, danc to dance).
+//
+func stem(s string) (string, bool) {
+	if !strings.ContainsAny(s, "aeiouy") || len(s) < 2 {
+		return "", false
+	}
+	n := len(s)
+	last := s[n-1]
+	switch {
+	case last == s[n-2] && strings.IndexByte("bdgmnprt", last) >= 0:
+		return s[:n-1], true
+	case strings.IndexByte("vzcu", last) >= 0:
+		return s + "e", true
+	case n <= 3 && isConsonant(last) && isVowel(s[n-2]) && isConsonant(s[0]) && last != 'w' && last != 'x' && last != 'y':
+		// short words such as hop(e), mak(e), us(e)
+		return s + "e", true
+	}
+	return s, true
+}
+
+func comparative(w, suffix string) string {
+	if !strings.HasSuffix(w, suffix) {
+		return w
+	}
+	s := w[:len(w)-len(suffix)]
+	n := len(s)
+	switch {
+	case n > 1 && s[n-1] == 'i':
+		return s[:n-1] + "y"
+	case n > 2 && s[n-1] == s[n-2] && isConsonant(s[n-1]):
+		return s[:n-1]
+	}
+	return s
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+func isConsonant(c byte) bool {
+	return c >= 'a' && c <= 'z' && !isVowel(c)
+}
@@ -0,0 +1,53 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLemma(t *testing.T) {
+	for _, c := range [][3]string{
+		{"was", "VBD", "be"},
+		{"Went", "", "go"},
+		{"children", "NNS", "child"},
+		{"studies", "VBZ", "study"},
+		{"boxes", "NNS", "box"},
+		{"cats", "", "cat"},
+		{"class", "NN", "class"},
+		{"stopped", "VBD", "stop"},
+		{"loved", "VBN", "love"},
+		{"walked", "", "walk"},
+		{"hoped", "VBD", "hope"},
+		{"running", "VBG", "run"},
+		{"dancing", "VBG", "dance"},
+		{"thing", "", "thing"},
+		{"bigger", "JJR", "big"},
+		{"happiest", "JJS", "happy"},
+		{"Paris", "NNP", "Paris"},
+		{"appears", "VBZ", "appear"},
+	} {
+		if got := Lemma(c[0], c[1]); got != c[2] {
+			t.Errorf("%s/%s: %s", c[0], c[1], got)
+		}
+	}
+}
+
+func TestLemmaOf(t *testing.T) {
+	for _, c := range []struct {
+		token *nlp.Token
+		want  string
+	}{
+		{&nlp.Token{Word: proto.String("ran"), Lemma: proto.String("run")}, "run"},
+		{&nlp.Token{Word: proto.String("running"), Pos: proto.String("VBG")}, "run"},
+		{&nlp.Token{Word: proto.String("Nothing"), Pos: proto.String("NN")}, "nothing"},
+		// without a POS tag no suffix is stripped
+		{&nlp.Token{Word: proto.String("Nothing")}, "nothing"},
+		{&nlp.Token{Word: proto.String("cats")}, "cats"},
+	} {
+		if got := LemmaOf(c.token); got != c.want {
+			t.Errorf("%s: %s", c.token.GetWord(), got)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// EntityMention is a named entity mention of the "ner" annotator, with its
+// position and normalized value.
+//
+type EntityMention struct {
+	// index of the sentence in the document
+	Sentence int
+
+	// the text of the mention, e.g. "next Tuesday"
+	Text string
+
+	// the NER type, e.g. tags.Date
+	Type string
+
+	// character offsets [BeginChar, EndChar) in the document text, in UTF-16 units
+	BeginChar uint32
+	EndChar   uint32
+
+	// 0-based token span [TokenBegin, TokenEnd) in the sentence
+	TokenBegin int
+	TokenEnd   int
+
+	// the normalized value, e.g. "2024-01-02" for a date or "$100.0" for
+	// money, and the TIMEX3 value of temporal expressions; "" if none
+	Normalized string
+	Timex      string
+
+	// the Wikipedia page of the entity linked by "entitylink", "" if none
+	Wikipedia string
+
+	// the text of the canonical mention of the entity, e.g. "Barack Obama"
+	// for "Obama", the mention itself if none
+	Canonical string
+
+	// the lowest probability of the NER label of its tokens, see
+	// NERConfidence; -1 if CoreNLP gave none
+	Confidence float64
+
+	// the gender guessed by the "gender" annotator for a PERSON, MALE or
+	// FEMALE, "" if none
+	Gender string
+}
+
+// ExtractEntityMentions returns the entity mentions of doc in document
+// order, from Sentence.Mentions as set by the "ner" annotator. Sentences
+// without mentions fall back to the runs of tokens of the same NER type, as
+// ExtractNamedEntities, with the normalized value of their first token.
+//
+func ExtractEntityMentions(doc *nlp.Document) []*EntityMention {
+	text := newDocText(doc)
+	var mentions []*EntityMention
+	// the mentions by index in the document, which the canonical indexes count
+	var indexed []*EntityMention
+	var canonical []*nlp.NERMention
+	for i, s := range doc.GetSentence() {
+		if len(s.Mentions) == 0 {
+			for _, run := range tags.EntityRuns(s.Token) {
+				m := newEntityMention(text, i, s, run.Begin, run.End, run.NER)
+				first := s.Token[run.Begin]
+				m.Normalized = first.GetNormalizedNER()
+				m.Timex = first.GetTimexValue().GetValue()
+				m.Wikipedia = first.GetWikipediaEntity()
+				m.Gender = first.GetGender()
+				mentions = append(mentions, m)
+			}
+			continue
+		}
+		for _, nm := range s.Mentions {
+			indexed = append(indexed, nil)
+			canonical = append(canonical, nm)
+			begin, end := int(nm.GetTokenStartInSentenceInclusive()), int(nm.GetTokenEndInSentenceExclusive())
+			if begin >= end || end > len(s.Token) {
+				continue
+			}
+			m := newEntityMention(text, i, s, begin, end, nm.GetNer())
+			if nm.EntityMentionText != nil {
+				m.Text = nm.GetEntityMentionText()
+			}
+			m.Normalized = nm.GetNormalizedNER()
+			m.Timex = nm.GetTimex().GetValue()
+			m.Wikipedia = nm.GetWikipediaEntity()
+			if m.Wikipedia == tags.O {
+				m.Wikipedia = ""
+			}
+			m.Gender = nm.GetGender()
+			if m.Gender == "" {
+				m.Gender = s.Token[begin].GetGender()
+			}
+			indexed[len(indexed)-1] = m
+			mentions = append(mentions, m)
+		}
+	}
+
+	for i, m := range indexed {
+		c := int(canonical[i].GetCanonicalEntityMentionIndex())
+		if m != nil && canonical[i].CanonicalEntityMentionIndex != nil && c < len(indexed) && indexed[c] != nil {
+			m.Canonical = indexed[c].Text
+		}
+	}
+	for _, m := range mentions {
+		if m.Canonical == "" {
+			m.Canonical = m.Text
+		}
+	}
+	return mentions
+}
+
+// newEntityMention creates the mention of the tokens [begin, end) of sentence s,
+// its text taken from the document, or else the words.
+//
+func newEntityMention(text *docText, sentence int, s *nlp.Sentence, begin, end int, ner string) *EntityMention {
+	first, last := s.Token[begin], s.Token[end-1]
+	m := &EntityMention{Sentence: sentence, Type: ner, TokenBegin: begin, TokenEnd: end,
+		BeginChar: first.GetBeginChar(), EndChar: last.GetEndChar(), Confidence: mentionConfidence(s.Token[begin:end])}
+	if t, ok := text.slice(first.GetBeginChar(), last.GetEndChar()); ok && first.BeginChar != nil && last.EndChar != nil {
+		m.Text = t
+	} else {
+		m.Text = DetokenizeTokens(s.Token[begin:end])
+	}
+	return m
+}
@@ -0,0 +1,50 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractEntityMentions(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("Barack|NNP|Barack|PERSON Obama|NNP|Obama|PERSON paid|VBD|pay $|$|$|MONEY 100|CD|100|MONEY"),
+		testdoc.Sentence("Obama|NNP|Obama|PERSON left|VBD|leave today|NN|today|DATE"),
+	)
+	doc.Sentence[0].Mentions = []*nlp.NERMention{
+		{Ner: proto.String("PERSON"), TokenStartInSentenceInclusive: proto.Uint32(0), TokenEndInSentenceExclusive: proto.Uint32(2), CanonicalEntityMentionIndex: proto.Uint32(0)},
+		{Ner: proto.String("MONEY"), TokenStartInSentenceInclusive: proto.Uint32(3), TokenEndInSentenceExclusive: proto.Uint32(5), NormalizedNER: proto.String("$100.0")},
+	}
+	doc.Sentence[1].Mentions = []*nlp.NERMention{
+		{Ner: proto.String("PERSON"), TokenStartInSentenceInclusive: proto.Uint32(0), TokenEndInSentenceExclusive: proto.Uint32(1), CanonicalEntityMentionIndex: proto.Uint32(0)},
+		{Ner: proto.String("DATE"), TokenStartInSentenceInclusive: proto.Uint32(2), TokenEndInSentenceExclusive: proto.Uint32(3), NormalizedNER: proto.String("2024-01-02"),
+			Timex: &nlp.Timex{Value: proto.String("2024-01-02"), Type: proto.String("DATE")}},
+	}
+
+	mentions := ExtractEntityMentions(doc)
+	if len(mentions) != 4 {
+		t.Fatalf("%#v", mentions)
+	}
+	m := mentions[0]
+	if m.Text != "Barack Obama" || m.Type != "PERSON" || m.BeginChar != 0 || m.EndChar != 12 || m.TokenEnd != 2 || m.Canonical != "Barack Obama" {
+		t.Errorf("%#v", m)
+	}
+	if m = mentions[1]; m.Text != "$ 100" || m.Normalized != "$100.0" {
+		t.Errorf("%#v", m)
+	}
+	if m = mentions[2]; m.Sentence != 1 || m.Text != "Obama" || m.Canonical != "Barack Obama" || m.BeginChar != 24 {
+		t.Errorf("%#v", m)
+	}
+	if m = mentions[3]; m.Normalized != "2024-01-02" || m.Timex != "2024-01-02" || m.Canonical != "today" {
+		t.Errorf("%#v", m)
+	}
+
+	doc.Sentence[1].Mentions = nil
+	doc.Sentence[1].Token[2].NormalizedNER = proto.String("2024-01-02")
+	mentions = ExtractEntityMentions(doc)
+	if len(mentions) != 4 || mentions[3].Type != "DATE" || mentions[3].Normalized != "2024-01-02" || mentions[3].TokenBegin != 2 {
+		t.Errorf("%#v", mentions[3])
+	}
+}
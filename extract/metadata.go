@@ -0,0 +1,105 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// TokenField selects the optional fields ExtractTokenMetadata fills; the
+// fields combine with |.
+//
+type TokenField uint
+
+const (
+	// TokenIndex fills Sentence and Index.
+	TokenIndex TokenField = 1 << iota
+	// TokenOffsets fills BeginChar and EndChar.
+	TokenOffsets
+	// TokenOriginalText fills OriginalText.
+	TokenOriginalText
+	// TokenSpeaker fills Speaker and SpeakerType.
+	TokenSpeaker
+	// TokenNormalizedNER fills NormalizedNER.
+	TokenNormalizedNER
+	// TokenTrueCase fills TrueCase and TrueCaseText.
+	TokenTrueCase
+	// TokenNERProbs fills NERProbs.
+	TokenNERProbs
+
+	// AllTokenFields fills every field.
+	AllTokenFields = TokenIndex | TokenOffsets | TokenOriginalText | TokenSpeaker | TokenNormalizedNER | TokenTrueCase | TokenNERProbs
+)
+
+// TokenWithMetadata is a token with its tags, and the optional fields its
+// TokenField mask selected; the others are left zero.
+//
+type TokenWithMetadata struct {
+	Word  string
+	POS   string
+	Lemma string
+	NER   string
+
+	// 0-based sentence index in the document, and token index in the sentence
+	Sentence int
+	Index    int
+
+	// character offsets [BeginChar, EndChar) in the document text, in UTF-16 units
+	BeginChar uint32
+	EndChar   uint32
+
+	// the token as written, e.g. "(" for the word "-LRB-"
+	OriginalText string
+
+	// the speaker of the utterance, e.g. "PER0", and its type, e.g. "LIST"
+	Speaker     string
+	SpeakerType string
+
+	// the normalized value of the entity, e.g. "2024-05-01" for "May 1st"
+	NormalizedNER string
+
+	// the case of the truecase annotator, e.g. "INIT_UPPER", and the word in that case
+	TrueCase     string
+	TrueCaseText string
+
+	// the probabilities of the NER labels, see NERProbabilities
+	NERProbs map[string]float64
+}
+
+// ExtractTokenMetadata returns the tokens of doc in document order, with the
+// word, POS, lemma and NER, and the optional fields selected by fields, e.g.
+// TokenIndex|TokenOffsets. Fields whose annotation is missing are left zero.
+//
+func ExtractTokenMetadata(doc *nlp.Document, fields TokenField) []*TokenWithMetadata {
+	n := 0
+	for _, s := range doc.GetSentence() {
+		n += len(s.Token)
+	}
+	tokens := make([]*TokenWithMetadata, 0, n)
+	for i, s := range doc.GetSentence() {
+		for j, t := range s.Token {
+			m := &TokenWithMetadata{Word: t.GetWord(), POS: t.GetPos(), Lemma: t.GetLemma(), NER: t.GetNer()}
+			if fields&TokenIndex != 0 {
+				m.Sentence, m.Index = i, j
+			}
+			if fields&TokenOffsets != 0 {
+				m.BeginChar, m.EndChar = t.GetBeginChar(), t.GetEndChar()
+			}
+			if fields&TokenOriginalText != 0 {
+				m.OriginalText = t.GetOriginalText()
+			}
+			if fields&TokenSpeaker != 0 {
+				m.Speaker, m.SpeakerType = t.GetSpeaker(), t.GetSpeakerType()
+			}
+			if fields&TokenNormalizedNER != 0 {
+				m.NormalizedNER = t.GetNormalizedNER()
+			}
+			if fields&TokenTrueCase != 0 {
+				m.TrueCase, m.TrueCaseText = t.GetTrueCase(), t.GetTrueCaseText()
+			}
+			if fields&TokenNERProbs != 0 {
+				m.NERProbs = NERProbabilities(t)
+			}
+			tokens = append(tokens, m)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,41 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractTokenMetadata(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("Hi|UH|hi|O"),
+		testdoc.Sentence("john|NNP|john|PERSON left|VBD|leave|O tomorrow|NN|tomorrow|DATE"),
+	)
+	john := doc.Sentence[1].Token[0]
+	john.Speaker = proto.String("PER0")
+	john.TrueCase = proto.String("INIT_UPPER")
+	john.TrueCaseText = proto.String("John")
+	doc.Sentence[1].Token[2].NormalizedNER = proto.String("OFFSET P1D")
+
+	tokens := ExtractTokenMetadata(doc, TokenIndex|TokenTrueCase)
+	if len(tokens) != 4 {
+		t.Fatalf("%d tokens", len(tokens))
+	}
+	if m := tokens[1]; m.Word != "john" || m.POS != "NNP" || m.Lemma != "john" || m.NER != "PERSON" || m.Sentence != 1 || m.Index != 0 ||
+		m.TrueCaseText != "John" || m.TrueCase != "INIT_UPPER" || m.Speaker != "" || m.BeginChar != 0 || m.EndChar != 0 {
+		t.Errorf("%#v", m)
+	}
+
+	tokens = ExtractTokenMetadata(doc, AllTokenFields)
+	if m := tokens[1]; m.Speaker != "PER0" || m.OriginalText != "john" || m.BeginChar != 3 || m.EndChar != 7 {
+		t.Errorf("%#v", m)
+	}
+	if m := tokens[3]; m.NormalizedNER != "OFFSET P1D" || m.Index != 2 {
+		t.Errorf("%#v", m)
+	}
+
+	if m := ExtractTokenMetadata(doc, 0)[3]; m.Sentence != 0 || m.NormalizedNER != "" || m.Word != "tomorrow" {
+		t.Errorf("%#v", m)
+	}
+}
@@ -0,0 +1,102 @@
+package extract
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// NGramOptions selects the n-grams of ExtractNGrams. The zero value counts
+// lower-cased words, within sentences and between punctuation.
+//
+type NGramOptions struct {
+	// use the lemmas, see LemmaOf, instead of the words
+	Lemmas bool
+
+	// keep the case of the words
+	KeepCase bool
+
+	// let n-grams span sentence boundaries
+	CrossSentences bool
+
+	// count the punctuation tokens as words, instead of breaking n-grams
+	KeepPunctuation bool
+
+	// the n-grams seen fewer times are left out
+	MinCount int
+}
+
+// NGram is a sequence of n words, or lemmas, with the number of its occurrences.
+//
+type NGram struct {
+	Words []string
+	Count int
+}
+
+// String returns the words joined by spaces.
+//
+func (self *NGram) String() string {
+	return strings.Join(self.Words, " ")
+}
+
+// ExtractNGrams returns the n-grams of doc, most frequent first, then in
+// alphabetical order, for collocation and phrase mining.
+//
+// For example, the bigrams of lemmas:
+// ExtractNGrams(doc, 2, NGramOptions{Lemmas: true})
+//
+func ExtractNGrams(doc *nlp.Document, n int, opts NGramOptions) []*NGram {
+	if n <= 0 {
+		return nil
+	}
+	counts := make(map[string]*NGram)
+	var window []string
+	for _, s := range doc.GetSentence() {
+		if !opts.CrossSentences {
+			window = window[:0]
+		}
+		for _, t := range s.Token {
+			if !opts.KeepPunctuation && tags.IsPunctuation(t.GetPos()) {
+				window = window[:0]
+				continue
+			}
+			w := t.GetWord()
+			if opts.Lemmas {
+				w = LemmaOf(t)
+			}
+			if !opts.KeepCase {
+				w = strings.ToLower(w)
+			}
+			window = append(window, w)
+			if len(window) > n {
+				window = window[1:]
+			}
+			if len(window) < n {
+				continue
+			}
+			key := strings.Join(window, "\x00")
+			g, ok := counts[key]
+			if !ok {
+				g = &NGram{Words: append([]string{}, window...)}
+				counts[key] = g
+			}
+			g.Count++
+		}
+	}
+
+	ngrams := make([]*NGram, 0, len(counts))
+	for _, g := range counts {
+		if g.Count >= opts.MinCount {
+			ngrams = append(ngrams, g)
+		}
+	}
+	sort.Slice(ngrams, func(i, j int) bool {
+		if ngrams[i].Count != ngrams[j].Count {
+			return ngrams[i].Count > ngrams[j].Count
+		}
+		return ngrams[i].String() < ngrams[j].String()
+	})
+	return ngrams
+}
@@ -0,0 +1,45 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+)
+
+func TestExtractNGrams(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("New|NNP|New|O York|NNP|York|O is|VBZ|be|O big|JJ|big|O .|.|.|O"),
+		testdoc.Sentence("New|NNP|New|O York|NNP|York|O ,|,|,|O new|JJ|new|O yorkers|NNS|yorker|O"),
+	)
+
+	ngrams := ExtractNGrams(doc, 2, NGramOptions{})
+	var got []string
+	for _, g := range ngrams {
+		got = append(got, g.String())
+	}
+	if len(ngrams) != 4 || ngrams[0].String() != "new york" || ngrams[0].Count != 2 || got[1] != "is big" || got[3] != "york is" {
+		t.Errorf("%q", got)
+	}
+
+	if ngrams := ExtractNGrams(doc, 2, NGramOptions{MinCount: 2, KeepCase: true}); len(ngrams) != 1 || ngrams[0].String() != "New York" {
+		t.Errorf("%v", ngrams)
+	}
+	if ngrams := ExtractNGrams(doc, 2, NGramOptions{Lemmas: true, MinCount: 1}); ngrams[len(ngrams)-1].String() != "york be" {
+		t.Errorf("%v", ngrams)
+	}
+
+	// across the period and the comma
+	all := ExtractNGrams(doc, 3, NGramOptions{CrossSentences: true, KeepPunctuation: true})
+	found := false
+	for _, g := range all {
+		if g.String() == "big . new" {
+			found = true
+		}
+	}
+	if len(all) != 8 || !found {
+		t.Errorf("%d %v", len(all), found)
+	}
+	if ExtractNGrams(doc, 0, NGramOptions{}) != nil {
+		t.Errorf("0-grams")
+	}
+}
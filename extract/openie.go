@@ -0,0 +1,121 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// TokenSpan is a 0-based token span [Begin, End) in a sentence; both are -1
+// when the annotator gave no tokens.
+//
+type TokenSpan struct {
+	Begin int
+	End   int
+}
+
+// OpenIETriple is a (subject, relation, object) extraction of the "openie" annotator.
+//
+type OpenIETriple struct {
+	// index of the sentence of the first occurrence in the document
+	Sentence int
+
+	Subject  string
+	Relation string
+	Object   string
+
+	// the highest confidence among the occurrences, 1 if none was given
+	Confidence float64
+
+	// token spans of the first occurrence
+	SubjectSpan  TokenSpan
+	RelationSpan TokenSpan
+	ObjectSpan   TokenSpan
+
+	// number of occurrences merged into the triple
+	Count int
+}
+
+// ExtractOpenIETriples returns the OpenIE triples of doc, in document order.
+// Triples repeated in the document, with the same subject, relation and
+// object up to case and spacing, are merged into the first occurrence,
+// keeping the highest confidence. The surface forms fall back to the words of
+// the token spans when missing.
+//
+func ExtractOpenIETriples(doc *nlp.Document) []*OpenIETriple {
+	var triples []*OpenIETriple
+	seen := make(map[string]*OpenIETriple)
+	for i, s := range doc.GetSentence() {
+		for _, t := range s.GetOpenieTriple() {
+			triple := &OpenIETriple{
+				Sentence:     i,
+				SubjectSpan:  tokenSpan(t.SubjectTokens),
+				RelationSpan: tokenSpan(t.RelationTokens),
+				ObjectSpan:   tokenSpan(t.ObjectTokens),
+				Confidence:   1,
+				Count:        1,
+			}
+			triple.Subject = tripleText(t.Subject, s, triple.SubjectSpan)
+			triple.Relation = tripleText(t.Relation, s, triple.RelationSpan)
+			triple.Object = tripleText(t.Object, s, triple.ObjectSpan)
+			if t.Confidence != nil {
+				triple.Confidence = t.GetConfidence()
+			}
+
+			key := tripleKey(triple.Subject, triple.Relation, triple.Object)
+			if first, ok := seen[key]; ok {
+				first.Count++
+				if triple.Confidence > first.Confidence {
+					first.Confidence = triple.Confidence
+				}
+				continue
+			}
+			seen[key] = triple
+			triples = append(triples, triple)
+		}
+	}
+	return triples
+}
+
+// tokenSpan returns the span from the first to the last of the locations.
+//
+func tokenSpan(locs []*nlp.TokenLocation) TokenSpan {
+	if len(locs) == 0 {
+		return TokenSpan{-1, -1}
+	}
+	span := TokenSpan{int(locs[0].GetTokenIndex()), int(locs[0].GetTokenIndex()) + 1}
+	for _, l := range locs[1:] {
+		if int(l.GetTokenIndex()) < span.Begin {
+			span.Begin = int(l.GetTokenIndex())
+		}
+		if int(l.GetTokenIndex())+1 > span.End {
+			span.End = int(l.GetTokenIndex()) + 1
+		}
+	}
+	return span
+}
+
+// tripleText returns the surface form if set, or the words of the span.
+//
+func tripleText(surface *string, s *nlp.Sentence, span TokenSpan) string {
+	if surface != nil {
+		return *surface
+	}
+	if span.Begin < 0 || span.End > len(s.Token) {
+		return ""
+	}
+	var words []string
+	for _, token := range s.Token[span.Begin:span.End] {
+		words = append(words, token.GetWord())
+	}
+	return Detokenize(words)
+}
+
+// tripleKey identifies the triples with the same parts up to case and spacing.
+//
+func tripleKey(parts ...string) string {
+	for i, p := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(p), " "))
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,41 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractOpenIETriples(t *testing.T) {
+	loc := func(i uint32) *nlp.TokenLocation {
+		return &nlp.TokenLocation{TokenIndex: proto.Uint32(i)}
+	}
+	doc := testdoc.Doc(
+		testdoc.Sentence("Obama|NNP was|VBD born|VBN in|IN Hawaii|NNP"),
+		testdoc.Sentence("Obama|NNP was|VBD born|VBN in|IN Hawaii|NNP"),
+	)
+	doc.Sentence[0].OpenieTriple = []*nlp.RelationTriple{{Subject: proto.String("Obama"), Relation: proto.String("was born in"), Object: proto.String("Hawaii"), Confidence: proto.Float64(0.8),
+		SubjectTokens: []*nlp.TokenLocation{loc(0)}, RelationTokens: []*nlp.TokenLocation{loc(1), loc(2), loc(3)}, ObjectTokens: []*nlp.TokenLocation{loc(4)}}}
+	doc.Sentence[1].OpenieTriple = []*nlp.RelationTriple{
+		{SubjectTokens: []*nlp.TokenLocation{loc(0)}, RelationTokens: []*nlp.TokenLocation{loc(3), loc(1), loc(2)}, ObjectTokens: []*nlp.TokenLocation{loc(4)}, Confidence: proto.Float64(0.9)},
+		{Subject: proto.String("Obama"), Relation: proto.String("was"), Object: proto.String("born")},
+	}
+
+	triples := ExtractOpenIETriples(doc)
+	if len(triples) != 2 {
+		t.Fatalf("%#v", triples)
+	}
+	tr := triples[0]
+	if tr.Sentence != 0 || tr.Subject != "Obama" || tr.Relation != "was born in" || tr.Object != "Hawaii" || tr.Confidence != 0.9 || tr.Count != 2 {
+		t.Errorf("%#v", tr)
+	}
+	if tr.SubjectSpan != (TokenSpan{0, 1}) || tr.RelationSpan != (TokenSpan{1, 4}) || tr.ObjectSpan != (TokenSpan{4, 5}) {
+		t.Errorf("%#v", tr)
+	}
+	tr = triples[1]
+	if tr.Sentence != 1 || tr.Confidence != 1 || tr.Count != 1 || tr.SubjectSpan != (TokenSpan{-1, -1}) {
+		t.Errorf("%#v", tr)
+	}
+}
@@ -0,0 +1,179 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// pronounFeatures lists third-person pronouns with their gender and number,
+// in the vocabulary of CorefChain_CorefMention.
+//
+var pronounFeatures = map[string][2]string{
+	"he": {"MALE", "SINGULAR"}, "him": {"MALE", "SINGULAR"}, "his": {"MALE", "SINGULAR"}, "himself": {"MALE", "SINGULAR"},
+	"she": {"FEMALE", "SINGULAR"}, "her": {"FEMALE", "SINGULAR"}, "hers": {"FEMALE", "SINGULAR"}, "herself": {"FEMALE", "SINGULAR"},
+	"it": {"NEUTRAL", "SINGULAR"}, "its": {"NEUTRAL", "SINGULAR"}, "itself": {"NEUTRAL", "SINGULAR"},
+	"they": {"UNKNOWN", "PLURAL"}, "them": {"UNKNOWN", "PLURAL"}, "their": {"UNKNOWN", "PLURAL"}, "theirs": {"UNKNOWN", "PLURAL"}, "themselves": {"UNKNOWN", "PLURAL"},
+}
+
+// Pronoun is a third-person pronoun found in the document.
+//
+type Pronoun struct {
+	// index of the sentence, and 0-based index of the token in the sentence
+	Sentence int
+	Token    int
+
+	Word string
+
+	// ID of the coref chain containing the pronoun, -1 if none
+	ChainID int
+
+	// the representative mention of the chain, empty if unresolved
+	Antecedent string
+
+	// number of entities in the current and the previous sentence
+	// agreeing with the pronoun in gender and number
+	Candidates int
+}
+
+// PronounReport summarizes how well the coreference annotator resolved pronouns.
+//
+type PronounReport struct {
+	// all third-person pronouns
+	Pronouns []*Pronoun
+
+	// pronouns linked to a chain with a nominal or proper mention
+	Resolved []*Pronoun
+
+	// pronouns in no chain, or in a chain made of pronouns only
+	Unresolved []*Pronoun
+
+	// pronouns with more than one agreeing candidate antecedent nearby
+	Ambiguous []*Pronoun
+
+	// chain size (number of mentions) to the number of chains of that size
+	ChainSizes map[int]int
+}
+
+// UnresolvedRate returns the fraction of pronouns left unresolved.
+//
+func (self *PronounReport) UnresolvedRate() float64 {
+	if len(self.Pronouns) == 0 {
+		return 0
+	}
+	return float64(len(self.Unresolved)) / float64(len(self.Pronouns))
+}
+
+// AmbiguityRate returns the fraction of pronouns with competing antecedents.
+// A high rate suggests the neural coref algorithm is worth its cost.
+//
+func (self *PronounReport) AmbiguityRate() float64 {
+	if len(self.Pronouns) == 0 {
+		return 0
+	}
+	return float64(len(self.Ambiguous)) / float64(len(self.Pronouns))
+}
+
+// ReportPronouns analyzes the coref chains of doc.
+// The document should be annotated with at least "pos" and "coref".
+//
+func ReportPronouns(doc *nlp.Document) *PronounReport {
+	report := &PronounReport{ChainSizes: make(map[int]int)}
+
+	// a token belongs to the chain of the smallest mention around it, so
+	// that "his" in "his mother" is not taken for the mother
+	type key struct{ sentence, token uint32 }
+	chainOf := make(map[key]*nlp.CorefChain)
+	width := make(map[key]uint32)
+	for _, chain := range doc.GetCorefChain() {
+		report.ChainSizes[len(chain.Mention)]++
+		for _, m := range chain.Mention {
+			w := m.GetEndIndex() - m.GetBeginIndex()
+			for i := m.GetBeginIndex(); i < m.GetEndIndex(); i++ {
+				k := key{m.GetSentenceIndex(), i}
+				if chainOf[k] == nil || w < width[k] {
+					chainOf[k], width[k] = chain, w
+				}
+			}
+		}
+	}
+
+	for i, sentence := range doc.GetSentence() {
+		for j, token := range sentence.Token {
+			word := strings.ToLower(token.GetWord())
+			features, ok := pronounFeatures[word]
+			if !ok || (token.GetPos() != tags.PRP && token.GetPos() != tags.PRPS) {
+				continue
+			}
+
+			p := &Pronoun{Sentence: i, Token: j, Word: token.GetWord(), ChainID: -1}
+			p.Candidates = candidates(doc, i, j, features)
+			report.Pronouns = append(report.Pronouns, p)
+			if p.Candidates > 1 {
+				report.Ambiguous = append(report.Ambiguous, p)
+			}
+
+			chain := chainOf[key{uint32(i), uint32(j)}]
+			if chain != nil {
+				p.ChainID = int(chain.GetChainID())
+				if rep := representative(doc, chain); rep != nil && rep.GetMentionType() != "PRONOMINAL" {
+					p.Antecedent = mentionText(doc, rep)
+					report.Resolved = append(report.Resolved, p)
+					continue
+				}
+			}
+			report.Unresolved = append(report.Unresolved, p)
+		}
+	}
+	return report
+}
+
+// candidates counts the chains with a non-pronominal mention agreeing with
+// features, located in the previous sentence or before the pronoun.
+//
+func candidates(doc *nlp.Document, sentence, token int, features [2]string) int {
+	n := 0
+	for _, chain := range doc.GetCorefChain() {
+		for _, m := range chain.Mention {
+			s := int(m.GetSentenceIndex())
+			if m.GetMentionType() == "PRONOMINAL" || s < sentence-1 || s > sentence || (s == sentence && int(m.GetBeginIndex()) >= token) {
+				continue
+			}
+			if agree(m.GetGender(), features[0]) && agree(m.GetNumber(), features[1]) {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+func agree(a, b string) bool {
+	return a == b || a == "" || b == "" || a == "UNKNOWN" || b == "UNKNOWN"
+}
+
+// representative returns the representative mention of the chain.
+//
+func representative(doc *nlp.Document, chain *nlp.CorefChain) *nlp.CorefChain_CorefMention {
+	rep := int(chain.GetRepresentative())
+	if rep < len(chain.Mention) {
+		return chain.Mention[rep]
+	}
+	return nil
+}
+
+// mentionText returns the words of the mention joined by spaces.
+//
+func mentionText(doc *nlp.Document, m *nlp.CorefChain_CorefMention) string {
+	s := int(m.GetSentenceIndex())
+	if s >= len(doc.GetSentence()) {
+		return ""
+	}
+	tokens := doc.Sentence[s].Token
+	var words []string
+	for i := int(m.GetBeginIndex()); i < int(m.GetEndIndex()) && i < len(tokens); i++ {
+		words = append(words, tokens[i].GetWord())
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,72 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func corefMention(sentence, begin, end uint32, typ, gender, number string) *nlp.CorefChain_CorefMention {
+	return &nlp.CorefChain_CorefMention{SentenceIndex: proto.Uint32(sentence), BeginIndex: proto.Uint32(begin), EndIndex: proto.Uint32(end), MentionType: proto.String(typ), Gender: proto.String(gender), Number: proto.String(number)}
+}
+
+func TestReportPronouns(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("John|NNP met|VBD Bill|NNP .|."),
+		testdoc.Sentence("He|PRP smiled|VBD and|CC it|PRP rained|VBD .|."),
+	)
+	doc.CorefChain = []*nlp.CorefChain{
+		{ChainID: proto.Int32(1), Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+			corefMention(0, 0, 1, "PROPER", "MALE", "SINGULAR"),
+			corefMention(1, 0, 1, "PRONOMINAL", "MALE", "SINGULAR"),
+		}},
+		{ChainID: proto.Int32(2), Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+			corefMention(0, 2, 3, "PROPER", "MALE", "SINGULAR"),
+		}},
+	}
+
+	r := ReportPronouns(doc)
+	if len(r.Pronouns) != 2 || len(r.Resolved) != 1 || len(r.Unresolved) != 1 || len(r.Ambiguous) != 1 {
+		t.Fatalf("%#v", r)
+	}
+	he := r.Resolved[0]
+	if he.Word != "He" || he.ChainID != 1 || he.Antecedent != "John" || he.Candidates != 2 {
+		t.Errorf("%#v", he)
+	}
+	if it := r.Unresolved[0]; it.Word != "it" || it.ChainID != -1 || it.Candidates != 0 {
+		t.Errorf("%#v", it)
+	}
+	if r.ChainSizes[2] != 1 || r.ChainSizes[1] != 1 {
+		t.Errorf("%v", r.ChainSizes)
+	}
+	if r.UnresolvedRate() != 0.5 || r.AmbiguityRate() != 0.5 {
+		t.Errorf("%f %f", r.UnresolvedRate(), r.AmbiguityRate())
+	}
+}
+
+func TestReportPronounsNested(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("John|NNP smiled|VBD .|."),
+		testdoc.Sentence("His|PRP$ mother|NN left|VBD .|."),
+	)
+	doc.CorefChain = []*nlp.CorefChain{
+		{ChainID: proto.Int32(1), Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+			corefMention(0, 0, 1, "PROPER", "MALE", "SINGULAR"),
+			corefMention(1, 0, 1, "PRONOMINAL", "MALE", "SINGULAR"),
+		}},
+		// "His mother" covers the token of "His"
+		{ChainID: proto.Int32(2), Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+			corefMention(1, 0, 2, "NOMINAL", "FEMALE", "SINGULAR"),
+		}},
+	}
+
+	r := ReportPronouns(doc)
+	if len(r.Resolved) != 1 {
+		t.Fatalf("%#v", r)
+	}
+	if his := r.Resolved[0]; his.Word != "His" || his.ChainID != 1 || his.Antecedent != "John" {
+		t.Errorf("%#v", his)
+	}
+}
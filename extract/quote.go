@@ -0,0 +1,96 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Quote is a quotation found by the "quote" annotator, with its speaker
+// when "quote.attribution" ran.
+//
+type Quote struct {
+	// index of the quote in the document
+	Index int
+
+	// the text of the quote, quotation marks included
+	Text string
+
+	// character offsets [BeginChar, EndChar) in the document text, in UTF-16 units
+	BeginChar uint32
+	EndChar   uint32
+
+	// the sentences [SentenceBegin, SentenceEnd) the quote spans
+	SentenceBegin int
+	SentenceEnd   int
+
+	// the tokens [TokenBegin, TokenEnd) the quote spans, indexed in the document
+	TokenBegin int
+	TokenEnd   int
+
+	// the canonical speaker, e.g. "Barack Obama", or else the speaker, or
+	// else the mention the quote is attributed to; "" if unattributed
+	Speaker string
+
+	// the mention the quote is attributed to, e.g. "he", and its type, e.g. "pronoun"
+	Mention     string
+	MentionType string
+}
+
+// ExtractQuotes returns the quotes of doc, in document order. The sentence
+// and token ranges are computed from the character offsets when the
+// document has tokens, and otherwise taken from the annotator, whose ends
+// are inclusive.
+//
+func ExtractQuotes(doc *nlp.Document) []*Quote {
+	text := newDocText(doc)
+	var quotes []*Quote
+	for i, q := range doc.GetQuote() {
+		quote := &Quote{
+			Index:         i,
+			Text:          q.GetText(),
+			BeginChar:     q.GetBegin(),
+			EndChar:       q.GetEnd(),
+			SentenceBegin: int(q.GetSentenceBegin()),
+			SentenceEnd:   int(q.GetSentenceEnd()) + 1,
+			TokenBegin:    int(q.GetTokenBegin()),
+			TokenEnd:      int(q.GetTokenEnd()) + 1,
+			Mention:       q.GetMention(),
+			MentionType:   q.GetMentionType(),
+		}
+		if q.Index != nil {
+			quote.Index = int(q.GetIndex())
+		}
+		if quote.Text == "" {
+			quote.Text, _ = text.slice(q.GetBegin(), q.GetEnd())
+		}
+		quoteRanges(doc, quote)
+
+		for _, speaker := range []string{q.GetCanonicalMention(), q.GetSpeaker(), q.GetMention()} {
+			if speaker != "" && speaker != "Unknown" {
+				quote.Speaker = speaker
+				break
+			}
+		}
+		quotes = append(quotes, quote)
+	}
+	return quotes
+}
+
+// quoteRanges sets the sentence and token ranges of the quote to those of
+// the tokens within its character offsets, if any.
+//
+func quoteRanges(doc *nlp.Document, quote *Quote) {
+	first := true
+	index := 0
+	for i, s := range doc.GetSentence() {
+		for _, t := range s.Token {
+			if t.BeginChar != nil && t.GetBeginChar() >= quote.BeginChar && t.GetEndChar() <= quote.EndChar {
+				if first {
+					quote.SentenceBegin, quote.TokenBegin = i, index
+					first = false
+				}
+				quote.SentenceEnd, quote.TokenEnd = i+1, index+1
+			}
+			index++
+		}
+	}
+}
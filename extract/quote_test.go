@@ -0,0 +1,38 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractQuotes(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("Obama|NNP said|VBD :|:"),
+		testdoc.Sentence("\"|`` Yes|UH .|. \"|''"),
+		testdoc.Sentence("\"|`` No|UH \"|''"),
+	)
+	doc.Quote = []*nlp.Quote{
+		{Text: proto.String(`" Yes . "`), Begin: proto.Uint32(13), End: proto.Uint32(22), Index: proto.Uint32(0),
+			Mention: proto.String("Obama"), MentionType: proto.String("name"), Speaker: proto.String("Obama"), CanonicalMention: proto.String("Barack Obama")},
+		{Begin: proto.Uint32(23), End: proto.Uint32(29), Index: proto.Uint32(1), Speaker: proto.String("Unknown")},
+	}
+
+	quotes := ExtractQuotes(doc)
+	if len(quotes) != 2 {
+		t.Fatalf("%#v", quotes)
+	}
+	q := quotes[0]
+	if q.Text != `" Yes . "` || q.SentenceBegin != 1 || q.SentenceEnd != 2 || q.TokenBegin != 3 || q.TokenEnd != 7 {
+		t.Errorf("%#v", q)
+	}
+	if q.Speaker != "Barack Obama" || q.Mention != "Obama" || q.MentionType != "name" {
+		t.Errorf("%#v", q)
+	}
+	q = quotes[1]
+	if q.Index != 1 || q.Text != `" No "` || q.SentenceBegin != 2 || q.TokenBegin != 7 || q.TokenEnd != 10 || q.Speaker != "" {
+		t.Errorf("%#v", q)
+	}
+}
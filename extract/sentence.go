@@ -0,0 +1,75 @@
+package extract
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// JoinStrategy decides how ExtractSentences reconstructs a sentence from its tokens.
+//
+type JoinStrategy int
+
+const (
+	// JoinSpaces joins the words with single spaces, e.g. "Hello , world ."
+	JoinSpaces JoinStrategy = iota
+	// JoinOffsets cuts the original text by the sentence's character offsets,
+	// falling back to JoinDetokenized when offsets or text are missing.
+	JoinOffsets
+	// JoinDetokenized joins the words with smart punctuation spacing, e.g. "Hello, world."
+	JoinDetokenized
+	// JoinLemmas joins the lemmas with single spaces, e.g. "he be go ."
+	JoinLemmas
+	// JoinOriginal rebuilds the text as written from the tokens and their
+	// whitespace, see SentenceText, e.g. "Hello, world."
+	JoinOriginal
+)
+
+// ExtractSentences returns the text of each sentence in doc.
+// strategy is optional, default to JoinSpaces.
+//
+func ExtractSentences(doc *nlp.Document, strategy ...JoinStrategy) []string {
+	how := JoinSpaces
+	if len(strategy) > 0 {
+		how = strategy[0]
+	}
+
+	var text *docText
+	if how == JoinOffsets {
+		text = newDocText(doc)
+	}
+
+	sentences := make([]string, 0, len(doc.GetSentence()))
+	for _, sentence := range doc.GetSentence() {
+		sentences = append(sentences, joinSentence(sentence, how, text))
+	}
+	return sentences
+}
+
+func joinSentence(sentence *nlp.Sentence, how JoinStrategy, text *docText) string {
+	words := make([]string, len(sentence.Token))
+	for i, token := range sentence.Token {
+		words[i] = token.GetWord()
+	}
+
+	switch how {
+	case JoinOffsets:
+		if sentence.CharacterOffsetBegin != nil && sentence.CharacterOffsetEnd != nil {
+			if s, ok := text.slice(sentence.GetCharacterOffsetBegin(), sentence.GetCharacterOffsetEnd()); ok && s != "" {
+				return s
+			}
+		}
+		return Detokenize(words)
+	case JoinDetokenized:
+		return Detokenize(words)
+	case JoinOriginal:
+		return SentenceText(sentence)
+	case JoinLemmas:
+		for i, token := range sentence.Token {
+			if token.Lemma != nil {
+				words[i] = token.GetLemma()
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
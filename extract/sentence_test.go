@@ -0,0 +1,45 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractSentences(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("He|PRP|he is|VBZ|be n't|RB|not here|RB|here .|.|."),
+		testdoc.Sentence("Hello|UH|hello ,|,|, (|-LRB-|( world|NN|world )|-RRB-|) !|.|!"),
+	)
+	doc.Text = proto.String("He isn't here. Hello, (world)!")
+	doc.Sentence[0].CharacterOffsetBegin = proto.Uint32(0)
+	doc.Sentence[0].CharacterOffsetEnd = proto.Uint32(14)
+	doc.Sentence[1].CharacterOffsetBegin = proto.Uint32(15)
+	doc.Sentence[1].CharacterOffsetEnd = proto.Uint32(30)
+
+	for _, c := range []struct {
+		how  JoinStrategy
+		want [2]string
+	}{
+		{JoinSpaces, [2]string{"He is n't here .", "Hello , ( world ) !"}},
+		{JoinOffsets, [2]string{"He isn't here.", "Hello, (world)!"}},
+		{JoinDetokenized, [2]string{"He isn't here.", "Hello, (world)!"}},
+		{JoinLemmas, [2]string{"he be not here .", "hello , ( world ) !"}},
+	} {
+		got := ExtractSentences(doc, c.how)
+		if len(got) != 2 || got[0] != c.want[0] || got[1] != c.want[1] {
+			t.Errorf("%d: %q", c.how, got)
+		}
+	}
+
+	if got := ExtractSentences(doc); got[0] != "He is n't here ." {
+		t.Errorf("%q", got)
+	}
+
+	// out-of-range offsets fall back to detokenizing
+	doc.Text = nil
+	if got := ExtractSentences(doc, JoinOffsets); got[0] != "He isn't here." {
+		t.Errorf("%q", got)
+	}
+}
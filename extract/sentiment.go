@@ -0,0 +1,165 @@
+package extract
+
+import (
+	"math"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// SentimentLabels are the labels of the five sentiment classes of CoreNLP,
+// from 0, very negative, to 4, very positive.
+//
+var SentimentLabels = []string{"Very negative", "Negative", "Neutral", "Positive", "Very positive"}
+
+// SentimentAggregate selects how ExtractSentiment combines the sentences.
+//
+type SentimentAggregate int
+
+const (
+	// SentimentMean averages the classes of the sentences.
+	SentimentMean SentimentAggregate = iota
+	// SentimentLengthWeighted averages the classes weighted by the sentence lengths in tokens.
+	SentimentLengthWeighted
+	// SentimentMajority takes the most frequent class, the closest to neutral on a tie.
+	SentimentMajority
+)
+
+// SentenceSentiment is the sentiment of a sentence.
+//
+type SentenceSentiment struct {
+	// index of the sentence in the document
+	Sentence int
+
+	// class from 0, very negative, to 4, very positive, -1 if unknown
+	Class int
+
+	// one of SentimentLabels, "" if unknown
+	Label string
+
+	// share of the phrases of the sentiment tree in each class, nil without
+	// the tree: the protobuf output keeps no class probabilities, so this is
+	// how much of the sentence leans each way
+	Distribution []float64
+}
+
+// DocumentSentiment is the sentiment of the sentences of a document and
+// their aggregate.
+//
+type DocumentSentiment struct {
+	Sentences []*SentenceSentiment
+
+	// aggregated class, -1 if no sentence has a sentiment
+	Class int
+	Label string
+
+	// the aggregated class before rounding, from 0 to 4; for SentimentMajority, the class
+	Score float64
+
+	// share of the sentences in each class, weighted as the aggregate
+	Distribution []float64
+}
+
+// ExtractSentiment returns the sentiment of each sentence, from
+// Sentence.Sentiment or else the root of the sentiment tree, and the
+// document aggregate, by default SentimentMean. It needs the "sentiment"
+// annotator; sentences without it are left out of the aggregate.
+//
+func ExtractSentiment(doc *nlp.Document, aggregate ...SentimentAggregate) *DocumentSentiment {
+	how := SentimentMean
+	if len(aggregate) > 0 {
+		how = aggregate[0]
+	}
+
+	result := &DocumentSentiment{Class: -1, Distribution: make([]float64, len(SentimentLabels))}
+	var total float64
+	for i, s := range doc.GetSentence() {
+		ss := &SentenceSentiment{Sentence: i, Class: sentimentClass(s)}
+		if ss.Class >= 0 {
+			ss.Label = SentimentLabels[ss.Class]
+		}
+		ss.Distribution = treeDistribution(s.GetAnnotatedParseTree())
+		result.Sentences = append(result.Sentences, ss)
+
+		if ss.Class < 0 {
+			continue
+		}
+		weight := 1.0
+		if how == SentimentLengthWeighted {
+			weight = float64(len(s.Token))
+		}
+		result.Distribution[ss.Class] += weight
+		result.Score += weight * float64(ss.Class)
+		total += weight
+	}
+	if total == 0 {
+		result.Score = 0
+		return result
+	}
+	for c := range result.Distribution {
+		result.Distribution[c] /= total
+	}
+
+	if how == SentimentMajority {
+		best := -1
+		for c, share := range result.Distribution {
+			if share == 0 {
+				continue
+			}
+			if best < 0 || share > result.Distribution[best] ||
+				share == result.Distribution[best] && math.Abs(float64(c-2)) < math.Abs(float64(best-2)) {
+				best = c
+			}
+		}
+		result.Class, result.Score = best, float64(best)
+	} else {
+		result.Score /= total
+		result.Class = int(math.Round(result.Score))
+	}
+	result.Label = SentimentLabels[result.Class]
+	return result
+}
+
+// sentimentClass reads the class of the sentence from its label, e.g.
+// "Verypositive" or "Very positive", or else from its sentiment tree.
+//
+func sentimentClass(s *nlp.Sentence) int {
+	label := strings.ToLower(strings.Join(strings.Fields(s.GetSentiment()), ""))
+	for c, l := range SentimentLabels {
+		if label != "" && label == strings.ToLower(strings.Join(strings.Fields(l), "")) {
+			return c
+		}
+	}
+	if t := s.GetAnnotatedParseTree(); t != nil && t.Sentiment != nil {
+		return int(t.GetSentiment())
+	}
+	return -1
+}
+
+// treeDistribution returns the share of the nodes of the tree in each class,
+// nil if none has a sentiment.
+//
+func treeDistribution(t *nlp.ParseTree) []float64 {
+	counts := make([]float64, len(SentimentLabels))
+	n := 0
+	var walk func(t *nlp.ParseTree)
+	walk = func(t *nlp.ParseTree) {
+		if t.Sentiment != nil && int(t.GetSentiment()) < len(counts) {
+			counts[t.GetSentiment()]++
+			n++
+		}
+		for _, c := range t.Child {
+			walk(c)
+		}
+	}
+	if t != nil {
+		walk(t)
+	}
+	if n == 0 {
+		return nil
+	}
+	for c := range counts {
+		counts[c] /= float64(n)
+	}
+	return counts
+}
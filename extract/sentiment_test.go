@@ -0,0 +1,52 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractSentiment(t *testing.T) {
+	class := func(c nlp.Sentiment) *nlp.Sentiment { return &c }
+	doc := testdoc.Doc(
+		testdoc.Sentence("Great|JJ|great movie|NN|movie"),
+		testdoc.Sentence("Bad|JJ|bad ,|,|, really|RB|really bad|JJ|bad plot|NN|plot"),
+		testdoc.Sentence("OK|UH|ok"),
+		testdoc.Sentence("Nothing|NN|nothing"),
+	)
+	doc.Sentence[0].Sentiment = proto.String("Verypositive")
+	doc.Sentence[0].AnnotatedParseTree = &nlp.ParseTree{Sentiment: class(nlp.Sentiment_STRONG_POSITIVE), Child: []*nlp.ParseTree{
+		{Sentiment: class(nlp.Sentiment_WEAK_POSITIVE)},
+		{Sentiment: class(nlp.Sentiment_NEUTRAL)},
+		{Sentiment: class(nlp.Sentiment_STRONG_POSITIVE)},
+	}}
+	doc.Sentence[1].Sentiment = proto.String("Negative")
+	// no label, the root of the tree gives the class
+	doc.Sentence[2].AnnotatedParseTree = &nlp.ParseTree{Sentiment: class(nlp.Sentiment_NEUTRAL)}
+
+	s := ExtractSentiment(doc)
+	if len(s.Sentences) != 4 || s.Sentences[0].Class != 4 || s.Sentences[0].Label != "Very positive" || s.Sentences[2].Class != 2 || s.Sentences[3].Class != -1 {
+		t.Fatalf("%#v", s.Sentences)
+	}
+	if d := s.Sentences[0].Distribution; d[4] != 0.5 || d[3] != 0.25 || d[2] != 0.25 || s.Sentences[1].Distribution != nil {
+		t.Errorf("%v", d)
+	}
+	// (4 + 1 + 2) / 3
+	if s.Class != 2 || s.Label != "Neutral" || s.Score < 2.33 || s.Score > 2.34 || s.Distribution[1] < 0.33 || s.Distribution[1] > 0.34 {
+		t.Errorf("%#v", s)
+	}
+
+	// (4*2 + 1*5 + 2*1) / 8
+	if s = ExtractSentiment(doc, SentimentLengthWeighted); s.Score != 1.875 || s.Class != 2 {
+		t.Errorf("%#v", s)
+	}
+	// a three-way tie goes to neutral
+	if s = ExtractSentiment(doc, SentimentMajority); s.Class != 2 || s.Label != "Neutral" {
+		t.Errorf("%#v", s)
+	}
+	if s = ExtractSentiment(&nlp.Document{}); s.Class != -1 || s.Label != "" {
+		t.Errorf("%#v", s)
+	}
+}
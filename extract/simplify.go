@@ -0,0 +1,261 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// SimplifyLevel is how aggressively Simplify drops modifiers. Each level
+// drops what the previous ones drop.
+//
+type SimplifyLevel int
+
+const (
+	// SimplifyLight drops parentheticals and appositions.
+	SimplifyLight SimplifyLevel = iota
+	// SimplifyModerate also drops relative clauses, discourse markers and vocatives.
+	SimplifyModerate
+	// SimplifyAggressive also drops adjectival, adverbial, nominal and oblique
+	// modifiers and adverbial clauses, but never negations or possessives.
+	SimplifyAggressive
+)
+
+// Simplification is a simplified variant of a sentence.
+//
+type Simplification struct {
+	// 0-based sentence index in the document
+	Sentence int
+	Level    SimplifyLevel
+	Text     string
+
+	// 0-based token indexes in the sentence of the tokens kept
+	Kept []int
+}
+
+// Simplify returns a simplified variant of every sentence of doc, at level
+// or SimplifyModerate by default. The modifiers are found in the basic
+// dependencies, and parentheticals also by their brackets; a sentence without
+// dependencies only loses its parentheticals.
+//
+func Simplify(doc *nlp.Document, level ...SimplifyLevel) []*Simplification {
+	l := SimplifyModerate
+	if len(level) > 0 {
+		l = level[0]
+	}
+	var simplified []*Simplification
+	for i, sentence := range doc.GetSentence() {
+		simplified = append(simplified, simplify(i, sentence, l))
+	}
+	return simplified
+}
+
+// SimplifyVariants returns, for every sentence of doc, its distinct
+// simplifications from the lightest to the most aggressive.
+//
+func SimplifyVariants(doc *nlp.Document) [][]*Simplification {
+	var variants [][]*Simplification
+	for i, sentence := range doc.GetSentence() {
+		var vs []*Simplification
+		for l := SimplifyLight; l <= SimplifyAggressive; l++ {
+			s := simplify(i, sentence, l)
+			if len(vs) == 0 || len(vs[len(vs)-1].Kept) != len(s.Kept) {
+				vs = append(vs, s)
+			}
+		}
+		variants = append(variants, vs)
+	}
+	return variants
+}
+
+func simplify(index int, sentence *nlp.Sentence, level SimplifyLevel) *Simplification {
+	tokens := sentence.Token
+	drop := make([]bool, len(tokens))
+	dropBrackets(tokens, drop)
+
+	spans := make(map[uint32][2]int)
+	if g := basicGraph(sentence); g != nil {
+		children := make(map[uint32][]uint32)
+		for _, edge := range g.Edge {
+			children[edge.GetSource()] = append(children[edge.GetSource()], edge.GetTarget())
+		}
+		for _, edge := range g.Edge {
+			span := subtreeSpan(len(tokens), children, edge.GetTarget(), spans)
+			if span[0] <= span[1] && droppable(edge.GetDep(), tokens, edge.GetTarget(), level) {
+				for i := span[0]; i <= span[1]; i++ {
+					drop[i] = true
+				}
+			}
+		}
+	}
+	dropDelimiters(tokens, drop, spans)
+
+	s := &Simplification{Sentence: index, Level: level}
+	var kept []*nlp.Token
+	for i, token := range tokens {
+		if !drop[i] {
+			s.Kept = append(s.Kept, i)
+			kept = append(kept, token)
+		}
+	}
+	s.Text = DetokenizeTokens(kept)
+	return s
+}
+
+// droppable reports whether the dependent of relation dep can be dropped at level.
+//
+func droppable(dep string, tokens []*nlp.Token, target uint32, level SimplifyLevel) bool {
+	switch dep {
+	case tags.Appos:
+		return true
+	// rcmod is the relative clause of the Stanford Dependencies
+	case tags.AclRelcl, "rcmod", tags.Discourse, tags.Vocative:
+		return level >= SimplifyModerate
+	case tags.NmodPoss, tags.Poss, tags.Neg:
+		return false
+	}
+	if level < SimplifyAggressive {
+		return false
+	}
+	switch tags.BaseRelation(dep) {
+	case tags.Amod, tags.Nmod, tags.Obl, tags.Advcl, tags.Prep:
+		return true
+	case tags.Advmod:
+		// not, never are advmod in UD
+		if int(target) <= len(tokens) && negations[lower(tokens[target-1])] {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+var negations = set("not", "n't", "never", "no", "neither", "nor")
+
+// subtreeSpan returns the 0-based first and last token of the subtree of the
+// 1-based node, caching the spans in spans.
+//
+func subtreeSpan(n int, children map[uint32][]uint32, node uint32, spans map[uint32][2]int) [2]int {
+	if span, ok := spans[node]; ok {
+		return span
+	}
+	span := [2]int{n, -1}
+	// guards against cycles in malformed graphs
+	spans[node] = span
+	if i := int(node) - 1; i >= 0 && i < n {
+		span = [2]int{i, i}
+	}
+	for _, c := range children[node] {
+		cs := subtreeSpan(n, children, c, spans)
+		if cs[0] < span[0] {
+			span[0] = cs[0]
+		}
+		if cs[1] > span[1] {
+			span[1] = cs[1]
+		}
+	}
+	spans[node] = span
+	return span
+}
+
+// dropBrackets marks the parenthesized spans, brackets included.
+//
+func dropBrackets(tokens []*nlp.Token, drop []bool) {
+	depth, begin := 0, 0
+	for i, token := range tokens {
+		switch token.GetWord() {
+		case "-LRB-", "(", "-LSB-", "[":
+			if depth == 0 {
+				begin = i
+			}
+			depth++
+		case "-RRB-", ")", "-RSB-", "]":
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth == 0 {
+				for j := begin; j <= i; j++ {
+					drop[j] = true
+				}
+			}
+		}
+	}
+}
+
+// dropDelimiters fixes the commas and dashes around the dropped spans: a span
+// set off on both sides, as in "Obama, the president, said", loses both
+// delimiters, unless the second one also opens a kept span, as in "Obama,
+// the president, who won, said"; a span at the end loses the delimiter
+// before it, and a span at the start the one after it.
+//
+func dropDelimiters(tokens []*nlp.Token, drop []bool, spans map[uint32][2]int) {
+	n := len(tokens)
+	// the delimiters at the edges of a span are decided below
+	var runs [][2]int
+	for i := 0; i < n; {
+		if !drop[i] {
+			i++
+			continue
+		}
+		j := i
+		for j+1 < n && drop[j+1] {
+			j++
+		}
+		for i <= j && delimiter(tokens[i]) {
+			drop[i] = false
+			i++
+		}
+		for j >= i && delimiter(tokens[j]) {
+			drop[j] = false
+			j--
+		}
+		if i <= j {
+			runs = append(runs, [2]int{i, j})
+		}
+		i = j + 1
+		for i < n && delimiter(tokens[i]) && !drop[i] {
+			i++
+		}
+	}
+
+	opens := func(begin int) bool {
+		for _, span := range spans {
+			if span[0] == begin && begin < n && !drop[begin] && (delimiter(tokens[span[1]]) || span[1]+1 < n && delimiter(tokens[span[1]+1])) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, run := range runs {
+		l, r := run[0]-1, run[1]+1
+		left := l >= 0 && delimiter(tokens[l])
+		right := r < n && delimiter(tokens[r])
+		switch {
+		case left && right:
+			drop[l] = true
+			if !opens(r + 1) {
+				drop[r] = true
+			}
+		case left && (r == n || final(tokens[r])):
+			drop[l] = true
+		case l < 0 && right:
+			drop[r] = true
+		}
+	}
+}
+
+func delimiter(token *nlp.Token) bool {
+	switch token.GetWord() {
+	case ",", "--", "-", ";":
+		return true
+	}
+	return false
+}
+
+func final(token *nlp.Token) bool {
+	switch token.GetWord() {
+	case ".", "!", "?", "...":
+		return true
+	}
+	return false
+}
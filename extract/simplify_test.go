@@ -0,0 +1,41 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+)
+
+func TestSimplify(t *testing.T) {
+	// Obama , the president , who won twice , did not leave Berlin quickly .
+	s := testdoc.Sentence("Obama|NNP , the|DT president|NN , who|WP won|VBD twice|RB , did|VBD not|RB leave|VB Berlin|NNP quickly|RB .|.",
+		"0>12:root", "12>1:nsubj", "1>2:punct", "4>3:det", "1>4:appos", "4>5:punct", "7>6:nsubj", "1>7:acl:relcl",
+		"7>8:advmod", "7>9:punct", "12>10:aux", "12>11:advmod", "12>13:obj", "12>14:advmod", "12>15:punct")
+	p := testdoc.Sentence("Mary|NNP -LRB-|-LRB- 30|CD -RRB-|-RRB- sleeps|VBZ .|.")
+	doc := testdoc.Doc(s, p)
+
+	light := Simplify(doc, SimplifyLight)
+	if light[0].Text != "Obama, who won twice, did not leave Berlin quickly." || light[1].Text != "Mary sleeps." {
+		t.Errorf("%q %q", light[0].Text, light[1].Text)
+	}
+	if moderate := Simplify(doc); moderate[0].Text != "Obama did not leave Berlin quickly." {
+		t.Errorf("%q", moderate[0].Text)
+	}
+	aggressive := Simplify(doc, SimplifyAggressive)
+	if aggressive[0].Text != "Obama did not leave Berlin." {
+		t.Errorf("%q", aggressive[0].Text)
+	}
+	if kept := aggressive[1].Kept; len(kept) != 3 || kept[1] != 4 {
+		t.Errorf("%v", kept)
+	}
+
+	a := testdoc.Sentence("Obama|NNP , the|DT president|NN , said|VBD so|RB", "0>6:root", "6>1:nsubj", "1>2:punct", "4>3:det", "1>4:appos", "1>5:punct", "6>7:advmod")
+	if got := Simplify(testdoc.Doc(a))[0].Text; got != "Obama said so" {
+		t.Errorf("%q", got)
+	}
+
+	variants := SimplifyVariants(doc)
+	if len(variants[0]) != 3 || len(variants[1]) != 1 || variants[0][2].Level != SimplifyAggressive {
+		t.Errorf("%v", variants)
+	}
+}
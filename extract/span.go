@@ -0,0 +1,95 @@
+package extract
+
+import (
+	"sort"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// OffsetToken is a token with its place in the document text.
+//
+type OffsetToken struct {
+	// 0-based sentence index in the document, and token index in the sentence
+	Sentence int
+	Index    int
+
+	Word string
+
+	// character offsets [BeginChar, EndChar) in the document text, in UTF-16
+	// units as CoreNLP counts them; 0 if the token has none
+	BeginChar uint32
+	EndChar   uint32
+
+	// the document text between the offsets, which keeps what the word
+	// normalizes, e.g. "(" for the word "-LRB-"; the original text of the
+	// token if the offsets are missing or out of the document text
+	Text string
+
+	Token *nlp.Token
+}
+
+// ExtractTokenSpans returns the tokens of doc with their character offsets,
+// in document order. Tokens without offsets are left out.
+//
+func ExtractTokenSpans(doc *nlp.Document) []*OffsetToken {
+	text := newDocText(doc)
+	var tokens []*OffsetToken
+	for i, s := range doc.GetSentence() {
+		for j, t := range s.Token {
+			if t.BeginChar == nil || t.EndChar == nil {
+				continue
+			}
+			tokens = append(tokens, newOffsetToken(text, i, j, t))
+		}
+	}
+	return tokens
+}
+
+func newOffsetToken(text *docText, sentence, index int, t *nlp.Token) *OffsetToken {
+	ot := &OffsetToken{Sentence: sentence, Index: index, Word: t.GetWord(), BeginChar: t.GetBeginChar(), EndChar: t.GetEndChar(), Token: t}
+	var ok bool
+	if t.BeginChar == nil || t.EndChar == nil {
+		ot.Text = t.GetOriginalText()
+	} else if ot.Text, ok = text.slice(ot.BeginChar, ot.EndChar); !ok {
+		ot.Text = t.GetOriginalText()
+	}
+	return ot
+}
+
+// CoveringTokens returns the tokens, from ExtractTokenSpans, overlapping the
+// character range [begin, end), or holding begin if the range is empty; nil if none.
+//
+func CoveringTokens(tokens []*OffsetToken, begin, end uint32) []*OffsetToken {
+	if end <= begin {
+		end = begin + 1
+	}
+	i := sort.Search(len(tokens), func(i int) bool { return tokens[i].EndChar > begin })
+	j := i
+	for j < len(tokens) && tokens[j].BeginChar < end {
+		j++
+	}
+	if i == j {
+		return nil
+	}
+	return tokens[i:j]
+}
+
+// CoveringSentence returns the index of the sentence of doc holding the
+// character range [begin, end), or -1 if the range is outside the sentences
+// or spans several of them.
+//
+func CoveringSentence(doc *nlp.Document, begin, end uint32) int {
+	for i, s := range doc.GetSentence() {
+		if s.CharacterOffsetBegin == nil && len(s.Token) == 0 {
+			continue
+		}
+		first, last := s.GetCharacterOffsetBegin(), s.GetCharacterOffsetEnd()
+		if s.CharacterOffsetBegin == nil {
+			first, last = s.Token[0].GetBeginChar(), s.Token[len(s.Token)-1].GetEndChar()
+		}
+		if first <= begin && end <= last {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,46 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractTokenSpans(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("John|NNP runs|VBZ .|."),
+		testdoc.Sentence("He|PRP -LRB-|-LRB- left|VBD"),
+	)
+	doc.Text = proto.String("John runs . He ( left")
+	doc.Sentence[1].Token[1].EndChar = proto.Uint32(16)
+	doc.Sentence[1].Token[2].BeginChar = proto.Uint32(17)
+	doc.Sentence[1].Token[2].EndChar = proto.Uint32(21)
+	doc.Sentence[1].CharacterOffsetEnd = proto.Uint32(21)
+	doc.Sentence[0].Token[1].BeginChar = nil
+
+	tokens := ExtractTokenSpans(doc)
+	if len(tokens) != 5 {
+		t.Fatalf("%d tokens", len(tokens))
+	}
+	if tok := tokens[3]; tok.Sentence != 1 || tok.Index != 1 || tok.Word != "-LRB-" || tok.Text != "(" || tok.BeginChar != 15 || tok.EndChar != 16 {
+		t.Errorf("%#v", tok)
+	}
+
+	if covered := CoveringTokens(tokens, 12, 16); len(covered) != 2 || covered[0].Word != "He" || covered[1].Word != "-LRB-" {
+		t.Errorf("%v", covered)
+	}
+	if covered := CoveringTokens(tokens, 2, 2); len(covered) != 1 || covered[0].Word != "John" {
+		t.Errorf("%v", covered)
+	}
+	if covered := CoveringTokens(tokens, 4, 5); covered != nil {
+		t.Errorf("%v", covered)
+	}
+
+	if i := CoveringSentence(doc, 12, 21); i != 1 {
+		t.Errorf("sentence %d", i)
+	}
+	if i := CoveringSentence(doc, 5, 14); i != -1 {
+		t.Errorf("sentence %d", i)
+	}
+}
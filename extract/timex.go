@@ -0,0 +1,99 @@
+package extract
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Timex is a temporal expression normalized by SUTime, part of the "ner"
+// annotator, e.g. "next Tuesday" with the value "2024-05-07".
+//
+type Timex struct {
+	// 0-based sentence index in the document
+	Sentence int
+
+	// the expression as written
+	Text string
+
+	// the TIMEX3 id, e.g. "t1", the type, DATE, TIME, DURATION or SET, and
+	// the value, e.g. "2024-05-07", "P3D" or "XXXX-WXX-2"
+	Tid   string
+	Type  string
+	Value string
+
+	// the alternative value of an expression relative to an unknown date,
+	// e.g. "THIS P1D OFFSET P1D"
+	AltValue string
+
+	// the normalized value of the tokens, as in Token.NormalizedNER
+	Normalized string
+
+	// the 0-based token span [TokenBegin, TokenEnd) in the sentence, and its
+	// character offsets
+	TokenBegin int
+	TokenEnd   int
+	BeginChar  uint32
+	EndChar    uint32
+}
+
+// ExtractTimexes returns the temporal expressions of doc, in document order:
+// the runs of tokens carrying the same Timex. A relative expression, e.g.
+// "tomorrow", resolves against the document date, see client.WithDocDate.
+//
+func ExtractTimexes(doc *nlp.Document) []*Timex {
+	text := newDocText(doc)
+	var timexes []*Timex
+	for i, s := range doc.GetSentence() {
+		tokens := s.Token
+		for j := 0; j < len(tokens); {
+			tx := tokens[j].GetTimexValue()
+			if tx == nil {
+				j++
+				continue
+			}
+			k := j + 1
+			for k < len(tokens) && sameTimex(tokens[k].GetTimexValue(), tx) {
+				k++
+			}
+			timexes = append(timexes, newTimex(text, i, tokens, j, k, tx))
+			j = k
+		}
+	}
+	return timexes
+}
+
+// sameTimex reports whether a token carries the expression tx: the same
+// id, or without ids, the same type and value.
+//
+func sameTimex(t, tx *nlp.Timex) bool {
+	if t == nil {
+		return false
+	}
+	if t.Tid != nil || tx.Tid != nil {
+		return t.GetTid() == tx.GetTid()
+	}
+	return t.GetType() == tx.GetType() && t.GetValue() == tx.GetValue()
+}
+
+func newTimex(text *docText, sentence int, tokens []*nlp.Token, begin, end int, tx *nlp.Timex) *Timex {
+	first, last := tokens[begin], tokens[end-1]
+	t := &Timex{
+		Sentence:   sentence,
+		Text:       tx.GetText(),
+		Tid:        tx.GetTid(),
+		Type:       tx.GetType(),
+		Value:      tx.GetValue(),
+		AltValue:   tx.GetAltValue(),
+		Normalized: first.GetNormalizedNER(),
+		TokenBegin: begin,
+		TokenEnd:   end,
+		BeginChar:  first.GetBeginChar(),
+		EndChar:    last.GetEndChar(),
+	}
+	if t.Text == "" && first.BeginChar != nil && last.EndChar != nil {
+		t.Text, _ = text.slice(t.BeginChar, t.EndChar)
+	}
+	if t.Text == "" {
+		t.Text = DetokenizeTokens(tokens[begin:end])
+	}
+	return t
+}
@@ -0,0 +1,35 @@
+package extract
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtractTimexes(t *testing.T) {
+	doc := testdoc.Doc(
+		testdoc.Sentence("We|PRP|we|O leave|VBP|leave|O next|JJ|next|DATE Tuesday|NNP|Tuesday|DATE for|IN|for|O three|CD|three|DURATION days|NNS|day|DURATION"),
+		testdoc.Sentence("Fine|JJ|fine|O"),
+	)
+	tokens := doc.Sentence[0].Token
+	tuesday := &nlp.Timex{Tid: proto.String("t1"), Type: proto.String("DATE"), Value: proto.String("2024-05-07")}
+	tokens[2].TimexValue, tokens[3].TimexValue = tuesday, tuesday
+	tokens[2].NormalizedNER = proto.String("2024-05-07")
+	days := &nlp.Timex{Type: proto.String("DURATION"), Value: proto.String("P3D"), Text: proto.String("three days")}
+	tokens[5].TimexValue = days
+	tokens[6].TimexValue = &nlp.Timex{Type: proto.String("DURATION"), Value: proto.String("P3D")}
+
+	timexes := ExtractTimexes(doc)
+	if len(timexes) != 2 {
+		t.Fatalf("%d timexes", len(timexes))
+	}
+	if tx := timexes[0]; tx.Text != "next Tuesday" || tx.Tid != "t1" || tx.Type != "DATE" || tx.Value != "2024-05-07" || tx.Normalized != "2024-05-07" ||
+		tx.TokenBegin != 2 || tx.TokenEnd != 4 || tx.BeginChar != 9 || tx.EndChar != 21 {
+		t.Errorf("%#v", tx)
+	}
+	if tx := timexes[1]; tx.Text != "three days" || tx.Value != "P3D" || tx.TokenBegin != 5 || tx.TokenEnd != 7 {
+		t.Errorf("%#v", tx)
+	}
+}
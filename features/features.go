@@ -0,0 +1,185 @@
+// Package features tells which outputs to expect from a CoreNLP release, so
+// that code reading the documents can branch on what the server gives. The
+// release comes from client.CoreNLPVersion, or from the deployment; Observed
+// tells what a document actually holds.
+//
+package features
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Feature is an output that appeared in a CoreNLP release.
+//
+type Feature int
+
+const (
+	// UniversalDependencies: depparse and parse give Universal Dependencies by default.
+	UniversalDependencies Feature = iota
+	// OpenIE: the openie annotator and Sentence.OpenieTriple.
+	OpenIE
+	// EnhancedPlusPlus: Sentence.EnhancedDependencies and EnhancedPlusPlusDependencies.
+	EnhancedPlusPlus
+	// KBP: the kbp annotator and Sentence.KbpTriple.
+	KBP
+	// EntityMentions: ner runs entitymentions, filling Sentence.Mentions and Document.Mentions.
+	EntityMentions
+	// QuoteAttribution: quote.attribution fills Quote.Speaker, Mention and CanonicalMention.
+	QuoteAttribution
+	// FineGrainedNER: Token.CoarseNER and FineGrainedNER, e.g. CITY under LOCATION.
+	FineGrainedNER
+	// UniversalDependencies2: UD v2 relations, e.g. "obl" for "nmod" on clauses.
+	UniversalDependencies2
+)
+
+// changelog lists the release introducing each feature, in the order of the Features.
+//
+var changelog = []struct {
+	name    string
+	release string
+}{
+	{"universal dependencies", "3.5.2"},
+	{"openie", "3.6.0"},
+	{"enhanced++ dependencies", "3.7.0"},
+	{"kbp", "3.7.0"},
+	{"entity mentions", "3.9.0"},
+	{"quote attribution", "3.9.0"},
+	{"fine-grained ner", "3.9.0"},
+	{"universal dependencies v2", "4.0.0"},
+}
+
+// Features lists all the features, in the order of their release.
+//
+var Features = []Feature{UniversalDependencies, OpenIE, EnhancedPlusPlus, KBP, EntityMentions, QuoteAttribution, FineGrainedNER, UniversalDependencies2}
+
+func (self Feature) String() string {
+	if self < 0 || int(self) >= len(changelog) {
+		return "feature(" + strconv.Itoa(int(self)) + ")"
+	}
+	return changelog[self].name
+}
+
+// Since returns the CoreNLP release introducing the feature, e.g. "3.7.0", "" if unknown.
+//
+func (self Feature) Since() string {
+	if self < 0 || int(self) >= len(changelog) {
+		return ""
+	}
+	return changelog[self].release
+}
+
+// Supports reports whether the release, e.g. "4.5.4", has the feature. An
+// empty release is taken as the latest, and a prefix "v" and a suffix such as
+// "-SNAPSHOT" are ignored.
+//
+func Supports(release string, f Feature) bool {
+	since := f.Since()
+	if since == "" {
+		return false
+	}
+	return release == "" || Compare(release, since) >= 0
+}
+
+// Expected returns the features of the release, in the order of Features.
+//
+func Expected(release string) []Feature {
+	var expected []Feature
+	for _, f := range Features {
+		if Supports(release, f) {
+			expected = append(expected, f)
+		}
+	}
+	return expected
+}
+
+// Compare compares two releases numerically, part by part, e.g. "4.10.0" is
+// after "4.9.2", and returns -1, 0 or 1. Missing parts count as 0.
+//
+func Compare(a, b string) int {
+	as, bs := parts(a), parts(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func parts(release string) []int {
+	release = strings.TrimPrefix(strings.TrimSpace(release), "v")
+	if i := strings.IndexAny(release, "-+ "); i >= 0 {
+		release = release[:i]
+	}
+	var ns []int
+	for _, p := range strings.Split(release, ".") {
+		n, _ := strconv.Atoi(p)
+		ns = append(ns, n)
+	}
+	return ns
+}
+
+// Observed returns the features whose output doc holds, in the order of
+// Features. UniversalDependencies and UniversalDependencies2 are told by
+// the relations of the basic dependencies; a document without the output of
+// a feature tells nothing about its release, as the annotator may not have run.
+//
+func Observed(doc *nlp.Document) []Feature {
+	seen := make(map[Feature]bool)
+	if len(doc.GetMentions()) > 0 {
+		seen[EntityMentions] = true
+	}
+	for _, q := range doc.GetQuote() {
+		if q.Speaker != nil || q.CanonicalMention != nil {
+			seen[QuoteAttribution] = true
+		}
+	}
+	for _, s := range doc.GetSentence() {
+		if len(s.OpenieTriple) > 0 {
+			seen[OpenIE] = true
+		}
+		if len(s.KbpTriple) > 0 {
+			seen[KBP] = true
+		}
+		if len(s.Mentions) > 0 {
+			seen[EntityMentions] = true
+		}
+		if len(s.GetEnhancedPlusPlusDependencies().GetEdge()) > 0 {
+			seen[EnhancedPlusPlus] = true
+		}
+		for _, e := range s.GetBasicDependencies().GetEdge() {
+			switch strings.SplitN(e.GetDep(), ":", 2)[0] {
+			case "nmod", "case", "compound", "nummod":
+				seen[UniversalDependencies] = true
+			case "obl", "flat", "orphan":
+				seen[UniversalDependencies] = true
+				seen[UniversalDependencies2] = true
+			}
+		}
+		for _, t := range s.Token {
+			if t.FineGrainedNER != nil || t.CoarseNER != nil {
+				seen[FineGrainedNER] = true
+			}
+		}
+	}
+
+	var observed []Feature
+	for _, f := range Features {
+		if seen[f] {
+			observed = append(observed, f)
+		}
+	}
+	return observed
+}
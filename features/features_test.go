@@ -0,0 +1,40 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSupports(t *testing.T) {
+	if !Supports("4.5.4", UniversalDependencies2) || !Supports("v3.7.0-SNAPSHOT", EnhancedPlusPlus) || !Supports("", QuoteAttribution) {
+		t.Error("supported features")
+	}
+	if Supports("3.6.0", KBP) || Supports("3.9.2", UniversalDependencies2) || Supports("4.5.4", Feature(99)) {
+		t.Error("unsupported features")
+	}
+	if got := Expected("3.7.0"); len(got) != 4 || got[3] != KBP {
+		t.Errorf("%v", got)
+	}
+	if Compare("4.10.0", "4.9.2") != 1 || Compare("4.5", "4.5.0") != 0 || Compare("3.9.2", "4.0.0") != -1 {
+		t.Error("compare")
+	}
+	if EnhancedPlusPlus.String() != "enhanced++ dependencies" || EnhancedPlusPlus.Since() != "3.7.0" || Feature(-1).Since() != "" {
+		t.Error(EnhancedPlusPlus)
+	}
+}
+
+func TestObserved(t *testing.T) {
+	doc := &nlp.Document{
+		Sentence: []*nlp.Sentence{{
+			Token:             []*nlp.Token{{Word: proto.String("Paris"), CoarseNER: proto.String("LOCATION"), FineGrainedNER: proto.String("CITY")}},
+			BasicDependencies: &nlp.DependencyGraph{Edge: []*nlp.DependencyGraph_Edge{{Dep: proto.String("obl:in")}}},
+		}},
+		Quote: []*nlp.Quote{{Text: proto.String(`"Hi"`)}},
+	}
+	got := Observed(doc)
+	if len(got) != 3 || got[0] != UniversalDependencies || got[1] != FineGrainedNER || got[2] != UniversalDependencies2 {
+		t.Errorf("%v", got)
+	}
+}
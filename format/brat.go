@@ -0,0 +1,152 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// ToBrat writes the annotations of the document in the brat standoff format,
+// the content of the .ann file going with the document text as the .txt file,
+// for review in brat or INCEpTION:
+//
+// - T lines for the NER mentions, typed by NER, and for the arguments below;
+//
+// - R lines for the KBP triples, e.g. "R1 per:city_of_birth Arg1:T1 Arg2:T2";
+//
+// - E lines for the OpenIE triples, triggered by their relation words;
+//
+// - "* Coreference" lines grouping the mentions of each coreference chain.
+//
+// The offsets count Unicode code points, as brat does, not the UTF-16 units
+// of CoreNLP. Spans are given one T line each, whatever refers to them.
+//
+func ToBrat(doc *nlp.Document) string {
+	w := &bratWriter{doc: doc, units: utf16.Encode([]rune(doc.GetText())), ids: make(map[string]string)}
+	w.runes = runeOffsets(w.units)
+
+	for _, s := range doc.GetSentence() {
+		for _, m := range s.Mentions {
+			w.span(m.GetNer(), s, m.GetTokenStartInSentenceInclusive(), m.GetTokenEndInSentenceExclusive())
+		}
+	}
+	for i, s := range doc.GetSentence() {
+		for _, t := range s.KbpTriple {
+			arg1, arg2 := w.locations("Entity", i, t.SubjectTokens), w.locations("Entity", i, t.ObjectTokens)
+			if arg1 != "" && arg2 != "" {
+				w.relations++
+				fmt.Fprintf(&w.b, "R%d\t%s Arg1:%s Arg2:%s\n", w.relations, bratType(t.GetRelation()), arg1, arg2)
+			}
+		}
+		for _, t := range s.OpenieTriple {
+			trigger := w.locations("Relation", i, t.RelationTokens)
+			subject, object := w.locations("Entity", i, t.SubjectTokens), w.locations("Entity", i, t.ObjectTokens)
+			if trigger != "" && subject != "" && object != "" {
+				w.events++
+				fmt.Fprintf(&w.b, "E%d\tRelation:%s Subject:%s Object:%s\n", w.events, trigger, subject, object)
+			}
+		}
+	}
+	for _, chain := range doc.GetCorefChain() {
+		var ids []string
+		for _, m := range chain.Mention {
+			if int(m.GetSentenceIndex()) >= len(doc.GetSentence()) {
+				continue
+			}
+			s := doc.Sentence[m.GetSentenceIndex()]
+			if id := w.span("Mention", s, m.GetBeginIndex(), m.GetEndIndex()); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) > 1 {
+			fmt.Fprintf(&w.b, "*\tCoreference %s\n", strings.Join(ids, " "))
+		}
+	}
+	return w.b.String()
+}
+
+type bratWriter struct {
+	doc   *nlp.Document
+	units []uint16
+	runes []int
+	b     strings.Builder
+
+	// T ids by span, "begin end", whatever their type
+	ids       map[string]string
+	texts     int
+	relations int
+	events    int
+}
+
+// span returns the T id of the tokens [begin, end) of the sentence, writing
+// its T line the first time, or "" if the span is empty or out of the text.
+//
+func (self *bratWriter) span(typ string, s *nlp.Sentence, begin, end uint32) string {
+	if begin >= end || int(end) > len(s.Token) {
+		return ""
+	}
+	from, to := int(s.Token[begin].GetBeginChar()), int(s.Token[end-1].GetEndChar())
+	if from >= to || to > len(self.units) {
+		return ""
+	}
+	key := fmt.Sprintf("%d %d", from, to)
+	if id, ok := self.ids[key]; ok {
+		return id
+	}
+	self.texts++
+	id := fmt.Sprintf("T%d", self.texts)
+	self.ids[key] = id
+	text := strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(string(utf16.Decode(self.units[from:to])))
+	fmt.Fprintf(&self.b, "%s\t%s %d %d\t%s\n", id, bratType(typ), self.runes[from], self.runes[to], text)
+	return id
+}
+
+// locations returns the T id of the span covered by the token locations of
+// sentence i, from the first to the last.
+//
+func (self *bratWriter) locations(typ string, i int, locs []*nlp.TokenLocation) string {
+	if len(locs) == 0 {
+		return ""
+	}
+	begin, end := locs[0].GetTokenIndex(), locs[0].GetTokenIndex()+1
+	for _, l := range locs {
+		if l.SentenceIndex != nil && int(l.GetSentenceIndex()) != i {
+			return ""
+		}
+		if l.GetTokenIndex() < begin {
+			begin = l.GetTokenIndex()
+		}
+		if l.GetTokenIndex()+1 > end {
+			end = l.GetTokenIndex() + 1
+		}
+	}
+	return self.span(typ, self.doc.Sentence[i], begin, end)
+}
+
+// runeOffsets maps the UTF-16 offsets of the text to code point offsets.
+//
+func runeOffsets(units []uint16) []int {
+	offsets := make([]int, len(units)+1)
+	n := 0
+	for i := 0; i < len(units); i++ {
+		offsets[i] = n
+		if utf16.IsSurrogate(rune(units[i])) && i+1 < len(units) {
+			i++
+			offsets[i] = n
+		}
+		n++
+	}
+	offsets[len(units)] = n
+	return offsets
+}
+
+// bratType makes s a brat type, which may not contain spaces.
+//
+func bratType(s string) string {
+	if s == "" {
+		return "Entity"
+	}
+	return strings.Join(strings.Fields(s), "_")
+}
@@ -0,0 +1,41 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestToBrat(t *testing.T) {
+	data := "1\t😀\t😀\tSYM\tO\n2\tBarack\tBarack\tNNP\tPERSON\n3\tObama\tObama\tNNP\tPERSON\n4\twas\tbe\tVBD\tO\n5\tborn\tbear\tVBN\tO\n6\tin\tin\tIN\tO\n7\tHawaii\tHawaii\tNNP\tSTATE_OR_PROVINCE\n\n1\tHe\the\tPRP\tO\n"
+	doc, err := Parse(CoNLL, []byte(data))
+	if err != nil { t.Fatal(err) }
+	loc := func(i uint32) *nlp.TokenLocation {
+		return &nlp.TokenLocation{SentenceIndex: proto.Uint32(0), TokenIndex: proto.Uint32(i)}
+	}
+	s := doc.Sentence[0]
+	s.Mentions = []*nlp.NERMention{
+		{Ner: proto.String("PERSON"), TokenStartInSentenceInclusive: proto.Uint32(1), TokenEndInSentenceExclusive: proto.Uint32(3)},
+		{Ner: proto.String("STATE_OR_PROVINCE"), TokenStartInSentenceInclusive: proto.Uint32(6), TokenEndInSentenceExclusive: proto.Uint32(7)},
+	}
+	s.KbpTriple = []*nlp.RelationTriple{{Relation: proto.String("per:stateorprovince_of_birth"),
+		SubjectTokens: []*nlp.TokenLocation{loc(1), loc(2)}, ObjectTokens: []*nlp.TokenLocation{loc(6)}}}
+	s.OpenieTriple = []*nlp.RelationTriple{{Relation: proto.String("was born in"),
+		SubjectTokens: []*nlp.TokenLocation{loc(1), loc(2)}, RelationTokens: []*nlp.TokenLocation{loc(3), loc(4), loc(5)}, ObjectTokens: []*nlp.TokenLocation{loc(6)}}}
+	doc.CorefChain = []*nlp.CorefChain{{ChainID: proto.Int32(1), Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+		{SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(1), EndIndex: proto.Uint32(3)},
+		{SentenceIndex: proto.Uint32(1), BeginIndex: proto.Uint32(0), EndIndex: proto.Uint32(1)},
+	}}}
+
+	want := "T1\tPERSON 2 14\tBarack Obama\n" +
+		"T2\tSTATE_OR_PROVINCE 27 33\tHawaii\n" +
+		"R1\tper:stateorprovince_of_birth Arg1:T1 Arg2:T2\n" +
+		"T3\tRelation 15 26\twas born in\n" +
+		"E1\tRelation:T3 Subject:T1 Object:T2\n" +
+		"T4\tMention 34 36\tHe\n" +
+		"*\tCoreference T1 T4\n"
+	if out := ToBrat(doc); out != want {
+		t.Errorf("%q", out)
+	}
+}
@@ -0,0 +1,116 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseCoNLL parses CoreNLP's conll output, whose tab-separated columns are
+// index, word, lemma, POS, NER, head index and dependency relation. Sentences
+// are separated by blank lines and missing values are written as "_".
+//
+func ParseCoNLL(data []byte) (*JSONDocument, error) {
+	return parseColumns(data, func(s *JSONSentence, fields []string) error {
+		if len(fields) < 2 {
+			return fmt.Errorf("conll: %d columns", len(fields))
+		}
+		idx, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return fmt.Errorf("conll: %v", err)
+		}
+		t := &JSONToken{Index: idx, Word: fields[1]}
+		t.Lemma = column(fields, 2)
+		t.POS = column(fields, 3)
+		t.NER = column(fields, 4)
+		if head := column(fields, 5); head != "" {
+			gov, err := strconv.Atoi(head)
+			if err != nil {
+				return fmt.Errorf("conll: %v", err)
+			}
+			s.BasicDependencies = append(s.BasicDependencies, &JSONDependency{Dep: column(fields, 6), Governor: gov, Dependent: idx})
+		}
+		s.Tokens = append(s.Tokens, t)
+		return nil
+	})
+}
+
+// ParseCoNLLU parses CoNLL-U: ID, FORM, LEMMA, UPOS, XPOS, FEATS, HEAD,
+// DEPREL, DEPS and MISC. XPOS, the Penn Treebank tag, is taken as POS, and
+// SpaceAfter=No in MISC is kept so the text can be rebuilt. Multiword tokens
+// and empty nodes are skipped.
+//
+func ParseCoNLLU(data []byte) (*JSONDocument, error) {
+	return parseColumns(data, func(s *JSONSentence, fields []string) error {
+		if len(fields) != 10 {
+			return fmt.Errorf("conllu: %d columns", len(fields))
+		}
+		if strings.ContainsAny(fields[0], "-.") {
+			return nil
+		}
+		idx, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return fmt.Errorf("conllu: %v", err)
+		}
+		t := &JSONToken{Index: idx, Word: fields[1], Lemma: column(fields, 2), POS: column(fields, 4)}
+		if t.POS == "" {
+			t.POS = column(fields, 3)
+		}
+		after := " "
+		for _, misc := range strings.Split(fields[9], "|") {
+			if misc == "SpaceAfter=No" {
+				after = ""
+			}
+		}
+		t.After = &after
+		if head := column(fields, 6); head != "" {
+			gov, err := strconv.Atoi(head)
+			if err != nil {
+				return fmt.Errorf("conllu: %v", err)
+			}
+			s.BasicDependencies = append(s.BasicDependencies, &JSONDependency{Dep: column(fields, 7), Governor: gov, Dependent: idx})
+		}
+		s.Tokens = append(s.Tokens, t)
+		return nil
+	})
+}
+
+// parseColumns splits data into sentences and tab-separated rows, skipping
+// comment lines, and lets row fill in the sentence.
+//
+func parseColumns(data []byte, row func(*JSONSentence, []string) error) (*JSONDocument, error) {
+	doc := &JSONDocument{}
+	var s *JSONSentence
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			s = nil
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if s == nil {
+			s = &JSONSentence{Index: len(doc.Sentences)}
+			doc.Sentences = append(doc.Sentences, s)
+		}
+		if err := row(s, strings.Split(line, "\t")); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func column(fields []string, i int) string {
+	if i >= len(fields) || fields[i] == "_" {
+		return ""
+	}
+	return fields[i]
+}
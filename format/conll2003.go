@@ -0,0 +1,55 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// DocStart is the line opening every document in CoNLL 2003.
+//
+const DocStart = "-DOCSTART- -X- -X- O"
+
+// ToCoNLL2003 writes the documents in the token-per-line format of the
+// CoNLL 2003 shared task: word, POS, chunk and NER tag separated by spaces,
+// a blank line after every sentence, and every document opened by DocStart.
+// CoreNLP has no chunker, so the chunk column is always "O". Spaces inside
+// words are replaced with "_", and missing tags are written as "_".
+//
+func ToCoNLL2003(docs ...*nlp.Document) string {
+	var b strings.Builder
+	for _, doc := range docs {
+		b.WriteString(DocStart + "\n\n")
+		for _, s := range doc.GetSentence() {
+			labels := BIO(s.Token)
+			for i, t := range s.Token {
+				b.WriteString(strings.Join([]string{
+					strings.Join(strings.Fields(field(t.GetWord(), "_")), "_"),
+					field(t.GetPos(), "_"), "O", labels[i]}, " "))
+				b.WriteByte('\n')
+			}
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// BIO returns the NER tags of the tokens in the BIO encoding, e.g.
+// "B-PERSON", "I-PERSON", "O", an entity for every run of tags.EntityRuns,
+// so adjacent entities of one type are kept apart when the entitymentions
+// annotator ran.
+//
+func BIO(tokens []*nlp.Token) []string {
+	labels := make([]string, len(tokens))
+	for i := range labels {
+		labels[i] = tags.O
+	}
+	for _, run := range tags.EntityRuns(tokens) {
+		labels[run.Begin] = "B-" + run.NER
+		for i := run.Begin + 1; i < run.End; i++ {
+			labels[i] = "I-" + run.NER
+		}
+	}
+	return labels
+}
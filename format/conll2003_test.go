@@ -0,0 +1,32 @@
+package format
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestToCoNLL2003(t *testing.T) {
+	data := "1\tBarack\tBarack\tNNP\tPERSON\n2\tObama\tObama\tNNP\tPERSON\n3\tmet\tmeet\tVBD\tO\n4\tMerkel\tMerkel\tNNP\tPERSON\n5\tin\tin\tIN\tO\n6\tNew York\tNew York\tNNP\tCITY\n\n1\tHi\thi\tUH\tO\n"
+	doc, err := Parse(CoNLL, []byte(data))
+	if err != nil { t.Fatal(err) }
+
+	out := ToCoNLL2003(doc)
+	want := DocStart + "\n\n" +
+		"Barack NNP O B-PERSON\nObama NNP O I-PERSON\nmet VBD O O\nMerkel NNP O B-PERSON\nin IN O O\nNew_York NNP O B-CITY\n\n" +
+		"Hi UH O O\n\n"
+	if out != want {
+		t.Errorf("%q", out)
+	}
+
+	// adjacent mentions of one type are split by the entity mention index
+	tokens := doc.Sentence[0].Token
+	tokens[2].Ner = proto.String("PERSON")
+	tokens[0].EntityMentionIndex = proto.Uint32(0)
+	tokens[1].EntityMentionIndex = proto.Uint32(0)
+	tokens[2].EntityMentionIndex = proto.Uint32(1)
+	labels := BIO(tokens)
+	if labels[0] != "B-PERSON" || labels[1] != "I-PERSON" || labels[2] != "B-PERSON" || labels[3] != "I-PERSON" {
+		t.Errorf("%v", labels)
+	}
+}
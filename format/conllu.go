@@ -0,0 +1,204 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/genelet/corenlp-golang/graph"
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// ToCoNLLU writes the document in CoNLL-U, with ID, FORM, LEMMA, UPOS, XPOS,
+// HEAD and DEPREL filled from the tokens and the basic dependencies, DEPS
+// from the enhanced++ or else the enhanced dependencies, and SpaceAfter=No
+// in MISC where the token is followed by no space. UPOS is mapped from the
+// Penn Treebank tag, refined by the relation for auxiliaries and
+// subordinating conjunctions. Sentences without basic dependencies get "_"
+// for HEAD and DEPREL, as the enhanced graphs are no trees; the edges of the
+// copy nodes of the enhanced graphs are left out of DEPS. It fails if the
+// basic dependencies do not give every token exactly one head.
+//
+func ToCoNLLU(doc *nlp.Document) (string, error) {
+	units := utf16.Encode([]rune(doc.GetText()))
+	var b strings.Builder
+	for i, s := range doc.GetSentence() {
+		heads, err := sentenceHeads(s)
+		if err != nil {
+			return "", fmt.Errorf("sentence %d: %w", i, err)
+		}
+		deps := sentenceDeps(s)
+
+		fmt.Fprintf(&b, "# sent_id = %d\n", i+1)
+		fmt.Fprintf(&b, "# text = %s\n", field(sentenceText(s, units), " "))
+		for j, t := range s.Token {
+			head, rel := "_", "_"
+			if heads != nil {
+				head, rel = strconv.Itoa(int(heads[j].head)), heads[j].rel
+			}
+			xpos := t.GetPos()
+			upos := "_"
+			if xpos != "" {
+				upos = universalPOS(t, rel)
+			}
+			misc := "_"
+			if t.After != nil && t.GetAfter() == "" && j < len(s.Token)-1 {
+				misc = "SpaceAfter=No"
+			}
+			b.WriteString(strings.Join([]string{strconv.Itoa(j + 1), field(t.GetWord(), "_"), field(t.GetLemma(), "_"),
+				upos, field(xpos, "_"), "_", head, rel, field(deps[uint32(j+1)], "_"), misc}, "\t"))
+			b.WriteByte('\n')
+		}
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+type head struct {
+	head uint32
+	rel  string
+}
+
+// sentenceHeads returns the head and relation of every token in the basic
+// dependencies, or nil if the sentence has none.
+//
+func sentenceHeads(s *nlp.Sentence) ([]head, error) {
+	g := graph.Basic.Graph(s)
+	if g == nil {
+		return nil, nil
+	}
+
+	n := uint32(len(s.Token))
+	heads := make([]head, n)
+	set := make([]bool, n)
+	assign := func(dependent, governor uint32, rel string) error {
+		if dependent == 0 || dependent > n || governor > n {
+			return fmt.Errorf("token %d out of range", dependent)
+		}
+		if set[dependent-1] {
+			return fmt.Errorf("token %d has two heads", dependent)
+		}
+		set[dependent-1] = true
+		heads[dependent-1] = head{governor, rel}
+		return nil
+	}
+	for _, r := range g.Root {
+		if err := assign(r, 0, tags.Root); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range g.Edge {
+		if err := assign(e.GetTarget(), e.GetSource(), e.GetDep()); err != nil {
+			return nil, err
+		}
+	}
+	for i, ok := range set {
+		if !ok {
+			return nil, fmt.Errorf("token %d has no head", i+1)
+		}
+	}
+	return heads, nil
+}
+
+// sentenceDeps returns the DEPS column of the tokens, keyed by their 1-based
+// index, e.g. "2:nsubj|4:nsubj:xsubj", from the enhanced++ or else the
+// enhanced dependencies.
+//
+func sentenceDeps(s *nlp.Sentence) map[uint32]string {
+	g := graph.EnhancedPlusPlus.Graph(s)
+	if g == nil {
+		g = graph.Enhanced.Graph(s)
+	}
+	heads := make(map[uint32][]head)
+	for _, r := range g.GetRoot() {
+		heads[r] = append(heads[r], head{0, tags.Root})
+	}
+	for _, e := range g.GetEdge() {
+		if e.GetSourceCopy() > 0 || e.GetTargetCopy() > 0 {
+			continue
+		}
+		heads[e.GetTarget()] = append(heads[e.GetTarget()], head{e.GetSource(), e.GetDep()})
+	}
+
+	deps := make(map[uint32]string, len(heads))
+	for dependent, hs := range heads {
+		sort.Slice(hs, func(i, j int) bool {
+			if hs[i].head != hs[j].head {
+				return hs[i].head < hs[j].head
+			}
+			return hs[i].rel < hs[j].rel
+		})
+		items := make([]string, len(hs))
+		for i, h := range hs {
+			items[i] = strconv.Itoa(int(h.head)) + ":" + h.rel
+		}
+		deps[dependent] = strings.Join(items, "|")
+	}
+	return deps
+}
+
+// universalPOS maps the Penn Treebank tag of the token, with the relation
+// and the word for the cases the tag does not decide.
+//
+func universalPOS(t *nlp.Token, rel string) string {
+	xpos := t.GetPos()
+	if xpos == tags.RB {
+		switch strings.ToLower(t.GetWord()) {
+		case "not", "n't":
+			return tags.UPART
+		}
+	}
+	switch rel {
+	case tags.Aux, tags.AuxPass, tags.Auxpass, tags.Cop:
+		if tags.IsVerb(xpos) || xpos == tags.MD {
+			return tags.UAUX
+		}
+	case tags.Mark:
+		if xpos == tags.IN {
+			return tags.USCONJ
+		}
+	}
+	return tags.UniversalPOS(xpos)
+}
+
+// sentenceText returns the text of the sentence by its character offsets,
+// or its words joined by their following whitespace.
+//
+func sentenceText(s *nlp.Sentence, units []uint16) string {
+	if n := len(s.Token); n > 0 {
+		begin, end := s.Token[0].GetBeginChar(), s.Token[n-1].GetEndChar()
+		if begin < end && int(end) <= len(units) {
+			return string(utf16.Decode(units[begin:end]))
+		}
+	}
+	var b strings.Builder
+	for i, t := range s.Token {
+		b.WriteString(t.GetWord())
+		if i < len(s.Token)-1 {
+			if t.After != nil {
+				b.WriteString(t.GetAfter())
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+	}
+	return b.String()
+}
+
+// field makes s fit a CoNLL-U column: tabs and line breaks become spaces,
+// and an empty s becomes the placeholder empty.
+//
+func field(s, empty string) string {
+	if s == "" {
+		return empty
+	}
+	return strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return ' '
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,69 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestToCoNLLU(t *testing.T) {
+	data := "1\tJohn\tJohn\tNNP\tPERSON\t3\tnsubj\n2\tcan\tcan\tMD\tO\t3\taux\n3\tgo\tgo\tVB\tO\t0\tROOT\n4\t.\t.\t.\tO\t3\tpunct\n"
+	doc, err := Parse(CoNLL, []byte(data))
+	if err != nil { t.Fatal(err) }
+	doc.Sentence[0].Token[2].After = nil
+	doc.Sentence[0].Token[3].Before = nil
+
+	out, err := ToCoNLLU(doc)
+	if err != nil { t.Fatal(err) }
+	want := "# sent_id = 1\n# text = John can go .\n" +
+		"1\tJohn\tJohn\tPROPN\tNNP\t_\t3\tnsubj\t_\t_\n" +
+		"2\tcan\tcan\tAUX\tMD\t_\t3\taux\t_\t_\n" +
+		"3\tgo\tgo\tVERB\tVB\t_\t0\troot\t_\t_\n" +
+		"4\t.\t.\tPUNCT\t.\t_\t3\tpunct\t_\t_\n\n"
+	if out != want {
+		t.Errorf("%q", out)
+	}
+
+	// the enhanced graph, with two heads for John, goes to DEPS only
+	s := doc.Sentence[0]
+	s.EnhancedPlusPlusDependencies = proto.Clone(s.BasicDependencies).(*nlp.DependencyGraph)
+	s.EnhancedPlusPlusDependencies.Edge = append(s.EnhancedPlusPlusDependencies.Edge,
+		&nlp.DependencyGraph_Edge{Source: proto.Uint32(2), Target: proto.Uint32(1), Dep: proto.String("nsubj:xsubj")})
+	out, err = ToCoNLLU(doc)
+	if err != nil { t.Fatal(err) }
+	if !strings.Contains(out, "\tNNP\t_\t3\tnsubj\t2:nsubj:xsubj|3:nsubj\t_\n") || !strings.Contains(out, "\tVB\t_\t0\troot\t0:root\t_\n") {
+		t.Errorf("%q", out)
+	}
+	s.BasicDependencies = nil
+	out, err = ToCoNLLU(doc)
+	if err != nil { t.Fatal(err) }
+	if !strings.Contains(out, "\tNNP\t_\t_\t_\t2:nsubj:xsubj|3:nsubj\t_\n") {
+		t.Errorf("%q", out)
+	}
+
+	// round trip through the CoNLL-U parser keeps SpaceAfter
+	u := "1\tDo\tdo\tAUX\tVBP\t_\t3\taux\t_\tSpaceAfter=No\n2\tn't\tnot\tPART\tRB\t_\t3\tadvmod\t_\t_\n3\tgo\tgo\tVERB\tVB\t_\t0\troot\t_\tSpaceAfter=No\n4\t.\t.\tPUNCT\t.\t_\t3\tpunct\t_\t_\n"
+	doc, err = Parse(CoNLLU, []byte(u))
+	if err != nil { t.Fatal(err) }
+	out, err = ToCoNLLU(doc)
+	if err != nil { t.Fatal(err) }
+	if !strings.HasPrefix(out, "# sent_id = 1\n# text = Don't go.\n") || !strings.Contains(out, "\tPART\tRB\t_\t3\tadvmod\t_\t_\n") {
+		t.Errorf("%q", out)
+	}
+	back, err := Parse(CoNLLU, []byte(out))
+	if err != nil { t.Fatal(err) }
+	if back.GetText() != doc.GetText() {
+		t.Errorf("%q", back.GetText())
+	}
+
+	doc.Sentence[0].BasicDependencies.Edge = doc.Sentence[0].BasicDependencies.Edge[1:]
+	if _, err = ToCoNLLU(doc); err == nil {
+		t.Errorf("a token without head should fail")
+	}
+	doc.Sentence[0].BasicDependencies = nil
+	if out, err = ToCoNLLU(doc); err != nil || !strings.Contains(out, "\t_\t_\t_\t_\tSpaceAfter=No") {
+		t.Errorf("%q %v", out, err)
+	}
+}
@@ -0,0 +1,96 @@
+package format
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// MaxSize is the largest output, in bytes, that Gunzip decompresses and
+// ReadFile reads, so that a hostile or corrupted file cannot exhaust memory.
+//
+var MaxSize int64 = 512 << 20
+
+// MaxGap is the largest gap, in UTF-16 units, filled with spaces between two
+// tokens when a text is rebuilt from their character offsets, by Parse and by
+// extract.SentenceText; farther offsets are taken as wrong.
+//
+const MaxGap = 1 << 20
+
+// ParseError is returned when data cannot be read in its format.
+//
+type ParseError struct {
+	// the format of the data, e.g. "json", or "gzip"
+	Format string
+	Err    error
+}
+
+func (self *ParseError) Error() string {
+	return "parse " + self.Format + ": " + self.Err.Error()
+}
+
+func (self *ParseError) Unwrap() error {
+	return self.Err
+}
+
+// IsGzip reports whether data starts with the gzip magic number.
+//
+func IsGzip(data []byte) bool {
+	return bytes.HasPrefix(data, []byte{0x1f, 0x8b})
+}
+
+// Gunzip decompresses data, failing with *ParseError if it is not valid gzip
+// or decompresses to more than MaxSize bytes.
+//
+func Gunzip(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, &ParseError{"gzip", err}
+	}
+	defer r.Close()
+	out, err := ioutil.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, &ParseError{"gzip", err}
+	}
+	if int64(len(out)) > MaxSize {
+		return nil, &ParseError{"gzip", fmt.Errorf("decompresses to more than %d bytes", MaxSize)}
+	}
+	return out, nil
+}
+
+// Detect guesses the format of data from its content: JSON or XML by the
+// first character, CoNLL-U by ten tab-separated columns, CoNLL by fewer. It
+// returns "" for anything else, gzip included, and never fails on any input.
+//
+func Detect(data []byte) string {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(trimmed) == 0 {
+		return ""
+	}
+	switch trimmed[0] {
+	case '{':
+		return JSON
+	case '<':
+		return XML
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		switch n := len(strings.Split(line, "\t")); {
+		case n == 10:
+			return CoNLLU
+		case n > 1:
+			return CoNLL
+		}
+		return ""
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package format
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	for data, want := range map[string]string{
+		jsonSample:                         JSON,
+		"\xef\xbb\xbf " + xmlSample:        XML,
+		"1\tJohn\tJohn\tNNP\tO\t0\tROOT\n": CoNLL,
+		"# text = John\n1\tJohn\tJohn\tPROPN\tNNP\t_\t0\troot\t_\t_\n": CoNLLU,
+		"John runs.":   "",
+		"":             "",
+		"\x1f\x8b\x08": "",
+	} {
+		if got := Detect([]byte(data)); got != want {
+			t.Errorf("%.20q: %q, want %q", data, got, want)
+		}
+	}
+}
+
+func TestGunzip(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(bytes.Repeat([]byte(" "), 1000))
+	w.Close()
+
+	data, err := Gunzip(buf.Bytes())
+	if err != nil { t.Fatal(err) }
+	if len(data) != 1000 {
+		t.Errorf("%d bytes", len(data))
+	}
+
+	defer func(max int64) { MaxSize = max }(MaxSize)
+	MaxSize = 999
+	var pe *ParseError
+	if _, err := Gunzip(buf.Bytes()); !errors.As(err, &pe) || pe.Format != "gzip" {
+		t.Errorf("over the limit: %v", err)
+	}
+	if _, err := Gunzip([]byte("\x1f\x8bjunk")); !errors.As(err, &pe) {
+		t.Errorf("junk: %v", err)
+	}
+}
+
+func TestParseHostile(t *testing.T) {
+	// an offset far beyond the text is recomputed, not padded up to
+	data := `{"sentences":[{"index":0,"tokens":[{"index":1,"word":"John","characterOffsetBegin":1000000000000,"characterOffsetEnd":1000000000004}]}]}`
+	doc, err := Parse(JSON, []byte(data))
+	if err != nil { t.Fatal(err) }
+	if doc.GetText() != "John" || doc.Sentence[0].Token[0].GetBeginChar() != 0 {
+		t.Errorf("%q %v", doc.GetText(), doc.Sentence[0].Token[0])
+	}
+
+	var pe *ParseError
+	if _, err := Parse(JSON, []byte(`{"sentences":[`)); !errors.As(err, &pe) || pe.Format != JSON {
+		t.Errorf("truncated: %v", err)
+	}
+}
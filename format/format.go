@@ -0,0 +1,229 @@
+// Package format converts between nlp.Document and the other output formats
+// of CoreNLP: json, xml, conll and conllu.
+//
+package format
+
+import (
+	"fmt"
+	"sort"
+	"unicode/utf16"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tree"
+	"google.golang.org/protobuf/proto"
+)
+
+// The values of CoreNLP's -outputFormat option.
+//
+const (
+	Serialized = "serialized"
+	JSON       = "json"
+	XML        = "xml"
+	CoNLL      = "conll"
+	CoNLLU     = "conllu"
+	Text       = "text"
+)
+
+// Extension returns the extension CoreNLP appends to the output file of the format.
+//
+func Extension(format string) string {
+	switch format {
+	case Serialized, "":
+		return ".ser.gz"
+	case Text:
+		return ".out"
+	}
+	return "." + format
+}
+
+// Parse converts data in the given format into a document. The errors of
+// malformed data are *ParseError. The serialized and text formats are not
+// handled here.
+//
+func Parse(format string, data []byte) (*nlp.Document, error) {
+	var doc *JSONDocument
+	var err error
+	switch format {
+	case JSON:
+		doc, err = ParseJSON(data)
+	case XML:
+		doc, err = ParseXML(data)
+	case CoNLL:
+		doc, err = ParseCoNLL(data)
+	case CoNLLU:
+		doc, err = ParseCoNLLU(data)
+	default:
+		return nil, fmt.Errorf("output format %q cannot be parsed", format)
+	}
+	if err != nil {
+		return nil, &ParseError{format, err}
+	}
+	pb, err := doc.Document()
+	if err != nil {
+		return nil, &ParseError{format, err}
+	}
+	return pb, nil
+}
+
+// Document converts the JSON model into nlp.Document. Character offsets,
+// when missing, are computed from the reconstructed text, and the parse
+// strings are read into parse trees; the model itself is left unchanged.
+// The error is that of a coreference mention out of its sentence.
+//
+func (self *JSONDocument) Document() (*nlp.Document, error) {
+	doc := &nlp.Document{}
+	if self.DocID != "" {
+		doc.DocID = proto.String(self.DocID)
+	}
+	if self.DocDate != "" {
+		doc.DocDate = proto.String(self.DocDate)
+	}
+
+	// rebuildText fills in the offsets of the tokens, so it works on copies
+	sentences := make([]*JSONSentence, len(self.Sentences))
+	for i, js := range self.Sentences {
+		c := *js
+		c.Tokens = make([]*JSONToken, len(js.Tokens))
+		for j, t := range js.Tokens {
+			token := *t
+			c.Tokens[j] = &token
+		}
+		sentences[i] = &c
+	}
+	text := rebuildText(sentences)
+	doc.Text = proto.String(text)
+
+	docToken := uint32(0)
+	for i, js := range sentences {
+		s := &nlp.Sentence{SentenceIndex: proto.Uint32(uint32(i)), TokenOffsetBegin: proto.Uint32(docToken)}
+		for j, jt := range js.Tokens {
+			s.Token = append(s.Token, jt.token(docToken+uint32(j)))
+		}
+		docToken += uint32(len(js.Tokens))
+		s.TokenOffsetEnd = proto.Uint32(docToken)
+		if n := len(s.Token); n > 0 {
+			s.CharacterOffsetBegin = proto.Uint32(s.Token[0].GetBeginChar())
+			s.CharacterOffsetEnd = proto.Uint32(s.Token[n-1].GetEndChar())
+		}
+		if js.Line > 0 {
+			s.LineNumber = proto.Uint32(uint32(js.Line))
+		}
+		if js.Sentiment != "" {
+			s.Sentiment = proto.String(js.Sentiment)
+		}
+		if js.Parse != "" {
+			// a malformed parse is left out, as the other missing layers
+			if t, err := tree.Parse(js.Parse); err == nil {
+				s.ParseTree = t
+			}
+		}
+
+		s.BasicDependencies = dependencyGraph(i, js.BasicDependencies)
+		s.EnhancedDependencies = dependencyGraph(i, js.EnhancedDependencies)
+		s.EnhancedPlusPlusDependencies = dependencyGraph(i, js.EnhancedPlusPlusDependencies)
+		for _, m := range js.EntityMentions {
+			nm := m.mention(i)
+			s.Mentions = append(s.Mentions, nm)
+			doc.Mentions = append(doc.Mentions, nm)
+		}
+		for _, t := range js.OpenIE {
+			s.OpenieTriple = append(s.OpenieTriple, t.triple(i))
+		}
+		for _, t := range js.KBP {
+			s.KbpTriple = append(s.KbpTriple, t.triple(i))
+		}
+		doc.Sentence = append(doc.Sentence, s)
+	}
+
+	chains, err := corefChains(self.Corefs)
+	if err != nil {
+		return nil, err
+	}
+	doc.CorefChain = chains
+	for _, q := range self.Quotes {
+		doc.Quote = append(doc.Quote, q.quote())
+	}
+	return doc, nil
+}
+
+// rebuildText returns the document text, and fills in the missing character
+// offsets of the tokens. The text is exact when the tokens carry before and
+// after, e.g. from json or conllu with SpaceAfter, otherwise the words are
+// placed at their offsets, or separated by single spaces. Offsets that are
+// inconsistent, or leave a gap over MaxGap, are recomputed.
+//
+func rebuildText(sentences []*JSONSentence) string {
+	var units []uint16
+	var after *string
+	first := true
+	for _, s := range sentences {
+		for _, t := range s.Tokens {
+			surface := t.OriginalText
+			if surface == "" {
+				surface = t.Word
+			}
+			located := t.CharacterOffsetEnd > 0 && t.CharacterOffsetBegin >= 0 &&
+				t.CharacterOffsetBegin <= t.CharacterOffsetEnd && t.CharacterOffsetBegin-len(units) <= MaxGap
+
+			if first && t.Before != nil {
+				units = append(units, utf16.Encode([]rune(*t.Before))...)
+			}
+			if located {
+				for len(units) < t.CharacterOffsetBegin {
+					units = append(units, ' ')
+				}
+			} else if !first && after == nil {
+				units = append(units, ' ')
+			}
+			encoded := utf16.Encode([]rune(surface))
+			if !located {
+				t.CharacterOffsetBegin = len(units)
+				t.CharacterOffsetEnd = len(units) + len(encoded)
+			}
+			units = append(units, encoded...)
+
+			after = t.After
+			if after != nil {
+				units = append(units, utf16.Encode([]rune(*after))...)
+			}
+			first = false
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+func dependencyGraph(sentence int, deps []*JSONDependency) *nlp.DependencyGraph {
+	if len(deps) == 0 {
+		return nil
+	}
+	g := &nlp.DependencyGraph{}
+	nodes := make(map[int]bool)
+	var indexes []int
+	for _, d := range deps {
+		for _, i := range []int{d.Governor, d.Dependent} {
+			if i > 0 && !nodes[i] {
+				nodes[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		g.Node = append(g.Node, &nlp.DependencyGraph_Node{SentenceIndex: proto.Uint32(uint32(sentence)), Index: proto.Uint32(uint32(i))})
+	}
+	for _, d := range deps {
+		if d.Governor == 0 {
+			g.Root = append(g.Root, uint32(d.Dependent))
+			continue
+		}
+		g.Edge = append(g.Edge, &nlp.DependencyGraph_Edge{Source: proto.Uint32(uint32(d.Governor)), Target: proto.Uint32(uint32(d.Dependent)), Dep: proto.String(d.Dep)})
+	}
+	return g
+}
+
+func optString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return proto.String(s)
+}
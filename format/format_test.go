@@ -0,0 +1,165 @@
+package format
+
+import (
+	"testing"
+)
+
+const jsonSample = `{"docId":"d1","sentences":[{"index":0,"parse":"(ROOT (S (NP (NNP John)) (VP (VBZ runs)) (. .)))",
+"basicDependencies":[{"dep":"ROOT","governor":0,"governorGloss":"ROOT","dependent":2,"dependentGloss":"runs"},
+{"dep":"nsubj","governor":2,"governorGloss":"runs","dependent":1,"dependentGloss":"John"},
+{"dep":"punct","governor":2,"governorGloss":"runs","dependent":3,"dependentGloss":"."}],
+"entitymentions":[{"docTokenBegin":0,"docTokenEnd":1,"tokenBegin":0,"tokenEnd":1,"text":"John","characterOffsetBegin":0,"characterOffsetEnd":4,"ner":"PERSON"}],
+"tokens":[{"index":1,"word":"John","originalText":"John","lemma":"John","characterOffsetBegin":0,"characterOffsetEnd":4,"pos":"NNP","ner":"PERSON","before":"","after":" "},
+{"index":2,"word":"runs","originalText":"runs","lemma":"run","characterOffsetBegin":5,"characterOffsetEnd":9,"pos":"VBZ","ner":"O","before":" ","after":""},
+{"index":3,"word":".","originalText":".","lemma":".","characterOffsetBegin":9,"characterOffsetEnd":10,"pos":".","ner":"O","before":"","after":"  "}]},
+{"index":1,"tokens":[{"index":1,"word":"He","originalText":"He","lemma":"he","characterOffsetBegin":12,"characterOffsetEnd":14,"pos":"PRP","ner":"O","before":"  ","after":""},
+{"index":2,"word":"stops","originalText":"stops","lemma":"stop","characterOffsetBegin":14,"characterOffsetEnd":19,"pos":"VBZ","ner":"O","before":"","after":""}]}],
+"corefs":{"3":[{"id":3,"text":"John","type":"PROPER","number":"SINGULAR","gender":"MALE","animacy":"ANIMATE","startIndex":1,"endIndex":2,"headIndex":1,"sentNum":1,"position":[1,1],"isRepresentativeMention":true},
+{"id":4,"text":"He","type":"PRONOMINAL","number":"SINGULAR","gender":"MALE","animacy":"ANIMATE","startIndex":1,"endIndex":2,"headIndex":1,"sentNum":2,"position":[2,1],"isRepresentativeMention":false}]}}`
+
+func TestParseJSON(t *testing.T) {
+	doc, err := Parse(JSON, []byte(jsonSample))
+	if err != nil { t.Fatal(err) }
+
+	if doc.GetDocID() != "d1" || doc.GetText() != "John runs.  Hestops" {
+		t.Errorf("%q %q", doc.GetDocID(), doc.GetText())
+	}
+	if len(doc.Sentence) != 2 || len(doc.Sentence[1].Token) != 2 {
+		t.Fatalf("%v", doc.Sentence)
+	}
+	s := doc.Sentence[1]
+	if s.GetTokenOffsetBegin() != 3 || s.GetCharacterOffsetBegin() != 12 || s.Token[1].GetTokenBeginIndex() != 4 {
+		t.Errorf("%v", s)
+	}
+	g := doc.Sentence[0].BasicDependencies
+	if len(g.Node) != 3 || len(g.Root) != 1 || g.Root[0] != 2 || len(g.Edge) != 2 || g.Edge[0].GetDep() != "nsubj" {
+		t.Errorf("%v", g)
+	}
+	if pt := doc.Sentence[0].ParseTree; pt.GetValue() != "ROOT" || pt.Child[0].Child[1].GetValue() != "VP" || doc.Sentence[1].ParseTree != nil {
+		t.Errorf("%v", pt)
+	}
+	if len(doc.Mentions) != 1 || doc.Mentions[0].GetNer() != "PERSON" {
+		t.Errorf("%v", doc.Mentions)
+	}
+	if len(doc.CorefChain) != 1 {
+		t.Fatalf("%v", doc.CorefChain)
+	}
+	m := doc.CorefChain[0].Mention[1]
+	if doc.CorefChain[0].GetChainID() != 3 || m.GetSentenceIndex() != 1 || m.GetBeginIndex() != 0 || m.GetEndIndex() != 1 {
+		t.Errorf("%v", doc.CorefChain[0])
+	}
+}
+
+func TestJSONDocument(t *testing.T) {
+	// no offsets: Document computes them without filling in the model
+	doc, err := ParseJSON([]byte(`{"sentences":[{"index":0,"tokens":[{"index":1,"word":"Hi"},{"index":2,"word":"there"}]}]}`))
+	if err != nil { t.Fatal(err) }
+	pb, err := doc.Document()
+	if err != nil { t.Fatal(err) }
+	if pb.Sentence[0].Token[1].GetBeginChar() != 3 || doc.Sentences[0].Tokens[1].CharacterOffsetBegin != 0 {
+		t.Errorf("%v %v", pb, doc.Sentences[0].Tokens[1])
+	}
+
+	for _, mention := range []string{
+		`"startIndex":0,"endIndex":1,"headIndex":1,"sentNum":1`,
+		`"startIndex":1,"endIndex":1,"headIndex":1,"sentNum":1`,
+		`"startIndex":1,"endIndex":2,"headIndex":0,"sentNum":1`,
+		`"startIndex":1,"endIndex":2,"headIndex":1`,
+	} {
+		data := `{"sentences":[{"index":0,"tokens":[{"index":1,"word":"Hi"}]}],"corefs":{"1":[{"id":1,` + mention + `}]}}`
+		if _, err := Parse(JSON, []byte(data)); err == nil {
+			t.Errorf("%s: no error", mention)
+		}
+	}
+}
+
+const xmlSample = `<?xml version="1.0" encoding="UTF-8"?>
+<root><document><docId>d2</docId><sentences>
+<sentence id="1"><tokens>
+<token id="1"><word>Mary</word><lemma>Mary</lemma><CharacterOffsetBegin>0</CharacterOffsetBegin><CharacterOffsetEnd>4</CharacterOffsetEnd><POS>NNP</POS><NER>PERSON</NER></token>
+<token id="2"><word>left</word><lemma>leave</lemma><CharacterOffsetBegin>5</CharacterOffsetBegin><CharacterOffsetEnd>9</CharacterOffsetEnd><POS>VBD</POS><NER>O</NER></token>
+<token id="3"><word>today</word><lemma>today</lemma><CharacterOffsetBegin>10</CharacterOffsetBegin><CharacterOffsetEnd>15</CharacterOffsetEnd><POS>NN</POS><NER>DATE</NER><Timex tid="t1" type="DATE">PRESENT_REF</Timex></token>
+</tokens><parse>(ROOT (S (NP (NNP Mary)) (VP (VBD left) (NP (NN today)))))</parse>
+<dependencies type="basic-dependencies">
+<dep type="root"><governor idx="0">ROOT</governor><dependent idx="2">left</dependent></dep>
+<dep type="nsubj"><governor idx="2">left</governor><dependent idx="1">Mary</dependent></dep>
+<dep type="obl:tmod"><governor idx="2">left</governor><dependent idx="3">today</dependent></dep>
+</dependencies>
+<dependencies type="collapsed-dependencies"></dependencies>
+</sentence></sentences>
+<coreference><coreference>
+<mention representative="true"><sentence>1</sentence><start>1</start><end>2</end><head>1</head><text>Mary</text></mention>
+</coreference></coreference>
+</document></root>`
+
+func TestParseXML(t *testing.T) {
+	doc, err := Parse(XML, []byte(xmlSample))
+	if err != nil { t.Fatal(err) }
+
+	if doc.GetDocID() != "d2" || doc.GetText() != "Mary left today" {
+		t.Errorf("%q %q", doc.GetDocID(), doc.GetText())
+	}
+	tokens := doc.Sentence[0].Token
+	if tokens[1].GetLemma() != "leave" || tokens[2].GetTimexValue().GetValue() != "PRESENT_REF" {
+		t.Errorf("%v", tokens)
+	}
+	if g := doc.Sentence[0].BasicDependencies; len(g.Edge) != 2 || g.Edge[1].GetDep() != "obl:tmod" {
+		t.Errorf("%v", g)
+	}
+	if len(doc.CorefChain) != 1 || doc.CorefChain[0].GetRepresentative() != 0 {
+		t.Errorf("%v", doc.CorefChain)
+	}
+}
+
+func TestParseCoNLL(t *testing.T) {
+	data := "1\tJohn\tJohn\tNNP\tPERSON\t2\tnsubj\n2\truns\trun\tVBZ\tO\t0\tROOT\n\n1\tOK\tok\tJJ\tO\t_\t_\n"
+	doc, err := Parse(CoNLL, []byte(data))
+	if err != nil { t.Fatal(err) }
+
+	if doc.GetText() != "John runs OK" || len(doc.Sentence) != 2 {
+		t.Fatalf("%q %v", doc.GetText(), doc.Sentence)
+	}
+	if tk := doc.Sentence[1].Token[0]; tk.GetBeginChar() != 10 || tk.GetEndChar() != 12 || tk.GetPos() != "JJ" {
+		t.Errorf("%v", tk)
+	}
+	if doc.Sentence[1].BasicDependencies != nil || len(doc.Sentence[0].BasicDependencies.Edge) != 1 {
+		t.Errorf("%v", doc.Sentence)
+	}
+
+	if _, err := Parse(CoNLL, []byte("x\tJohn\n")); err == nil {
+		t.Errorf("bad index should fail")
+	}
+}
+
+func TestParseCoNLLU(t *testing.T) {
+	data := "# sent_id = 1\n# text = Don't go.\n" +
+		"1-2\tDon't\t_\t_\t_\t_\t_\t_\t_\t_\n" +
+		"1\tDo\tdo\tAUX\tVBP\t_\t3\taux\t_\tSpaceAfter=No\n" +
+		"2\tn't\tnot\tPART\tRB\t_\t3\tadvmod\t_\t_\n" +
+		"3\tgo\tgo\tVERB\tVB\t_\t0\troot\t_\tSpaceAfter=No\n" +
+		"4\t.\t.\tPUNCT\t.\t_\t3\tpunct\t_\tSpaceAfter=No\n"
+	doc, err := Parse(CoNLLU, []byte(data))
+	if err != nil { t.Fatal(err) }
+
+	if doc.GetText() != "Don't go." {
+		t.Errorf("%q", doc.GetText())
+	}
+	s := doc.Sentence[0]
+	if len(s.Token) != 4 || s.Token[1].GetPos() != "RB" || s.Token[2].GetBeginChar() != 6 {
+		t.Errorf("%v", s.Token)
+	}
+	if len(s.BasicDependencies.Root) != 1 || s.BasicDependencies.Root[0] != 3 {
+		t.Errorf("%v", s.BasicDependencies)
+	}
+}
+
+func TestExtension(t *testing.T) {
+	for f, ext := range map[string]string{Serialized: ".ser.gz", "": ".ser.gz", JSON: ".json", CoNLLU: ".conllu", Text: ".out"} {
+		if Extension(f) != ext {
+			t.Errorf("%s: %s", f, Extension(f))
+		}
+	}
+	if _, err := Parse(Text, nil); err == nil {
+		t.Errorf("text should not parse")
+	}
+}
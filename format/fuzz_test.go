@@ -0,0 +1,24 @@
+//go:build go1.18
+// +build go1.18
+
+package format
+
+import (
+	"testing"
+)
+
+func FuzzParse(f *testing.F) {
+	f.Add(jsonSample)
+	f.Add(xmlSample)
+	f.Add("1\tJohn\tJohn\tNNP\tPERSON\t2\tnsubj\n2\truns\trun\tVBZ\tO\t0\tROOT\n")
+	f.Add("# text = John runs\n1\tJohn\tJohn\tPROPN\tNNP\t_\t2\tnsubj\t_\t_\n2\truns\trun\tVERB\tVBZ\t_\t0\troot\t_\tSpaceAfter=No\n")
+	f.Fuzz(func(t *testing.T, data string) {
+		for _, format := range []string{JSON, XML, CoNLL, CoNLLU} {
+			doc, err := Parse(format, []byte(data))
+			if err == nil && doc == nil {
+				t.Fatalf("%s: nil document without error", format)
+			}
+		}
+		Detect([]byte(data))
+	})
+}
@@ -0,0 +1,86 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// FormatOf returns the format of an output file by its extension, e.g. JSON
+// for "a.txt.json" or "a.txt.json.gz", or "" if it is not one Parse reads.
+//
+func FormatOf(path string) string {
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(path)), ".gz")
+	for _, format := range []string{JSON, XML, CoNLLU, CoNLL} {
+		if strings.HasSuffix(name, Extension(format)) {
+			return format
+		}
+	}
+	return ""
+}
+
+// ReadFile reads an output file of CoreNLP into a document, in the format
+// given by its extension, see FormatOf, or else by its content, see Detect,
+// gunzipping it if it is compressed. Files over MaxSize bytes are refused.
+// Without a document id in the file, the id is the file name stripped of
+// the extensions, e.g. "a.txt" for "a.txt.json".
+//
+func ReadFile(path string) (*nlp.Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(f, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > MaxSize {
+		return nil, fmt.Errorf("%s: larger than %d bytes", path, MaxSize)
+	}
+	if IsGzip(data) {
+		if data, err = Gunzip(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	format := FormatOf(path)
+	if format == "" {
+		format = Detect(data)
+	}
+	if format == "" {
+		return nil, fmt.Errorf("%s: unknown output format", path)
+	}
+	doc, err := Parse(format, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if doc.GetDocID() == "" {
+		name := strings.TrimSuffix(filepath.Base(path), ".gz")
+		doc.DocID = proto.String(strings.TrimSuffix(name, Extension(format)))
+	}
+	return doc, nil
+}
+
+// Import reads the output files under root, in lexical order, and calls fn
+// with each path and its document, or the error reading it. Files of unknown
+// formats are skipped. An error returned by fn stops the import, and is returned.
+//
+func Import(root string, fn func(path string, doc *nlp.Document, err error) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || FormatOf(path) == "" {
+			return nil
+		}
+		doc, err := ReadFile(path)
+		return fn(path, doc, err)
+	})
+}
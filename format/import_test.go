@@ -0,0 +1,46 @@
+package format
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestImport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "archive")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	os.Mkdir(filepath.Join(dir, "2020"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "2020", "a.txt.json"), []byte(jsonSample), 0644)
+	var zipped bytes.Buffer
+	zw := gzip.NewWriter(&zipped)
+	zw.Write([]byte(`{"sentences":[{"index":0,"tokens":[{"index":1,"word":"Hi","originalText":"Hi","characterOffsetBegin":0,"characterOffsetEnd":2}]}]}`))
+	zw.Close()
+	ioutil.WriteFile(filepath.Join(dir, "b.txt.json.gz"), zipped.Bytes(), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "c.txt.json"), []byte("{"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "notes.md"), []byte("skip"), 0644)
+
+	ids := map[string]string{}
+	failed := 0
+	err = Import(dir, func(path string, doc *nlp.Document, err error) error {
+		if err != nil {
+			failed++
+			return nil
+		}
+		ids[filepath.Base(path)] = doc.GetDocID()
+		return nil
+	})
+	if err != nil { t.Fatal(err) }
+	if len(ids) != 2 || ids["a.txt.json"] != "d1" || ids["b.txt.json.gz"] != "b.txt" || failed != 1 {
+		t.Errorf("%v %d", ids, failed)
+	}
+	if FormatOf("x.conllu") != CoNLLU || FormatOf("x.conll") != CoNLL || FormatOf("x.XML") != XML || FormatOf("x.ser.gz") != "" {
+		t.Errorf("%s", FormatOf("x.conllu"))
+	}
+}
@@ -0,0 +1,287 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// JSONDocument is the document in CoreNLP's json output format.
+// Token, sentence and mention indexes are 1-based, as in the output.
+//
+type JSONDocument struct {
+	DocID     string          `json:"docId,omitempty"`
+	DocDate   string          `json:"docDate,omitempty"`
+	Sentences []*JSONSentence `json:"sentences"`
+
+	// coref chains keyed by chain ID
+	Corefs map[string][]*JSONCorefMention `json:"corefs,omitempty"`
+
+	Quotes []*JSONQuote `json:"quotes,omitempty"`
+}
+
+// JSONSentence is a sentence in the json output format.
+//
+type JSONSentence struct {
+	Index int `json:"index"`
+	Line  int `json:"line,omitempty"`
+
+	// constituency parse in Penn Treebank brackets, e.g. "(ROOT (S ...))"
+	Parse string `json:"parse,omitempty"`
+
+	BasicDependencies            []*JSONDependency `json:"basicDependencies,omitempty"`
+	EnhancedDependencies         []*JSONDependency `json:"enhancedDependencies,omitempty"`
+	EnhancedPlusPlusDependencies []*JSONDependency `json:"enhancedPlusPlusDependencies,omitempty"`
+
+	EntityMentions []*JSONEntityMention `json:"entitymentions,omitempty"`
+	OpenIE         []*JSONTriple        `json:"openie,omitempty"`
+	KBP            []*JSONTriple        `json:"kbp,omitempty"`
+
+	Sentiment             string    `json:"sentiment,omitempty"`
+	SentimentValue        string    `json:"sentimentValue,omitempty"`
+	SentimentDistribution []float64 `json:"sentimentDistribution,omitempty"`
+	SentimentTree         string    `json:"sentimentTree,omitempty"`
+
+	Tokens []*JSONToken `json:"tokens"`
+}
+
+// JSONToken is a token in the json output format.
+//
+type JSONToken struct {
+	Index                int        `json:"index"`
+	Word                 string     `json:"word"`
+	OriginalText         string     `json:"originalText,omitempty"`
+	Lemma                string     `json:"lemma,omitempty"`
+	CharacterOffsetBegin int        `json:"characterOffsetBegin"`
+	CharacterOffsetEnd   int        `json:"characterOffsetEnd"`
+	POS                  string     `json:"pos,omitempty"`
+	NER                  string     `json:"ner,omitempty"`
+	NormalizedNER        string     `json:"normalizedNER,omitempty"`
+	Speaker              string     `json:"speaker,omitempty"`
+	Truecase             string     `json:"truecase,omitempty"`
+	TruecaseText         string     `json:"truecaseText,omitempty"`
+	Before               *string    `json:"before,omitempty"`
+	After                *string    `json:"after,omitempty"`
+	Timex                *JSONTimex `json:"timex,omitempty"`
+}
+
+// JSONDependency is a dependency edge; Governor is 0 for the root.
+//
+type JSONDependency struct {
+	Dep            string `json:"dep"`
+	Governor       int    `json:"governor"`
+	GovernorGloss  string `json:"governorGloss,omitempty"`
+	Dependent      int    `json:"dependent"`
+	DependentGloss string `json:"dependentGloss,omitempty"`
+}
+
+// JSONEntityMention is a named entity mention; token offsets are 0-based here.
+//
+type JSONEntityMention struct {
+	DocTokenBegin        int                `json:"docTokenBegin"`
+	DocTokenEnd          int                `json:"docTokenEnd"`
+	TokenBegin           int                `json:"tokenBegin"`
+	TokenEnd             int                `json:"tokenEnd"`
+	Text                 string             `json:"text"`
+	CharacterOffsetBegin int                `json:"characterOffsetBegin"`
+	CharacterOffsetEnd   int                `json:"characterOffsetEnd"`
+	NER                  string             `json:"ner"`
+	NormalizedNER        string             `json:"normalizedNER,omitempty"`
+	NERConfidences       map[string]float64 `json:"nerConfidences,omitempty"`
+	Timex                *JSONTimex         `json:"timex,omitempty"`
+}
+
+// JSONTimex is a temporal expression normalized by SUTime.
+//
+type JSONTimex struct {
+	Tid      string `json:"tid"`
+	Type     string `json:"type"`
+	Value    string `json:"value,omitempty"`
+	AltValue string `json:"altValue,omitempty"`
+}
+
+// JSONTriple is an OpenIE or KBP relation triple; spans are 0-based [begin, end).
+//
+type JSONTriple struct {
+	Subject      string  `json:"subject"`
+	SubjectSpan  []int   `json:"subjectSpan"`
+	Relation     string  `json:"relation"`
+	RelationSpan []int   `json:"relationSpan"`
+	Object       string  `json:"object"`
+	ObjectSpan   []int   `json:"objectSpan"`
+	Confidence   float64 `json:"confidence,omitempty"`
+}
+
+// JSONCorefMention is a mention of a coref chain.
+//
+type JSONCorefMention struct {
+	ID                      int    `json:"id"`
+	Text                    string `json:"text"`
+	Type                    string `json:"type"`
+	Number                  string `json:"number"`
+	Gender                  string `json:"gender"`
+	Animacy                 string `json:"animacy"`
+	StartIndex              int    `json:"startIndex"`
+	EndIndex                int    `json:"endIndex"`
+	HeadIndex               int    `json:"headIndex"`
+	SentNum                 int    `json:"sentNum"`
+	Position                []int  `json:"position"`
+	IsRepresentativeMention bool   `json:"isRepresentativeMention"`
+}
+
+// JSONQuote is a quotation; BeginIndex and EndIndex are character offsets.
+//
+type JSONQuote struct {
+	ID               int    `json:"id"`
+	Text             string `json:"text"`
+	BeginIndex       int    `json:"beginIndex"`
+	EndIndex         int    `json:"endIndex"`
+	BeginToken       int    `json:"beginToken"`
+	EndToken         int    `json:"endToken"`
+	BeginSentence    int    `json:"beginSentence"`
+	EndSentence      int    `json:"endSentence"`
+	Speaker          string `json:"speaker,omitempty"`
+	CanonicalSpeaker string `json:"canonicalSpeaker,omitempty"`
+}
+
+// ParseJSON parses CoreNLP's json output.
+//
+func ParseJSON(data []byte) (*JSONDocument, error) {
+	doc := &JSONDocument{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (self *JSONToken) token(docIndex uint32) *nlp.Token {
+	t := &nlp.Token{
+		Word:            proto.String(self.Word),
+		OriginalText:    optString(self.OriginalText),
+		Lemma:           optString(self.Lemma),
+		Pos:             optString(self.POS),
+		Ner:             optString(self.NER),
+		NormalizedNER:   optString(self.NormalizedNER),
+		Speaker:         optString(self.Speaker),
+		TrueCase:        optString(self.Truecase),
+		TrueCaseText:    optString(self.TruecaseText),
+		Before:          self.Before,
+		After:           self.After,
+		BeginChar:       proto.Uint32(uint32(self.CharacterOffsetBegin)),
+		EndChar:         proto.Uint32(uint32(self.CharacterOffsetEnd)),
+		TokenBeginIndex: proto.Uint32(docIndex),
+		TokenEndIndex:   proto.Uint32(docIndex + 1),
+	}
+	if self.OriginalText == "" {
+		t.OriginalText = proto.String(self.Word)
+	}
+	if self.Timex != nil {
+		t.TimexValue = self.Timex.timex()
+	}
+	return t
+}
+
+func (self *JSONTimex) timex() *nlp.Timex {
+	return &nlp.Timex{Tid: optString(self.Tid), Type: optString(self.Type), Value: optString(self.Value), AltValue: optString(self.AltValue)}
+}
+
+func (self *JSONEntityMention) mention(sentence int) *nlp.NERMention {
+	m := &nlp.NERMention{
+		SentenceIndex:                 proto.Uint32(uint32(sentence)),
+		TokenStartInSentenceInclusive: proto.Uint32(uint32(self.TokenBegin)),
+		TokenEndInSentenceExclusive:   proto.Uint32(uint32(self.TokenEnd)),
+		Ner:                           proto.String(self.NER),
+		NormalizedNER:                 optString(self.NormalizedNER),
+		EntityMentionText:             optString(self.Text),
+	}
+	if self.Timex != nil {
+		m.Timex = self.Timex.timex()
+	}
+	return m
+}
+
+func (self *JSONTriple) triple(sentence int) *nlp.RelationTriple {
+	t := &nlp.RelationTriple{Subject: proto.String(self.Subject), Relation: proto.String(self.Relation), Object: proto.String(self.Object)}
+	if self.Confidence != 0 {
+		t.Confidence = proto.Float64(self.Confidence)
+	}
+	t.SubjectTokens = locations(sentence, self.SubjectSpan)
+	t.RelationTokens = locations(sentence, self.RelationSpan)
+	t.ObjectTokens = locations(sentence, self.ObjectSpan)
+	return t
+}
+
+func locations(sentence int, span []int) []*nlp.TokenLocation {
+	if len(span) != 2 {
+		return nil
+	}
+	var locs []*nlp.TokenLocation
+	for i := span[0]; i < span[1]; i++ {
+		locs = append(locs, &nlp.TokenLocation{SentenceIndex: proto.Uint32(uint32(sentence)), TokenIndex: proto.Uint32(uint32(i))})
+	}
+	return locs
+}
+
+func (self *JSONQuote) quote() *nlp.Quote {
+	return &nlp.Quote{
+		Text:             proto.String(self.Text),
+		Begin:            proto.Uint32(uint32(self.BeginIndex)),
+		End:              proto.Uint32(uint32(self.EndIndex)),
+		TokenBegin:       proto.Uint32(uint32(self.BeginToken)),
+		TokenEnd:         proto.Uint32(uint32(self.EndToken)),
+		SentenceBegin:    proto.Uint32(uint32(self.BeginSentence)),
+		SentenceEnd:      proto.Uint32(uint32(self.EndSentence)),
+		Index:            proto.Uint32(uint32(self.ID)),
+		Speaker:          optString(self.Speaker),
+		CanonicalMention: optString(self.CanonicalSpeaker),
+	}
+}
+
+// corefChains converts the chains, ordered by ID, with 0-based indexes. The
+// 1-based indexes of the mentions must be given.
+//
+func corefChains(corefs map[string][]*JSONCorefMention) ([]*nlp.CorefChain, error) {
+	ids := make([]int, 0, len(corefs))
+	for k := range corefs {
+		if id, err := strconv.Atoi(k); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	var chains []*nlp.CorefChain
+	for _, id := range ids {
+		chain := &nlp.CorefChain{ChainID: proto.Int32(int32(id)), Representative: proto.Uint32(0)}
+		for i, m := range corefs[strconv.Itoa(id)] {
+			switch {
+			case m.SentNum < 1:
+				return nil, fmt.Errorf("coref chain %d: mention %d: sentNum %d", id, m.ID, m.SentNum)
+			case m.StartIndex < 1 || m.EndIndex <= m.StartIndex:
+				return nil, fmt.Errorf("coref chain %d: mention %d: startIndex %d, endIndex %d", id, m.ID, m.StartIndex, m.EndIndex)
+			case m.HeadIndex < 1:
+				return nil, fmt.Errorf("coref chain %d: mention %d: headIndex %d", id, m.ID, m.HeadIndex)
+			}
+			if m.IsRepresentativeMention {
+				chain.Representative = proto.Uint32(uint32(i))
+			}
+			chain.Mention = append(chain.Mention, &nlp.CorefChain_CorefMention{
+				MentionID:     proto.Int32(int32(m.ID)),
+				MentionType:   optString(m.Type),
+				Number:        optString(m.Number),
+				Gender:        optString(m.Gender),
+				Animacy:       optString(m.Animacy),
+				BeginIndex:    proto.Uint32(uint32(m.StartIndex - 1)),
+				EndIndex:      proto.Uint32(uint32(m.EndIndex - 1)),
+				HeadIndex:     proto.Uint32(uint32(m.HeadIndex - 1)),
+				SentenceIndex: proto.Uint32(uint32(m.SentNum - 1)),
+				Position:      proto.Uint32(uint32(i)),
+			})
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
@@ -0,0 +1,140 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf16"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tree"
+)
+
+// Options selects the sections printed by Format. The zero value prints all.
+//
+type Options struct {
+	HideTokens       bool
+	HideParse        bool
+	HideDependencies bool
+	HideEntities     bool
+	HideCoref        bool
+
+	// the dependencies to print, "basic" (the default), "enhanced" or "enhanced++"
+	Dependencies string
+}
+
+// Format returns a readable dump of the document, similar to the text output
+// format of CoreNLP: every sentence with its text, a table of its tokens with
+// their offsets, POS, lemma and NER, its constituency parse, its dependencies
+// as rel(governor-i, dependent-j) and its entity mentions, followed by the
+// coreference chains. Sections with no data are left out.
+//
+func Format(doc *nlp.Document, opts ...*Options) string {
+	o := &Options{}
+	if len(opts) > 0 && opts[0] != nil {
+		o = opts[0]
+	}
+	units := utf16.Encode([]rune(doc.GetText()))
+
+	var b strings.Builder
+	tokens := 0
+	for _, s := range doc.GetSentence() {
+		tokens += len(s.Token)
+	}
+	fmt.Fprintf(&b, "Document: ID=%s (%d sentences, %d tokens)\n", doc.GetDocID(), len(doc.GetSentence()), tokens)
+
+	for i, s := range doc.GetSentence() {
+		fmt.Fprintf(&b, "\nSentence #%d (%d tokens):\n%s\n", i+1, len(s.Token), sentenceText(s, units))
+
+		if !o.HideTokens && len(s.Token) > 0 {
+			b.WriteString("\nTokens:\n")
+			w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "#\tWord\tBegin\tEnd\tPOS\tLemma\tNER")
+			for j, t := range s.Token {
+				fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%s\t%s\t%s\n", j+1, t.GetWord(), t.GetBeginChar(), t.GetEndChar(),
+					field(t.GetPos(), "-"), field(t.GetLemma(), "-"), field(t.GetNer(), "-"))
+			}
+			w.Flush()
+		}
+
+		if !o.HideParse && s.ParseTree != nil {
+			fmt.Fprintf(&b, "\nConstituency parse:\n%s\n", tree.String(s.ParseTree))
+		}
+
+		if g := dependencies(s, o.Dependencies); !o.HideDependencies && g != nil {
+			fmt.Fprintf(&b, "\nDependency parse (%s):\n", field(o.Dependencies, "basic"))
+			word := func(index uint32) string {
+				if index == 0 || int(index) > len(s.Token) {
+					return fmt.Sprintf("ROOT-%d", index)
+				}
+				return fmt.Sprintf("%s-%d", s.Token[index-1].GetWord(), index)
+			}
+			for _, root := range g.Root {
+				fmt.Fprintf(&b, "root(ROOT-0, %s)\n", word(root))
+			}
+			for _, e := range g.Edge {
+				fmt.Fprintf(&b, "%s(%s, %s)\n", e.GetDep(), word(e.GetSource()), word(e.GetTarget()))
+			}
+		}
+
+		if !o.HideEntities && len(s.Mentions) > 0 {
+			b.WriteString("\nEntity mentions:\n")
+			for _, m := range s.Mentions {
+				fmt.Fprintf(&b, "%s\t%s\n", m.GetEntityMentionText(), m.GetEntityType())
+			}
+		}
+	}
+
+	if !o.HideCoref && len(doc.GetCorefChain()) > 0 {
+		b.WriteString("\nCoreference chains:\n")
+		for _, chain := range doc.GetCorefChain() {
+			var rep *nlp.CorefChain_CorefMention
+			for _, m := range chain.Mention {
+				if uint32(m.GetMentionID()) == chain.GetRepresentative() {
+					rep = m
+				}
+			}
+			fmt.Fprintf(&b, "Chain %d:\n", chain.GetChainID())
+			for _, m := range chain.Mention {
+				mark := ""
+				if m == rep {
+					mark = " (representative)"
+				}
+				fmt.Fprintf(&b, "\t(%d,%d,[%d,%d]) %q%s\n", m.GetSentenceIndex()+1, m.GetHeadIndex()+1,
+					m.GetBeginIndex()+1, m.GetEndIndex()+1, mentionText(doc, m), mark)
+			}
+		}
+	}
+	return b.String()
+}
+
+// dependencies returns the graph of the sentence selected by name.
+//
+func dependencies(s *nlp.Sentence, name string) *nlp.DependencyGraph {
+	switch name {
+	case "enhanced":
+		return s.EnhancedDependencies
+	case "enhanced++":
+		return s.EnhancedPlusPlusDependencies
+	}
+	return s.BasicDependencies
+}
+
+// mentionText returns the words of a coreference mention.
+//
+func mentionText(doc *nlp.Document, m *nlp.CorefChain_CorefMention) string {
+	sentences := doc.GetSentence()
+	if int(m.GetSentenceIndex()) >= len(sentences) {
+		return ""
+	}
+	tokens := sentences[m.GetSentenceIndex()].Token
+	begin, end := int(m.GetBeginIndex()), int(m.GetEndIndex())
+	if begin > end || end > len(tokens) {
+		return ""
+	}
+	words := make([]string, 0, end-begin)
+	for _, t := range tokens[begin:end] {
+		words = append(words, t.GetWord())
+	}
+	return strings.Join(words, " ")
+}
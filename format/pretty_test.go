@@ -0,0 +1,41 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFormat(t *testing.T) {
+	data := "1\tObama\tObama\tNNP\tPERSON\t2\tnsubj\n2\tsang\tsing\tVBD\tO\t0\tROOT\n\n1\tHe\the\tPRP\tO\t2\tnsubj\n2\tdanced\tdance\tVBD\tO\t0\tROOT\n"
+	doc, err := Parse(CoNLL, []byte(data))
+	if err != nil { t.Fatal(err) }
+	doc.CorefChain = []*nlp.CorefChain{{
+		ChainID:        proto.Int32(3),
+		Representative: proto.Uint32(0),
+		Mention: []*nlp.CorefChain_CorefMention{
+			{MentionID: proto.Int32(0), SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(0), EndIndex: proto.Uint32(1), HeadIndex: proto.Uint32(0)},
+			{MentionID: proto.Int32(1), SentenceIndex: proto.Uint32(1), BeginIndex: proto.Uint32(0), EndIndex: proto.Uint32(1), HeadIndex: proto.Uint32(0)},
+		},
+	}}
+
+	out := Format(doc)
+	for _, want := range []string{
+		"Document: ID= (2 sentences, 4 tokens)\n",
+		"\nSentence #1 (2 tokens):\nObama sang\n",
+		"#  Word   Begin  End  POS  Lemma  NER\n1  Obama  0      5    NNP  Obama  PERSON\n",
+		"Dependency parse (basic):\nroot(ROOT-0, sang-2)\nnsubj(sang-2, Obama-1)\n",
+		"Chain 3:\n\t(1,1,[1,2]) \"Obama\" (representative)\n\t(2,1,[1,2]) \"He\"\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+
+	out = Format(doc, &Options{HideTokens: true, HideCoref: true})
+	if strings.Contains(out, "Tokens:") || strings.Contains(out, "Chain") || !strings.Contains(out, "nsubj(danced-2, He-1)") {
+		t.Errorf("%s", out)
+	}
+}
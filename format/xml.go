@@ -0,0 +1,150 @@
+package format
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+type xmlRoot struct {
+	Document xmlDocument `xml:"document"`
+}
+
+type xmlDocument struct {
+	DocID     string         `xml:"docId"`
+	DocDate   string         `xml:"docDate"`
+	Sentences []*xmlSentence `xml:"sentences>sentence"`
+	Chains    []*xmlChain    `xml:"coreference>coreference"`
+}
+
+type xmlSentence struct {
+	ID             int                `xml:"id,attr"`
+	Line           int                `xml:"line,attr"`
+	Sentiment      string             `xml:"sentiment,attr"`
+	SentimentValue string             `xml:"sentimentValue,attr"`
+	Tokens         []*xmlToken        `xml:"tokens>token"`
+	Parse          string             `xml:"parse"`
+	Dependencies   []*xmlDependencies `xml:"dependencies"`
+}
+
+type xmlToken struct {
+	ID                   int       `xml:"id,attr"`
+	Word                 string    `xml:"word"`
+	Lemma                string    `xml:"lemma"`
+	CharacterOffsetBegin int       `xml:"CharacterOffsetBegin"`
+	CharacterOffsetEnd   int       `xml:"CharacterOffsetEnd"`
+	POS                  string    `xml:"POS"`
+	NER                  string    `xml:"NER"`
+	NormalizedNER        string    `xml:"NormalizedNER"`
+	Speaker              string    `xml:"Speaker"`
+	Truecase             string    `xml:"TrueCase"`
+	TruecaseText         string    `xml:"TrueCaseText"`
+	Timex                *xmlTimex `xml:"Timex"`
+}
+
+type xmlTimex struct {
+	Tid   string `xml:"tid,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDependencies struct {
+	Type string    `xml:"type,attr"`
+	Deps []*xmlDep `xml:"dep"`
+}
+
+type xmlDep struct {
+	Type      string  `xml:"type,attr"`
+	Governor  xmlNode `xml:"governor"`
+	Dependent xmlNode `xml:"dependent"`
+}
+
+type xmlNode struct {
+	Idx  int    `xml:"idx,attr"`
+	Text string `xml:",chardata"`
+}
+
+type xmlChain struct {
+	Mentions []*xmlMention `xml:"mention"`
+}
+
+type xmlMention struct {
+	Representative bool   `xml:"representative,attr"`
+	Sentence       int    `xml:"sentence"`
+	Start          int    `xml:"start"`
+	End            int    `xml:"end"`
+	Head           int    `xml:"head"`
+	Text           string `xml:"text"`
+}
+
+// ParseXML parses CoreNLP's xml output into the JSON model.
+// The dependency types basic-dependencies, enhanced-dependencies and
+// enhanced-plus-plus-dependencies are kept, the collapsed variants dropped.
+//
+func ParseXML(data []byte) (*JSONDocument, error) {
+	root := &xmlRoot{}
+	if err := xml.Unmarshal(data, root); err != nil {
+		return nil, err
+	}
+	x := root.Document
+
+	doc := &JSONDocument{DocID: x.DocID, DocDate: x.DocDate}
+	for i, xs := range x.Sentences {
+		s := &JSONSentence{Index: i, Line: xs.Line, Parse: xs.Parse, Sentiment: xs.Sentiment, SentimentValue: xs.SentimentValue}
+		for _, xt := range xs.Tokens {
+			t := &JSONToken{
+				Index:                xt.ID,
+				Word:                 xt.Word,
+				Lemma:                xt.Lemma,
+				CharacterOffsetBegin: xt.CharacterOffsetBegin,
+				CharacterOffsetEnd:   xt.CharacterOffsetEnd,
+				POS:                  xt.POS,
+				NER:                  xt.NER,
+				NormalizedNER:        xt.NormalizedNER,
+				Speaker:              xt.Speaker,
+				Truecase:             xt.Truecase,
+				TruecaseText:         xt.TruecaseText,
+			}
+			if xt.Timex != nil {
+				t.Timex = &JSONTimex{Tid: xt.Timex.Tid, Type: xt.Timex.Type, Value: xt.Timex.Value}
+			}
+			s.Tokens = append(s.Tokens, t)
+		}
+		for _, xd := range xs.Dependencies {
+			var deps []*JSONDependency
+			for _, d := range xd.Deps {
+				deps = append(deps, &JSONDependency{Dep: d.Type, Governor: d.Governor.Idx, GovernorGloss: d.Governor.Text, Dependent: d.Dependent.Idx, DependentGloss: d.Dependent.Text})
+			}
+			switch xd.Type {
+			case "basic-dependencies":
+				s.BasicDependencies = deps
+			case "enhanced-dependencies":
+				s.EnhancedDependencies = deps
+			case "enhanced-plus-plus-dependencies":
+				s.EnhancedPlusPlusDependencies = deps
+			}
+		}
+		doc.Sentences = append(doc.Sentences, s)
+	}
+
+	id := 0
+	for i, xc := range x.Chains {
+		var chain []*JSONCorefMention
+		for _, m := range xc.Mentions {
+			id++
+			chain = append(chain, &JSONCorefMention{
+				ID:                      id,
+				Text:                    m.Text,
+				StartIndex:              m.Start,
+				EndIndex:                m.End,
+				HeadIndex:               m.Head,
+				SentNum:                 m.Sentence,
+				IsRepresentativeMention: m.Representative,
+			})
+		}
+		if doc.Corefs == nil {
+			doc.Corefs = make(map[string][]*JSONCorefMention)
+		}
+		doc.Corefs[strconv.Itoa(i+1)] = chain
+	}
+	return doc, nil
+}
@@ -2,4 +2,7 @@ module github.com/genelet/corenlp-golang
 
 go 1.17
 
-require google.golang.org/protobuf v1.27.1
+require (
+	google.golang.org/protobuf v1.27.1
+	gopkg.in/yaml.v3 v3.0.1
+)
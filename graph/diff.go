@@ -0,0 +1,147 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Arc is the attachment of a token: its governor, 0 for a root, and the relation.
+//
+type Arc struct {
+	Head     int
+	Relation string
+}
+
+// TokenDiff is a token attached differently by two graphs.
+//
+type TokenDiff struct {
+	// 1-based index of the token, and its word
+	Index int
+	Word  string
+
+	// the arcs of the token in each graph, by head; nil if not in the
+	// graph, and several in the enhanced graphs
+	A []Arc
+	B []Arc
+}
+
+// Attachment reports whether the token has other governors in B than in A.
+//
+func (self *TokenDiff) Attachment() bool {
+	if len(self.A) != len(self.B) {
+		return true
+	}
+	for i := range self.A {
+		if self.A[i].Head != self.B[i].Head {
+			return true
+		}
+	}
+	return false
+}
+
+// Label reports whether the token has the same governors in both graphs,
+// but with other relations.
+//
+func (self *TokenDiff) Label() bool {
+	return !self.Attachment()
+}
+
+// GraphDiff compares two dependency graphs of a sentence, e.g. from two
+// models or two versions of CoreNLP.
+//
+type GraphDiff struct {
+	// the tokens compared
+	Tokens int
+
+	// the tokens attached differently, by index
+	Diffs []*TokenDiff
+
+	// the share of the tokens with the same governors (unlabeled attachment
+	// score), and with the same relations too (labeled attachment score),
+	// of B against A; 1 for two empty graphs
+	UAS float64
+	LAS float64
+}
+
+// Diff compares the graphs a and b token by token, the copy nodes aside.
+// A nil graph has no arcs.
+//
+func Diff(a, b *Graph) *GraphDiff {
+	arcsA, arcsB := a.arcs(), b.arcs()
+	indexes := make(map[int]bool)
+	words := make(map[int]string)
+	for _, g := range []*Graph{a, b} {
+		if g == nil {
+			continue
+		}
+		for _, n := range g.Nodes {
+			if n.Copy == 0 && n.Index > 0 {
+				indexes[n.Index] = true
+				if words[n.Index] == "" {
+					words[n.Index] = n.Word()
+				}
+			}
+		}
+	}
+
+	d := &GraphDiff{Tokens: len(indexes)}
+	unlabeled := 0
+	for i := range indexes {
+		td := &TokenDiff{Index: i, Word: words[i], A: arcsA[i], B: arcsB[i]}
+		if sameArcs(td.A, td.B) {
+			unlabeled++
+			continue
+		}
+		if !td.Attachment() {
+			unlabeled++
+		}
+		d.Diffs = append(d.Diffs, td)
+	}
+	sort.Slice(d.Diffs, func(i, j int) bool { return d.Diffs[i].Index < d.Diffs[j].Index })
+	if d.Tokens == 0 {
+		d.UAS, d.LAS = 1, 1
+	} else {
+		d.UAS = float64(unlabeled) / float64(d.Tokens)
+		d.LAS = float64(d.Tokens-len(d.Diffs)) / float64(d.Tokens)
+	}
+	return d
+}
+
+// arcs returns the arcs of the tokens by index, ordered by head, without
+// the copy nodes.
+//
+func (self *Graph) arcs() map[int][]Arc {
+	arcs := make(map[int][]Arc)
+	if self == nil {
+		return arcs
+	}
+	for _, n := range self.Nodes {
+		if n.Copy != 0 {
+			continue
+		}
+		if n.root {
+			arcs[n.Index] = append(arcs[n.Index], Arc{Head: 0, Relation: "root"})
+		}
+		for _, e := range n.In {
+			if e.Source.Copy == 0 {
+				arcs[n.Index] = append(arcs[n.Index], Arc{Head: e.Source.Index, Relation: e.Relation})
+			}
+		}
+		sort.SliceStable(arcs[n.Index], func(i, j int) bool {
+			x, y := arcs[n.Index][i], arcs[n.Index][j]
+			return x.Head < y.Head || (x.Head == y.Head && x.Relation < y.Relation)
+		})
+	}
+	return arcs
+}
+
+func sameArcs(a, b []Arc) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
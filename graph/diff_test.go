@@ -0,0 +1,33 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	// I saw the man with a telescope: "with" goes to "saw", then to "man",
+	// and "man" is an object, then an oblique
+	a := New(makeGraph("I saw the man with a telescope", "0>2:root", "2>1:nsubj", "2>4:obj", "4>3:det", "2>7:obl", "7>5:case", "7>6:det"))
+	b := New(makeGraph("I saw the man with a telescope", "0>2:root", "2>1:nsubj", "2>4:obl", "4>3:det", "4>7:nmod", "7>5:case", "7>6:det"))
+
+	d := Diff(a, b)
+	if d.Tokens != 7 || len(d.Diffs) != 2 {
+		t.Fatalf("%d %v", d.Tokens, d.Diffs)
+	}
+	if td := d.Diffs[0]; td.Index != 4 || td.Word != "man" || td.Attachment() || !td.Label() || td.A[0] != (Arc{2, "obj"}) || td.B[0] != (Arc{2, "obl"}) {
+		t.Errorf("%#v", td)
+	}
+	if td := d.Diffs[1]; td.Index != 7 || !td.Attachment() || td.Label() || td.B[0] != (Arc{4, "nmod"}) {
+		t.Errorf("%#v", td)
+	}
+	if d.UAS != 6.0/7 || d.LAS != 5.0/7 {
+		t.Errorf("%v %v", d.UAS, d.LAS)
+	}
+
+	if d = Diff(a, nil); len(d.Diffs) != 7 || d.UAS != 0 || d.Diffs[1].A[0] != (Arc{0, "root"}) || d.Diffs[1].B != nil {
+		t.Errorf("%v %v", d.Diffs, d.UAS)
+	}
+	if d = Diff(nil, nil); d.Tokens != 0 || d.LAS != 1 {
+		t.Errorf("%#v", d)
+	}
+}
@@ -0,0 +1,270 @@
+// Package graph indexes the dependency graphs of CoreNLP, so that the nodes
+// can be navigated by their parents, children and relations instead of the
+// node and edge lists of a nlp.DependencyGraph.
+//
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Node is a node of a dependency graph: a token of the sentence, or a copy
+// of one added by the enhanced graphs, e.g. for an elided verb.
+//
+type Node struct {
+	// 1-based index of the token in the sentence
+	Index int
+
+	// 0 for the token itself, the copy number for a copy node
+	Copy int
+
+	// the token, nil if the tokens were not given
+	Token *nlp.Token
+
+	// the edges from the governors, and to the dependents, by index
+	In  []*Edge
+	Out []*Edge
+
+	root bool
+}
+
+// Word returns the word of the token, "" if the tokens were not given.
+//
+func (self *Node) Word() string {
+	return self.Token.GetWord()
+}
+
+// IsRoot reports whether the node is a root of the graph.
+//
+func (self *Node) IsRoot() bool {
+	return self.root
+}
+
+// Edge is a typed dependency from a governor to a dependent.
+//
+type Edge struct {
+	Source *Node
+	Target *Node
+
+	// the relation, e.g. "nsubj" or "obl:in"
+	Relation string
+
+	// the edge is an extra one of the enhanced graphs, which makes them no tree
+	Extra bool
+}
+
+// Graph is a dependency graph indexed by node. It is built once by New, and
+// not updated if the nlp.DependencyGraph changes.
+//
+type Graph struct {
+	// the nodes by index, each copy after its token
+	Nodes []*Node
+
+	edges []*Edge
+	roots []*Node
+	index map[nodeKey]*Node
+}
+
+type nodeKey struct {
+	index, copy int
+}
+
+// New indexes g, with the tokens of its sentence, or else those kept in g,
+// and returns nil if g is nil. Edges to nodes missing from g are left out.
+//
+// For example:
+// g := graph.New(sentence.GetBasicDependencies(), sentence.Token)
+//
+func New(g *nlp.DependencyGraph, tokens []*nlp.Token) *Graph {
+	if g == nil {
+		return nil
+	}
+	if tokens == nil {
+		tokens = g.Token
+	}
+	self := &Graph{index: make(map[nodeKey]*Node)}
+	add := func(index, copy int) *Node {
+		key := nodeKey{index, copy}
+		if n, ok := self.index[key]; ok {
+			return n
+		}
+		n := &Node{Index: index, Copy: copy}
+		if index > 0 && index <= len(tokens) {
+			n.Token = tokens[index-1]
+		}
+		self.index[key] = n
+		self.Nodes = append(self.Nodes, n)
+		return n
+	}
+	for _, n := range g.Node {
+		add(int(n.GetIndex()), int(n.GetCopyAnnotation()))
+	}
+	// graphs written without their node list, e.g. by hand
+	if len(g.Node) == 0 {
+		for _, e := range g.Edge {
+			add(int(e.GetSource()), int(e.GetSourceCopy()))
+			add(int(e.GetTarget()), int(e.GetTargetCopy()))
+		}
+		for _, r := range g.Root {
+			add(int(r), 0)
+		}
+	}
+	sort.SliceStable(self.Nodes, func(i, j int) bool { return less(self.Nodes[i], self.Nodes[j]) })
+
+	for _, r := range g.Root {
+		if n, ok := self.index[nodeKey{int(r), 0}]; ok && !n.root {
+			n.root = true
+			self.roots = append(self.roots, n)
+		}
+	}
+	for _, e := range g.Edge {
+		source, ok1 := self.index[nodeKey{int(e.GetSource()), int(e.GetSourceCopy())}]
+		target, ok2 := self.index[nodeKey{int(e.GetTarget()), int(e.GetTargetCopy())}]
+		if !ok1 || !ok2 {
+			continue
+		}
+		edge := &Edge{Source: source, Target: target, Relation: e.GetDep(), Extra: e.GetIsExtra()}
+		source.Out = append(source.Out, edge)
+		target.In = append(target.In, edge)
+		self.edges = append(self.edges, edge)
+	}
+	for _, n := range self.Nodes {
+		sort.SliceStable(n.Out, func(i, j int) bool { return less(n.Out[i].Target, n.Out[j].Target) })
+		sort.SliceStable(n.In, func(i, j int) bool { return less(n.In[i].Source, n.In[j].Source) })
+	}
+	sort.SliceStable(self.edges, func(i, j int) bool {
+		if self.edges[i].Target != self.edges[j].Target {
+			return less(self.edges[i].Target, self.edges[j].Target)
+		}
+		return less(self.edges[i].Source, self.edges[j].Source)
+	})
+	return self
+}
+
+func less(a, b *Node) bool {
+	if a.Index != b.Index {
+		return a.Index < b.Index
+	}
+	return a.Copy < b.Copy
+}
+
+// Node returns the node of the token at the 1-based index, nil if none.
+//
+func (self *Graph) Node(index int) *Node {
+	return self.index[nodeKey{index, 0}]
+}
+
+// Roots returns the roots of the graph, usually one per sentence.
+//
+func (self *Graph) Roots() []*Node {
+	return append([]*Node{}, self.roots...)
+}
+
+// Edges returns the edges of the graph, ordered by dependent, then governor.
+//
+func (self *Graph) Edges() []*Edge {
+	return append([]*Edge{}, self.edges...)
+}
+
+// Parent returns the governor of n, by its first edge which is not extra;
+// nil for a root.
+//
+func (self *Graph) Parent(n *Node) *Node {
+	for _, e := range n.In {
+		if !e.Extra {
+			return e.Source
+		}
+	}
+	if len(n.In) > 0 {
+		return n.In[0].Source
+	}
+	return nil
+}
+
+// Parents returns every governor of n, several in the enhanced graphs.
+//
+func (self *Graph) Parents(n *Node) []*Node {
+	var parents []*Node
+	for _, e := range n.In {
+		parents = append(parents, e.Source)
+	}
+	return parents
+}
+
+// Children returns the dependents of n by index, those by the relations
+// only if given, see Matches.
+//
+// For example, the subjects of the root:
+// g.Children(g.Roots()[0], "nsubj", "nsubj:pass")
+//
+func (self *Graph) Children(n *Node, relations ...string) []*Node {
+	var children []*Node
+	for _, e := range n.Out {
+		if Matches(e.Relation, relations...) {
+			children = append(children, e.Target)
+		}
+	}
+	return children
+}
+
+// Descendants returns the nodes reached from n, n aside, following the
+// edges of the relations only if given, in depth-first order by index. Each
+// node comes once, so the cycles of the enhanced graphs end.
+//
+func (self *Graph) Descendants(n *Node, relations ...string) []*Node {
+	var nodes []*Node
+	seen := map[*Node]bool{n: true}
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		for _, e := range n.Out {
+			if seen[e.Target] || !Matches(e.Relation, relations...) {
+				continue
+			}
+			seen[e.Target] = true
+			nodes = append(nodes, e.Target)
+			walk(e.Target)
+		}
+	}
+	walk(n)
+	return nodes
+}
+
+// Walk calls fn on the edges reached from n in depth-first order, with the
+// depth of their dependent, 1 for the children of n, and does not follow
+// the edges for which fn returns false. Each node is entered once.
+//
+func (self *Graph) Walk(n *Node, fn func(e *Edge, depth int) bool) {
+	seen := map[*Node]bool{n: true}
+	var walk func(n *Node, depth int)
+	walk = func(n *Node, depth int) {
+		for _, e := range n.Out {
+			if seen[e.Target] {
+				continue
+			}
+			seen[e.Target] = true
+			if fn(e, depth) {
+				walk(e.Target, depth+1)
+			}
+		}
+	}
+	walk(n, 1)
+}
+
+// Matches reports whether relation is one of relations, or true if none is
+// given. A relation without a subtype also matches its subtypes, e.g. "obl"
+// matches "obl:in", while "obl:in" matches "obl:in" only.
+//
+func Matches(relation string, relations ...string) bool {
+	if len(relations) == 0 {
+		return true
+	}
+	for _, r := range relations {
+		if relation == r || (!strings.Contains(r, ":") && strings.HasPrefix(relation, r+":")) {
+			return true
+		}
+	}
+	return false
+}
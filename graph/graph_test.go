@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// makeGraph builds the graph of a sentence of words separated by spaces,
+// with "gov>dep:rel" edges of 1-based indexes, 0 for the root.
+//
+func makeGraph(sentence string, edges ...string) (*nlp.DependencyGraph, []*nlp.Token) {
+	var tokens []*nlp.Token
+	g := &nlp.DependencyGraph{}
+	for i, w := range strings.Fields(sentence) {
+		tokens = append(tokens, &nlp.Token{Word: proto.String(w)})
+		g.Node = append(g.Node, &nlp.DependencyGraph_Node{SentenceIndex: proto.Uint32(0), Index: proto.Uint32(uint32(i + 1))})
+	}
+	for _, e := range edges {
+		var gov, dep uint32
+		var rel string
+		fmt.Sscanf(e, "%d>%d:%s", &gov, &dep, &rel)
+		if gov == 0 {
+			g.Root = append(g.Root, dep)
+			continue
+		}
+		g.Edge = append(g.Edge, &nlp.DependencyGraph_Edge{Source: proto.Uint32(gov), Target: proto.Uint32(dep), Dep: proto.String(rel)})
+	}
+	return g, tokens
+}
+
+func words(nodes []*Node) string {
+	var w []string
+	for _, n := range nodes {
+		w = append(w, n.Word())
+	}
+	return strings.Join(w, " ")
+}
+
+func TestGraph(t *testing.T) {
+	// John gave Mary a book about Paris
+	g := New(makeGraph("John gave Mary a book about Paris",
+		"0>2:root", "2>1:nsubj", "2>3:iobj", "2>5:obj", "5>4:det", "5>7:nmod:about", "7>6:case"))
+	if g == nil || len(g.Nodes) != 7 {
+		t.Fatalf("%v", g)
+	}
+	roots := g.Roots()
+	if len(roots) != 1 || roots[0].Word() != "gave" || !roots[0].IsRoot() {
+		t.Fatalf("%v", roots)
+	}
+	gave := roots[0]
+	if got := words(g.Children(gave)); got != "John Mary book" {
+		t.Errorf("%s", got)
+	}
+	if got := words(g.Children(gave, "nsubj", "obj")); got != "John book" {
+		t.Errorf("%s", got)
+	}
+	book := g.Node(5)
+	if got := words(g.Children(book, "nmod")); got != "Paris" {
+		t.Errorf("%s", got)
+	}
+	if g.Parent(book) != gave || g.Parent(gave) != nil || len(g.Parents(g.Node(7))) != 1 {
+		t.Errorf("parents")
+	}
+	if got := words(g.Descendants(gave)); got != "John Mary book a Paris about" {
+		t.Errorf("%s", got)
+	}
+	if got := words(g.Descendants(gave, "obj", "det")); got != "book a" {
+		t.Errorf("%s", got)
+	}
+
+	edges := g.Edges()
+	if len(edges) != 6 || edges[0].Target.Word() != "John" || edges[5].Relation != "nmod:about" {
+		t.Errorf("%v", edges)
+	}
+
+	var depths []int
+	g.Walk(gave, func(e *Edge, depth int) bool {
+		depths = append(depths, depth)
+		return e.Relation != "obj"
+	})
+	if len(depths) != 3 || depths[0] != 1 {
+		t.Errorf("%v", depths)
+	}
+
+	if New(nil, nil) != nil || g.Node(9) != nil {
+		t.Errorf("missing nodes")
+	}
+}
+
+func TestEnhancedGraph(t *testing.T) {
+	// "Sue and Paul left": an extra subject edge, and a cycle from a relative clause
+	dg, tokens := makeGraph("Sue and Paul left", "0>4:root", "4>1:nsubj", "1>3:conj:and", "3>2:cc", "4>3:nsubj", "3>4:dep")
+	dg.Edge[3].IsExtra = proto.Bool(true)
+	g := New(dg, tokens)
+	paul := g.Node(3)
+	if g.Parent(paul).Word() != "Sue" || len(g.Parents(paul)) != 2 {
+		t.Errorf("%v", g.Parents(paul))
+	}
+	if got := words(g.Descendants(g.Node(4))); got != "Sue Paul and" {
+		t.Errorf("%s", got)
+	}
+
+	// a copy node, and a graph without its node list
+	dg = &nlp.DependencyGraph{
+		Root:  []uint32{1},
+		Edge:  []*nlp.DependencyGraph_Edge{{Source: proto.Uint32(1), Target: proto.Uint32(1), TargetCopy: proto.Uint32(1), Dep: proto.String("conj")}},
+		Token: tokens[:1],
+	}
+	g = New(dg, nil)
+	if len(g.Nodes) != 2 || g.Nodes[1].Copy != 1 || g.Nodes[1].Word() != "Sue" || len(g.Children(g.Node(1))) != 1 {
+		t.Errorf("%v", g.Nodes)
+	}
+
+	if !Matches("obl:in", "obl") || Matches("obl", "obl:in") || Matches("nsubj", "obj") || !Matches("x") {
+		t.Errorf("Matches")
+	}
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Step is a step of a Path: a node, and the edge reaching it from the node before.
+//
+type Step struct {
+	Node *Node
+
+	// the relation of the edge, "" for the first step
+	Relation string
+
+	// the edge goes up, from the dependent to its governor, rather than down
+	Up bool
+}
+
+// Path is a path between two nodes of a dependency graph, following the
+// edges in either direction.
+//
+type Path struct {
+	Steps []*Step
+}
+
+// Nodes returns the nodes of the path, from the first to the last.
+//
+func (self *Path) Nodes() []*Node {
+	nodes := make([]*Node, len(self.Steps))
+	for i, s := range self.Steps {
+		nodes[i] = s.Node
+	}
+	return nodes
+}
+
+// Relations returns the relations of the path with their direction, e.g.
+// []string{"<nsubj", ">obj"} from the subject to the object of a verb: "<"
+// going up to a governor, ">" down to a dependent.
+//
+func (self *Path) Relations() []string {
+	var rels []string
+	for _, s := range self.Steps[1:] {
+		if s.Up {
+			rels = append(rels, "<"+s.Relation)
+		} else {
+			rels = append(rels, ">"+s.Relation)
+		}
+	}
+	return rels
+}
+
+// String writes the path with the words and the directed relations, e.g.
+// "John <-nsubj- saw -obj-> dog".
+//
+func (self *Path) String() string {
+	var b strings.Builder
+	for i, s := range self.Steps {
+		if i > 0 {
+			if s.Up {
+				b.WriteString(" <-" + s.Relation + "- ")
+			} else {
+				b.WriteString(" -" + s.Relation + "-> ")
+			}
+		}
+		b.WriteString(s.Node.Word())
+	}
+	return b.String()
+}
+
+// ShortestPath returns the shortest path from a to b, following the edges
+// in either direction, nil if they are not connected. Of paths of the same
+// length, it takes the first found by going from every node to its
+// neighbors by increasing index, whatever the direction of the edges.
+//
+func (self *Graph) ShortestPath(a, b *Node) *Path {
+	if a == nil || b == nil {
+		return nil
+	}
+	from := map[*Node]*Step{a: {Node: a}}
+	previous := map[*Node]*Node{}
+	queue := []*Node{a}
+	for len(queue) > 0 && from[b] == nil {
+		n := queue[0]
+		queue = queue[1:]
+		type next struct {
+			edge *Edge
+			node *Node
+			up   bool
+		}
+		var neighbors []next
+		for _, e := range n.Out {
+			neighbors = append(neighbors, next{e, e.Target, false})
+		}
+		for _, e := range n.In {
+			neighbors = append(neighbors, next{e, e.Source, true})
+		}
+		sort.SliceStable(neighbors, func(i, j int) bool { return less(neighbors[i].node, neighbors[j].node) })
+		for _, m := range neighbors {
+			if from[m.node] != nil {
+				continue
+			}
+			from[m.node] = &Step{Node: m.node, Relation: m.edge.Relation, Up: m.up}
+			previous[m.node] = n
+			queue = append(queue, m.node)
+		}
+	}
+	if from[b] == nil {
+		return nil
+	}
+
+	var steps []*Step
+	for n := b; n != nil; n = previous[n] {
+		steps = append([]*Step{from[n]}, steps...)
+	}
+	return &Path{Steps: steps}
+}
+
+// ShortestPath returns the shortest dependency path between the tokens i
+// and j of the sentence, 0-based as in Sentence.Token, in its basic
+// dependencies, or else the closest variant, see Dependencies; nil if there
+// is none. It is the usual feature of relation extraction between two
+// entities.
+//
+// For example:
+// graph.ShortestPath(sentence, 0, 4).String()
+// "John <-nsubj- bought -obj-> car"
+//
+func ShortestPath(sentence *nlp.Sentence, i, j int) *Path {
+	dg, _ := Dependencies(sentence, Basic)
+	g := New(dg, sentence.Token)
+	if g == nil {
+		return nil
+	}
+	return g.ShortestPath(g.Node(i+1), g.Node(j+1))
+}
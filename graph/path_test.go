@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestShortestPath(t *testing.T) {
+	dg, tokens := makeGraph("John bought a red car in Paris",
+		"0>2:root", "2>1:nsubj", "2>5:obj", "5>3:det", "5>4:amod", "2>7:obl:in", "7>6:case")
+	s := &nlp.Sentence{Token: tokens, BasicDependencies: dg}
+
+	for _, c := range []struct {
+		i, j      int
+		path      string
+		relations string
+	}{
+		{0, 4, "John <-nsubj- bought -obj-> car", "<nsubj >obj"},
+		{0, 6, "John <-nsubj- bought -obl:in-> Paris", "<nsubj >obl:in"},
+		{3, 6, "red <-amod- car <-obj- bought -obl:in-> Paris", "<amod <obj >obl:in"},
+		{4, 3, "car -amod-> red", ">amod"},
+		{1, 1, "bought", ""},
+	} {
+		p := ShortestPath(s, c.i, c.j)
+		if p == nil {
+			t.Fatalf("%d %d: no path", c.i, c.j)
+		}
+		if p.String() != c.path || strings.Join(p.Relations(), " ") != c.relations || len(p.Nodes()) != len(p.Steps) {
+			t.Errorf("%d %d: %s %v", c.i, c.j, p, p.Relations())
+		}
+	}
+
+	if ShortestPath(s, 0, 9) != nil || ShortestPath(&nlp.Sentence{}, 0, 1) != nil {
+		t.Errorf("no path expected")
+	}
+
+	// the paths through "b" and "c" have the same length; "b" comes first,
+	// though governing "a" when "a" governs "c"
+	dg, tokens = makeGraph("a b c d", "0>1:root", "1>3:x", "2>1:y", "3>4:z", "2>4:w")
+	if p := ShortestPath(&nlp.Sentence{Token: tokens, EnhancedDependencies: dg}, 0, 3); p == nil || p.String() != "a <-y- b -w-> d" {
+		t.Errorf("%s", p)
+	}
+
+	// two sentences' worth of roots, unconnected
+	dg, tokens = makeGraph("Stop . Go", "0>1:root", "0>3:root", "1>2:punct")
+	if p := ShortestPath(&nlp.Sentence{Token: tokens, EnhancedDependencies: dg}, 0, 2); p != nil {
+		t.Errorf("%s", p)
+	}
+}
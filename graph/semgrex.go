@@ -0,0 +1,259 @@
+package graph
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/internal/pattern"
+)
+
+// Pattern is a compiled pattern in a subset of the Semgrex language of
+// CoreNLP, matched against a Graph in Go without a server:
+//
+// - nodes: {} for any node, {attr:value} or {attr:/regex/} on word, lemma,
+// tag (or pos), ner and idx, {attr:!value} for the negation, several
+// attributes separated by ";", and {$} for a root; =name names the node;
+//
+// - relations, between a node and the next one: A >rel B if A governs B,
+// A <rel B if A depends on B, >> and << through any number of edges, with
+// rel a relation as in Matches, a /regex/ on the relation, or nothing for
+// any; ! before a relation requires that no such node exists;
+//
+// - the relations after a node all apply to it, "&" between them is
+// optional, and parentheses group a node with its own relations.
+//
+// For example, the persons subject of a verb:
+// {tag:/VB.*/}=verb >nsubj {ner:PERSON}=who
+//
+type Pattern struct {
+	p *pattern.Pattern
+}
+
+// Match is a node matching a pattern, with the nodes of its names.
+//
+type Match struct {
+	Node  *Node
+	Named map[string]*Node
+}
+
+type attrPattern struct {
+	key    string
+	value  string
+	re     *regexp.Regexp
+	negate bool
+}
+
+// semgrex is the language of the patterns on the nodes of a Graph.
+//
+var semgrex = &pattern.Language{Name: "semgrex", Operators: "<>", Node: readNode, Relation: readRelation}
+
+// Compile parses a pattern.
+//
+func Compile(source string) (*Pattern, error) {
+	p, err := pattern.Compile(semgrex, source)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{p: p}, nil
+}
+
+// MustCompile parses a pattern, and panics on error.
+//
+func MustCompile(source string) *Pattern {
+	p, err := Compile(source)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source of the pattern.
+//
+func (self *Pattern) String() string {
+	return self.p.String()
+}
+
+// Match returns the matches of the pattern in g, by node index, with every
+// distinct assignment of the named nodes.
+//
+func (self *Pattern) Match(g *Graph) []*Match {
+	var matches []*Match
+	for _, n := range g.Nodes {
+		for _, named := range self.p.Match(n) {
+			m := &Match{Node: n, Named: make(map[string]*Node, len(named))}
+			for name, v := range named {
+				m.Named[name] = v.(*Node)
+			}
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// MatchNode reports whether n matches the pattern in g.
+//
+func (self *Pattern) MatchNode(g *Graph, n *Node) bool {
+	return self.p.MatchNode(n)
+}
+
+// readNode reads {...}: {} for any node, {$} for a root, or attributes.
+//
+func readNode(p *pattern.Parser) (func(n pattern.Node) bool, error) {
+	if p.Peek() != '{' {
+		return nil, p.Errorf("expected { or (")
+	}
+	p.Skip()
+	body, err := p.Until('}')
+	if err != nil {
+		return nil, err
+	}
+	root := false
+	var attrs []*attrPattern
+	switch body = strings.TrimSpace(body); body {
+	case "":
+	case "$":
+		root = true
+	default:
+		for _, item := range strings.Split(body, ";") {
+			a, err := readAttr(p, strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			attrs = append(attrs, a)
+		}
+	}
+	return func(n pattern.Node) bool { return matchAttrs(n.(*Node), root, attrs) }, nil
+}
+
+func matchAttrs(n *Node, root bool, attrs []*attrPattern) bool {
+	if root && !n.root {
+		return false
+	}
+	for _, a := range attrs {
+		var value string
+		switch a.key {
+		case "word":
+			value = n.Token.GetWord()
+		case "lemma":
+			value = n.Token.GetLemma()
+		case "tag", "pos":
+			value = n.Token.GetPos()
+		case "ner":
+			value = n.Token.GetNer()
+		case "idx", "index":
+			value = strconv.Itoa(n.Index)
+		}
+		ok := value == a.value
+		if a.re != nil {
+			ok = a.re.MatchString(value)
+		}
+		if ok == a.negate {
+			return false
+		}
+	}
+	return true
+}
+
+func readAttr(p *pattern.Parser, item string) (*attrPattern, error) {
+	i := strings.Index(item, ":")
+	if i <= 0 {
+		return nil, p.Errorf("bad attribute %q", item)
+	}
+	a := &attrPattern{key: strings.TrimSpace(item[:i]), value: strings.TrimSpace(item[i+1:])}
+	switch a.key {
+	case "word", "lemma", "tag", "pos", "ner", "idx", "index":
+	default:
+		return nil, p.Errorf("unknown attribute %q", a.key)
+	}
+	if strings.HasPrefix(a.value, "!") {
+		a.negate = true
+		a.value = a.value[1:]
+	}
+	if len(a.value) >= 2 && strings.HasPrefix(a.value, "/") && strings.HasSuffix(a.value, "/") {
+		re, err := regexp.Compile("^(?:" + a.value[1:len(a.value)-1] + ")$")
+		if err != nil {
+			return nil, p.Errorf("%v", err)
+		}
+		a.re = re
+	}
+	return a, nil
+}
+
+// readRelation reads one of >, <, >>, << with its relation: a name as in
+// Matches, a /regex/, or nothing for any.
+//
+func readRelation(p *pattern.Parser) (func(n pattern.Node) []pattern.Node, error) {
+	r := p.Peek()
+	if r != '>' && r != '<' {
+		return nil, p.Errorf("expected > or <")
+	}
+	p.Skip()
+	op := string(r)
+	if p.Peek() == r {
+		p.Skip()
+		op += string(r)
+	}
+
+	var matchRelation func(relation string) bool
+	if p.Peek() == '/' {
+		body, err := p.Regex()
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile("^(?:" + body + ")$")
+		if err != nil {
+			return nil, p.Errorf("%v", err)
+		}
+		matchRelation = re.MatchString
+	} else if name := p.Ident(); name != "" {
+		matchRelation = func(relation string) bool { return Matches(relation, name) }
+	} else {
+		matchRelation = func(string) bool { return true }
+	}
+	return func(n pattern.Node) []pattern.Node { return candidates(op, matchRelation, n.(*Node)) }, nil
+}
+
+// candidates returns the nodes in the relation op with n.
+//
+func candidates(op string, matchRelation func(string) bool, n *Node) []pattern.Node {
+	var nodes []pattern.Node
+	switch op {
+	case ">":
+		for _, e := range n.Out {
+			if matchRelation(e.Relation) {
+				nodes = append(nodes, e.Target)
+			}
+		}
+	case "<":
+		for _, e := range n.In {
+			if matchRelation(e.Relation) {
+				nodes = append(nodes, e.Source)
+			}
+		}
+	case ">>", "<<":
+		seen := map[*Node]bool{n: true}
+		queue := []*Node{n}
+		for len(queue) > 0 {
+			m := queue[0]
+			queue = queue[1:]
+			edges := m.Out
+			if op == "<<" {
+				edges = m.In
+			}
+			for _, e := range edges {
+				next := e.Target
+				if op == "<<" {
+					next = e.Source
+				}
+				if seen[next] || !matchRelation(e.Relation) {
+					continue
+				}
+				seen[next] = true
+				nodes = append(nodes, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nodes
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// tagged sets the tags of the tokens from "POS|NER|lemma" items separated by spaces.
+//
+func tagged(tokens []*nlp.Token, tags string) []*nlp.Token {
+	for i, item := range strings.Fields(tags) {
+		f := strings.Split(item, "|")
+		tokens[i].Pos, tokens[i].Ner, tokens[i].Lemma = proto.String(f[0]), proto.String(f[1]), proto.String(f[2])
+	}
+	return tokens
+}
+
+func TestSemgrex(t *testing.T) {
+	dg, tokens := makeGraph("Mary and John saw the big dog",
+		"0>4:root", "4>1:nsubj", "1>3:conj:and", "3>2:cc", "4>3:nsubj", "4>7:obj", "7>5:det", "7>6:amod")
+	tagged(tokens, "NNP|PERSON|Mary CC|O|and NNP|PERSON|John VBD|O|see DT|O|the JJ|O|big NN|O|dog")
+	dg.Edge[4].IsExtra = proto.Bool(true)
+	g := New(dg, tokens)
+
+	for _, c := range []struct {
+		pattern string
+		want    []string
+	}{
+		{`{tag:/VB.*/}=verb >nsubj {ner:PERSON}=who`, []string{"saw Mary", "saw John"}},
+		{`{pos:VBD} >nsubj {}`, []string{"saw"}},
+		{`{} >obj ({lemma:dog} >amod {word:big})`, []string{"saw"}},
+		{`{} >obj {} !>cc {}`, []string{"saw"}},
+		{`{ner:PERSON} !<nsubj {}`, nil},
+		{`{ner:PERSON}=who <nsubj {$}`, []string{"Mary Mary", "John John"}},
+		{`{word:big} << {$}`, []string{"big"}},
+		{`{$} >> {word:/an.*/}`, []string{"saw"}},
+		{`{} >/conj.*/ {}=c`, []string{"Mary John"}},
+		{`{ner:!O;tag:NNP} > {}`, []string{"Mary", "John"}},
+		{`{ner:!O} >cc {}`, []string{"John"}},
+		{`{idx:4} >nsubj {}=a & >nsubj {}=b >obj {}`, []string{"saw", "saw", "saw", "saw"}},
+		{`{}=x >nsubj {}=x`, nil},
+	} {
+		var got []string
+		for _, m := range MustCompile(c.pattern).Match(g) {
+			s := m.Node.Word()
+			for _, name := range []string{"who", "c"} {
+				if n, ok := m.Named[name]; ok {
+					s += " " + n.Word()
+				}
+			}
+			got = append(got, s)
+		}
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("%s: %q", c.pattern, got)
+		}
+	}
+
+	if !MustCompile(`{} >obj {}`).MatchNode(g, g.Node(4)) || MustCompile(`{} >obj {}`).MatchNode(g, g.Node(7)) {
+		t.Errorf("MatchNode")
+	}
+
+	for _, bad := range []string{``, `{`, `{tag:NN`, `{color:red}`, `{} >nsubj`, `{} >/x {}`, `({} >obj {}`, `{} {}`, `{word:/(/}`, `{}=`} {
+		if _, err := Compile(bad); err == nil {
+			t.Errorf("%s: no error", bad)
+		}
+	}
+}
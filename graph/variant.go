@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Variant selects one of the dependency graphs of a sentence.
+//
+type Variant int
+
+const (
+	// Basic reads Sentence.BasicDependencies, a tree.
+	Basic Variant = iota
+	// Enhanced reads Sentence.EnhancedDependencies.
+	Enhanced
+	// EnhancedPlusPlus reads Sentence.EnhancedPlusPlusDependencies.
+	EnhancedPlusPlus
+	// Collapsed reads Sentence.CollapsedDependencies, of the older Stanford
+	// Dependencies.
+	Collapsed
+	// CollapsedCCProcessed reads Sentence.CollapsedCCProcessedDependencies.
+	CollapsedCCProcessed
+)
+
+var variantNames = []string{"basic", "enhanced", "enhanced++", "collapsed", "collapsed-ccprocessed"}
+
+// String returns the name of the variant, e.g. "enhanced++".
+//
+func (self Variant) String() string {
+	if self < 0 || int(self) >= len(variantNames) {
+		return fmt.Sprintf("Variant(%d)", int(self))
+	}
+	return variantNames[self]
+}
+
+// Graph returns the graph of the variant in s, nil if s has none. A graph
+// without any node, root or edge, as left by merging empty messages, counts
+// as none.
+//
+func (self Variant) Graph(s *nlp.Sentence) *nlp.DependencyGraph {
+	var g *nlp.DependencyGraph
+	switch self {
+	case Basic:
+		g = s.GetBasicDependencies()
+	case Enhanced:
+		g = s.GetEnhancedDependencies()
+	case EnhancedPlusPlus:
+		g = s.GetEnhancedPlusPlusDependencies()
+	case Collapsed:
+		g = s.GetCollapsedDependencies()
+	case CollapsedCCProcessed:
+		g = s.GetCollapsedCCProcessedDependencies()
+	}
+	if len(g.GetNode()) == 0 && len(g.GetRoot()) == 0 && len(g.GetEdge()) == 0 {
+		return nil
+	}
+	return g
+}
+
+// fallbacks are the variants to use instead of a missing one, closest first.
+//
+var fallbacks = map[Variant][]Variant{
+	Basic:                {Enhanced, EnhancedPlusPlus, Collapsed, CollapsedCCProcessed},
+	Enhanced:             {EnhancedPlusPlus, Basic, CollapsedCCProcessed, Collapsed},
+	EnhancedPlusPlus:     {Enhanced, Basic, CollapsedCCProcessed, Collapsed},
+	Collapsed:            {CollapsedCCProcessed, Enhanced, EnhancedPlusPlus, Basic},
+	CollapsedCCProcessed: {Collapsed, EnhancedPlusPlus, Enhanced, Basic},
+}
+
+// MissingDependenciesError is returned by Dependencies when the sentence has
+// no graph of the variant.
+//
+type MissingDependenciesError struct {
+	Variant Variant
+
+	// the variant used instead, -1 if none
+	Fallback Variant
+
+	// the variants the sentence has
+	Present []Variant
+}
+
+func (self *MissingDependenciesError) Error() string {
+	if len(self.Present) == 0 {
+		return fmt.Sprintf("no %s dependencies, nor any other variant", self.Variant)
+	}
+	present := make([]string, len(self.Present))
+	for i, v := range self.Present {
+		present[i] = v.String()
+	}
+	return fmt.Sprintf("no %s dependencies, using %s of %s", self.Variant, self.Fallback, strings.Join(present, ", "))
+}
+
+// Dependencies returns the dependency graph of the variant in the sentence,
+// see Variant.Graph. When the sentence does not have it, the graph is that
+// of the closest variant present, e.g. enhanced for enhanced++, and the
+// error is a *MissingDependenciesError telling the variants present; so a
+// caller may use the graph whatever the error, or insist on the variant. The
+// graph is nil only if the sentence has no dependencies at all.
+//
+func Dependencies(sentence *nlp.Sentence, variant Variant) (*nlp.DependencyGraph, error) {
+	if g := variant.Graph(sentence); g != nil {
+		return g, nil
+	}
+	err := &MissingDependenciesError{Variant: variant, Fallback: -1}
+	for v := range variantNames {
+		if Variant(v).Graph(sentence) != nil {
+			err.Present = append(err.Present, Variant(v))
+		}
+	}
+	for _, v := range fallbacks[variant] {
+		if g := v.Graph(sentence); g != nil {
+			err.Fallback = v
+			return g, err
+		}
+	}
+	return nil, err
+}
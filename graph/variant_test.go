@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDependencies(t *testing.T) {
+	g, tokens := makeGraph("John runs", "0>2:root", "2>1:nsubj")
+	s := &nlp.Sentence{Token: tokens, BasicDependencies: g}
+	s.EnhancedPlusPlusDependencies = proto.Clone(g).(*nlp.DependencyGraph)
+	// an empty graph, as merged from an empty message, counts as none
+	s.EnhancedDependencies = &nlp.DependencyGraph{}
+
+	if g, err := Dependencies(s, Basic); err != nil || g != s.BasicDependencies {
+		t.Errorf("%v", err)
+	}
+	g, err := Dependencies(s, Enhanced)
+	missing, ok := err.(*MissingDependenciesError)
+	if !ok || g != s.EnhancedPlusPlusDependencies || missing.Fallback != EnhancedPlusPlus || len(missing.Present) != 2 {
+		t.Fatalf("%v", err)
+	}
+	if err.Error() != "no enhanced dependencies, using enhanced++ of basic, enhanced++" {
+		t.Errorf("%s", err)
+	}
+	if g, err = Dependencies(s, Collapsed); g != s.EnhancedPlusPlusDependencies || err == nil {
+		t.Errorf("%v", err)
+	}
+
+	g, err = Dependencies(&nlp.Sentence{BasicDependencies: &nlp.DependencyGraph{}}, CollapsedCCProcessed)
+	if g != nil || err == nil || err.Error() != "no collapsed-ccprocessed dependencies, nor any other variant" {
+		t.Errorf("%v", err)
+	}
+	if Variant(9).String() != "Variant(9)" {
+		t.Errorf("%s", Variant(9))
+	}
+}
@@ -0,0 +1,341 @@
+// Package pattern is the engine shared by the Tregex patterns of the tree
+// package and the Semgrex patterns of the graph package: a node followed by
+// its relations to other nodes, "&" between them optional, parentheses
+// grouping a node with its own relations, ! before a relation requiring that
+// no such node exists, and =name naming a node. A Language tells how to read
+// and match the nodes and the relations.
+//
+package pattern
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Node is a node of the tree or the graph matched.
+//
+type Node = interface{}
+
+// Language is what a pattern language adds to the engine.
+//
+type Language struct {
+	// the name of the language in the errors, e.g. "tregex"
+	Name string
+
+	// the characters starting a relation operator, e.g. "<>"
+	Operators string
+
+	// Node reads the description of a node, e.g. {tag:NN}, and returns the
+	// test of the nodes
+	Node func(p *Parser) (func(n Node) bool, error)
+
+	// Relation reads an operator, after any !, and returns the nodes in the
+	// relation with n
+	Relation func(p *Parser) (func(n Node) []Node, error)
+}
+
+// Pattern is a compiled pattern.
+//
+type Pattern struct {
+	source string
+	root   *nodePattern
+}
+
+type nodePattern struct {
+	test func(n Node) bool
+	name string
+	rels []*relPattern
+}
+
+type relPattern struct {
+	candidates func(n Node) []Node
+	negate     bool
+	node       *nodePattern
+}
+
+// Compile parses a pattern of the language.
+//
+func Compile(lang *Language, source string) (*Pattern, error) {
+	p := &Parser{lang: lang, input: []rune(source)}
+	root, err := p.pattern()
+	if err != nil {
+		return nil, err
+	}
+	p.Space()
+	if p.pos < len(p.input) {
+		return nil, p.Errorf("unexpected %q", string(p.input[p.pos]))
+	}
+	return &Pattern{source: source, root: root}, nil
+}
+
+// String returns the source of the pattern.
+//
+func (self *Pattern) String() string {
+	return self.source
+}
+
+// Match returns the distinct assignments of the names under which n matches
+// the pattern, nil if none.
+//
+func (self *Pattern) Match(n Node) []map[string]Node {
+	var matches []map[string]Node
+	seen := make(map[string]bool)
+	for _, named := range self.root.match(n, map[string]Node{}) {
+		if key := bindingKey(named); !seen[key] {
+			seen[key] = true
+			matches = append(matches, named)
+		}
+	}
+	return matches
+}
+
+// MatchNode reports whether n matches the pattern.
+//
+func (self *Pattern) MatchNode(n Node) bool {
+	return len(self.root.match(n, map[string]Node{})) > 0
+}
+
+// bindingKey identifies an assignment of the names.
+//
+func bindingKey(named map[string]Node) string {
+	keys := make([]string, 0, len(named))
+	for name, n := range named {
+		keys = append(keys, fmt.Sprintf("%s=%p", name, n))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, " ")
+}
+
+// match returns the assignments of the names, extending named, under which
+// n matches the node pattern with its relations; nil if none.
+//
+func (self *nodePattern) match(n Node, named map[string]Node) []map[string]Node {
+	if !self.test(n) {
+		return nil
+	}
+	if self.name != "" {
+		if bound, ok := named[self.name]; ok {
+			if bound != n {
+				return nil
+			}
+		} else {
+			named = extend(named, self.name, n)
+		}
+	}
+
+	results := []map[string]Node{named}
+	for _, rel := range self.rels {
+		var next []map[string]Node
+		for _, bindings := range results {
+			var found []map[string]Node
+			for _, m := range rel.candidates(n) {
+				found = append(found, rel.node.match(m, bindings)...)
+				if rel.negate && len(found) > 0 {
+					break
+				}
+			}
+			if rel.negate {
+				if len(found) == 0 {
+					next = append(next, bindings)
+				}
+				continue
+			}
+			next = append(next, found...)
+		}
+		results = next
+		if len(results) == 0 {
+			return nil
+		}
+	}
+	return results
+}
+
+func extend(named map[string]Node, name string, n Node) map[string]Node {
+	m := make(map[string]Node, len(named)+1)
+	for k, v := range named {
+		m[k] = v
+	}
+	m[name] = n
+	return m
+}
+
+// Parser reads a pattern; the languages read their nodes and relations with
+// its methods.
+//
+type Parser struct {
+	lang  *Language
+	input []rune
+	pos   int
+}
+
+// Errorf returns an error at the current position.
+//
+func (self *Parser) Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s: at %d: %s", self.lang.Name, self.pos, fmt.Sprintf(format, args...))
+}
+
+// Space skips the spaces.
+//
+func (self *Parser) Space() {
+	for self.pos < len(self.input) && unicode.IsSpace(self.input[self.pos]) {
+		self.pos++
+	}
+}
+
+// Peek returns the next character, 0 at the end.
+//
+func (self *Parser) Peek() rune {
+	if self.pos < len(self.input) {
+		return self.input[self.pos]
+	}
+	return 0
+}
+
+// Skip moves past the next character.
+//
+func (self *Parser) Skip() {
+	self.pos++
+}
+
+// While reads the characters as long as ok.
+//
+func (self *Parser) While(ok func(r rune) bool) string {
+	start := self.pos
+	for self.pos < len(self.input) && ok(self.input[self.pos]) {
+		self.pos++
+	}
+	return string(self.input[start:self.pos])
+}
+
+// Ident reads a node name or a relation name: letters, digits, "_", ":"
+// and "-".
+//
+func (self *Parser) Ident() string {
+	return self.While(func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == ':' || r == '-'
+	})
+}
+
+// Regex reads /.../, and returns what is between the slashes.
+//
+func (self *Parser) Regex() (string, error) {
+	self.pos++
+	start := self.pos
+	for self.pos < len(self.input) {
+		switch self.input[self.pos] {
+		case '\\':
+			self.pos++
+		case '/':
+			s := string(self.input[start:self.pos])
+			self.pos++
+			return s, nil
+		}
+		self.pos++
+	}
+	return "", self.Errorf("missing /")
+}
+
+// Until reads up to the delimiter, outside of the regexes, and skips it.
+//
+func (self *Parser) Until(delim rune) (string, error) {
+	start := self.pos
+	inRegex := false
+	for self.pos < len(self.input) {
+		r := self.input[self.pos]
+		switch {
+		case r == '\\':
+			self.pos++
+		case r == '/':
+			inRegex = !inRegex
+		case r == delim && !inRegex:
+			s := string(self.input[start:self.pos])
+			self.pos++
+			return s, nil
+		}
+		self.pos++
+	}
+	return "", self.Errorf("missing %c", delim)
+}
+
+// pattern reads a node with its relations.
+//
+func (self *Parser) pattern() (*nodePattern, error) {
+	n, err := self.node()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		self.Space()
+		if self.Peek() == '&' {
+			self.pos++
+			self.Space()
+		}
+		r := self.Peek()
+		if r == 0 || (r != '!' && !strings.ContainsRune(self.lang.Operators, r)) {
+			return n, nil
+		}
+		rel, err := self.relation()
+		if err != nil {
+			return nil, err
+		}
+		n.rels = append(n.rels, rel)
+	}
+}
+
+// node reads a node of the language with its name, or a parenthesized
+// pattern.
+//
+func (self *Parser) node() (*nodePattern, error) {
+	self.Space()
+	if self.Peek() == '(' {
+		self.pos++
+		n, err := self.pattern()
+		if err != nil {
+			return nil, err
+		}
+		self.Space()
+		if self.Peek() != ')' {
+			return nil, self.Errorf("missing )")
+		}
+		self.pos++
+		return n, nil
+	}
+
+	test, err := self.lang.Node(self)
+	if err != nil {
+		return nil, err
+	}
+	n := &nodePattern{test: test}
+	if self.Peek() == '=' {
+		self.pos++
+		n.name = self.Ident()
+		if n.name == "" {
+			return nil, self.Errorf("missing name after =")
+		}
+	}
+	return n, nil
+}
+
+// relation reads a relation and the node it leads to.
+//
+func (self *Parser) relation() (*relPattern, error) {
+	rel := &relPattern{}
+	if self.Peek() == '!' {
+		rel.negate = true
+		self.pos++
+	}
+	candidates, err := self.lang.Relation(self)
+	if err != nil {
+		return nil, err
+	}
+	rel.candidates = candidates
+
+	node, err := self.node()
+	if err != nil {
+		return nil, err
+	}
+	rel.node = node
+	return rel, nil
+}
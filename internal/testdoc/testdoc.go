@@ -0,0 +1,96 @@
+// Package testdoc builds the annotated documents of the tests from short
+// descriptions, so that the packages share one fixture instead of each
+// walking the protobuf messages by hand.
+//
+package testdoc
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// Doc builds a document from sentences produced by Sentence, filling in the
+// text and the character offsets as if tokens were space-separated.
+//
+func Doc(sentences ...*nlp.Sentence) *nlp.Document {
+	var parts []string
+	offset := 0
+	for i, s := range sentences {
+		s.SentenceIndex = proto.Uint32(uint32(i))
+		s.CharacterOffsetBegin = proto.Uint32(uint32(offset))
+		for j, t := range s.Token {
+			t.BeginChar = proto.Uint32(uint32(offset))
+			offset += len(t.GetWord())
+			t.EndChar = proto.Uint32(uint32(offset))
+			t.Before = proto.String(" ")
+			t.After = proto.String(" ")
+			if i == 0 && j == 0 {
+				t.Before = proto.String("")
+			}
+			if i == len(sentences)-1 && j == len(s.Token)-1 {
+				t.After = proto.String("")
+			}
+			parts = append(parts, t.GetWord())
+			offset++
+		}
+		s.CharacterOffsetEnd = proto.Uint32(uint32(offset - 1))
+	}
+	return &nlp.Document{Text: proto.String(strings.Join(parts, " ")), Sentence: sentences}
+}
+
+// Sentence builds a sentence from "word|POS|lemma|NER" items separated by
+// spaces, where an empty field is left unset, and basic dependency edges
+// written as "governor>dependent:relation" with 1-based indexes.
+//
+func Sentence(tagged string, edges ...string) *nlp.Sentence {
+	s := &nlp.Sentence{}
+	for i, item := range strings.Fields(tagged) {
+		f := strings.Split(item, "|")
+		t := &nlp.Token{Word: proto.String(f[0]), OriginalText: proto.String(f[0]), Value: proto.String(f[0]), TokenBeginIndex: proto.Uint32(uint32(i)), TokenEndIndex: proto.Uint32(uint32(i + 1))}
+		if len(f) > 1 && f[1] != "" {
+			t.Pos = proto.String(f[1])
+		}
+		if len(f) > 2 && f[2] != "" {
+			t.Lemma = proto.String(f[2])
+		}
+		if len(f) > 3 && f[3] != "" {
+			t.Ner = proto.String(f[3])
+		}
+		s.Token = append(s.Token, t)
+	}
+	if edges == nil {
+		return s
+	}
+
+	g := &nlp.DependencyGraph{}
+	for i := range s.Token {
+		g.Node = append(g.Node, &nlp.DependencyGraph_Node{Index: proto.Uint32(uint32(i + 1)), SentenceIndex: proto.Uint32(0)})
+	}
+	for _, e := range edges {
+		colon := strings.Index(e, ":")
+		arrow := strings.Index(e, ">")
+		source, _ := strconv.Atoi(e[:arrow])
+		target, _ := strconv.Atoi(e[arrow+1 : colon])
+		if source == 0 {
+			g.Root = append(g.Root, uint32(target))
+			continue
+		}
+		g.Edge = append(g.Edge, &nlp.DependencyGraph_Edge{Source: proto.Uint32(uint32(source)), Target: proto.Uint32(uint32(target)), Dep: proto.String(e[colon+1:])})
+	}
+	s.BasicDependencies = g
+	return s
+}
+
+// Tagged builds a document with one sentence per argument, each written as
+// the items of Sentence, without dependencies.
+//
+func Tagged(sentences ...string) *nlp.Document {
+	built := make([]*nlp.Sentence, len(sentences))
+	for i, tagged := range sentences {
+		built[i] = Sentence(tagged)
+	}
+	return Doc(built...)
+}
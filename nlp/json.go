@@ -0,0 +1,30 @@
+package nlp
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// JSONMarshalOptions are the options of Document.MarshalJSON: camelCase
+// field names, unset fields omitted, and documents missing required fields
+// accepted, as CoreNLP leaves some of them out.
+//
+var JSONMarshalOptions = protojson.MarshalOptions{AllowPartial: true}
+
+// JSONUnmarshalOptions are the options of Document.UnmarshalJSON: unknown
+// fields, e.g. from a newer CoreNLP, are skipped.
+//
+var JSONUnmarshalOptions = protojson.UnmarshalOptions{AllowPartial: true, DiscardUnknown: true}
+
+// MarshalJSON encodes the document with protojson, so it can be stored with
+// encoding/json, e.g. in a document database. Note that protojson does not
+// promise a stable output: compare documents, not their JSON.
+//
+func (x *Document) MarshalJSON() ([]byte, error) {
+	return JSONMarshalOptions.Marshal(x)
+}
+
+// UnmarshalJSON decodes a document encoded by MarshalJSON.
+//
+func (x *Document) UnmarshalJSON(data []byte) error {
+	return JSONUnmarshalOptions.Unmarshal(data, x)
+}
@@ -0,0 +1,46 @@
+package nlp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDocumentJSON(t *testing.T) {
+	doc := &Document{
+		Text: proto.String("Hi there."),
+		Sentence: []*Sentence{{
+			TokenOffsetBegin: proto.Uint32(0),
+			TokenOffsetEnd:   proto.Uint32(2),
+			Token: []*Token{
+				{Word: proto.String("Hi"), Pos: proto.String("UH"), BeginChar: proto.Uint32(0)},
+				{Word: proto.String("there"), Pos: proto.String("RB"), BeginChar: proto.Uint32(3)},
+			},
+		}},
+		// the representative is required, and missing
+		CorefChain: []*CorefChain{{ChainID: proto.Int32(1)}},
+	}
+
+	stored := struct {
+		ID  string    `json:"id"`
+		Doc *Document `json:"doc"`
+	}{"d1", doc}
+	data, err := json.Marshal(stored)
+	if err != nil { t.Fatal(err) }
+	if !strings.Contains(string(data), `"tokenOffsetBegin"`) || !strings.Contains(string(data), `"beginChar"`) {
+		t.Errorf("%s", data)
+	}
+
+	stored.Doc = nil
+	if err = json.Unmarshal(data, &stored); err != nil { t.Fatal(err) }
+	if !proto.Equal(stored.Doc, doc) {
+		t.Errorf("%v", stored.Doc)
+	}
+
+	back := &Document{}
+	if err = json.Unmarshal([]byte(`{"text":"x","unknownField":1}`), back); err != nil || back.GetText() != "x" {
+		t.Errorf("%v %v", back, err)
+	}
+}
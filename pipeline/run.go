@@ -0,0 +1,450 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/genelet/corenlp-golang/client"
+	"github.com/genelet/corenlp-golang/format"
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/redact"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+var strategies = map[string]redact.Strategy{
+	"":            redact.Placeholder,
+	"placeholder": redact.Placeholder,
+	"mask":        redact.Mask,
+	"hash":        redact.Hash,
+}
+
+// fileNames escapes the IDs of the documents into the names of their files
+// in DirSink, reversibly, so that "a/b" and "a_b" keep apart.
+//
+var fileNames = strings.NewReplacer("%", "%25", "/", "%2F", "\\", "%5C", ":", "%3A", "*", "%2A", "?", "%3F", `"`, "%22", "<", "%3C", ">", "%3E", "|", "%7C")
+
+// extensions are the file extensions of the formats, in DirSink.
+//
+var extensions = map[string]string{
+	JSONExport:       ".json",
+	SerializedExport: ".ser",
+	CoNLLUExport:     ".conllu",
+	CoNLL2003Export:  ".conll",
+	BratExport:       ".ann",
+	TextExport:       ".txt",
+}
+
+// Summary is the outcome of a run.
+//
+type Summary struct {
+	// the documents annotated and exported
+	Documents int
+
+	// the texts that failed, with their error, by ID
+	Failed map[string]error
+}
+
+// Runner executes a PipelineSpec.
+//
+type Runner struct {
+	Spec *PipelineSpec
+
+	// the client, built from the spec if nil
+	Client client.Client
+
+	// the standard input of Input.Lines "-", and the standard output of
+	// StdoutSink, os.Stdin and os.Stdout by default
+	Stdin  io.Reader
+	Stdout io.Writer
+}
+
+// NewRunner creates a Runner of spec.
+//
+func NewRunner(spec *PipelineSpec) *Runner {
+	return &Runner{Spec: spec, Stdin: os.Stdin, Stdout: os.Stdout}
+}
+
+// Run annotates the texts of the input, applies the post-processors to the
+// documents and writes them with the exporters, with Spec.Workers documents
+// at once; so the documents come in no particular order in the files. A
+// text failing to annotate or to export is recorded in the summary, and the
+// run goes on. The error is that of the spec, of the input or of the sinks,
+// or ctx.Err() if ctx is done first.
+//
+func (self *Runner) Run(ctx context.Context) (*Summary, error) {
+	spec := self.Spec
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	c := self.Client
+	if c == nil {
+		var err error
+		if c, err = spec.client(); err != nil {
+			return nil, err
+		}
+	}
+	processors := spec.processors()
+
+	var sinks []*sink
+	defer func() {
+		for _, s := range sinks {
+			s.close()
+		}
+	}()
+	for _, e := range spec.Exporters {
+		s, err := self.open(e)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, s)
+	}
+
+	summary := &Summary{Failed: make(map[string]error)}
+	runner := client.NewRunner(c, client.SinkFunc(func(result *client.Result) error {
+		err := result.Err
+		for _, p := range processors {
+			if err != nil {
+				break
+			}
+			err = p(result.Document)
+		}
+		for _, s := range sinks {
+			if err != nil {
+				break
+			}
+			err = s.write(result.ID, result.Document)
+		}
+		if err != nil {
+			summary.Failed[result.ID] = err
+			return nil
+		}
+		summary.Documents++
+		return nil
+	}), spec.Workers)
+
+	err := self.read(func(item *client.Item) error {
+		return runner.Submit(ctx, item)
+	})
+	if _, derr := runner.Drain(ctx); err == nil {
+		err = derr
+	}
+	for _, s := range sinks {
+		if cerr := s.close(); err == nil {
+			err = cerr
+		}
+	}
+	return summary, err
+}
+
+// read sends the texts of the input to fn.
+//
+func (self *Runner) read(fn func(item *client.Item) error) error {
+	in := self.Spec.Input
+	for _, pattern := range in.Files {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return err
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("%s: no such files", pattern)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			text, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if err = fn(&client.Item{ID: path, Text: text}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if in.Lines != "" {
+		r := self.Stdin
+		if in.Lines != "-" {
+			f, err := os.Open(in.Lines)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(nil, 16<<20)
+		for n := 1; scanner.Scan(); n++ {
+			if strings.TrimSpace(scanner.Text()) == "" {
+				continue
+			}
+			item := &client.Item{ID: fmt.Sprintf("%s:%d", in.Lines, n), Text: append([]byte(nil), scanner.Bytes()...)}
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	for i, text := range in.Texts {
+		if err := fn(&client.Item{ID: fmt.Sprintf("text:%d", i+1), Text: []byte(text)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processors returns the post-processors of the spec, sharing their
+// pseudonyms across the documents.
+//
+func (self *PipelineSpec) processors() []func(doc *nlp.Document) error {
+	var fns []func(doc *nlp.Document) error
+	for _, p := range self.PostProcessors {
+		p := p
+		switch p.Type {
+		case DropProcessor:
+			fns = append(fns, func(doc *nlp.Document) error {
+				client.DropLayers(doc, p.Annotators...)
+				return nil
+			})
+		case RedactProcessor:
+			r := &redact.Redactor{Types: p.Types, Strategy: strategies[p.Strategy], Salt: p.Salt}
+			fns = append(fns, func(doc *nlp.Document) error {
+				result, err := r.Redact(doc)
+				if err != nil {
+					return err
+				}
+				replaceEntities(doc, result)
+				return nil
+			})
+		case PseudonymizeProcessor:
+			ps := redact.NewPseudonymizer(p.Salt)
+			ps.Types = p.Types
+			fns = append(fns, func(doc *nlp.Document) error {
+				result, err := ps.Pseudonymize(doc)
+				if err != nil {
+					return err
+				}
+				replaceEntities(doc, result)
+				return nil
+			})
+		}
+	}
+	return fns
+}
+
+// replaceEntities sets the text of doc to the redacted one, with the
+// character offsets rebased on it: the first token of a redacted span takes
+// its replacement, and the other tokens of the span become empty at its end.
+//
+func replaceEntities(doc *nlp.Document, result *redact.Result) {
+	spans := append([]*redact.Span(nil), result.Spans...)
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Begin < spans[j].Begin })
+	// shift moves an offset of the original text outside the spans
+	shift := func(offset uint32) uint32 {
+		delta := 0
+		for _, span := range spans {
+			if span.End > int(offset) {
+				break
+			}
+			delta += units(span.Replacement) - (span.End - span.Begin)
+		}
+		return uint32(int(offset) + delta)
+	}
+
+	for _, s := range doc.GetSentence() {
+		for _, t := range s.Token {
+			span := within(spans, t)
+			if span == nil {
+				t.BeginChar, t.EndChar = proto.Uint32(shift(t.GetBeginChar())), proto.Uint32(shift(t.GetEndChar()))
+				continue
+			}
+			word := ""
+			begin := shift(uint32(span.Begin))
+			end := begin + uint32(units(span.Replacement))
+			if int(t.GetBeginChar()) == span.Begin {
+				word = span.Replacement
+			} else {
+				begin = end
+			}
+			t.BeginChar, t.EndChar = proto.Uint32(begin), proto.Uint32(end)
+			t.Word, t.OriginalText, t.Value = proto.String(word), proto.String(word), proto.String(word)
+			if t.Lemma != nil {
+				t.Lemma = proto.String(word)
+			}
+		}
+		if s.CharacterOffsetBegin != nil {
+			s.CharacterOffsetBegin = proto.Uint32(shift(s.GetCharacterOffsetBegin()))
+			s.CharacterOffsetEnd = proto.Uint32(shift(s.GetCharacterOffsetEnd()))
+		}
+	}
+	// the mentions keep no text of the entities
+	for _, m := range doc.Mentions {
+		replaceMention(doc, m)
+	}
+	for _, s := range doc.GetSentence() {
+		for _, m := range s.Mentions {
+			replaceMention(doc, m)
+		}
+	}
+	for _, q := range doc.Quote {
+		if q.Begin != nil {
+			q.Begin, q.End = proto.Uint32(shift(q.GetBegin())), proto.Uint32(shift(q.GetEnd()))
+		}
+	}
+	doc.Text = proto.String(result.Text)
+
+	// the code point offsets follow the UTF-16 ones
+	codepoints := make([]uint32, 0, len(result.Text)+1)
+	n := uint32(0)
+	for _, r := range result.Text {
+		codepoints = append(codepoints, n)
+		if r >= 0x10000 {
+			codepoints = append(codepoints, n)
+		}
+		n++
+	}
+	codepoints = append(codepoints, n)
+	for _, s := range doc.GetSentence() {
+		for _, t := range s.Token {
+			if t.CodepointOffsetBegin != nil {
+				t.CodepointOffsetBegin = proto.Uint32(codepoint(codepoints, t.GetBeginChar()))
+				t.CodepointOffsetEnd = proto.Uint32(codepoint(codepoints, t.GetEndChar()))
+			}
+		}
+	}
+}
+
+// replaceMention sets the text of m, if any, to the words of its tokens.
+//
+func replaceMention(doc *nlp.Document, m *nlp.NERMention) {
+	if m.EntityMentionText == nil || int(m.GetSentenceIndex()) >= len(doc.GetSentence()) {
+		return
+	}
+	tokens := doc.Sentence[m.GetSentenceIndex()].Token
+	begin, end := int(m.GetTokenStartInSentenceInclusive()), int(m.GetTokenEndInSentenceExclusive())
+	if begin >= end || end > len(tokens) {
+		return
+	}
+	var words []string
+	for _, t := range tokens[begin:end] {
+		if t.GetWord() != "" {
+			words = append(words, t.GetWord())
+		}
+	}
+	m.EntityMentionText = proto.String(strings.Join(words, " "))
+}
+
+// within returns the span of the original text covering t, nil if none.
+//
+func within(spans []*redact.Span, t *nlp.Token) *redact.Span {
+	for _, span := range spans {
+		if int(t.GetBeginChar()) >= span.Begin && int(t.GetEndChar()) <= span.End {
+			return span
+		}
+	}
+	return nil
+}
+
+// units returns the length of s in UTF-16 code units, as CoreNLP counts
+// the characters.
+//
+func units(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+func codepoint(codepoints []uint32, offset uint32) uint32 {
+	if int(offset) >= len(codepoints) {
+		return codepoints[len(codepoints)-1]
+	}
+	return codepoints[offset]
+}
+
+// sink writes the documents of an exporter.
+//
+type sink struct {
+	format string
+	dir    string
+	w      io.Writer
+	file   *os.File
+}
+
+func (self *Runner) open(e *ExporterSpec) (*sink, error) {
+	s := &sink{format: e.Format}
+	switch e.Sink.Type {
+	case DirSink:
+		if err := os.MkdirAll(e.Sink.Path, 0755); err != nil {
+			return nil, err
+		}
+		s.dir = e.Sink.Path
+	case FileSink:
+		f, err := os.Create(e.Sink.Path)
+		if err != nil {
+			return nil, err
+		}
+		s.file, s.w = f, f
+	case StdoutSink:
+		s.w = self.Stdout
+	}
+	return s, nil
+}
+
+func (self *sink) write(id string, doc *nlp.Document) error {
+	data, err := encode(self.format, doc)
+	if err != nil {
+		return err
+	}
+	if self.dir != "" {
+		name := fileNames.Replace(id) + extensions[self.format]
+		return ioutil.WriteFile(filepath.Join(self.dir, name), data, 0666)
+	}
+	if self.format != SerializedExport && len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	_, err = self.w.Write(data)
+	return err
+}
+
+func (self *sink) close() error {
+	if self.file == nil {
+		return nil
+	}
+	err := self.file.Close()
+	self.file = nil
+	return err
+}
+
+// encode writes doc in the format of an exporter.
+//
+func encode(f string, doc *nlp.Document) ([]byte, error) {
+	switch f {
+	case JSONExport:
+		return protojson.Marshal(doc)
+	case SerializedExport:
+		data, err := proto.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		return protowire.AppendBytes(nil, data), nil
+	case CoNLLUExport:
+		s, err := format.ToCoNLLU(doc)
+		return []byte(s), err
+	case CoNLL2003Export:
+		return []byte(format.ToCoNLL2003(doc)), nil
+	case BratExport:
+		return []byte(format.ToBrat(doc)), nil
+	case TextExport:
+		return []byte(format.Format(doc)), nil
+	}
+	return nil, fmt.Errorf("unknown format %q", f)
+}
@@ -0,0 +1,166 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fakeClient tokenizes on spaces, tagging Marie and Curie as PERSON, and
+// fails on "fail".
+//
+type fakeClient struct{}
+
+func (self fakeClient) Run(ctx context.Context, input string, msg protoreflect.ProtoMessage) error {
+	text, err := ioutil.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	return self.RunText(ctx, text, msg)
+}
+
+func (self fakeClient) RunText(ctx context.Context, text []byte, msg protoreflect.ProtoMessage) error {
+	if string(text) == "fail" {
+		return errors.New("failed")
+	}
+	doc := msg.(*nlp.Document)
+	doc.Text = proto.String(string(text))
+	s := &nlp.Sentence{}
+	offset := 0
+	for i, w := range strings.Fields(string(text)) {
+		ner := "O"
+		if w == "Marie" || w == "Curie" {
+			ner = "PERSON"
+		}
+		s.Token = append(s.Token, &nlp.Token{Word: proto.String(w), OriginalText: proto.String(w), Value: proto.String(w), Pos: proto.String("NN"), Ner: proto.String(ner),
+			BeginChar: proto.Uint32(uint32(offset)), EndChar: proto.Uint32(uint32(offset + len(w))), TokenBeginIndex: proto.Uint32(uint32(i)), TokenEndIndex: proto.Uint32(uint32(i + 1))})
+		offset += len(w) + 1
+	}
+	s.TokenOffsetBegin, s.TokenOffsetEnd = proto.Uint32(0), proto.Uint32(uint32(len(s.Token)))
+	doc.Sentence = []*nlp.Sentence{s}
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pipeline")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+	if err = ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("Marie Curie won"), 0666); err != nil { t.Fatal(err) }
+
+	spec, err := ParseSpec([]byte(`
+input:
+  files: [` + filepath.Join(dir, "*.txt") + `]
+  lines: "-"
+annotators: [tokenize, ner]
+workers: 1
+postProcessors:
+- type: redact
+- type: drop
+  annotators: [pos]
+exporters:
+- format: conllu
+  sink: {type: dir, path: ` + filepath.Join(dir, "out") + `}
+- format: text
+  sink: {type: file, path: ` + filepath.Join(dir, "all.out") + `}
+- format: serialized
+  sink: {type: stdout}
+`))
+	if err != nil { t.Fatal(err) }
+
+	r := NewRunner(spec)
+	r.Client = fakeClient{}
+	r.Stdin = strings.NewReader("Curie left\n\nfail\n")
+	stdout := &bytes.Buffer{}
+	r.Stdout = stdout
+	summary, err := r.Run(context.Background())
+	if err != nil { t.Fatal(err) }
+	if summary.Documents != 2 || len(summary.Failed) != 1 || summary.Failed["-:3"] == nil {
+		t.Errorf("%v", summary.Failed)
+	}
+
+	conllu, err := ioutil.ReadFile(filepath.Join(dir, "out", strings.NewReplacer("/", "%2F", ":", "%3A").Replace(filepath.Join(dir, "a.txt"))+".conllu"))
+	if err != nil { t.Fatal(err) }
+	// the replacement goes on the first token of the entity only
+	if !strings.Contains(string(conllu), "# text = [PERSON] won\n1\t[PERSON]\t_\t") || !strings.Contains(string(conllu), "\n2\t_\t") || strings.Contains(string(conllu), "Curie") || strings.Contains(string(conllu), "NN") {
+		t.Errorf("%s", conllu)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "out", "-%3A1.conllu")); err != nil {
+		t.Errorf("%v", err)
+	}
+	all, err := ioutil.ReadFile(filepath.Join(dir, "all.out"))
+	if err != nil || strings.Contains(string(all), "Curie") || !strings.Contains(string(all), "left") {
+		t.Errorf("%s %v", all, err)
+	}
+	if stdout.Len() == 0 || strings.Contains(stdout.String(), "Marie") {
+		t.Errorf("%q", stdout.String())
+	}
+
+	r.Spec.Input = InputSpec{Files: []string{filepath.Join(dir, "*.md")}}
+	if _, err = r.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "no such files") {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestDirSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pipeline")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	s := &sink{format: TextExport, dir: dir}
+	for _, id := range []string{"a/b", "a_b", "a%2Fb", `a\b`} {
+		if err = s.write(id, &nlp.Document{Text: proto.String(id)}); err != nil { t.Fatal(err) }
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil { t.Fatal(err) }
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	if strings.Join(names, " ") != "a%252Fb.txt a%2Fb.txt a%5Cb.txt a_b.txt" {
+		t.Errorf("%v", names)
+	}
+}
+
+func TestReplaceEntities(t *testing.T) {
+	doc := &nlp.Document{}
+	if err := (fakeClient{}).RunText(context.Background(), []byte("Marie Curie won"), doc); err != nil { t.Fatal(err) }
+	doc.Sentence[0].Mentions = []*nlp.NERMention{{SentenceIndex: proto.Uint32(0), TokenStartInSentenceInclusive: proto.Uint32(0), TokenEndInSentenceExclusive: proto.Uint32(2),
+		Ner: proto.String("PERSON"), EntityMentionText: proto.String("Marie Curie")}}
+	spec := &PipelineSpec{PostProcessors: []*ProcessorSpec{{Type: RedactProcessor}}}
+	for _, p := range spec.processors() {
+		if err := p(doc); err != nil { t.Fatal(err) }
+	}
+
+	if doc.GetText() != "[PERSON] won" {
+		t.Fatalf("%q", doc.GetText())
+	}
+	for i, want := range []struct {
+		word       string
+		begin, end uint32
+	}{{"[PERSON]", 0, 8}, {"", 8, 8}, {"won", 9, 12}} {
+		tk := doc.Sentence[0].Token[i]
+		if tk.GetWord() != want.word || tk.GetBeginChar() != want.begin || tk.GetEndChar() != want.end {
+			t.Errorf("%d: %v", i, tk)
+		}
+		if got := doc.GetText()[tk.GetBeginChar():tk.GetEndChar()]; got != want.word {
+			t.Errorf("%d: %q", i, got)
+		}
+	}
+	if m := doc.Sentence[0].Mentions[0]; m.GetEntityMentionText() != "[PERSON]" {
+		t.Errorf("%v", m)
+	}
+	data, err := encode(BratExport, doc)
+	if err != nil { t.Fatal(err) }
+	if string(data) != "T1\tPERSON 0 8\t[PERSON]\n" {
+		t.Errorf("%q", data)
+	}
+}
@@ -0,0 +1,360 @@
+// Package pipeline runs CoreNLP as a configurable annotation engine: a
+// declarative PipelineSpec, in JSON or YAML, tells where the texts come
+// from, how to annotate them, what to do with the documents and where to
+// write them, and a Runner executes it.
+//
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/genelet/corenlp-golang/client"
+	"gopkg.in/yaml.v3"
+)
+
+// PipelineSpec describes an annotation job, e.g. in YAML:
+//
+//	input:
+//	  files: ["news/*.txt"]
+//	server: http://localhost:9000
+//	annotators: [tokenize, ssplit, pos, lemma, ner]
+//	properties:
+//	  ner.applyFineGrained: false
+//	postProcessors:
+//	  - type: redact
+//	    strategy: hash
+//	exporters:
+//	  - format: conllu
+//	    sink: {type: dir, path: out}
+//
+// The same in JSON has the same keys.
+//
+type PipelineSpec struct {
+	Name string `json:"name,omitempty"`
+
+	Input InputSpec `json:"input"`
+
+	// the URL of a CoreNLP server; if empty, CoreNLP runs locally with Java
+	Server string `json:"server,omitempty"`
+
+	// for the local runs, the classpath of CoreNLP, the java command and
+	// its memory, e.g. "4g"
+	ClassPath string `json:"classPath,omitempty"`
+	Java      string `json:"java,omitempty"`
+	Memory    string `json:"memory,omitempty"`
+
+	// the language of the pipeline, e.g. "chinese"
+	Language string `json:"language,omitempty"`
+
+	// a preset of client.Presets, e.g. "fast", whose annotators and
+	// properties Annotators and Properties override
+	Preset string `json:"preset,omitempty"`
+
+	Annotators []string          `json:"annotators,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+
+	// the documents annotated at once, default 4
+	Workers int `json:"workers,omitempty"`
+
+	// applied to every document, in order, before the exporters
+	PostProcessors []*ProcessorSpec `json:"postProcessors,omitempty"`
+
+	Exporters []*ExporterSpec `json:"exporters"`
+}
+
+// InputSpec gives the texts to annotate. Their IDs, used by the "dir"
+// sinks and in the errors, are the paths of the files, the path and the
+// line number, e.g. "texts.txt:3", for the lines, and "text:1" for the first
+// of the texts.
+//
+type InputSpec struct {
+	// text files, as filepath.Glob patterns
+	Files []string `json:"files,omitempty"`
+
+	// a file holding one text per line, "-" for the standard input
+	Lines string `json:"lines,omitempty"`
+
+	// texts given in the spec
+	Texts []string `json:"texts,omitempty"`
+}
+
+// The types of ProcessorSpec.
+//
+const (
+	// DropProcessor removes the layers of the annotators, see client.DropLayers.
+	DropProcessor = "drop"
+	// RedactProcessor redacts the entities, see redact.Redactor.
+	RedactProcessor = "redact"
+	// PseudonymizeProcessor replaces the entities with fake names, the same
+	// across the documents of the run, see redact.Pseudonymizer.
+	PseudonymizeProcessor = "pseudonymize"
+)
+
+// ProcessorSpec describes a post-processor of the documents. RedactProcessor
+// and PseudonymizeProcessor change the text of the document, the word of the
+// first token of each entity and its character offsets, so no exporter
+// writes the entities.
+//
+type ProcessorSpec struct {
+	Type string `json:"type"`
+
+	// the annotators whose layers DropProcessor removes
+	Annotators []string `json:"annotators,omitempty"`
+
+	// the NER types to replace, default redact.PII
+	Types []string `json:"types,omitempty"`
+
+	// for RedactProcessor: "placeholder", the default, "mask" or "hash"
+	Strategy string `json:"strategy,omitempty"`
+
+	// the salt of the "hash" strategy, or the seed of PseudonymizeProcessor
+	Salt string `json:"salt,omitempty"`
+}
+
+// The formats of ExporterSpec.
+//
+const (
+	// JSONExport writes the documents as protobuf JSON, one per line in a file.
+	JSONExport = "json"
+	// SerializedExport writes the documents as ProtobufAnnotationSerializer
+	// does, each prefixed by its length.
+	SerializedExport = "serialized"
+	// CoNLLUExport writes the documents in CoNLL-U, see format.ToCoNLLU.
+	CoNLLUExport = "conllu"
+	// CoNLL2003Export writes the entities in BIO, see format.ToCoNLL2003.
+	CoNLL2003Export = "conll2003"
+	// BratExport writes the brat standoff annotations, see format.ToBrat.
+	BratExport = "brat"
+	// TextExport writes a readable dump, see format.Format.
+	TextExport = "text"
+)
+
+// ExporterSpec writes the documents in a format to a sink.
+//
+type ExporterSpec struct {
+	Format string   `json:"format"`
+	Sink   SinkSpec `json:"sink"`
+}
+
+// The types of SinkSpec.
+//
+const (
+	// DirSink writes a file by document in the directory Path, named after
+	// the ID of the document with the extension of the format; the
+	// characters not allowed in file names, and %, are escaped as in URLs,
+	// e.g. "a%2Fb.txt.json" for "a/b.txt".
+	DirSink = "dir"
+	// FileSink writes the documents one after the other to the file Path.
+	FileSink = "file"
+	// StdoutSink writes the documents one after the other to the standard output.
+	StdoutSink = "stdout"
+)
+
+// SinkSpec is where an exporter writes.
+//
+type SinkSpec struct {
+	Type string `json:"type"`
+	Path string `json:"path,omitempty"`
+}
+
+// ParseSpec reads a spec in JSON, if it starts with "{", or else in YAML.
+// The values of Properties may be given as YAML numbers and booleans, and
+// keep their source text, e.g. "1.10". The spec is validated.
+//
+func ParseSpec(data []byte) (*PipelineSpec, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var err error
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+
+	spec := &PipelineSpec{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(spec); err != nil {
+		return nil, fmt.Errorf("spec: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// yamlToJSON converts a YAML document to JSON, so that it is decoded and
+// checked as JSON.
+//
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("spec: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return []byte("{}"), nil
+	}
+	v, err := yamlValue(doc.Content[0], true, false)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// yamlValue returns the value of a node for json.Marshal: the scalars as
+// YAML resolves them, or their text if raw. The properties under the top
+// mapping are raw.
+//
+func yamlValue(n *yaml.Node, top, raw bool) (interface{}, error) {
+	switch n.Kind {
+	case yaml.AliasNode:
+		return yamlValue(n.Alias, false, raw)
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if _, ok := m[key.Value]; ok {
+				return nil, fmt.Errorf("spec: line %d: duplicate key %q", key.Line, key.Value)
+			}
+			v, err := yamlValue(n.Content[i+1], false, raw || (top && key.Value == "properties"))
+			if err != nil {
+				return nil, err
+			}
+			m[key.Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		list := make([]interface{}, 0, len(n.Content))
+		for _, c := range n.Content {
+			v, err := yamlValue(c, false, raw)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	}
+	if raw && n.Tag != "!!null" {
+		return n.Value, nil
+	}
+	var v interface{}
+	if err := n.Decode(&v); err != nil {
+		return nil, fmt.Errorf("spec: %w", err)
+	}
+	return v, nil
+}
+
+// ReadSpec reads the spec in the file, see ParseSpec.
+//
+func ReadSpec(path string) (*PipelineSpec, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := ParseSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// Validate checks the spec before a run: some input, the annotators or a
+// preset, at least an exporter, and the known types and formats.
+//
+func (self *PipelineSpec) Validate() error {
+	switch {
+	case len(self.Input.Files) == 0 && self.Input.Lines == "" && len(self.Input.Texts) == 0:
+		return fmt.Errorf("spec: no input")
+	case len(self.Annotators) == 0 && self.Preset == "":
+		return fmt.Errorf("spec: no annotators nor preset")
+	case len(self.Exporters) == 0:
+		return fmt.Errorf("spec: no exporters")
+	}
+	if self.Preset != "" {
+		if _, err := client.PresetOf(self.Preset); err != nil {
+			return fmt.Errorf("spec: %w", err)
+		}
+	}
+	for i, p := range self.PostProcessors {
+		if p == nil {
+			return fmt.Errorf("spec: post-processor %d: empty", i)
+		}
+		switch p.Type {
+		case DropProcessor, PseudonymizeProcessor:
+		case RedactProcessor:
+			if _, ok := strategies[p.Strategy]; !ok {
+				return fmt.Errorf("spec: post-processor %d: unknown strategy %q", i, p.Strategy)
+			}
+		default:
+			return fmt.Errorf("spec: post-processor %d: unknown type %q", i, p.Type)
+		}
+	}
+	for i, e := range self.Exporters {
+		if e == nil {
+			return fmt.Errorf("spec: exporter %d: empty", i)
+		}
+		if _, ok := extensions[e.Format]; !ok {
+			return fmt.Errorf("spec: exporter %d: unknown format %q", i, e.Format)
+		}
+		switch e.Sink.Type {
+		case DirSink, FileSink:
+			if e.Sink.Path == "" {
+				return fmt.Errorf("spec: exporter %d: no path for the %s sink", i, e.Sink.Type)
+			}
+		case StdoutSink:
+		default:
+			return fmt.Errorf("spec: exporter %d: unknown sink %q", i, e.Sink.Type)
+		}
+	}
+	return nil
+}
+
+// client builds the client of the spec.
+//
+func (self *PipelineSpec) client() (client.Client, error) {
+	var preset *client.Preset
+	if self.Preset != "" {
+		var err error
+		if preset, err = client.PresetOf(self.Preset); err != nil {
+			return nil, err
+		}
+	}
+
+	if self.Server != "" {
+		c := client.NewHttpClient(nil, self.Server)
+		var opts []client.HttpOption
+		if preset != nil {
+			opts = append(opts, preset.HttpOption())
+		}
+		if len(self.Annotators) > 0 {
+			opts = append(opts, client.WithAnnotators(self.Annotators...))
+		}
+		opts = append(opts, client.WithProperties(self.Properties), client.WithLanguage(self.Language))
+		return c.With(opts...), nil
+	}
+
+	c := client.NewCmd(nil)
+	var opts []client.CmdOption
+	if preset != nil {
+		opts = append(opts, preset.CmdOption())
+	}
+	if len(self.Annotators) > 0 {
+		opts = append(opts, client.WithCmdAnnotators(self.Annotators...))
+	}
+	if self.ClassPath != "" {
+		opts = append(opts, client.WithClassPath(self.ClassPath))
+	}
+	if self.Java != "" {
+		opts = append(opts, client.WithJava(self.Java))
+	}
+	if self.Memory != "" {
+		opts = append(opts, client.WithMemory(self.Memory))
+	}
+	opts = append(opts, client.WithCmdProperties(self.Properties))
+	if self.Language != "" {
+		// loads StanfordCoreNLP-<language>.properties
+		opts = append(opts, client.WithCmdProperties(map[string]string{"props": self.Language}))
+	}
+	return c.With(opts...), nil
+}
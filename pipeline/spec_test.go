@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/client"
+)
+
+const yamlSpec = `
+# a nightly job
+name: news
+input:
+  files: ["news/*.txt"]
+  texts:
+    - Marie Curie won.
+    - 'It''s "quoted"'
+    - Don't stop # a comment
+server: http://localhost:9000   # the shared server
+annotators: [tokenize, ssplit, pos, lemma, ner]
+properties:
+  ner.applyFineGrained: false
+  pos.maxlen: 100
+  parse.threshold: 1.10
+workers: 2
+postProcessors:
+  - type: redact
+    strategy: hash
+    types:
+    - PERSON
+  - {type: drop, annotators: [pos]}
+exporters:
+  - format: conllu
+    sink: {type: dir, path: out}
+  - format: json
+    sink:
+      type: stdout
+`
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec([]byte(yamlSpec))
+	if err != nil { t.Fatal(err) }
+	want := &PipelineSpec{
+		Name:       "news",
+		Input:      InputSpec{Files: []string{"news/*.txt"}, Texts: []string{"Marie Curie won.", `It's "quoted"`, "Don't stop"}},
+		Server:     "http://localhost:9000",
+		Annotators: []string{"tokenize", "ssplit", "pos", "lemma", "ner"},
+		Properties: map[string]string{"ner.applyFineGrained": "false", "pos.maxlen": "100", "parse.threshold": "1.10"},
+		Workers:    2,
+		PostProcessors: []*ProcessorSpec{
+			{Type: RedactProcessor, Strategy: "hash", Types: []string{"PERSON"}},
+			{Type: DropProcessor, Annotators: []string{"pos"}},
+		},
+		Exporters: []*ExporterSpec{
+			{Format: CoNLLUExport, Sink: SinkSpec{Type: DirSink, Path: "out"}},
+			{Format: JSONExport, Sink: SinkSpec{Type: StdoutSink}},
+		},
+	}
+	if !reflect.DeepEqual(spec, want) {
+		t.Errorf("%#v", spec)
+	}
+
+	spec, err = ParseSpec([]byte(`{"input": {"texts": ["Hi"]}, "preset": "fast", "exporters": [{"format": "text", "sink": {"type": "stdout"}}]}`))
+	if err != nil || spec.Preset != "fast" {
+		t.Errorf("%v", err)
+	}
+
+	for bad, msg := range map[string]string{
+		`{"input": {"texts": ["Hi"]}, "annotator": ["tokenize"]}`:                                     "unknown field",
+		"input:\n  texts: [Hi]\nannotators: [tokenize]\n":                                             "no exporters",
+		"input:\n  texts: [Hi]\npreset: slow\nexporters:\n- {format: text, sink: {type: stdout}}\n":   "slow",
+		"input:\n  texts: [Hi]\nannotators: [a]\nexporters:\n- {format: xml, sink: {type: stdout}}\n": "unknown format",
+		"input:\n  texts: [Hi]\nannotators: [a]\nexporters:\n- {format: text, sink: {type: dir}}\n":   "no path",
+		"input:\n  texts: [Hi]\n   bad: 1\n":                                                          "did not find expected key",
+		"input:\n  texts: [Hi]\ninput: {}\n":                                                          "duplicate key",
+		"input: {texts: [Hi]}\nannotators: [a]\nexporters: [text]\n":                                  "cannot unmarshal",
+		"annotators: [a]\nexporters:\n- {format: text, sink: {type: stdout}}\n":                       "no input",
+	} {
+		if _, err := ParseSpec([]byte(bad)); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%q: %v", bad, err)
+		}
+	}
+	bad := "input: {texts: [Hi, Ho]}\nannotators: [a]\npostProcessors:\n- type: redact\n  strategy: blur\nexporters: [{format: text, sink: {type: stdout}}]\n"
+	if _, err := ParseSpec([]byte(bad)); err == nil || !strings.Contains(err.Error(), "unknown strategy") {
+		t.Errorf("%v", err)
+	}
+}
+
+func TestSpecClient(t *testing.T) {
+	spec := &PipelineSpec{Server: "http://nlp:9000", Preset: "fast", Annotators: []string{"tokenize", "ssplit"}, Language: "french", Properties: map[string]string{"pos.maxlen": "50"}}
+	c, err := spec.client()
+	if err != nil { t.Fatal(err) }
+	hc := c.(*client.HttpClient)
+	if hc.URL != "http://nlp:9000/" || strings.Join(hc.Annotators, ",") != "tokenize,ssplit" || hc.Language != "french" || hc.Properties["pos.maxlen"] != "50" || hc.Properties["ner.useSUTime"] != "false" {
+		t.Errorf("%#v", hc)
+	}
+
+	spec.Server = ""
+	spec.Memory = "4g"
+	if c, err = spec.client(); err != nil { t.Fatal(err) }
+	cmd := c.(*client.Cmd)
+	if cmd.Memory != "4g" || cmd.Properties["props"] != "french" || cmd.Properties["pos.maxlen"] != "50" || len(cmd.Annotators) != 2 {
+		t.Errorf("%#v", cmd)
+	}
+}
@@ -0,0 +1,329 @@
+// Package proxy serves the REST API of one CoreNLP server to several tenants
+// sharing it, e.g. teams of a company, each with its own key, allowed
+// annotators, rate limit and maximal text size, so that no tenant can run
+// what it was not given or starve the others.
+//
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/genelet/corenlp-golang/client"
+)
+
+// KeyHeader is the request header carrying the key of the tenant.
+//
+const KeyHeader = "X-API-Key"
+
+// Tenant is the configuration of a tenant, as read by ReadTenants.
+//
+type Tenant struct {
+	Name string `json:"name"`
+
+	// the secret the tenant sends in KeyHeader
+	Key string `json:"key"`
+
+	// the annotators the tenant may run; any if empty
+	Annotators []string `json:"annotators,omitempty"`
+
+	// requests per second allowed on average, and at once; unlimited if Rate is 0
+	Rate  float64 `json:"rate,omitempty"`
+	Burst int     `json:"burst,omitempty"`
+
+	// the largest text in bytes; unlimited if 0
+	MaxTextSize int64 `json:"maxTextSize,omitempty"`
+
+	// properties set on every request of the tenant, over those it sends,
+	// e.g. "parse.maxlen": "80"
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// ReadTenants reads the tenants from a JSON array, e.g.
+//
+// [{"name": "search", "key": "...", "annotators": ["tokenize", "ssplit", "pos"], "rate": 20, "burst": 5, "maxTextSize": 100000}]
+//
+// The names and the keys must be given and unique.
+//
+func ReadTenants(r io.Reader) ([]*Tenant, error) {
+	var tenants []*Tenant
+	if err := json.NewDecoder(r).Decode(&tenants); err != nil {
+		return nil, fmt.Errorf("tenants: %w", err)
+	}
+	names, keys := make(map[string]bool), make(map[string]bool)
+	for i, t := range tenants {
+		switch {
+		case t == nil || t.Name == "" || t.Key == "":
+			return nil, fmt.Errorf("tenant %d: no name or key", i)
+		case names[t.Name]:
+			return nil, fmt.Errorf("tenant %s: duplicate name", t.Name)
+		case keys[t.Key]:
+			return nil, fmt.Errorf("tenant %s: duplicate key", t.Name)
+		}
+		names[t.Name], keys[t.Key] = true, true
+	}
+	return tenants, nil
+}
+
+// Proxy is an http.Handler forwarding the requests of the tenants to a
+// CoreNLP server, once checked against their configuration. A request
+// without a known key gets 401, one with annotators, a property, a query
+// parameter or an endpoint not allowed 403, a text over the limit 413, and
+// one over the rate 429, without reaching the server. A tenant limited to
+// some annotators may only send their options that name no class, file or
+// limit, and the pipeline options, e.g. outputFormat; a serializer, a model
+// or parse.maxlen are for the Properties of its configuration. The tregex,
+// semgrex and tokensregex endpoints run annotators of their own, so only the
+// tenants allowed any annotator may use them. It is safe for concurrent use.
+//
+type Proxy struct {
+	// URL of the CoreNLP server, e.g. "http://127.0.0.1:9000"
+	URL string
+
+	// the transport to the server, http.DefaultTransport if nil
+	Transport http.RoundTripper
+
+	tenants map[string]*tenant
+}
+
+// endpoints are the query parameters the proxy forwards, by path.
+//
+var endpoints = map[string]map[string]bool{
+	"/":            {"properties": true, "pipelineLanguage": true, "resetDefault": true},
+	"/tregex":      {"properties": true, "pipelineLanguage": true, "resetDefault": true, "pattern": true, "filter": true},
+	"/semgrex":     {"properties": true, "pipelineLanguage": true, "resetDefault": true, "pattern": true, "filter": true},
+	"/tokensregex": {"properties": true, "pipelineLanguage": true, "resetDefault": true, "pattern": true, "filter": true},
+}
+
+// languages are the values of pipelineLanguage a restricted tenant may send.
+//
+var languages = map[string]bool{
+	"arabic": true, "chinese": true, "english": true, "french": true, "german": true, "hungarian": true, "italian": true, "spanish": true,
+	"ar": true, "zh": true, "en": true, "fr": true, "de": true, "hu": true, "it": true, "es": true,
+}
+
+type tenant struct {
+	*Tenant
+	allowed map[string]bool
+	limiter *client.RateLimiter
+}
+
+// New creates a proxy to the CoreNLP server at url for the tenants.
+//
+func New(url string, tenants []*Tenant) *Proxy {
+	p := &Proxy{URL: strings.TrimSuffix(url, "/"), tenants: make(map[string]*tenant, len(tenants))}
+	for _, t := range tenants {
+		state := &tenant{Tenant: t}
+		if len(t.Annotators) > 0 {
+			state.allowed = make(map[string]bool, len(t.Annotators))
+			for _, a := range t.Annotators {
+				state.allowed[a] = true
+			}
+		}
+		if t.Rate > 0 {
+			state.limiter = client.NewRateLimiter(t.Rate, t.Burst)
+		}
+		p.tenants[t.Key] = state
+	}
+	return p
+}
+
+func (self *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t := self.tenants[r.Header.Get(KeyHeader)]
+	if t == nil {
+		http.Error(w, "unknown key", http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	if err := t.endpoint(r.URL.Path, query); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	props, err := t.properties(query.Get("properties"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	body := io.Reader(r.Body)
+	if t.MaxTextSize > 0 {
+		body = io.LimitReader(r.Body, t.MaxTextSize+1)
+	}
+	text, err := ioutil.ReadAll(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if t.MaxTextSize > 0 && int64(len(text)) > t.MaxTextSize {
+		http.Error(w, fmt.Sprintf("text over %d bytes", t.MaxTextSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if t.limiter != nil {
+		// a deadline already passed makes Wait take a token only if one is free
+		ctx, cancel := context.WithDeadline(r.Context(), time.Now())
+		err := t.limiter.Wait(ctx)
+		cancel()
+		if err != nil {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+	}
+
+	query.Set("properties", props)
+	self.forward(w, r, query, text)
+}
+
+// endpoint checks the path and the query parameters of a request against
+// the tenant.
+//
+func (self *tenant) endpoint(path string, query url.Values) error {
+	params, ok := endpoints[path]
+	if !ok || (path != "/" && self.allowed != nil) {
+		return fmt.Errorf("endpoint %q not allowed", path)
+	}
+	for k := range query {
+		if !params[k] {
+			return fmt.Errorf("query parameter %q not allowed", k)
+		}
+	}
+	if lang := query.Get("pipelineLanguage"); lang != "" && self.allowed != nil && !languages[strings.ToLower(lang)] {
+		return fmt.Errorf("language %q not allowed", lang)
+	}
+	return nil
+}
+
+// properties checks the properties of a request against the tenant, and
+// returns them encoded with those of the tenant set.
+//
+func (self *tenant) properties(query string) (string, error) {
+	props := make(map[string]interface{})
+	if query != "" {
+		if err := json.Unmarshal([]byte(query), &props); err != nil {
+			return "", fmt.Errorf("bad properties: %v", err)
+		}
+	}
+	if self.allowed != nil {
+		annotators, _ := props["annotators"].(string)
+		if annotators == "" {
+			return "", errors.New("annotators required")
+		}
+		for _, a := range strings.Split(annotators, ",") {
+			if !self.allowed[strings.TrimSpace(a)] {
+				return "", fmt.Errorf("annotator %q not allowed", strings.TrimSpace(a))
+			}
+		}
+		for k, v := range props {
+			if !self.option(k, v) {
+				return "", fmt.Errorf("property %q not allowed", k)
+			}
+		}
+	}
+	for k, v := range self.Properties {
+		props[k] = v
+	}
+	encoded, err := json.Marshal(props)
+	return string(encoded), err
+}
+
+// option tells if a tenant with an allow-list may send the property k with
+// the value v: a key its Properties set, as their values win, a pipeline
+// option, or a documented option of an allowed annotator that names neither
+// a class, a file nor a limit, see client.KnownProperties.
+//
+func (self *tenant) option(k string, v interface{}) bool {
+	if _, ok := self.Properties[k]; ok {
+		return true
+	}
+	if values, ok := pipelineOptions[k]; ok {
+		s, _ := v.(string)
+		return values == nil || values[s]
+	}
+	if !client.KnownProperties[k] {
+		return false
+	}
+	for a := range self.allowed {
+		for _, prefix := range append(optionPrefixes[a], a+".") {
+			if !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			name := strings.ToLower(k[len(prefix):])
+			for _, word := range restrictedWords {
+				if strings.Contains(name, word) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// pipelineOptions are the properties, beside those of the annotators, that
+// a tenant with an allow-list may send, with the values allowed, any if nil;
+// the serialized input would be read by a class of the server.
+//
+var pipelineOptions = map[string]map[string]bool{
+	"annotators":         nil,
+	"outputFormat":       nil,
+	"output.prettyPrint": nil,
+	"output.columns":     nil,
+	"output.includeText": nil,
+	"inputFormat":        {"text": true},
+}
+
+// optionPrefixes are the prefixes of the options of the annotators beside
+// their names.
+//
+var optionPrefixes = map[string][]string{
+	"ner": {"sutime."},
+}
+
+// restrictedWords mark the options choosing a class, a model or rules read
+// from a path, or raising a limit, e.g. "ner.model" or "parse.maxlen".
+//
+var restrictedWords = []string{"class", "model", "rules", "mapping", "file", "path", "semgrex", "tokensregex", "flags", "max", "threads", "kbest"}
+
+// forward sends the request to the server, and copies the response back.
+//
+func (self *Proxy) forward(w http.ResponseWriter, r *http.Request, query url.Values, text []byte) {
+	u := self.URL + r.URL.Path + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, u, bytes.NewReader(text))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		req.Header.Set("Content-Type", ct)
+	}
+
+	transport := self.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.WriteHeader(res.StatusCode)
+	io.Copy(w, res.Body)
+}
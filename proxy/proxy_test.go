@@ -0,0 +1,179 @@
+package proxy
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const tenantsConfig = `[
+	{"name": "search", "key": "k1", "annotators": ["tokenize", "ssplit", "pos"], "rate": 1, "burst": 2, "maxTextSize": 20, "properties": {"pos.maxlen": "50"}},
+	{"name": "research", "key": "k2"}
+]`
+
+func TestProxy(t *testing.T) {
+	var received []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		text, _ := ioutil.ReadAll(r.Body)
+		received = append(received, string(text))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(r.URL.Query().Get("properties")))
+	}))
+	defer upstream.Close()
+
+	tenants, err := ReadTenants(strings.NewReader(tenantsConfig))
+	if err != nil { t.Fatal(err) }
+	p := New(upstream.URL, tenants)
+
+	post := func(key, annotators, text string) (int, string) {
+		u := "/?properties=" + url.QueryEscape(`{"annotators":"`+annotators+`","outputFormat":"json"}`)
+		req := httptest.NewRequest(http.MethodPost, u, strings.NewReader(text))
+		if key != "" {
+			req.Header.Set(KeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		p.ServeHTTP(w, req)
+		return w.Code, w.Body.String()
+	}
+
+	code, body := post("k1", "tokenize,ssplit,pos", "John runs.")
+	if code != http.StatusOK {
+		t.Fatalf("%d %s", code, body)
+	}
+	props := make(map[string]string)
+	if err := json.Unmarshal([]byte(body), &props); err != nil { t.Fatal(err) }
+	if props["pos.maxlen"] != "50" || props["outputFormat"] != "json" || props["annotators"] != "tokenize,ssplit,pos" {
+		t.Errorf("%v", props)
+	}
+
+	for _, c := range []struct {
+		key, annotators, text string
+		code                  int
+	}{
+		{"", "tokenize", "x", http.StatusUnauthorized},
+		{"k3", "tokenize", "x", http.StatusUnauthorized},
+		{"k1", "tokenize,ssplit,parse", "x", http.StatusForbidden},
+		{"k1", "", "x", http.StatusForbidden},
+		{"k1", "tokenize", strings.Repeat("x", 21), http.StatusRequestEntityTooLarge},
+		// the burst of 2 is spent by the first request and this one
+		{"k1", "tokenize", "x", http.StatusOK},
+		{"k1", "tokenize", "x", http.StatusTooManyRequests},
+		// the other tenant is neither limited nor restricted
+		{"k2", "tokenize,ssplit,parse,coref", strings.Repeat("x", 100), http.StatusOK},
+	} {
+		if code, body := post(c.key, c.annotators, c.text); code != c.code {
+			t.Errorf("%s %s: %d %s", c.key, c.annotators, code, body)
+		}
+	}
+	if len(received) != 3 {
+		t.Errorf("%d requests forwarded", len(received))
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	var paths []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+	}))
+	defer upstream.Close()
+
+	tenants, err := ReadTenants(strings.NewReader(tenantsConfig))
+	if err != nil { t.Fatal(err) }
+	p := New(upstream.URL, tenants)
+
+	properties := "properties=" + url.QueryEscape(`{"annotators":"tokenize"}`)
+	for _, c := range []struct {
+		key, target string
+		code        int
+	}{
+		{"k1", "/semgrex?" + properties + "&pattern=" + url.QueryEscape("{}=a"), http.StatusForbidden},
+		{"k1", "/tregex?" + properties + "&pattern=NP", http.StatusForbidden},
+		{"k1", "/tokensregex?" + properties + "&pattern=x", http.StatusForbidden},
+		{"k1", "/ready?" + properties, http.StatusForbidden},
+		{"k1", "/?" + properties + "&pipelineLanguage=../../etc", http.StatusForbidden},
+		{"k1", "/?" + properties + "&serializer=x", http.StatusForbidden},
+		{"k1", "/?" + properties + "&pipelineLanguage=german", http.StatusOK},
+		{"k2", "/semgrex?" + properties + "&pattern=x", http.StatusOK},
+		{"k2", "/ready?" + properties, http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodPost, c.target, strings.NewReader("x"))
+		req.Header.Set(KeyHeader, c.key)
+		w := httptest.NewRecorder()
+		p.ServeHTTP(w, req)
+		if w.Code != c.code {
+			t.Errorf("%s %s: %d %s", c.key, c.target, w.Code, w.Body.String())
+		}
+	}
+	if len(paths) != 2 || !strings.HasPrefix(paths[0], "/?") || !strings.HasPrefix(paths[1], "/semgrex?") {
+		t.Errorf("%q", paths)
+	}
+}
+
+func TestProperties(t *testing.T) {
+	var forwarded []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = append(forwarded, r.URL.Query().Get("properties"))
+	}))
+	defer upstream.Close()
+
+	tenants, err := ReadTenants(strings.NewReader(tenantsConfig))
+	if err != nil { t.Fatal(err) }
+	p := New(upstream.URL, tenants)
+	// the rate of the tenant is not what is tested
+	p.tenants["k1"].limiter = nil
+
+	for _, c := range []struct {
+		properties string
+		code       int
+	}{
+		{`{"annotators":"tokenize","serializer":"x"}`, http.StatusForbidden},
+		{`{"ner.model":"/etc/passwd"}`, http.StatusForbidden},
+		{`{"annotators":"tokenize,ssplit,pos","pos.model":"/etc/passwd"}`, http.StatusForbidden},
+		{`{"annotators":"tokenize","outputSerializer":"x"}`, http.StatusForbidden},
+		{`{"annotators":"tokenize","inputFormat":"serialized"}`, http.StatusForbidden},
+		{`{"annotators":"tokenize","tokenize.class":"x"}`, http.StatusForbidden},
+		{`{"annotators":"tokenize","parse.maxlen":"1000"}`, http.StatusForbidden},
+		{`{"annotators":"tokenize","regexner.mapping":"/etc/passwd"}`, http.StatusForbidden},
+		{`{"annotators":"tokenize","nobody.knows":"x"}`, http.StatusForbidden},
+		{`{"annotators":"tokenize,ssplit","outputFormat":"json","ssplit.eolonly":"true","tokenize.whitespace":"true"}`, http.StatusOK},
+		// the tenant sets pos.maxlen itself
+		{`{"annotators":"tokenize,ssplit,pos","pos.maxlen":"1000"}`, http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/?properties="+url.QueryEscape(c.properties), strings.NewReader("x"))
+		req.Header.Set(KeyHeader, "k1")
+		w := httptest.NewRecorder()
+		p.ServeHTTP(w, req)
+		if w.Code != c.code {
+			t.Errorf("%s: %d %s", c.properties, w.Code, w.Body.String())
+		}
+	}
+	if len(forwarded) != 2 || !strings.Contains(forwarded[1], `"pos.maxlen":"50"`) {
+		t.Errorf("%q", forwarded)
+	}
+
+	// the other tenant may send anything
+	req := httptest.NewRequest(http.MethodPost, "/?properties="+url.QueryEscape(`{"annotators":"ner","ner.model":"x"}`), strings.NewReader("x"))
+	req.Header.Set(KeyHeader, "k2")
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("%d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReadTenants(t *testing.T) {
+	for _, config := range []string{
+		`[{"name": "a"}]`,
+		`[{"name": "a", "key": "k"}, {"name": "a", "key": "l"}]`,
+		`[{"name": "a", "key": "k"}, {"name": "b", "key": "k"}]`,
+		`{"name": "a"}`,
+	} {
+		if _, err := ReadTenants(strings.NewReader(config)); err == nil {
+			t.Errorf("%s: no error", config)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package redact
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// possessive reports whether the pronoun is replaced by the representative
+// with "'s": a PRP$ such as "his", or an independent "hers" or "theirs".
+//
+func possessive(t *nlp.Token) bool {
+	word := strings.ToLower(t.GetWord())
+	return t.GetPos() == tags.PRPS || word == "hers" || word == "theirs"
+}
+
+// ResolveCoreferences returns the text of doc with the pronominal and nominal
+// mentions of every coreference chain replaced by the representative mention,
+// e.g. "Obama said he" becomes "Obama said Obama". Possessive pronouns get
+// "'s", and the case of the first letter follows the position in the sentence.
+// Chains whose representative is a pronoun, and mentions overlapping the
+// representative or an earlier replacement, are left as they are. The rest of
+// the text is kept byte-identical. It needs the "coref" annotation and
+// character offsets.
+//
+func ResolveCoreferences(doc *nlp.Document) string {
+	editor := NewDocEditor(doc)
+	for _, chain := range doc.GetCorefChain() {
+		r := int(chain.GetRepresentative())
+		if r >= len(chain.Mention) || chain.Mention[r].GetMentionType() == "PRONOMINAL" {
+			continue
+		}
+		rep := chain.Mention[r]
+		repTokens := mentionTokens(doc, rep)
+		if len(repTokens) == 0 {
+			continue
+		}
+		text := editor.Text(int(repTokens[0].GetBeginChar()), int(repTokens[len(repTokens)-1].GetEndChar()))
+		if text == "" {
+			continue
+		}
+		if rep.GetBeginIndex() == 0 && !tags.IsProperNoun(repTokens[0].GetPos()) {
+			text = withFirst(text, unicode.ToLower)
+		}
+
+		for i, m := range chain.Mention {
+			if i == r || m.GetMentionType() == "PROPER" || m.GetMentionType() == "LIST" {
+				continue
+			}
+			if m.GetSentenceIndex() == rep.GetSentenceIndex() && m.GetBeginIndex() < rep.GetEndIndex() && rep.GetBeginIndex() < m.GetEndIndex() {
+				continue
+			}
+			tokens := mentionTokens(doc, m)
+			if len(tokens) == 0 {
+				continue
+			}
+			replacement := text
+			if len(tokens) == 1 && possessive(tokens[0]) {
+				replacement += "'s"
+			}
+			if m.GetBeginIndex() == 0 {
+				replacement = withFirst(replacement, unicode.ToUpper)
+			}
+			// an overlap with an earlier replacement keeps the mention as is
+			editor.ReplaceTokens(tokens, replacement)
+		}
+	}
+	return editor.String()
+}
+
+// mentionTokens returns the tokens of the coref mention, nil if they are out
+// of range or have no character offsets.
+//
+func mentionTokens(doc *nlp.Document, m *nlp.CorefChain_CorefMention) []*nlp.Token {
+	s := int(m.GetSentenceIndex())
+	if s >= len(doc.GetSentence()) {
+		return nil
+	}
+	tokens := doc.Sentence[s].Token
+	begin, end := int(m.GetBeginIndex()), int(m.GetEndIndex())
+	if begin >= end || end > len(tokens) || tokens[begin].BeginChar == nil || tokens[end-1].EndChar == nil {
+		return nil
+	}
+	return tokens[begin:end]
+}
+
+// withFirst applies to to the first letter of s.
+//
+func withFirst(s string, to func(rune) rune) string {
+	r, n := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(to(r)) + s[n:]
+}
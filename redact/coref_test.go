@@ -0,0 +1,32 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestResolveCoreferences(t *testing.T) {
+	doc := makeDoc("The firm hired Lee . It paid his salary and Lee thanked it",
+		"O", "O", "O", "PERSON", "O", "O", "O", "O", "O", "O", "PERSON", "O", "O")
+	for i, pos := range []string{"DT", "NN", "VBD", "NNP", ".", "PRP", "VBD", "PRP$", "NN", "CC", "NNP", "VBD", "PRP"} {
+		doc.Sentence[0].Token[i].Pos = proto.String(pos)
+	}
+	mention := func(begin, end uint32, typ string) *nlp.CorefChain_CorefMention {
+		return &nlp.CorefChain_CorefMention{SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(begin), EndIndex: proto.Uint32(end), MentionType: proto.String(typ)}
+	}
+	doc.CorefChain = []*nlp.CorefChain{
+		{Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{mention(0, 2, "NOMINAL"), mention(5, 6, "PRONOMINAL"), mention(12, 13, "PRONOMINAL")}},
+		{Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{mention(3, 4, "PROPER"), mention(7, 8, "PRONOMINAL"), mention(10, 11, "PROPER")}},
+	}
+
+	if got := ResolveCoreferences(doc); got != "The firm hired Lee . the firm paid Lee's salary and Lee thanked the firm" {
+		t.Errorf("%q", got)
+	}
+
+	doc.CorefChain[0].Representative = proto.Uint32(1)
+	if got := ResolveCoreferences(doc); got != "The firm hired Lee . It paid Lee's salary and Lee thanked it" {
+		t.Errorf("%q", got)
+	}
+}
@@ -0,0 +1,114 @@
+// Package redact rewrites the original text of annotated documents,
+// e.g. to mask or pseudonymize named entities, while keeping the rest
+// of the text byte-identical.
+//
+package redact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Edit replaces the text between two character offsets.
+// Offsets count UTF-16 code units, the same as CoreNLP's BeginChar and EndChar.
+//
+type Edit struct {
+	Begin       int
+	End         int
+	Replacement string
+}
+
+// Editor collects span-based replacements on a text, and applies them
+// all at once so the offsets always refer to the original text.
+//
+type Editor struct {
+	text   string
+	offset []int // byte position of each character offset, plus the end
+	edits  []Edit
+}
+
+// NewEditor creates an Editor on text.
+//
+func NewEditor(text string) *Editor {
+	offset := make([]int, 0, len(text)+1)
+	for i, r := range text {
+		offset = append(offset, i)
+		if r >= 0x10000 && r != utf8.RuneError {
+			// a surrogate pair takes two units in Java
+			offset = append(offset, i)
+		}
+	}
+	offset = append(offset, len(text))
+	return &Editor{text: text, offset: offset}
+}
+
+// NewDocEditor creates an Editor on the text of doc.
+//
+func NewDocEditor(doc *nlp.Document) *Editor {
+	return NewEditor(doc.GetText())
+}
+
+// Replace replaces the text between the character offsets begin and end.
+// It fails if the span is out of range or overlaps a previous edit.
+//
+func (self *Editor) Replace(begin, end int, replacement string) error {
+	if begin < 0 || begin > end || end >= len(self.offset) {
+		return fmt.Errorf("span [%d, %d) out of range", begin, end)
+	}
+	for _, e := range self.edits {
+		if begin < e.End && e.Begin < end || begin == end && begin == e.Begin && e.Begin == e.End {
+			return fmt.Errorf("span [%d, %d) overlaps [%d, %d)", begin, end, e.Begin, e.End)
+		}
+	}
+	self.edits = append(self.edits, Edit{begin, end, replacement})
+	return nil
+}
+
+// ReplaceTokens replaces the text covered by the consecutive tokens, from the
+// first token's BeginChar to the last token's EndChar.
+//
+func (self *Editor) ReplaceTokens(tokens []*nlp.Token, replacement string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	first, last := tokens[0], tokens[len(tokens)-1]
+	if first.BeginChar == nil || last.EndChar == nil {
+		return fmt.Errorf("token %q has no character offsets", first.GetWord())
+	}
+	return self.Replace(int(first.GetBeginChar()), int(last.GetEndChar()), replacement)
+}
+
+// Text returns the text between the character offsets begin and end in the original text.
+//
+func (self *Editor) Text(begin, end int) string {
+	if begin < 0 || begin > end || end >= len(self.offset) {
+		return ""
+	}
+	return self.text[self.offset[begin]:self.offset[end]]
+}
+
+// Edits returns the edits made so far, ordered by position.
+//
+func (self *Editor) Edits() []Edit {
+	edits := append([]Edit{}, self.edits...)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Begin < edits[j].Begin })
+	return edits
+}
+
+// String returns the text with all the edits applied.
+//
+func (self *Editor) String() string {
+	var b strings.Builder
+	last := 0
+	for _, e := range self.Edits() {
+		b.WriteString(self.text[last:self.offset[e.Begin]])
+		b.WriteString(e.Replacement)
+		last = self.offset[e.End]
+	}
+	b.WriteString(self.text[last:])
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEditor(t *testing.T) {
+	// the emoji takes two characters in CoreNLP offsets
+	text := "Hi  😀 John,\n\tcall Mary Lee."
+	e := NewEditor(text)
+	if got := e.Text(7, 11); got != "John" {
+		t.Fatalf("%q", got)
+	}
+
+	mary := &nlp.Token{Word: proto.String("Mary"), BeginChar: proto.Uint32(19), EndChar: proto.Uint32(23)}
+	lee := &nlp.Token{Word: proto.String("Lee"), BeginChar: proto.Uint32(24), EndChar: proto.Uint32(27)}
+	if err := e.ReplaceTokens([]*nlp.Token{mary, lee}, "[PERSON]"); err != nil { t.Fatal(err) }
+	if err := e.Replace(7, 11, "[PERSON]"); err != nil { t.Fatal(err) }
+
+	if got := e.String(); got != "Hi  😀 [PERSON],\n\tcall [PERSON]." {
+		t.Errorf("%q", got)
+	}
+	if edits := e.Edits(); len(edits) != 2 || edits[0].Begin != 7 {
+		t.Errorf("%v", edits)
+	}
+
+	if err := e.Replace(8, 12, "x"); err == nil {
+		t.Errorf("overlap accepted")
+	}
+	if err := e.Replace(20, 100, "x"); err == nil {
+		t.Errorf("out of range accepted")
+	}
+}
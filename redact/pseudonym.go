@@ -0,0 +1,211 @@
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// DefaultNames are the fake names Pseudonymizer draws from, by NER type.
+// Types not listed get numbered pseudonyms such as "ORGANIZATION-3".
+//
+var DefaultNames = map[string][]string{
+	tags.Person: {"Alex Morgan", "Jordan Lee", "Taylor Reed", "Casey Brooks", "Riley Hayes",
+		"Morgan Blake", "Jamie Fox", "Avery Quinn", "Drew Parker", "Cameron Wells",
+		"Skyler Grant", "Rowan Ellis", "Parker Shaw", "Quinn Harper", "Reese Carter"},
+	tags.City:            {"Springfield", "Riverton", "Lakeside", "Fairview", "Brookfield", "Greenville", "Oakdale", "Milton"},
+	tags.StateOrProvince: {"Northland", "Westmark", "Eastvale", "Southmoor"},
+	tags.Country:         {"Freedonia", "Sylvania", "Genovia", "Arendelle"},
+	tags.Location:        {"Pine Valley", "Silver Lake", "Cedar Hills", "Maple Ridge"},
+}
+
+// Pseudonymizer replaces entities with fake names, consistently: the same entity
+// gets the same pseudonym within a document and across all the documents
+// given to the same Pseudonymizer. Mentions are grouped into entities by their
+// coref chain, and a shorter PERSON mention such as "Obama" joins the full
+// name "Barack Obama" seen earlier. Given the same seed and the same order of
+// documents, the pseudonyms are the same from run to run.
+//
+// A Pseudonymizer is safe for concurrent use.
+//
+type Pseudonymizer struct {
+	// NER types to pseudonymize, default to PII
+	Types []string
+
+	// seed choosing the pseudonyms
+	Seed string
+
+	// fake names by NER type, default to DefaultNames
+	Names map[string][]string
+
+	mu       sync.Mutex
+	assigned map[string]string          // type and canonical text to pseudonym
+	used     map[string]map[string]bool // type to pseudonyms in use
+	persons  []string                   // canonical PERSON names seen, in order
+}
+
+// NewPseudonymizer creates an instance of Pseudonymizer.
+//
+func NewPseudonymizer(seed string) *Pseudonymizer {
+	return &Pseudonymizer{Seed: seed}
+}
+
+// Pseudonymize replaces the entities of the configured types in the text of doc.
+// The document must have the "ner" annotation and character offsets; with
+// "coref" too, coreferent mentions share the pseudonym.
+//
+func (self *Pseudonymizer) Pseudonymize(doc *nlp.Document) (*Result, error) {
+	chains := corefRepresentatives(doc)
+	r := &Redactor{Types: self.Types}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return r.redact(doc, func(span *Span, m mention) string {
+		canonical := normalize(span.Text)
+		if rep, ok := chains[[3]int{m.sentence, m.begin, m.end}]; ok {
+			canonical = normalize(rep)
+		}
+		return self.pseudonym(span.Type, canonical)
+	})
+}
+
+// Mapping returns a copy of the pseudonyms assigned so far, keyed by
+// NER type and normalized entity text, e.g. "PERSON/barack obama".
+//
+func (self *Pseudonymizer) Mapping() map[string]string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	m := make(map[string]string, len(self.assigned))
+	for k, v := range self.assigned {
+		m[k] = v
+	}
+	return m
+}
+
+func (self *Pseudonymizer) pseudonym(typ, canonical string) string {
+	if self.assigned == nil {
+		self.assigned = make(map[string]string)
+		self.used = make(map[string]map[string]bool)
+	}
+
+	if typ == tags.Person {
+		canonical = self.fullName(canonical)
+	}
+	key := typ + "/" + canonical
+	if p, ok := self.assigned[key]; ok {
+		return p
+	}
+
+	if self.used[typ] == nil {
+		self.used[typ] = make(map[string]bool)
+	}
+	names := self.Names
+	if names == nil {
+		names = DefaultNames
+	}
+
+	p := ""
+	list := names[typ]
+	if n := len(list); n > 0 {
+		sum := sha256.Sum256([]byte(self.Seed + "\x00" + key))
+		start := int(binary.BigEndian.Uint32(sum[:4]) % uint32(n))
+		for i := 0; i < n; i++ {
+			if c := list[(start+i)%n]; !self.used[typ][c] {
+				p = c
+				break
+			}
+		}
+	}
+	for i := len(self.used[typ]) + 1; p == ""; i++ {
+		if c := typ + "-" + strconv.Itoa(i); !self.used[typ][c] {
+			p = c
+		}
+	}
+
+	self.used[typ][p] = true
+	self.assigned[key] = p
+	return p
+}
+
+// fullName returns the earlier full name containing all the words of name,
+// e.g. "barack obama" for "obama", or name itself which is then remembered.
+//
+func (self *Pseudonymizer) fullName(name string) string {
+	words := strings.Fields(name)
+	for _, full := range self.persons {
+		if full == name {
+			return full
+		}
+		if containsAll(strings.Fields(full), words) {
+			return full
+		}
+	}
+	self.persons = append(self.persons, name)
+	return name
+}
+
+func containsAll(full, words []string) bool {
+	if len(words) >= len(full) {
+		return false
+	}
+	for _, w := range words {
+		found := false
+		for _, f := range full {
+			if f == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// corefRepresentatives maps the sentence and token span of every non-pronominal
+// coref mention to the text of its chain's representative mention. The whole
+// span is the key, for nested mentions, e.g. "Obama" in "Obama's mother",
+// start at the same token.
+//
+func corefRepresentatives(doc *nlp.Document) map[[3]int]string {
+	reps := make(map[[3]int]string)
+	for _, chain := range doc.GetCorefChain() {
+		r := int(chain.GetRepresentative())
+		if r >= len(chain.Mention) {
+			continue
+		}
+		text := mentionWords(doc, chain.Mention[r])
+		if text == "" {
+			continue
+		}
+		for _, m := range chain.Mention {
+			if m.GetMentionType() != "PRONOMINAL" {
+				reps[[3]int{int(m.GetSentenceIndex()), int(m.GetBeginIndex()), int(m.GetEndIndex())}] = text
+			}
+		}
+	}
+	return reps
+}
+
+func mentionWords(doc *nlp.Document, m *nlp.CorefChain_CorefMention) string {
+	s := int(m.GetSentenceIndex())
+	if s >= len(doc.GetSentence()) {
+		return ""
+	}
+	tokens := doc.Sentence[s].Token
+	var words []string
+	for i := int(m.GetBeginIndex()); i < int(m.GetEndIndex()) && i < len(tokens); i++ {
+		words = append(words, tokens[i].GetWord())
+	}
+	return strings.Join(words, " ")
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,76 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPseudonymize(t *testing.T) {
+	p := NewPseudonymizer("seed")
+	doc := makeDoc("Barack Obama met Bob . Obama called the president",
+		"PERSON", "PERSON", "O", "PERSON", "O", "PERSON", "O", "O", "O")
+	r, err := p.Pseudonymize(doc)
+	if err != nil { t.Fatal(err) }
+	if len(r.Spans) != 3 || r.Spans[0].Replacement != r.Spans[2].Replacement || r.Spans[0].Replacement == r.Spans[1].Replacement {
+		t.Fatalf("%q", r.Text)
+	}
+	if strings.Contains(r.Text, "Obama") || strings.Contains(r.Text, "Bob") {
+		t.Errorf("%q", r.Text)
+	}
+
+	// the same entity in another document gets the same pseudonym
+	other, _ := p.Pseudonymize(makeDoc("bob left", "PERSON", "O"))
+	if other.Spans[0].Replacement != r.Spans[1].Replacement {
+		t.Errorf("%q %q", other.Text, r.Text)
+	}
+
+	// the same seed gives the same pseudonyms
+	again, _ := NewPseudonymizer("seed").Pseudonymize(doc)
+	if again.Text != r.Text {
+		t.Errorf("%q %q", again.Text, r.Text)
+	}
+	if m := p.Mapping(); m["PERSON/barack obama"] != r.Spans[0].Replacement {
+		t.Errorf("%v", m)
+	}
+}
+
+func TestPseudonymizeCoref(t *testing.T) {
+	doc := makeDoc("Acme Corp hired Lee . The firm grew",
+		"ORGANIZATION", "ORGANIZATION", "O", "PERSON", "O", "ORGANIZATION", "ORGANIZATION", "O")
+	doc.CorefChain = []*nlp.CorefChain{{Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+		{SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(0), EndIndex: proto.Uint32(2), MentionType: proto.String("PROPER")},
+		{SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(5), EndIndex: proto.Uint32(7), MentionType: proto.String("NOMINAL")},
+	}}}
+
+	p := &Pseudonymizer{Types: []string{"ORGANIZATION"}}
+	r, err := p.Pseudonymize(doc)
+	if err != nil { t.Fatal(err) }
+	if r.Text != "ORGANIZATION-1 hired Lee . ORGANIZATION-1 grew" {
+		t.Errorf("%q", r.Text)
+	}
+}
+
+func TestPseudonymizeNestedCoref(t *testing.T) {
+	doc := makeDoc("Barack Obama spoke . Obama 's mother smiled",
+		"PERSON", "PERSON", "O", "O", "PERSON", "O", "O", "O")
+	doc.CorefChain = []*nlp.CorefChain{
+		{Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+			{SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(0), EndIndex: proto.Uint32(2), MentionType: proto.String("PROPER")},
+			{SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(4), EndIndex: proto.Uint32(5), MentionType: proto.String("PROPER")},
+		}},
+		// "Obama 's mother" starts at the token of "Obama"
+		{Representative: proto.Uint32(0), Mention: []*nlp.CorefChain_CorefMention{
+			{SentenceIndex: proto.Uint32(0), BeginIndex: proto.Uint32(4), EndIndex: proto.Uint32(7), MentionType: proto.String("NOMINAL")},
+		}},
+	}
+
+	p := &Pseudonymizer{Types: []string{"PERSON"}}
+	r, err := p.Pseudonymize(doc)
+	if err != nil { t.Fatal(err) }
+	if len(r.Spans) != 2 || r.Spans[0].Replacement != r.Spans[1].Replacement {
+		t.Errorf("%q", r.Text)
+	}
+}
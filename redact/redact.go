@@ -0,0 +1,147 @@
+package redact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// PII lists the NER types usually treated as personally identifiable information.
+//
+var PII = []string{tags.Person, tags.Email, tags.URL, tags.Handle, tags.Location, tags.City, tags.StateOrProvince}
+
+// Strategy decides what replaces a redacted entity.
+//
+type Strategy int
+
+const (
+	// Placeholder replaces the entity with its type, e.g. "[PERSON]".
+	Placeholder Strategy = iota
+	// Mask replaces every character of the entity with '*'.
+	Mask
+	// Hash replaces the entity with a short hash of its type and text,
+	// so equal entities get equal replacements, e.g. "[PERSON:1f3a9c0e]".
+	Hash
+)
+
+// Span records one redacted entity, for auditing.
+//
+type Span struct {
+	// index of the sentence
+	Sentence int
+
+	// character offsets in the original text
+	Begin int
+	End   int
+
+	Type        string
+	Text        string
+	Replacement string
+}
+
+// Result is the redacted text with the audit log of redacted spans.
+//
+type Result struct {
+	Text  string
+	Spans []*Span
+}
+
+// Redactor redacts named entities from the text of documents.
+//
+type Redactor struct {
+	// NER types to redact, default to PII
+	Types []string
+
+	Strategy Strategy
+
+	// secret mixed into the Hash strategy, so hashes cannot be reversed by guessing
+	Salt string
+}
+
+// Redact redacts the entities of the given types from the text of doc.
+// The document must have the "ner" annotation and character offsets.
+//
+func Redact(doc *nlp.Document, types []string, strategy Strategy) (*Result, error) {
+	return (&Redactor{Types: types, Strategy: strategy}).Redact(doc)
+}
+
+// Redact redacts the entities of the configured types from the text of doc.
+//
+func (self *Redactor) Redact(doc *nlp.Document) (*Result, error) {
+	return self.redact(doc, func(span *Span, m mention) string { return self.replace(span) })
+}
+
+func (self *Redactor) redact(doc *nlp.Document, replace func(*Span, mention) string) (*Result, error) {
+	types := self.Types
+	if types == nil {
+		types = PII
+	}
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		wanted[t] = true
+	}
+
+	editor := NewDocEditor(doc)
+	result := &Result{}
+	for _, m := range mentions(doc) {
+		if !wanted[m.ner] {
+			continue
+		}
+		tokens := doc.Sentence[m.sentence].Token[m.begin:m.end]
+		begin, end := int(tokens[0].GetBeginChar()), int(tokens[len(tokens)-1].GetEndChar())
+		span := &Span{Sentence: m.sentence, Begin: begin, End: end, Type: m.ner, Text: editor.Text(begin, end)}
+		span.Replacement = replace(span, m)
+		if err := editor.ReplaceTokens(tokens, span.Replacement); err != nil {
+			return nil, err
+		}
+		result.Spans = append(result.Spans, span)
+	}
+	result.Text = editor.String()
+	return result, nil
+}
+
+func (self *Redactor) replace(span *Span) string {
+	switch self.Strategy {
+	case Mask:
+		return strings.Repeat("*", utf8.RuneCountInString(span.Text))
+	case Hash:
+		sum := sha256.Sum256([]byte(self.Salt + "\x00" + span.Type + "\x00" + span.Text))
+		return "[" + span.Type + ":" + hex.EncodeToString(sum[:4]) + "]"
+	default:
+		return "[" + span.Type + "]"
+	}
+}
+
+type mention struct {
+	sentence int
+	begin    int
+	end      int
+	ner      string
+}
+
+// mentions returns the entity mentions of doc, from the "entitymentions"
+// annotation if present, otherwise from the runs of tags.EntityRuns.
+//
+func mentions(doc *nlp.Document) []mention {
+	var found []mention
+	for i, sentence := range doc.GetSentence() {
+		n := len(sentence.Token)
+		if len(sentence.Mentions) > 0 {
+			for _, m := range sentence.Mentions {
+				begin, end := int(m.GetTokenStartInSentenceInclusive()), int(m.GetTokenEndInSentenceExclusive())
+				if begin < end && end <= n {
+					found = append(found, mention{i, begin, end, m.GetNer()})
+				}
+			}
+			continue
+		}
+		for _, run := range tags.EntityRuns(sentence.Token) {
+			found = append(found, mention{i, run.Begin, run.End, run.NER})
+		}
+	}
+	return found
+}
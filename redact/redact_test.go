@@ -0,0 +1,59 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// makeDoc builds a document from the text, splitting tokens on single spaces;
+// ners gives the NER type of each token.
+//
+func makeDoc(text string, ners ...string) *nlp.Document {
+	s := &nlp.Sentence{}
+	offset := 0
+	for i, w := range strings.Split(text, " ") {
+		s.Token = append(s.Token, &nlp.Token{Word: proto.String(w), Ner: proto.String(ners[i]), BeginChar: proto.Uint32(uint32(offset)), EndChar: proto.Uint32(uint32(offset + len(w)))})
+		offset += len(w) + 1
+	}
+	return &nlp.Document{Text: proto.String(text), Sentence: []*nlp.Sentence{s}}
+}
+
+func TestRedact(t *testing.T) {
+	doc := makeDoc("Mary Lee met Bob in Paris on Monday",
+		"PERSON", "PERSON", "O", "PERSON", "O", "CITY", "O", "DATE")
+
+	r, err := Redact(doc, nil, Placeholder)
+	if err != nil { t.Fatal(err) }
+	if r.Text != "[PERSON] met [PERSON] in [CITY] on Monday" {
+		t.Errorf("%q", r.Text)
+	}
+	if len(r.Spans) != 3 || r.Spans[0].Text != "Mary Lee" || r.Spans[0].Begin != 0 || r.Spans[0].End != 8 || r.Spans[2].Type != "CITY" {
+		t.Errorf("%#v", r.Spans[0])
+	}
+
+	r, _ = Redact(doc, []string{"DATE"}, Mask)
+	if r.Text != "Mary Lee met Bob in Paris on ******" {
+		t.Errorf("%q", r.Text)
+	}
+
+	a, _ := (&Redactor{Types: []string{"PERSON"}, Strategy: Hash, Salt: "s"}).Redact(doc)
+	b, _ := (&Redactor{Types: []string{"PERSON"}, Strategy: Hash, Salt: "t"}).Redact(doc)
+	if a.Spans[0].Replacement == a.Spans[1].Replacement || a.Spans[0].Replacement == b.Spans[0].Replacement || !strings.HasPrefix(a.Text, "[PERSON:") {
+		t.Errorf("%q %q", a.Text, b.Text)
+	}
+}
+
+func TestRedactMentions(t *testing.T) {
+	doc := makeDoc("Mary Lee met Bob", "PERSON", "PERSON", "O", "PERSON")
+	doc.Sentence[0].Mentions = []*nlp.NERMention{
+		{Ner: proto.String("PERSON"), TokenStartInSentenceInclusive: proto.Uint32(0), TokenEndInSentenceExclusive: proto.Uint32(1)},
+	}
+	r, err := Redact(doc, nil, Placeholder)
+	if err != nil { t.Fatal(err) }
+	if r.Text != "[PERSON] Lee met Bob" {
+		t.Errorf("%q", r.Text)
+	}
+}
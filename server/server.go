@@ -0,0 +1,297 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Manager launches and supervises a local Stanford CoreNLP server.
+// The original Java-based CoreNLP package must be downloaded
+// and installed properly.
+//
+// see
+// https://stanfordnlp.github.io/CoreNLP/corenlp-server.html
+//
+type Manager struct {
+	// Classpath to the Java.
+	ClassPath string
+
+	// Class to run as the server
+	Class string
+
+	// port to listen on. 0 picks a free port when started
+	Port int
+
+	// server-side timeout in milliseconds, passed as -timeout
+	Timeout int
+
+	// interval between readiness checks
+	PollInterval time.Duration
+
+	// annotators to load at start, passed as -preload
+	Preload []string
+
+	javaCmd string
+
+	// extra arguments for the Java command
+	Args []string
+
+	// when set, receives a copy of the standard error of the server as it is
+	// written, e.g. the model loading
+	Log io.Writer
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	url    string
+	done   chan struct{}
+	err    error
+	stderr *tailWriter
+}
+
+// NewManager creates an instance of Manager.
+//
+// port: the port the server listens on, 0 for any free port;
+//
+// args[0], optional: the Java Classpath;
+//
+// args[1], optional: the Java class;
+//
+// args[2], optional: the Java command;
+//
+// args[3:], optional: other arguments.
+//
+// For example, if the CoreNLP is downloaded and unzipped to /home/user/standford,
+// you can create instance:
+// NewManager(9000, "/home/user/standford/*")
+//
+func NewManager(port int, args ...string) *Manager {
+	cp := "*"
+	c := "edu.stanford.nlp.pipeline.StanfordCoreNLPServer"
+	java := "java"
+	if len(args) > 0 {
+		cp = args[0]
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		c = args[0]
+		args = args[1:]
+	}
+	if len(args) > 0 {
+		java = args[0]
+		args = args[1:]
+	}
+
+	return &Manager{ClassPath: cp, Class: c, Port: port, Timeout: 15000, PollInterval: 500 * time.Millisecond, javaCmd: java, Args: args}
+}
+
+// Start launches the server and blocks until it reports ready,
+// the process exits, or ctx is done.
+//
+func (self *Manager) Start(ctx context.Context) error {
+	self.mu.Lock()
+	if self.cmd != nil {
+		self.mu.Unlock()
+		return errors.New("server already started")
+	}
+
+	port := self.Port
+	if port == 0 {
+		p, err := freePort()
+		if err != nil {
+			self.mu.Unlock()
+			return err
+		}
+		port = p
+	}
+
+	args := append([]string{}, self.Args...)
+	if self.ClassPath != "" {
+		args = append(args, "-cp", self.ClassPath)
+	}
+	args = append(args, self.Class, "-port", strconv.Itoa(port))
+	if self.Timeout > 0 {
+		args = append(args, "-timeout", strconv.Itoa(self.Timeout))
+	}
+	if len(self.Preload) > 0 {
+		args = append(args, "-preload", strings.Join(self.Preload, ","))
+	}
+
+	// the process must outlive ctx, which only bounds the start-up
+	cmd := exec.Command(self.javaCmd, args...)
+	self.stderr = &tailWriter{mu: &self.mu}
+	cmd.Stderr = self.stderr
+	if self.Log != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, self.Log)
+	}
+	if err := cmd.Start(); err != nil {
+		self.mu.Unlock()
+		return err
+	}
+
+	self.cmd = cmd
+	self.url = fmt.Sprintf("http://127.0.0.1:%d/", port)
+	self.done = make(chan struct{})
+	go func() {
+		err := cmd.Wait()
+		self.mu.Lock()
+		self.err = err
+		self.mu.Unlock()
+		close(self.done)
+	}()
+	url, done, interval := self.url, self.done, self.PollInterval
+	self.mu.Unlock()
+
+	if err := waitReady(ctx, url, done, interval); err != nil {
+		self.Close()
+		if errors.Is(err, errExited) {
+			return fmt.Errorf("%s: %s", err.Error(), self.Stderr())
+		}
+		return err
+	}
+	return nil
+}
+
+// URL returns the address of the running server, e.g. http://127.0.0.1:9000/,
+// or an empty string if the server has not been started.
+//
+func (self *Manager) URL() string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.url
+}
+
+// Done returns a channel which is closed when the server process exits.
+//
+func (self *Manager) Done() <-chan struct{} {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.done
+}
+
+// Stderr returns the end of what the server has written to the standard
+// error so far, its last lines up to 4 KB; Log receives all of it.
+//
+func (self *Manager) Stderr() string {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.stderr == nil {
+		return ""
+	}
+	tail := self.stderr.tail
+	if len(tail) > stderrTail {
+		tail = tail[len(tail)-stderrTail:]
+		if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+			tail = tail[i+1:]
+		}
+	}
+	return string(tail)
+}
+
+// Close stops the server and waits for the process to exit.
+// It is safe to call Close more than once.
+//
+func (self *Manager) Close() error {
+	self.mu.Lock()
+	cmd, done := self.cmd, self.done
+	self.mu.Unlock()
+	if cmd == nil {
+		return nil
+	}
+
+	select {
+	case <-done:
+	default:
+		if err := cmd.Process.Kill(); err != nil {
+			return err
+		}
+		<-done
+	}
+
+	self.mu.Lock()
+	self.cmd = nil
+	self.url = ""
+	self.mu.Unlock()
+	return nil
+}
+
+var errExited = errors.New("server exited before ready")
+
+// WaitReady polls the /ready endpoint of the server at url, e.g. http://127.0.0.1:9000/,
+// until it returns 200 or ctx is done.
+//
+func WaitReady(ctx context.Context, url string) error {
+	return waitReady(ctx, url, nil, 0)
+}
+
+// waitReady polls the server's /ready endpoint until it returns 200.
+//
+func waitReady(ctx context.Context, url string, done <-chan struct{}, interval time.Duration) error {
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", url+"ready", nil)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			return errExited
+		case <-ticker.C:
+		}
+	}
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// stderrTail is the size of the end of the standard error kept by Manager.
+//
+const stderrTail = 4096
+
+// tailWriter keeps the end of what is written to it, under mu, at most
+// twice stderrTail between the writes.
+//
+type tailWriter struct {
+	mu   *sync.Mutex
+	tail []byte
+}
+
+func (self *tailWriter) Write(p []byte) (int, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.tail = append(self.tail, p...)
+	if n := len(self.tail); n > 2*stderrTail {
+		self.tail = append(self.tail[:0], self.tail[n-stderrTail:]...)
+	}
+	return len(p), nil
+}
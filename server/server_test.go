@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitReady(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/ready" || calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	err := waitReady(context.Background(), ts.URL+"/", nil, 10*time.Millisecond)
+	if err != nil { t.Fatal(err) }
+	if calls != 3 {
+		t.Errorf("%d", calls)
+	}
+}
+
+func TestManagerExit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "server")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	java := filepath.Join(dir, "java")
+	if err = ioutil.WriteFile(java, []byte("#!/bin/sh\necho no models >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(0, "", "", java)
+	m.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = m.Start(ctx)
+	if err == nil || !strings.Contains(err.Error(), "no models") {
+		t.Errorf("%v", err)
+	}
+	if m.URL() != "" {
+		t.Errorf("%s", m.URL())
+	}
+	if err = m.Close(); err != nil { t.Fatal(err) }
+}
+
+func TestManagerStderr(t *testing.T) {
+	dir, err := ioutil.TempDir("", "server")
+	if err != nil { t.Fatal(err) }
+	defer os.RemoveAll(dir)
+
+	// some 20 KB of log before the failure
+	java := filepath.Join(dir, "java")
+	script := "#!/bin/sh\ni=0\nwhile [ $i -lt 1000 ]; do echo loading model $i >&2; i=$((i+1)); done\necho no models >&2\nexit 1\n"
+	if err = ioutil.WriteFile(java, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(0, "", "", java)
+	m.PollInterval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err = m.Start(ctx); err == nil {
+		t.Fatal("the server should not start")
+	}
+	stderr := m.Stderr()
+	if len(stderr) > stderrTail || !strings.HasPrefix(stderr, "loading model") || !strings.HasSuffix(stderr, "loading model 999\nno models\n") {
+		t.Errorf("%d %q", len(stderr), stderr)
+	}
+	if len(m.stderr.tail) > 2*stderrTail {
+		t.Errorf("%d bytes kept", len(m.stderr.tail))
+	}
+}
@@ -0,0 +1,218 @@
+// Package stats computes statistics over annotated documents and corpora.
+//
+package stats
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/genelet/corenlp-golang/extract"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Point is the vocabulary size after a number of tokens, for plotting
+// vocabulary growth.
+//
+type Point struct {
+	Tokens     int
+	Vocabulary int
+}
+
+// Snapshot is the state of the corpus statistics at a given time.
+//
+type Snapshot struct {
+	Time      time.Time
+	Documents int
+	Sentences int
+	Tokens    int
+
+	// number of distinct lower-cased words, or lemmas
+	Vocabulary int
+
+	// vocabulary size after every so many documents, evenly spread over the
+	// corpus, see Aggregator.MaxGrowth
+	Growth []Point
+
+	// number of entity mentions by NER type
+	EntityTypes map[string]int
+
+	// sentence length in tokens to the number of sentences of that length
+	SentenceLengths map[int]int
+}
+
+// MeanSentenceLength returns the average number of tokens per sentence.
+//
+func (self *Snapshot) MeanSentenceLength() float64 {
+	if self.Sentences == 0 {
+		return 0
+	}
+	return float64(self.Tokens) / float64(self.Sentences)
+}
+
+// SentenceLengthPercentile returns the sentence length below which p percent of the sentences fall.
+//
+func (self *Snapshot) SentenceLengthPercentile(p float64) int {
+	lengths := make([]int, 0, len(self.SentenceLengths))
+	for n := range self.SentenceLengths {
+		lengths = append(lengths, n)
+	}
+	sort.Ints(lengths)
+
+	target := p / 100 * float64(self.Sentences)
+	seen := 0
+	for _, n := range lengths {
+		seen += self.SentenceLengths[n]
+		if float64(seen) >= target {
+			return n
+		}
+	}
+	return 0
+}
+
+// Aggregator maintains running statistics over a stream of documents.
+// It is safe for concurrent use.
+//
+type Aggregator struct {
+	// count lemmas instead of lower-cased words for the vocabulary
+	UseLemmas bool
+
+	// when positive, OnSnapshot is called after every Every documents
+	Every      int
+	OnSnapshot func(*Snapshot)
+
+	// the most points kept in Snapshot.Growth, DefaultMaxGrowth if 0; when
+	// there are more, every other point is dropped and the points are taken
+	// twice as far apart
+	MaxGrowth int
+
+	mu         sync.Mutex
+	snapshot   Snapshot
+	vocabulary map[string]bool
+
+	// the number of documents between two points of Growth
+	stride int
+}
+
+// DefaultMaxGrowth is the default of Aggregator.MaxGrowth.
+//
+const DefaultMaxGrowth = 1000
+
+// NewAggregator creates an instance of Aggregator.
+//
+func NewAggregator() *Aggregator {
+	return &Aggregator{}
+}
+
+// Add adds the document to the statistics.
+//
+func (self *Aggregator) Add(doc *nlp.Document) {
+	entities := extract.ExtractNamedEntities(doc)
+
+	self.mu.Lock()
+	s := &self.snapshot
+	if self.vocabulary == nil {
+		self.vocabulary = make(map[string]bool)
+		s.EntityTypes = make(map[string]int)
+		s.SentenceLengths = make(map[int]int)
+	}
+
+	s.Documents++
+	for _, sentence := range doc.GetSentence() {
+		s.Sentences++
+		s.Tokens += len(sentence.Token)
+		s.SentenceLengths[len(sentence.Token)]++
+		for _, token := range sentence.Token {
+			w := token.GetWord()
+			if self.UseLemmas {
+				w = extract.LemmaOf(token)
+			}
+			self.vocabulary[strings.ToLower(w)] = true
+		}
+	}
+	for typ, names := range entities {
+		s.EntityTypes[typ] += len(names)
+	}
+	s.Vocabulary = len(self.vocabulary)
+	self.grow(Point{s.Tokens, s.Vocabulary})
+
+	var snap *Snapshot
+	if self.Every > 0 && self.OnSnapshot != nil && s.Documents%self.Every == 0 {
+		snap = self.copy()
+	}
+	self.mu.Unlock()
+
+	if snap != nil {
+		self.OnSnapshot(snap)
+	}
+}
+
+// grow adds the point to Growth if the document count is on the stride, and
+// thins Growth when it exceeds MaxGrowth.
+//
+func (self *Aggregator) grow(p Point) {
+	if self.stride == 0 {
+		self.stride = 1
+	}
+	s := &self.snapshot
+	if s.Documents%self.stride != 0 {
+		return
+	}
+	s.Growth = append(s.Growth, p)
+	limit := self.MaxGrowth
+	if limit <= 0 {
+		limit = DefaultMaxGrowth
+	}
+	if len(s.Growth) <= limit {
+		return
+	}
+	// keep the points after 2, 4, 6... strides
+	kept := s.Growth[:0]
+	for i := 1; i < len(s.Growth); i += 2 {
+		kept = append(kept, s.Growth[i])
+	}
+	s.Growth = kept
+	self.stride *= 2
+}
+
+// Consume adds the documents received from docs until the channel is closed
+// or ctx is done.
+//
+func (self *Aggregator) Consume(ctx context.Context, docs <-chan *nlp.Document) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case doc, ok := <-docs:
+			if !ok {
+				return nil
+			}
+			self.Add(doc)
+		}
+	}
+}
+
+// Snapshot returns a copy of the current statistics.
+//
+func (self *Aggregator) Snapshot() *Snapshot {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.copy()
+}
+
+func (self *Aggregator) copy() *Snapshot {
+	s := self.snapshot
+	s.Time = time.Now()
+	s.Growth = append([]Point{}, s.Growth...)
+	s.EntityTypes = make(map[string]int, len(self.snapshot.EntityTypes))
+	for k, v := range self.snapshot.EntityTypes {
+		s.EntityTypes[k] = v
+	}
+	s.SentenceLengths = make(map[int]int, len(self.snapshot.SentenceLengths))
+	for k, v := range self.snapshot.SentenceLengths {
+		s.SentenceLengths[k] = v
+	}
+	return &s
+}
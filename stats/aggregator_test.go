@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+func TestAggregator(t *testing.T) {
+	a := NewAggregator()
+	a.Every = 2
+	var snaps []*Snapshot
+	a.OnSnapshot = func(s *Snapshot) { snaps = append(snaps, s) }
+
+	docs := make(chan *nlp.Document, 3)
+	docs <- testdoc.Tagged("Mary|NNP|Mary|PERSON runs|VBZ|run .|.|.|O", "She|PRP|she ran|VBD|run")
+	docs <- testdoc.Tagged("Mary|NNP|Mary|PERSON left|VBD|leave Paris|NNP|Paris|CITY")
+	docs <- testdoc.Tagged("Runs|NNS|run")
+	close(docs)
+	if err := a.Consume(context.Background(), docs); err != nil { t.Fatal(err) }
+
+	s := a.Snapshot()
+	if s.Documents != 3 || s.Sentences != 4 || s.Tokens != 9 || s.Vocabulary != 7 {
+		t.Errorf("%#v", s)
+	}
+	if s.EntityTypes["PERSON"] != 2 || s.EntityTypes["CITY"] != 1 {
+		t.Errorf("%v", s.EntityTypes)
+	}
+	if len(s.Growth) != 3 || s.Growth[1] != (Point{8, 7}) {
+		t.Errorf("%v", s.Growth)
+	}
+	if s.SentenceLengths[3] != 2 || s.MeanSentenceLength() != 2.25 || s.SentenceLengthPercentile(50) != 2 || s.SentenceLengthPercentile(100) != 3 {
+		t.Errorf("%v", s.SentenceLengths)
+	}
+	if len(snaps) != 1 || snaps[0].Documents != 2 {
+		t.Errorf("%v", snaps)
+	}
+
+	lemmas := &Aggregator{UseLemmas: true}
+	lemmas.Add(testdoc.Tagged("Mary|NNP|Mary|PERSON runs|VBZ|run .|.|.|O", "She|PRP|she ran|VBD|run"))
+	if lemmas.Snapshot().Vocabulary != 4 {
+		t.Errorf("%d", lemmas.Snapshot().Vocabulary)
+	}
+}
+
+func TestAggregatorGrowth(t *testing.T) {
+	a := &Aggregator{MaxGrowth: 4}
+	for i := 0; i < 10; i++ {
+		a.Add(testdoc.Tagged("word|NN"))
+	}
+	// thinned after 5 documents to every 2, after 10 to every 4
+	s := a.Snapshot()
+	if len(s.Growth) != 2 || s.Growth[0] != (Point{4, 1}) || s.Growth[1] != (Point{8, 1}) {
+		t.Errorf("%v", s.Growth)
+	}
+	a.Add(testdoc.Tagged("other|NN"))
+	a.Add(testdoc.Tagged("word|NN"))
+	if s = a.Snapshot(); len(s.Growth) != 3 || s.Growth[0] != (Point{4, 1}) || s.Growth[2] != (Point{12, 2}) {
+		t.Errorf("%v", s.Growth)
+	}
+}
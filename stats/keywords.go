@@ -0,0 +1,191 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/genelet/corenlp-golang/extract"
+	"github.com/genelet/corenlp-golang/nlp"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+// EnglishStopWords is the default stop-word list of TermCounter: function
+// words and the most frequent verbs, lower-cased.
+//
+var EnglishStopWords = stopWords(`a about above after again against all also am an and any are as at
+be because been before being below between both but by can could did do does doing down during
+each few for from further get got had has have having he her here hers herself him himself his how
+i if in into is it its itself just let make many may me might more most much must my myself no nor
+not now of off on once one only or other ought our ours ourselves out over own same say said see
+shall she should so some such than that the their theirs them themselves then there these they
+this those through to too under until up upon us very was we were what when where which while who
+whom why will with would yet you your yours yourself yourselves`)
+
+func stopWords(list string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		words[w] = true
+	}
+	return words
+}
+
+// Term is a lemma, or a lower-cased word, with its counts.
+//
+type Term struct {
+	Term string `json:"term"`
+
+	// number of occurrences, and of documents containing the term
+	Count     int `json:"count"`
+	Documents int `json:"documents"`
+
+	// the rank of the term by Keywords, 0 in Frequencies
+	Score float64 `json:"score,omitempty"`
+}
+
+// TermCounter counts the terms of a document or a corpus, for frequency
+// lists and keywords. It is safe for concurrent use.
+//
+type TermCounter struct {
+	// count lower-cased words instead of lemmas
+	UseWords bool
+
+	// the POS tags counted, tags.IsContentWord if nil; tokens without a tag
+	// are counted
+	Filter func(pos string) bool
+
+	// the terms not counted, EnglishStopWords if nil; an empty map keeps all
+	StopWords map[string]bool
+
+	// the shortest term counted, in characters
+	MinLength int
+
+	mu        sync.Mutex
+	documents int
+	counts    map[string]int
+	docCounts map[string]int
+}
+
+// NewTermCounter creates an instance of TermCounter.
+//
+func NewTermCounter() *TermCounter {
+	return &TermCounter{}
+}
+
+// Add counts the terms of the document.
+//
+func (self *TermCounter) Add(doc *nlp.Document) {
+	filter := self.Filter
+	if filter == nil {
+		filter = tags.IsContentWord
+	}
+	stop := self.StopWords
+	if stop == nil {
+		stop = EnglishStopWords
+	}
+
+	seen := make(map[string]bool)
+	counts := make(map[string]int)
+	for _, sentence := range doc.GetSentence() {
+		for _, token := range sentence.Token {
+			if pos := token.GetPos(); pos != "" && !filter(pos) {
+				continue
+			}
+			term := extract.LemmaOf(token)
+			if self.UseWords {
+				term = token.GetWord()
+			}
+			term = strings.ToLower(term)
+			if stop[term] || len([]rune(term)) < self.MinLength || !hasLetter(term) {
+				continue
+			}
+			counts[term]++
+			seen[term] = true
+		}
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.counts == nil {
+		self.counts = make(map[string]int)
+		self.docCounts = make(map[string]int)
+	}
+	self.documents++
+	for term, n := range counts {
+		self.counts[term] += n
+		self.docCounts[term]++
+	}
+}
+
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Frequencies returns the terms counted, most frequent first, then in
+// alphabetical order.
+//
+func (self *TermCounter) Frequencies() []*Term {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	terms := make([]*Term, 0, len(self.counts))
+	for term, n := range self.counts {
+		terms = append(terms, &Term{Term: term, Count: n, Documents: self.docCounts[term]})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Count != terms[j].Count {
+			return terms[i].Count > terms[j].Count
+		}
+		return terms[i].Term < terms[j].Term
+	})
+	return terms
+}
+
+// Keywords returns the n terms of highest score, all if n is 0. The score is
+// the TF-IDF Count * log(1 + D/Documents), D being the number of documents:
+// over one document, the most frequent terms; over a corpus, the frequent
+// terms found in few documents rather than everywhere.
+//
+func (self *TermCounter) Keywords(n int) []*Term {
+	terms := self.Frequencies()
+	self.mu.Lock()
+	d := float64(self.documents)
+	self.mu.Unlock()
+	for _, t := range terms {
+		t.Score = float64(t.Count) * math.Log(1+d/float64(t.Documents))
+	}
+	sort.SliceStable(terms, func(i, j int) bool {
+		return terms[i].Score > terms[j].Score
+	})
+	if n > 0 && n < len(terms) {
+		terms = terms[:n]
+	}
+	return terms
+}
+
+// TermFrequencies returns the lemma frequencies of the content words of the
+// documents, without stop words, see TermCounter.Frequencies.
+//
+func TermFrequencies(docs ...*nlp.Document) []*Term {
+	c := NewTermCounter()
+	for _, doc := range docs {
+		c.Add(doc)
+	}
+	return c.Frequencies()
+}
+
+// Keywords returns the n top keywords of the documents, see TermCounter.Keywords.
+//
+func Keywords(n int, docs ...*nlp.Document) []*Term {
+	c := NewTermCounter()
+	for _, doc := range docs {
+		c.Add(doc)
+	}
+	return c.Keywords(n)
+}
@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/tags"
+)
+
+func TestTermFrequencies(t *testing.T) {
+	doc := testdoc.Tagged("The|DT|the cats|NNS|cat are|VBP|be sleeping|VBG|sleep .|.|.", "A|DT|a cat|NN|cat sleeps|VBZ|sleep on|IN|on mats|NNS|mat")
+	terms := TermFrequencies(doc)
+	if len(terms) != 3 || terms[0].Term != "cat" || terms[0].Count != 2 || terms[1].Term != "sleep" || terms[2].Term != "mat" || terms[2].Documents != 1 {
+		for _, term := range terms {
+			t.Errorf("%+v", term)
+		}
+	}
+
+	c := NewTermCounter()
+	c.UseWords = true
+	c.Filter = tags.IsNoun
+	c.StopWords = map[string]bool{"mats": true}
+	c.Add(doc)
+	terms = c.Frequencies()
+	if len(terms) != 2 || terms[0].Term != "cat" || terms[1].Term != "cats" {
+		for _, term := range terms {
+			t.Errorf("%+v", term)
+		}
+	}
+}
+
+func TestKeywords(t *testing.T) {
+	docs := []string{
+		"Markets|NNS|market fell|VBD|fall sharply|RB|sharply markets|NNS|market",
+		"Markets|NNS|market rose|VBD|rise",
+		"Markets|NNS|market tumbled|VBD|tumble tumbled|VBD|tumble tumbling|VBG|tumble",
+	}
+	c := NewTermCounter()
+	for _, d := range docs {
+		c.Add(testdoc.Tagged(d))
+	}
+	// tumble, three times in one document, outranks market, in every one
+	keywords := c.Keywords(2)
+	if len(keywords) != 2 || keywords[0].Term != "tumble" || keywords[1].Term != "market" || keywords[0].Score <= keywords[1].Score {
+		for _, k := range keywords {
+			t.Errorf("%+v", k)
+		}
+	}
+	if all := Keywords(0, testdoc.Tagged(docs[0])); len(all) != 3 || all[0].Term != "market" {
+		t.Errorf("%v", all)
+	}
+}
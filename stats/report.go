@@ -0,0 +1,282 @@
+package stats
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/genelet/corenlp-golang/extract"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Entity is a named entity with the number of times it was counted.
+//
+type Entity struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// DateCount is the number of mentions in the documents of a date.
+//
+type DateCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// EntityReport reports a top entity.
+//
+type EntityReport struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	// number of mentions
+	Mentions int `json:"mentions"`
+
+	// number of documents mentioning the entity
+	Documents int `json:"documents"`
+
+	// mentions by document date, in date order
+	Trend []DateCount `json:"trend,omitempty"`
+
+	// entities mentioned in the same documents, Count being the number of
+	// shared documents, most frequent first
+	CoOccurring []Entity `json:"cooccurring,omitempty"`
+}
+
+// Report is the entity report of a corpus.
+//
+type Report struct {
+	Documents int `json:"documents"`
+
+	// top entities by NER type, most mentioned first
+	TopEntities map[string][]*EntityReport `json:"topEntities"`
+}
+
+// ReportBuilder collects the named entities of a corpus for a Report.
+// It is safe for concurrent use.
+//
+type ReportBuilder struct {
+	// number of entities reported per type, and of co-occurring entities per entity
+	Top int
+
+	// DateBucket maps the document date to the period it is counted in.
+	// The default keeps the day of ISO dates, e.g. "2023-05-01" of "2023-05-01T10:00:00".
+	// Documents without a date are left out of the trends.
+	DateBucket func(string) string
+
+	mu        sync.Mutex
+	documents int
+	entities  map[entityKey]*entityStat
+}
+
+type entityKey struct {
+	typ  string
+	name string
+}
+
+type entityStat struct {
+	mentions  int
+	documents int
+	dates     map[string]int
+	with      map[entityKey]int
+}
+
+// NewReportBuilder creates an instance of ReportBuilder.
+//
+// top, optional: the number of entities reported per type, default 10.
+//
+func NewReportBuilder(top ...int) *ReportBuilder {
+	n := 10
+	if len(top) > 0 {
+		n = top[0]
+	}
+	return &ReportBuilder{Top: n}
+}
+
+// Add counts the named entities of the document.
+//
+func (self *ReportBuilder) Add(doc *nlp.Document) {
+	date := doc.GetDocDate()
+	if date != "" {
+		if self.DateBucket != nil {
+			date = self.DateBucket(date)
+		} else if i := strings.IndexByte(date, 'T'); i > 0 {
+			date = date[:i]
+		}
+	}
+
+	mentions := make(map[entityKey]int)
+	var keys []entityKey
+	for typ, names := range extract.ExtractNamedEntities(doc) {
+		for _, name := range names {
+			k := entityKey{typ, name}
+			if mentions[k] == 0 {
+				keys = append(keys, k)
+			}
+			mentions[k]++
+		}
+	}
+
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	if self.entities == nil {
+		self.entities = make(map[entityKey]*entityStat)
+	}
+	self.documents++
+	for _, k := range keys {
+		e := self.entities[k]
+		if e == nil {
+			e = &entityStat{dates: make(map[string]int), with: make(map[entityKey]int)}
+			self.entities[k] = e
+		}
+		e.mentions += mentions[k]
+		e.documents++
+		if date != "" {
+			e.dates[date] += mentions[k]
+		}
+		for _, other := range keys {
+			if other != k {
+				e.with[other]++
+			}
+		}
+	}
+}
+
+// Consume adds the documents received from docs until the channel is closed
+// or ctx is done.
+//
+func (self *ReportBuilder) Consume(ctx context.Context, docs <-chan *nlp.Document) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case doc, ok := <-docs:
+			if !ok {
+				return nil
+			}
+			self.Add(doc)
+		}
+	}
+}
+
+// Report returns the report of the documents added so far.
+//
+func (self *ReportBuilder) Report() *Report {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	byType := make(map[string][]entityKey)
+	for k := range self.entities {
+		byType[k.typ] = append(byType[k.typ], k)
+	}
+
+	r := &Report{Documents: self.documents, TopEntities: make(map[string][]*EntityReport)}
+	for typ, keys := range byType {
+		sort.Slice(keys, func(i, j int) bool {
+			return ranked(keys[i], self.entities[keys[i]].mentions, keys[j], self.entities[keys[j]].mentions)
+		})
+		if self.Top > 0 && len(keys) > self.Top {
+			keys = keys[:self.Top]
+		}
+		for _, k := range keys {
+			r.TopEntities[typ] = append(r.TopEntities[typ], self.entityReport(k))
+		}
+	}
+	return r
+}
+
+func (self *ReportBuilder) entityReport(k entityKey) *EntityReport {
+	e := self.entities[k]
+	er := &EntityReport{Type: k.typ, Name: k.name, Mentions: e.mentions, Documents: e.documents}
+	for date, n := range e.dates {
+		er.Trend = append(er.Trend, DateCount{date, n})
+	}
+	sort.Slice(er.Trend, func(i, j int) bool { return er.Trend[i].Date < er.Trend[j].Date })
+
+	with := make([]entityKey, 0, len(e.with))
+	for o := range e.with {
+		with = append(with, o)
+	}
+	sort.Slice(with, func(i, j int) bool {
+		return ranked(with[i], e.with[with[i]], with[j], e.with[with[j]])
+	})
+	if self.Top > 0 && len(with) > self.Top {
+		with = with[:self.Top]
+	}
+	for _, o := range with {
+		er.CoOccurring = append(er.CoOccurring, Entity{o.typ, o.name, e.with[o]})
+	}
+	return er
+}
+
+// ranked orders by count descending, then by type and name.
+//
+func ranked(a entityKey, na int, b entityKey, nb int) bool {
+	if na != nb {
+		return na > nb
+	}
+	if a.typ != b.typ {
+		return a.typ < b.typ
+	}
+	return a.name < b.name
+}
+
+// types returns the NER types of the report in alphabetical order.
+//
+func (self *Report) types() []string {
+	types := make([]string, 0, len(self.TopEntities))
+	for typ := range self.TopEntities {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// WriteJSON writes the report as indented JSON.
+//
+func (self *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(self)
+}
+
+// WriteCSV writes one row per top entity: type, name, mentions, documents,
+// and the co-occurring entities as "TYPE:name=count" joined by "; ".
+//
+func (self *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"type", "name", "mentions", "documents", "cooccurring"})
+	for _, typ := range self.types() {
+		for _, e := range self.TopEntities[typ] {
+			var with []string
+			for _, o := range e.CoOccurring {
+				with = append(with, o.Type+":"+o.Name+"="+strconv.Itoa(o.Count))
+			}
+			cw.Write([]string{e.Type, e.Name, strconv.Itoa(e.Mentions), strconv.Itoa(e.Documents), strings.Join(with, "; ")})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteTrendsCSV writes one row per top entity and date: type, name, date and mentions.
+//
+func (self *Report) WriteTrendsCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"type", "name", "date", "mentions"})
+	for _, typ := range self.types() {
+		for _, e := range self.TopEntities[typ] {
+			for _, d := range e.Trend {
+				cw.Write([]string{e.Type, e.Name, d.Date, strconv.Itoa(d.Count)})
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
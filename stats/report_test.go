@@ -0,0 +1,65 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/internal/testdoc"
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReportBuilder(t *testing.T) {
+	d1 := testdoc.Tagged("Obama|NNP|Obama|PERSON met|VBD|meet|O Merkel|NNP|Merkel|PERSON in|IN|in|O Berlin|NNP|Berlin|CITY", "Obama|NNP|Obama|PERSON left|VBD|leave|O")
+	d1.DocDate = proto.String("2015-03-01T09:00:00")
+	d2 := testdoc.Tagged("Obama|NNP|Obama|PERSON visited|VBD|visit|O Paris|NNP|Paris|CITY")
+	d2.DocDate = proto.String("2015-03-02")
+	d3 := testdoc.Tagged("Merkel|NNP|Merkel|PERSON spoke|VBD|speak|O")
+
+	b := NewReportBuilder(1)
+	for _, d := range []*nlp.Document{d1, d2, d3} {
+		b.Add(d)
+	}
+	r := b.Report()
+
+	if r.Documents != 3 || len(r.TopEntities["PERSON"]) != 1 || len(r.TopEntities["CITY"]) != 1 {
+		t.Fatalf("%#v", r)
+	}
+	obama := r.TopEntities["PERSON"][0]
+	if obama.Name != "Obama" || obama.Mentions != 3 || obama.Documents != 2 {
+		t.Errorf("%#v", obama)
+	}
+	if len(obama.Trend) != 2 || obama.Trend[0] != (DateCount{"2015-03-01", 2}) || obama.Trend[1] != (DateCount{"2015-03-02", 1}) {
+		t.Errorf("%v", obama.Trend)
+	}
+	// Berlin, Merkel and Paris all share one document, Berlin comes first by type
+	if len(obama.CoOccurring) != 1 || obama.CoOccurring[0] != (Entity{"CITY", "Berlin", 1}) {
+		t.Errorf("%v", obama.CoOccurring)
+	}
+	if city := r.TopEntities["CITY"][0]; city.Name != "Berlin" {
+		t.Errorf("%#v", city)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil { t.Fatal(err) }
+	back := &Report{}
+	if err := json.Unmarshal(buf.Bytes(), back); err != nil { t.Fatal(err) }
+	if back.TopEntities["PERSON"][0].Mentions != 3 {
+		t.Errorf("%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := r.WriteCSV(&buf); err != nil { t.Fatal(err) }
+	want := "type,name,mentions,documents,cooccurring\nCITY,Berlin,1,1,PERSON:Merkel=1\nPERSON,Obama,3,2,CITY:Berlin=1\n"
+	if buf.String() != want {
+		t.Errorf("%q", buf.String())
+	}
+
+	buf.Reset()
+	if err := r.WriteTrendsCSV(&buf); err != nil { t.Fatal(err) }
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 4 || lines[1] != "CITY,Berlin,2015-03-01,1" {
+		t.Errorf("%q", buf.String())
+	}
+}
@@ -0,0 +1,149 @@
+package tags
+
+import (
+	"strings"
+)
+
+// Universal Dependencies relations, as produced by the "depparse" annotator.
+//
+const (
+	// core arguments
+	Nsubj = "nsubj"
+	Obj   = "obj"
+	Iobj  = "iobj"
+	Csubj = "csubj"
+	Ccomp = "ccomp"
+	Xcomp = "xcomp"
+
+	// non-core dependents
+	Obl        = "obl"
+	Vocative   = "vocative"
+	Expl       = "expl"
+	Dislocated = "dislocated"
+	Advcl      = "advcl"
+	Advmod     = "advmod"
+	Discourse  = "discourse"
+	Aux        = "aux"
+	Cop        = "cop"
+	Mark       = "mark"
+
+	// nominal dependents
+	Nmod   = "nmod"
+	Appos  = "appos"
+	Nummod = "nummod"
+	Acl    = "acl"
+	Amod   = "amod"
+	Det    = "det"
+	Clf    = "clf"
+	Case   = "case"
+
+	// coordination, multiword expressions and others
+	Conj       = "conj"
+	Cc         = "cc"
+	Fixed      = "fixed"
+	Flat       = "flat"
+	Compound   = "compound"
+	List       = "list"
+	Parataxis  = "parataxis"
+	Orphan     = "orphan"
+	Goeswith   = "goeswith"
+	Reparandum = "reparandum"
+	Punct      = "punct"
+	Root       = "root"
+	Dep        = "dep"
+
+	// common subtypes
+	NsubjPass   = "nsubj:pass"
+	CsubjPass   = "csubj:pass"
+	AuxPass     = "aux:pass"
+	NmodPoss    = "nmod:poss"
+	NmodTmod    = "nmod:tmod"
+	OblTmod     = "obl:tmod"
+	OblNpmod    = "obl:npmod"
+	AclRelcl    = "acl:relcl"
+	CompoundPrt = "compound:prt"
+	DetPredet   = "det:predet"
+	CcPreconj   = "cc:preconj"
+	FlatForeign = "flat:foreign"
+
+	// Stanford Dependencies names still found in older models
+	Dobj      = "dobj"
+	Nsubjpass = "nsubjpass"
+	Csubjpass = "csubjpass"
+	Auxpass   = "auxpass"
+	Neg       = "neg"
+	Poss      = "poss"
+	Prep      = "prep"
+	Pobj      = "pobj"
+)
+
+// BaseRelation strips the subtype, and the enhanced case marker, from rel,
+// e.g. nsubj for nsubj:pass, obl for obl:in, conj for conj:and.
+//
+func BaseRelation(rel string) string {
+	if i := strings.IndexByte(rel, ':'); i >= 0 {
+		return rel[:i]
+	}
+	return rel
+}
+
+// IsCoreArgument reports whether rel attaches a subject, an object or a clausal complement.
+//
+func IsCoreArgument(rel string) bool {
+	switch BaseRelation(rel) {
+	case Nsubj, Obj, Iobj, Csubj, Ccomp, Xcomp, Dobj, Nsubjpass, Csubjpass:
+		return true
+	}
+	return false
+}
+
+// IsSubject reports whether rel attaches a nominal or clausal subject, active or passive.
+//
+func IsSubject(rel string) bool {
+	switch BaseRelation(rel) {
+	case Nsubj, Csubj, Nsubjpass, Csubjpass:
+		return true
+	}
+	return false
+}
+
+// IsObject reports whether rel attaches a direct or indirect object.
+//
+func IsObject(rel string) bool {
+	switch BaseRelation(rel) {
+	case Obj, Iobj, Dobj:
+		return true
+	}
+	return false
+}
+
+// IsModifier reports whether rel attaches an optional modifier, which may be
+// dropped without breaking the sentence, e.g. amod, advmod, nmod, appos, acl.
+//
+func IsModifier(rel string) bool {
+	switch BaseRelation(rel) {
+	case Amod, Advmod, Nmod, Nummod, Appos, Acl, Advcl, Obl, Discourse, Vocative, Neg, Poss, Prep:
+		return true
+	}
+	return false
+}
+
+// IsFunctionWord reports whether rel attaches a function word, e.g. aux, det, case.
+//
+func IsFunctionWord(rel string) bool {
+	switch BaseRelation(rel) {
+	case Aux, Cop, Mark, Det, Clf, Case, Cc, Auxpass:
+		return true
+	}
+	return false
+}
+
+// IsClausal reports whether rel attaches a clause, e.g. ccomp, advcl, acl:relcl.
+//
+func IsClausal(rel string) bool {
+	switch BaseRelation(rel) {
+	case Csubj, Ccomp, Xcomp, Advcl, Acl, Csubjpass:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,23 @@
+package tags
+
+import (
+	"testing"
+)
+
+func TestDependencies(t *testing.T) {
+	if BaseRelation(NsubjPass) != Nsubj || BaseRelation("obl:in") != Obl || BaseRelation(Amod) != Amod {
+		t.Errorf("%s", BaseRelation(NsubjPass))
+	}
+	if !IsCoreArgument(NsubjPass) || !IsCoreArgument(Dobj) || IsCoreArgument(Obl) {
+		t.Errorf("core arguments")
+	}
+	if !IsSubject("nsubj:xsubj") || IsSubject(Obj) || !IsObject(Iobj) {
+		t.Errorf("subjects and objects")
+	}
+	if !IsModifier(AclRelcl) || !IsModifier("nmod:of") || IsModifier(Det) || !IsFunctionWord(Det) {
+		t.Errorf("modifiers")
+	}
+	if !IsClausal(AclRelcl) || IsClausal(Amod) {
+		t.Errorf("clauses")
+	}
+}
@@ -0,0 +1,169 @@
+// Package tags defines the label sets produced by the CoreNLP annotators:
+// NER types, part-of-speech tags and dependency relations, with predicates
+// grouping them into categories.
+//
+package tags
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// NER types of the English models: the 4-class CoNLL types, the numeric and
+// temporal types from SUTime, and the fine-grained types from the regexner
+// and KBP models.
+//
+const (
+	Person       = "PERSON"
+	Location     = "LOCATION"
+	Organization = "ORGANIZATION"
+	Misc         = "MISC"
+
+	Money    = "MONEY"
+	Number   = "NUMBER"
+	Ordinal  = "ORDINAL"
+	Percent  = "PERCENT"
+	Date     = "DATE"
+	Time     = "TIME"
+	Duration = "DURATION"
+	Set      = "SET"
+
+	Email           = "EMAIL"
+	URL             = "URL"
+	City            = "CITY"
+	StateOrProvince = "STATE_OR_PROVINCE"
+	Country         = "COUNTRY"
+	Nationality     = "NATIONALITY"
+	Religion        = "RELIGION"
+	Title           = "TITLE"
+	Ideology        = "IDEOLOGY"
+	CriminalCharge  = "CRIMINAL_CHARGE"
+	CauseOfDeath    = "CAUSE_OF_DEATH"
+	Handle          = "HANDLE"
+
+	// O marks a token outside any named entity.
+	O = "O"
+)
+
+// NER types specific to the Chinese models.
+//
+const (
+	GPE      = "GPE"
+	Facility = "FACILITY"
+	Demonym  = "DEMONYM"
+)
+
+// English lists the NER types of the default English pipeline.
+//
+var English = []string{Person, Location, Organization, Misc,
+	Money, Number, Ordinal, Percent, Date, Time, Duration, Set,
+	Email, URL, City, StateOrProvince, Country, Nationality, Religion,
+	Title, Ideology, CriminalCharge, CauseOfDeath, Handle}
+
+// Chinese lists the NER types of the default Chinese pipeline.
+//
+var Chinese = []string{Person, Location, Organization, Misc, GPE, Facility, Demonym,
+	Money, Number, Ordinal, Percent, Date, Time,
+	Email, URL, City, StateOrProvince, Country, Nationality, Religion,
+	Title, Ideology, CriminalCharge, CauseOfDeath}
+
+// CoNLL lists the 4-class types of the German, French and Spanish models.
+//
+var CoNLL = []string{Person, Location, Organization, Misc}
+
+// NERTypes returns the NER types produced for the language, nil if unknown.
+//
+func NERTypes(lang nlp.Language) []string {
+	switch lang {
+	case nlp.Language_English, nlp.Language_UniversalEnglish:
+		return English
+	case nlp.Language_Chinese, nlp.Language_UniversalChinese:
+		return Chinese
+	case nlp.Language_German, nlp.Language_French, nlp.Language_Spanish:
+		return CoNLL
+	}
+	return nil
+}
+
+// IsEntity reports whether ner is a named entity type rather than O or empty.
+//
+func IsEntity(ner string) bool {
+	return ner != "" && ner != O
+}
+
+// EntityRun is a run of consecutive tokens of one named entity type, over the
+// 0-based token span [Begin, End).
+//
+type EntityRun struct {
+	Begin int
+	End   int
+	NER   string
+}
+
+// EntityRuns groups the tokens into runs of their named entity types, skipping
+// O. A run ends where the NER type changes, or where the entity mention index
+// changes between two tokens of the same type, so adjacent entities of one
+// type are kept apart when the entitymentions annotator ran.
+//
+func EntityRuns(tokens []*nlp.Token) []EntityRun {
+	var runs []EntityRun
+	for i := 0; i < len(tokens); {
+		ner := tokens[i].GetNer()
+		j := i + 1
+		for j < len(tokens) && tokens[j].GetNer() == ner && !newMention(tokens[j-1], tokens[j]) {
+			j++
+		}
+		if IsEntity(ner) {
+			runs = append(runs, EntityRun{i, j, ner})
+		}
+		i = j
+	}
+	return runs
+}
+
+func newMention(prev, t *nlp.Token) bool {
+	return prev.EntityMentionIndex != nil && t.EntityMentionIndex != nil &&
+		prev.GetEntityMentionIndex() != t.GetEntityMentionIndex()
+}
+
+// IsNumeric reports whether ner is a numeric type.
+//
+func IsNumeric(ner string) bool {
+	switch ner {
+	case Money, Number, Ordinal, Percent:
+		return true
+	}
+	return false
+}
+
+// IsTemporal reports whether ner is a temporal type normalized by SUTime.
+//
+func IsTemporal(ner string) bool {
+	switch ner {
+	case Date, Time, Duration, Set:
+		return true
+	}
+	return false
+}
+
+// IsPlace reports whether ner is a location, coarse or fine-grained.
+//
+func IsPlace(ner string) bool {
+	switch ner {
+	case Location, City, StateOrProvince, Country, GPE, Facility:
+		return true
+	}
+	return false
+}
+
+// Coarse maps a fine-grained type to its 4-class CoNLL type, e.g. CITY to LOCATION.
+// Numeric and temporal types are returned unchanged.
+//
+func Coarse(ner string) string {
+	switch {
+	case IsPlace(ner):
+		return Location
+	case ner == Nationality || ner == Religion || ner == Ideology || ner == Demonym || ner == Title || ner == CriminalCharge || ner == CauseOfDeath:
+		return Misc
+	}
+	return ner
+}
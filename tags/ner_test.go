@@ -0,0 +1,41 @@
+package tags
+
+import (
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNER(t *testing.T) {
+	if len(NERTypes(nlp.Language_English)) != 24 || NERTypes(nlp.Language_Arabic) != nil {
+		t.Errorf("%v", NERTypes(nlp.Language_English))
+	}
+	if Coarse(City) != Location || Coarse(Nationality) != Misc || Coarse(Date) != Date || Coarse(Person) != Person {
+		t.Errorf("%s", Coarse(City))
+	}
+	if !IsEntity(Person) || IsEntity(O) || IsEntity("") || !IsNumeric(Money) || !IsTemporal(Set) || IsTemporal(Money) {
+		t.Errorf("predicates")
+	}
+}
+
+func TestEntityRuns(t *testing.T) {
+	var tokens []*nlp.Token
+	for i, ner := range []string{"PERSON", "PERSON", "O", "CITY", "PERSON", "PERSON", ""} {
+		tokens = append(tokens, &nlp.Token{Ner: proto.String(ner)})
+		// the entitymentions annotator sets the mention of every entity token
+		if i >= 4 && i < 6 {
+			tokens[i].EntityMentionIndex = proto.Uint32(uint32(i))
+		}
+	}
+	runs := EntityRuns(tokens)
+	want := []EntityRun{{0, 2, Person}, {3, 4, City}, {4, 5, Person}, {5, 6, Person}}
+	if len(runs) != len(want) {
+		t.Fatalf("%v", runs)
+	}
+	for i := range want {
+		if runs[i] != want[i] {
+			t.Errorf("%d: %v", i, runs[i])
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package tags
+
+import (
+	"strings"
+)
+
+// Penn Treebank part-of-speech tags, as produced by the English "pos" annotator.
+//
+const (
+	CC   = "CC"   // coordinating conjunction
+	CD   = "CD"   // cardinal number
+	DT   = "DT"   // determiner
+	EX   = "EX"   // existential there
+	FW   = "FW"   // foreign word
+	IN   = "IN"   // preposition or subordinating conjunction
+	JJ   = "JJ"   // adjective
+	JJR  = "JJR"  // adjective, comparative
+	JJS  = "JJS"  // adjective, superlative
+	LS   = "LS"   // list item marker
+	MD   = "MD"   // modal
+	NN   = "NN"   // noun, singular or mass
+	NNS  = "NNS"  // noun, plural
+	NNP  = "NNP"  // proper noun, singular
+	NNPS = "NNPS" // proper noun, plural
+	PDT  = "PDT"  // predeterminer
+	POS  = "POS"  // possessive ending
+	PRP  = "PRP"  // personal pronoun
+	PRPS = "PRP$" // possessive pronoun
+	RB   = "RB"   // adverb
+	RBR  = "RBR"  // adverb, comparative
+	RBS  = "RBS"  // adverb, superlative
+	RP   = "RP"   // particle
+	SYM  = "SYM"  // symbol
+	TO   = "TO"   // to
+	UH   = "UH"   // interjection
+	VB   = "VB"   // verb, base form
+	VBD  = "VBD"  // verb, past tense
+	VBG  = "VBG"  // verb, gerund or present participle
+	VBN  = "VBN"  // verb, past participle
+	VBP  = "VBP"  // verb, non-3rd person singular present
+	VBZ  = "VBZ"  // verb, 3rd person singular present
+	WDT  = "WDT"  // wh-determiner
+	WP   = "WP"   // wh-pronoun
+	WPS  = "WP$"  // possessive wh-pronoun
+	WRB  = "WRB"  // wh-adverb
+
+	Period     = "."
+	Comma      = ","
+	Colon      = ":"
+	OpenQuote  = "``"
+	CloseQuote = "''"
+	LRB        = "-LRB-" // left bracket
+	RRB        = "-RRB-" // right bracket
+	Hash       = "#"
+	Dollar     = "$"
+	HYPH       = "HYPH" // hyphen
+	NFP        = "NFP"  // superfluous punctuation
+	ADD        = "ADD"  // email or URL
+	AFX        = "AFX"  // affix
+	GW         = "GW"   // goes-with
+)
+
+// IsNoun reports whether tag is a common or proper noun, i.e. NN, NNS, NNP or NNPS.
+//
+func IsNoun(tag string) bool {
+	return strings.HasPrefix(tag, NN)
+}
+
+// IsProperNoun reports whether tag is NNP or NNPS.
+//
+func IsProperNoun(tag string) bool {
+	return tag == NNP || tag == NNPS
+}
+
+// IsVerb reports whether tag is a verb in any form, i.e. VB, VBD, VBG, VBN, VBP or VBZ.
+// Modals (MD) are not included.
+//
+func IsVerb(tag string) bool {
+	return strings.HasPrefix(tag, VB)
+}
+
+// IsAdjective reports whether tag is JJ, JJR or JJS.
+//
+func IsAdjective(tag string) bool {
+	return strings.HasPrefix(tag, JJ)
+}
+
+// IsAdverb reports whether tag is RB, RBR, RBS or WRB.
+//
+func IsAdverb(tag string) bool {
+	return strings.HasPrefix(tag, RB) || tag == WRB
+}
+
+// IsPronoun reports whether tag is PRP, PRP$, WP or WP$.
+//
+func IsPronoun(tag string) bool {
+	return tag == PRP || tag == PRPS || tag == WP || tag == WPS
+}
+
+// IsWh reports whether tag is a wh-word: WDT, WP, WP$ or WRB.
+//
+func IsWh(tag string) bool {
+	return strings.HasPrefix(tag, "W")
+}
+
+// IsContentWord reports whether tag is a noun, verb, adjective or adverb.
+//
+func IsContentWord(tag string) bool {
+	return IsNoun(tag) || IsVerb(tag) || IsAdjective(tag) || IsAdverb(tag)
+}
+
+// IsPunctuation reports whether tag marks punctuation.
+//
+func IsPunctuation(tag string) bool {
+	switch tag {
+	case Period, Comma, Colon, OpenQuote, CloseQuote, LRB, RRB, Hash, Dollar, HYPH, NFP:
+		return true
+	}
+	return false
+}
+
+// Universal Dependencies part-of-speech tags.
+//
+const (
+	UADJ   = "ADJ"
+	UADP   = "ADP"
+	UADV   = "ADV"
+	UAUX   = "AUX"
+	UCCONJ = "CCONJ"
+	UDET   = "DET"
+	UINTJ  = "INTJ"
+	UNOUN  = "NOUN"
+	UNUM   = "NUM"
+	UPART  = "PART"
+	UPRON  = "PRON"
+	UPROPN = "PROPN"
+	UPUNCT = "PUNCT"
+	USCONJ = "SCONJ"
+	USYM   = "SYM"
+	UVERB  = "VERB"
+	UX     = "X"
+)
+
+var universal = map[string]string{
+	CC: UCCONJ, CD: UNUM, DT: UDET, EX: UPRON, FW: UX, IN: UADP,
+	JJ: UADJ, JJR: UADJ, JJS: UADJ, LS: UX, MD: UAUX,
+	NN: UNOUN, NNS: UNOUN, NNP: UPROPN, NNPS: UPROPN,
+	PDT: UDET, POS: UPART, PRP: UPRON, PRPS: UPRON,
+	RB: UADV, RBR: UADV, RBS: UADV, RP: UADP, SYM: USYM, TO: UPART, UH: UINTJ,
+	VB: UVERB, VBD: UVERB, VBG: UVERB, VBN: UVERB, VBP: UVERB, VBZ: UVERB,
+	WDT: UDET, WP: UPRON, WPS: UPRON, WRB: UADV,
+	Period: UPUNCT, Comma: UPUNCT, Colon: UPUNCT, OpenQuote: UPUNCT, CloseQuote: UPUNCT,
+	LRB: UPUNCT, RRB: UPUNCT, HYPH: UPUNCT, NFP: UPUNCT, Hash: USYM, Dollar: USYM,
+	ADD: UX, AFX: UADJ, GW: UX,
+}
+
+// UniversalPOS maps a Penn Treebank tag to the Universal Dependencies tag,
+// by the tag alone, e.g. VBZ to VERB even for an auxiliary, and IN to ADP even
+// for a subordinating conjunction. Unknown tags map to X.
+//
+func UniversalPOS(tag string) string {
+	if u, ok := universal[tag]; ok {
+		return u
+	}
+	return UX
+}
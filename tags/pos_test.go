@@ -0,0 +1,36 @@
+package tags
+
+import (
+	"testing"
+)
+
+func TestPOS(t *testing.T) {
+	for _, c := range []struct {
+		tag                                   string
+		noun, proper, verb, adj, adv, pronoun bool
+	}{
+		{NN, true, false, false, false, false, false},
+		{NNPS, true, true, false, false, false, false},
+		{VBZ, false, false, true, false, false, false},
+		{MD, false, false, false, false, false, false},
+		{JJS, false, false, false, true, false, false},
+		{WRB, false, false, false, false, true, false},
+		{PRPS, false, false, false, false, false, true},
+	} {
+		if IsNoun(c.tag) != c.noun || IsProperNoun(c.tag) != c.proper || IsVerb(c.tag) != c.verb ||
+			IsAdjective(c.tag) != c.adj || IsAdverb(c.tag) != c.adv || IsPronoun(c.tag) != c.pronoun {
+			t.Errorf("%s", c.tag)
+		}
+	}
+	if !IsContentWord(VBG) || IsContentWord(DT) || !IsPunctuation(LRB) || IsPunctuation(SYM) {
+		t.Errorf("content or punctuation")
+	}
+}
+
+func TestUniversalPOS(t *testing.T) {
+	for tag, u := range map[string]string{NNP: UPROPN, VBZ: UVERB, MD: UAUX, PRPS: UPRON, Comma: UPUNCT, Dollar: USYM, "XYZ": UX} {
+		if UniversalPOS(tag) != u {
+			t.Errorf("%s: %s", tag, UniversalPOS(tag))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package tree
+
+// Span is a labeled constituent: the category of a phrase, without its
+// function tags, over the 0-based leaves [Begin, End).
+//
+type Span struct {
+	Label string
+	Begin int
+	End   int
+}
+
+// TokenDiff is a token tagged or attached differently by two trees.
+//
+type TokenDiff struct {
+	// 0-based index of the token, and its word in A
+	Index int
+	Word  string
+
+	// the part-of-speech tags of the token
+	TagA string
+	TagB string
+
+	// the highest phrase the token heads, see HeadLeaf, its tag if none
+	PhraseA Span
+	PhraseB Span
+
+	// the index of the token heading the phrase the token attaches to, as
+	// its governor in a dependency graph; -1 for the head of the sentence
+	HeadA int
+	HeadB int
+}
+
+// TreeDiff compares two constituency trees of a sentence, e.g. from two
+// models or two versions of CoreNLP.
+//
+type TreeDiff struct {
+	// the tokens compared, the leaves of A
+	Tokens int
+
+	// the tokens of other tags, phrases or heads, in order
+	Diffs []*TokenDiff
+
+	// the phrases of the trees
+	SpanComparison
+}
+
+// SpanComparison compares two trees of a sentence by their phrases, the
+// root and the tags aside, as evalb does.
+//
+type SpanComparison struct {
+	// the phrases of both trees, and of only one of them, in preorder
+	Common []Span
+	OnlyA  []Span
+	OnlyB  []Span
+
+	// the labeled bracket F1 of B against A, 1 for the same phrases
+	F1 float64
+}
+
+// Diff compares the trees under a and b, token by token and phrase by
+// phrase. The trees should have the same leaves; the tokens past the
+// leaves of b are compared to nothing. A nil tree has no phrases.
+//
+func Diff(a, b *Node) *TreeDiff {
+	d := &TreeDiff{}
+	var tagsA, tagsB []*Node
+	if a != nil {
+		tagsA = a.Preterminals()
+	}
+	if b != nil {
+		tagsB = b.Preterminals()
+	}
+	d.Tokens = len(tagsA)
+	for i, ta := range tagsA {
+		td := &TokenDiff{Index: i, Word: ta.Children[0].Label(), TagA: ta.Label(), HeadB: -1}
+		td.PhraseA, td.HeadA = projection(ta)
+		if i < len(tagsB) {
+			td.TagB = tagsB[i].Label()
+			td.PhraseB, td.HeadB = projection(tagsB[i])
+		}
+		if td.TagA != td.TagB || td.PhraseA != td.PhraseB || td.HeadA != td.HeadB {
+			d.Diffs = append(d.Diffs, td)
+		}
+	}
+
+	d.SpanComparison = *CompareSpans(a, b)
+	return d
+}
+
+// CompareSpans compares the phrases of the trees under a and b, e.g. two
+// alternative parses, to see where the parser hesitates.
+//
+func CompareSpans(a, b *Node) *SpanComparison {
+	spansA, spansB := a.Spans(), b.Spans()
+	counts := make(map[Span]int)
+	for _, s := range spansB {
+		counts[s]++
+	}
+	c := &SpanComparison{}
+	for _, s := range spansA {
+		if counts[s] > 0 {
+			counts[s]--
+			c.Common = append(c.Common, s)
+		} else {
+			c.OnlyA = append(c.OnlyA, s)
+		}
+	}
+	for _, s := range spansB {
+		if counts[s] > 0 {
+			counts[s]--
+			c.OnlyB = append(c.OnlyB, s)
+		}
+	}
+	if len(spansA)+len(spansB) > 0 {
+		c.F1 = 2 * float64(len(c.Common)) / float64(len(spansA)+len(spansB))
+	} else {
+		c.F1 = 1
+	}
+	return c
+}
+
+// projection returns the highest phrase headed by the word of the tag, and
+// the index of the head of its parent, -1 if none.
+//
+func projection(tag *Node) (Span, int) {
+	leaf := tag.Children[0]
+	n := tag
+	for n.Parent != nil && n.Parent.HeadLeaf() == leaf {
+		n = n.Parent
+	}
+	head := -1
+	if n.Parent != nil {
+		head = n.Parent.HeadLeaf().Begin
+	}
+	return Span{Label: n.Category(), Begin: n.Begin, End: n.End}, head
+}
+
+// Spans returns the phrases under the node in preorder, itself and the
+// tags aside; none for a nil node.
+//
+func (self *Node) Spans() []Span {
+	var spans []Span
+	if self == nil {
+		return nil
+	}
+	self.Walk(func(n *Node) bool {
+		if n != self && !n.IsLeaf() && !n.IsPreterminal() {
+			spans = append(spans, Span{Label: n.Category(), Begin: n.Begin, End: n.End})
+		}
+		return true
+	})
+	return spans
+}
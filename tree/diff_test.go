@@ -0,0 +1,35 @@
+package tree
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	// the PP attaches to the verb in a, to the noun in b, and "saw" is tagged differently
+	a := New(mustParse(t, "(ROOT (S (NP (PRP I)) (VP (VBD saw) (NP (DT the) (NN man)) (PP (IN with) (NP (DT a) (NN telescope))))))"))
+	b := New(mustParse(t, "(ROOT (S (NP (PRP I)) (VP (VBP saw) (NP (NP (DT the) (NN man)) (PP (IN with) (NP (DT a) (NN telescope)))))))"))
+
+	d := Diff(a, b)
+	if d.Tokens != 7 || len(d.Diffs) != 3 {
+		t.Fatalf("%d %v", d.Tokens, d.Diffs)
+	}
+	if td := d.Diffs[0]; td.Index != 1 || td.Word != "saw" || td.TagA != "VBD" || td.TagB != "VBP" || td.PhraseA != td.PhraseB || td.HeadA != -1 || td.HeadB != -1 {
+		t.Errorf("%#v", td)
+	}
+	if td := d.Diffs[1]; td.Word != "man" || td.PhraseA != (Span{"NP", 2, 4}) || td.PhraseB != (Span{"NP", 2, 7}) || td.HeadA != 1 || td.HeadB != 1 {
+		t.Errorf("%#v", td)
+	}
+	if td := d.Diffs[2]; td.Word != "with" || td.PhraseA != td.PhraseB || td.HeadA != 1 || td.HeadB != 3 {
+		t.Errorf("%#v", td)
+	}
+	if len(d.OnlyA) != 0 || len(d.OnlyB) != 1 || d.OnlyB[0] != (Span{"NP", 2, 7}) || d.F1 <= 0.9 || d.F1 >= 1 {
+		t.Errorf("%v %v %v", d.OnlyA, d.OnlyB, d.F1)
+	}
+
+	if d = Diff(a, a); len(d.Diffs) != 0 || d.F1 != 1 {
+		t.Errorf("%v %v", d.Diffs, d.F1)
+	}
+	if d = Diff(a, nil); len(d.Diffs) != 7 || d.F1 != 0 || len(d.OnlyA) != 6 {
+		t.Errorf("%v %v %v", d.Diffs, d.F1, d.OnlyA)
+	}
+}
@@ -0,0 +1,204 @@
+package tree
+
+import (
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// The directions of a HeadRule.
+//
+const (
+	// for each category in turn, the first child of the category from the left
+	HeadLeft = "left"
+	// for each category in turn, the first child of the category from the right
+	HeadRight = "right"
+	// the first child of any of the categories from the left
+	HeadLeftDis = "leftdis"
+	// the first child of any of the categories from the right
+	HeadRightDis = "rightdis"
+)
+
+// HeadRule is a step of the search of the head child of a phrase: the
+// children of the categories, searched in the direction. A rule without
+// categories takes the first child in the direction, punctuation aside.
+//
+type HeadRule struct {
+	Direction  string
+	Categories []string
+}
+
+// HeadRules are the head rules of Collins (1999) as used by the
+// CollinsHeadFinder of CoreNLP, by phrase category. The rules of a phrase
+// are tried in order, and the last one always finds a child; a category not
+// listed takes its leftmost child. As in Collins, a noun phrase ending with
+// a possessive POS is headed by it, before the rules of NP. The rules may be
+// changed before use, e.g. to head a PP by its object.
+//
+var HeadRules = map[string][]HeadRule{
+	"ADJP":   {{HeadLeft, []string{"NNS", "QP", "NN", "$", "ADVP", "JJ", "VBN", "VBG", "ADJP", "JJR", "NP", "JJS", "DT", "FW", "RBR", "RBS", "SBAR", "RB"}}},
+	"ADVP":   {{HeadRight, []string{"RB", "RBR", "RBS", "FW", "ADVP", "TO", "CD", "JJR", "JJ", "IN", "NP", "JJS", "NN"}}},
+	"CONJP":  {{HeadRight, []string{"CC", "RB", "IN"}}},
+	"FRAG":   {{HeadRight, nil}},
+	"INTJ":   {{HeadLeft, nil}},
+	"LST":    {{HeadRight, []string{"LS", ":"}}},
+	"NAC":    {{HeadLeft, []string{"NN", "NNS", "NNP", "NNPS", "NP", "NAC", "EX", "$", "CD", "QP", "PRP", "VBG", "JJ", "JJS", "JJR", "ADJP", "FW"}}},
+	"NP":     {{HeadRightDis, []string{"NN", "NNP", "NNPS", "NNS", "NX", "POS", "JJR"}}, {HeadLeft, []string{"NP"}}, {HeadRightDis, []string{"$", "ADJP", "PRN"}}, {HeadRight, []string{"CD"}}, {HeadRightDis, []string{"JJ", "JJS", "RB", "QP"}}, {HeadRight, nil}},
+	"NX":     {{HeadLeft, nil}},
+	"PP":     {{HeadRight, []string{"IN", "TO", "VBG", "VBN", "RP", "FW"}}},
+	"PRN":    {{HeadLeft, nil}},
+	"PRT":    {{HeadRight, []string{"RP"}}},
+	"QP":     {{HeadLeft, []string{"$", "IN", "NNS", "NN", "JJ", "RB", "DT", "CD", "NCD", "QP", "JJR", "JJS"}}},
+	"ROOT":   {{HeadLeft, []string{"S", "SINV", "SQ", "SBARQ", "SBAR", "FRAG"}}},
+	"RRC":    {{HeadRight, []string{"VP", "NP", "ADVP", "ADJP", "PP"}}},
+	"S":      {{HeadLeft, []string{"TO", "IN", "VP", "S", "SBAR", "ADJP", "UCP", "NP"}}},
+	"SBAR":   {{HeadLeft, []string{"WHNP", "WHPP", "WHADVP", "WHADJP", "IN", "DT", "S", "SQ", "SINV", "SBAR", "FRAG"}}},
+	"SBARQ":  {{HeadLeft, []string{"SQ", "S", "SINV", "SBARQ", "FRAG"}}},
+	"SINV":   {{HeadLeft, []string{"VBZ", "VBD", "VBP", "VB", "MD", "VP", "S", "SINV", "ADJP", "NP"}}},
+	"SQ":     {{HeadLeft, []string{"VBZ", "VBD", "VBP", "VB", "MD", "VP", "SQ"}}},
+	"UCP":    {{HeadRight, nil}},
+	"VP":     {{HeadLeft, []string{"TO", "VBD", "VBN", "MD", "VBZ", "VB", "VBG", "VBP", "VP", "ADJP", "NN", "NNS", "NP"}}},
+	"WHADJP": {{HeadLeft, []string{"CC", "WRB", "JJ", "ADJP"}}},
+	"WHADVP": {{HeadRight, []string{"CC", "WRB"}}},
+	"WHNP":   {{HeadLeft, []string{"WDT", "WP", "WP$", "WHADJP", "WHPP", "WHNP"}}},
+	"WHPP":   {{HeadRight, []string{"IN", "TO", "FW"}}},
+	"X":      {{HeadRight, nil}},
+}
+
+// punctuation are the tags skipped when a rule takes any child.
+//
+var punctuation = map[string]bool{".": true, ",": true, ":": true, "``": true, "''": true, "-LRB-": true, "-RRB-": true}
+
+// HeadChild returns the head child of the phrase by HeadRules: the verb of
+// a VP, the noun of a NP, and so on. A part-of-speech node is headed by its
+// word, and a leaf has none.
+//
+func (self *Node) HeadChild() *Node {
+	switch {
+	case self.IsLeaf():
+		return nil
+	case len(self.Children) == 1:
+		return self.Children[0]
+	}
+
+	category := self.Category()
+	if category == "" {
+		category = "ROOT"
+	}
+	// a possessive NP is headed by its "'s"
+	if category == "NP" {
+		if last := self.Children[len(self.Children)-1]; last.Category() == "POS" {
+			return last
+		}
+	}
+	rules, ok := HeadRules[category]
+	if !ok {
+		rules = []HeadRule{{HeadLeft, nil}}
+	}
+	for _, rule := range rules {
+		if c := self.applyRule(rule); c != nil {
+			return c
+		}
+	}
+	// the first rule direction decides the default
+	return self.applyRule(HeadRule{rules[0].Direction, nil})
+}
+
+// applyRule returns the child found by rule, nil if none.
+//
+func (self *Node) applyRule(rule HeadRule) *Node {
+	children := self.Children
+	at := func(i int) *Node {
+		if rule.Direction == HeadRight || rule.Direction == HeadRightDis {
+			return children[len(children)-1-i]
+		}
+		return children[i]
+	}
+
+	if len(rule.Categories) == 0 {
+		for i := range children {
+			if c := at(i); !punctuation[c.Category()] {
+				return c
+			}
+		}
+		return at(0)
+	}
+	if rule.Direction == HeadLeftDis || rule.Direction == HeadRightDis {
+		for i := range children {
+			c := at(i)
+			for _, category := range rule.Categories {
+				if c.Category() == category {
+					return c
+				}
+			}
+		}
+		return nil
+	}
+	for _, category := range rule.Categories {
+		for i := range children {
+			if c := at(i); c.Category() == category {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// HeadLeaf returns the lexical head of the node, the word reached by
+// following the head children down; a leaf is its own head.
+//
+func (self *Node) HeadLeaf() *Node {
+	n := self
+	for !n.IsLeaf() {
+		n = n.HeadChild()
+	}
+	return n
+}
+
+// HeadPreterminal returns the part-of-speech node of the lexical head, nil
+// for a leaf.
+//
+func (self *Node) HeadPreterminal() *Node {
+	if self.IsLeaf() {
+		return nil
+	}
+	return self.HeadLeaf().Parent
+}
+
+// HeadToken returns the token of the sentence of the lexical head, nil if
+// out of the sentence.
+//
+func (self *Node) HeadToken(s *nlp.Sentence) *nlp.Token {
+	i := self.HeadLeaf().Begin
+	if i >= len(s.GetToken()) {
+		return nil
+	}
+	return s.Token[i]
+}
+
+// Dependencies derives the dependency tree of the node from its head rules:
+// every word depends on the lexical head of the phrase above the largest
+// phrase it heads, and the head of the node is the root. As the tree tells
+// no grammatical function, the relation of a word is the category of the
+// largest phrase it heads, e.g. "PP" for the preposition heading it, or its
+// part of speech. The heads are those of Collins, unlike the content words
+// of Universal Dependencies; the indexes are 1-based, in the sentence given.
+//
+func (self *Node) Dependencies(sentence uint32) *nlp.DependencyGraph {
+	g := &nlp.DependencyGraph{}
+	for _, leaf := range self.Leaves() {
+		index := uint32(leaf.Begin + 1)
+		g.Node = append(g.Node, &nlp.DependencyGraph_Node{Index: proto.Uint32(index), SentenceIndex: proto.Uint32(sentence)})
+
+		largest := leaf
+		for largest != self && largest.Parent.HeadLeaf() == leaf {
+			largest = largest.Parent
+		}
+		if largest == self {
+			g.Root = append(g.Root, index)
+			continue
+		}
+		head := largest.Parent.HeadLeaf()
+		g.Edge = append(g.Edge, &nlp.DependencyGraph_Edge{Source: proto.Uint32(uint32(head.Begin + 1)), Target: proto.Uint32(index), Dep: proto.String(largest.Category())})
+	}
+	return g
+}
@@ -0,0 +1,85 @@
+package tree
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestHead(t *testing.T) {
+	tr, err := Parse("(ROOT (S (NP (NP (DT the) (NN price)) (PP (IN of) (NP (NNP John) (POS 's) ))) (VP (MD will) (VP (VB rise) (NP (CD 5) (NN %)))) (. .)))")
+	if err != nil { t.Fatal(err) }
+	root := New(tr)
+
+	heads := map[string]string{}
+	root.Walk(func(n *Node) bool {
+		if !n.IsLeaf() && !n.IsPreterminal() {
+			heads[n.Label()+" "+n.Text()] = n.HeadLeaf().Label()
+		}
+		return true
+	})
+	want := map[string]string{
+		"ROOT the price of John 's will rise 5 % .": "will",
+		"S the price of John 's will rise 5 % .":    "will",
+		"NP the price of John 's":                   "price",
+		"NP the price":                              "price",
+		"PP of John 's":                             "of",
+		"NP John 's":                                "'s",
+		"VP will rise 5 %":                          "will",
+		"VP rise 5 %":                               "rise",
+		"NP 5 %":                                    "%",
+	}
+	for k, v := range want {
+		if heads[k] != v {
+			t.Errorf("%s: %q, want %q", k, heads[k], v)
+		}
+	}
+
+	vp := root.Find("VP")[1]
+	if c := vp.HeadChild(); c.Label() != "VB" || vp.HeadPreterminal() != c || c.HeadChild().Label() != "rise" || c.HeadChild().HeadChild() != nil {
+		t.Errorf("%v", c.Label())
+	}
+
+	s := &nlp.Sentence{}
+	for _, w := range root.Yield() {
+		s.Token = append(s.Token, &nlp.Token{Word: proto.String(w)})
+	}
+	if tok := vp.HeadToken(s); tok != s.Token[6] || root.Leaf(0).HeadPreterminal() != nil {
+		t.Errorf("%v", tok)
+	}
+	if tok := vp.HeadToken(&nlp.Sentence{}); tok != nil {
+		t.Errorf("%v", tok)
+	}
+
+	// the default direction skips the punctuation
+	frag := New(mustParse(t, "(FRAG (NP (NN what)) (. ?))"))
+	if h := frag.HeadLeaf(); h.Label() != "what" {
+		t.Errorf("%v", h.Label())
+	}
+}
+
+func mustParse(t *testing.T, s string) *nlp.ParseTree {
+	tr, err := Parse(s)
+	if err != nil { t.Fatal(err) }
+	return tr
+}
+
+func TestDependencies(t *testing.T) {
+	tr, err := Parse("(ROOT (S (NP (PRP I)) (VP (VBD saw) (NP (NP (DT the) (NN man)) (PP (IN with) (NP (DT a) (NN telescope)))))))")
+	if err != nil { t.Fatal(err) }
+	g := New(tr).Dependencies(2)
+	if len(g.Node) != 7 || g.Node[6].GetIndex() != 7 || g.Node[6].GetSentenceIndex() != 2 || len(g.Root) != 1 || g.Root[0] != 2 {
+		t.Fatalf("%v", g)
+	}
+	var arcs []string
+	for _, e := range g.Edge {
+		arcs = append(arcs, fmt.Sprintf("%d>%d:%s", e.GetSource(), e.GetTarget(), e.GetDep()))
+	}
+	want := "2>1:NP 4>3:DT 2>4:NP 4>5:PP 7>6:DT 5>7:NP"
+	if got := strings.Join(arcs, " "); got != want {
+		t.Errorf("%s", got)
+	}
+}
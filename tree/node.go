@@ -0,0 +1,228 @@
+package tree
+
+import (
+	"strings"
+
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// Node is a node of a constituency tree with its parent and its position,
+// for navigating a nlp.ParseTree. The nodes are built once by New, and not
+// updated if the tree changes.
+//
+type Node struct {
+	Tree     *nlp.ParseTree
+	Parent   *Node
+	Children []*Node
+
+	// index among the children of Parent, 0 for the root
+	Index int
+
+	// 0-based span [Begin, End) of the leaves under the node; for a leaf,
+	// its position in the sentence, which is the token index
+	Begin int
+	End   int
+}
+
+// New builds the nodes of t and returns its root, nil if t is nil.
+//
+func New(t *nlp.ParseTree) *Node {
+	if t == nil {
+		return nil
+	}
+	leaves := 0
+	return build(t, nil, 0, &leaves)
+}
+
+func build(t *nlp.ParseTree, parent *Node, index int, leaves *int) *Node {
+	n := &Node{Tree: t, Parent: parent, Index: index, Begin: *leaves}
+	for i, c := range t.Child {
+		n.Children = append(n.Children, build(c, n, i, leaves))
+	}
+	if len(t.Child) == 0 {
+		*leaves++
+	}
+	n.End = *leaves
+	return n
+}
+
+// Label returns the value of the node: the category, e.g. "NP", or the word of a leaf.
+//
+func (self *Node) Label() string {
+	return self.Tree.GetValue()
+}
+
+// Category returns the label without its function tags and indexes, e.g.
+// "NP" for "NP-TMP" or "NP-SBJ-1"; the labels starting with "-", e.g.
+// "-LRB-", are kept whole.
+//
+func (self *Node) Category() string {
+	label := self.Label()
+	if i := strings.IndexAny(label, "-="); i > 0 {
+		return label[:i]
+	}
+	return label
+}
+
+// IsLeaf reports whether the node is a word.
+//
+func (self *Node) IsLeaf() bool {
+	return len(self.Children) == 0
+}
+
+// IsPreterminal reports whether the node is a part-of-speech tag over a single word.
+//
+func (self *Node) IsPreterminal() bool {
+	return len(self.Children) == 1 && self.Children[0].IsLeaf()
+}
+
+// Root returns the root of the tree of the node.
+//
+func (self *Node) Root() *Node {
+	n := self
+	for n.Parent != nil {
+		n = n.Parent
+	}
+	return n
+}
+
+// Depth returns the number of ancestors of the node, 0 for the root.
+//
+func (self *Node) Depth() int {
+	d := 0
+	for n := self.Parent; n != nil; n = n.Parent {
+		d++
+	}
+	return d
+}
+
+// Ancestors returns the ancestors of the node, from its parent to the root.
+//
+func (self *Node) Ancestors() []*Node {
+	var ancestors []*Node
+	for n := self.Parent; n != nil; n = n.Parent {
+		ancestors = append(ancestors, n)
+	}
+	return ancestors
+}
+
+// Walk visits the node and its descendants in preorder, skipping the
+// descendants of a node for which fn returns false.
+//
+func (self *Node) Walk(fn func(n *Node) bool) {
+	if !fn(self) {
+		return
+	}
+	for _, c := range self.Children {
+		c.Walk(fn)
+	}
+}
+
+// FindFunc returns the nodes in preorder, the node itself included, for which match is true.
+//
+func (self *Node) FindFunc(match func(n *Node) bool) []*Node {
+	var found []*Node
+	self.Walk(func(n *Node) bool {
+		if match(n) {
+			found = append(found, n)
+		}
+		return true
+	})
+	return found
+}
+
+// Find returns the phrases and tags with one of the labels, in preorder,
+// comparing their Category, so "NP" finds "NP-TMP" too. Leaves are not searched.
+//
+func (self *Node) Find(labels ...string) []*Node {
+	return self.FindFunc(func(n *Node) bool {
+		if n.IsLeaf() {
+			return false
+		}
+		for _, l := range labels {
+			if n.Category() == l {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Leaves returns the leaves under the node, in order.
+//
+func (self *Node) Leaves() []*Node {
+	return self.FindFunc((*Node).IsLeaf)
+}
+
+// Preterminals returns the part-of-speech nodes under the node, in order.
+//
+func (self *Node) Preterminals() []*Node {
+	return self.FindFunc((*Node).IsPreterminal)
+}
+
+// Yield returns the words under the node, in order.
+//
+func (self *Node) Yield() []string {
+	var words []string
+	for _, leaf := range self.Leaves() {
+		words = append(words, leaf.Label())
+	}
+	return words
+}
+
+// Text returns the words under the node joined by spaces.
+//
+func (self *Node) Text() string {
+	return strings.Join(self.Yield(), " ")
+}
+
+// Tokens returns the tokens of the sentence covered by the node, nil if the
+// span is out of the sentence.
+//
+func (self *Node) Tokens(s *nlp.Sentence) []*nlp.Token {
+	if self.End > len(s.GetToken()) {
+		return nil
+	}
+	return s.Token[self.Begin:self.End]
+}
+
+// Leaf returns the leaf at the 0-based position i under the root, nil if out of range.
+//
+func (self *Node) Leaf(i int) *Node {
+	n := self.Root()
+	if i < n.Begin || i >= n.End {
+		return nil
+	}
+	for !n.IsLeaf() {
+		for _, c := range n.Children {
+			if i < c.End {
+				n = c
+				break
+			}
+		}
+	}
+	return n
+}
+
+// Covering returns the lowest node under the root covering the leaves [begin, end),
+// nil if the span is empty or out of range.
+//
+func (self *Node) Covering(begin, end int) *Node {
+	n := self.Root()
+	if begin >= end || begin < n.Begin || end > n.End {
+		return nil
+	}
+	for {
+		next := (*Node)(nil)
+		for _, c := range n.Children {
+			if c.Begin <= begin && end <= c.End {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return n
+		}
+		n = next
+	}
+}
@@ -0,0 +1,49 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNode(t *testing.T) {
+	tr, err := Parse("(ROOT (S (NP-SBJ (NNP John)) (VP (VBZ runs) (NP-TMP (NN today))) (. .)))")
+	if err != nil { t.Fatal(err) }
+	root := New(tr)
+	if root.Label() != "ROOT" || root.Begin != 0 || root.End != 4 || root.Text() != "John runs today ." {
+		t.Errorf("%v %d %d", root.Label(), root.Begin, root.End)
+	}
+
+	nps := root.Find("NP")
+	if len(nps) != 2 || nps[0].Label() != "NP-SBJ" || nps[1].Category() != "NP" || nps[1].Begin != 2 || nps[1].End != 3 {
+		t.Fatalf("%v", nps)
+	}
+	if vp := nps[1].Parent; vp.Label() != "VP" || vp.Index != 1 || vp.Text() != "runs today" || nps[1].Depth() != 3 || nps[1].Root() != root {
+		t.Errorf("%v", vp.Label())
+	}
+
+	tags := root.Preterminals()
+	if len(tags) != 4 || tags[3].Label() != "." || !tags[0].Children[0].IsLeaf() {
+		t.Errorf("%v", tags)
+	}
+	if leaf := root.Leaf(1); leaf.Label() != "runs" || leaf.Begin != 1 || leaf.Parent.Label() != "VBZ" {
+		t.Errorf("%v", leaf.Label())
+	}
+	if n := root.Covering(1, 3); n.Label() != "VP" {
+		t.Errorf("%v", n.Label())
+	}
+	if n := root.Covering(0, 2); n.Label() != "S" || root.Covering(2, 2) != nil || root.Leaf(4) != nil {
+		t.Errorf("%v", n.Label())
+	}
+
+	var labels []string
+	root.Walk(func(n *Node) bool {
+		labels = append(labels, n.Label())
+		return n.Category() != "VP"
+	})
+	if strings.Join(labels, " ") != "ROOT S NP-SBJ NNP John VP . ." {
+		t.Errorf("%v", labels)
+	}
+	if len(nps[0].Ancestors()) != 2 || New(nil) != nil {
+		t.Errorf("%v", nps[0].Ancestors())
+	}
+}
@@ -0,0 +1,114 @@
+// Package tree reads and writes constituency parse trees in the bracketed
+// Penn Treebank notation, e.g. "(ROOT (S (NP (NNP John)) (VP (VBZ runs))))",
+// as nlp.ParseTree, and navigates them through Node.
+//
+package tree
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/genelet/corenlp-golang/nlp"
+	"google.golang.org/protobuf/proto"
+)
+
+// Parse reads a tree in the Penn Treebank notation, as found in the parse
+// field of the json and xml outputs of CoreNLP. A word becomes a leaf with
+// the word as value, child of its part-of-speech node. The unlabeled root of
+// the treebank files, "( (S ...))", gives a root with an empty value.
+//
+func Parse(s string) (*nlp.ParseTree, error) {
+	p := &parser{input: []rune(s)}
+	p.space()
+	if p.pos >= len(p.input) || p.input[p.pos] != '(' {
+		return nil, p.errorf("expected (")
+	}
+	t, err := p.node()
+	if err != nil {
+		return nil, err
+	}
+	p.space()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected text after the tree")
+	}
+	return t, nil
+}
+
+type parser struct {
+	input []rune
+	pos   int
+}
+
+func (self *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("tree: at %d: %s", self.pos, fmt.Sprintf(format, args...))
+}
+
+func (self *parser) space() {
+	for self.pos < len(self.input) && unicode.IsSpace(self.input[self.pos]) {
+		self.pos++
+	}
+}
+
+// word reads a label or a leaf, up to a space or a bracket.
+//
+func (self *parser) word() string {
+	start := self.pos
+	for self.pos < len(self.input) {
+		r := self.input[self.pos]
+		if r == '(' || r == ')' || unicode.IsSpace(r) {
+			break
+		}
+		self.pos++
+	}
+	return string(self.input[start:self.pos])
+}
+
+// node reads a bracketed tree, starting at its "(".
+//
+func (self *parser) node() (*nlp.ParseTree, error) {
+	self.pos++
+	self.space()
+	t := &nlp.ParseTree{Value: proto.String(self.word())}
+	for {
+		self.space()
+		if self.pos >= len(self.input) {
+			return nil, self.errorf("missing )")
+		}
+		switch self.input[self.pos] {
+		case ')':
+			self.pos++
+			return t, nil
+		case '(':
+			child, err := self.node()
+			if err != nil {
+				return nil, err
+			}
+			t.Child = append(t.Child, child)
+		default:
+			t.Child = append(t.Child, &nlp.ParseTree{Value: proto.String(self.word())})
+		}
+	}
+}
+
+// String writes the tree in the Penn Treebank notation on one line.
+//
+func String(t *nlp.ParseTree) string {
+	var b strings.Builder
+	write(&b, t)
+	return b.String()
+}
+
+func write(b *strings.Builder, t *nlp.ParseTree) {
+	if len(t.Child) == 0 {
+		b.WriteString(t.GetValue())
+		return
+	}
+	b.WriteByte('(')
+	b.WriteString(t.GetValue())
+	for _, c := range t.Child {
+		b.WriteByte(' ')
+		write(b, c)
+	}
+	b.WriteByte(')')
+}
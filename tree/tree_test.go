@@ -0,0 +1,32 @@
+package tree
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	s := "(ROOT (S (NP (NNP John)) (VP (VBZ runs) (-LRB- -LRB-) (ADVP (RB fast))) (. .)))"
+	tr, err := Parse("\n" + s[:12] + "\n   " + s[13:] + "  ")
+	if err != nil { t.Fatal(err) }
+	if tr.GetValue() != "ROOT" || len(tr.Child) != 1 || len(tr.Child[0].Child) != 3 {
+		t.Errorf("%v", tr)
+	}
+	np := tr.Child[0].Child[0]
+	if np.GetValue() != "NP" || np.Child[0].GetValue() != "NNP" || np.Child[0].Child[0].GetValue() != "John" {
+		t.Errorf("%v", np)
+	}
+	if out := String(tr); out != s {
+		t.Errorf("%s", out)
+	}
+
+	tr, err = Parse("( (S (NN x)))")
+	if err != nil || tr.GetValue() != "" || String(tr) != "( (S (NN x)))" {
+		t.Errorf("%v %v", tr, err)
+	}
+
+	for _, bad := range []string{"", "ROOT", "(ROOT (S (NN x))", "(ROOT) (S)"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package tree
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/genelet/corenlp-golang/internal/pattern"
+)
+
+// Pattern is a compiled pattern in a subset of the Tregex language of
+// CoreNLP, matched against a tree in Go without a server:
+//
+// - nodes: a label, e.g. NP, several labels separated by "|", @NP for the
+// category without function tags, /regex/ on the label, __ for any node,
+// and ! before them for the negation; =name names the node;
+//
+// - relations, between a node and the next one: A << B if A dominates B,
+// A < B if A is the parent of B, A >> B and A > B the other way round,
+// A $ B if they are sisters, A $+ B if B is just after A, A $- B if B is
+// just before A, A $++ B and A $-- B for any sister after or before A;
+// ! before a relation requires that no such node exists;
+//
+// - the relations after a node all apply to it, "&" between them is
+// optional, and parentheses group a node with its own relations.
+//
+// For example, the noun phrases with a prepositional phrase on "of":
+// NP=np < (PP < (IN < of))
+//
+type Pattern struct {
+	p *pattern.Pattern
+}
+
+// Match is a node matching a pattern, with the nodes of its names.
+//
+type Match struct {
+	Node  *Node
+	Named map[string]*Node
+}
+
+// tregex is the language of the patterns on the nodes of a tree.
+//
+var tregex = &pattern.Language{Name: "tregex", Operators: "<>$", Node: readNode, Relation: readRelation}
+
+// Compile parses a pattern.
+//
+func Compile(source string) (*Pattern, error) {
+	p, err := pattern.Compile(tregex, source)
+	if err != nil {
+		return nil, err
+	}
+	return &Pattern{p: p}, nil
+}
+
+// MustCompile parses a pattern, and panics on error.
+//
+func MustCompile(source string) *Pattern {
+	p, err := Compile(source)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source of the pattern.
+//
+func (self *Pattern) String() string {
+	return self.p.String()
+}
+
+// Match returns the matches of the pattern among the nodes under root, root
+// included, in preorder, with every distinct assignment of the named nodes.
+//
+func (self *Pattern) Match(root *Node) []*Match {
+	var matches []*Match
+	if root == nil {
+		return nil
+	}
+	root.Walk(func(n *Node) bool {
+		for _, named := range self.p.Match(n) {
+			m := &Match{Node: n, Named: make(map[string]*Node, len(named))}
+			for name, v := range named {
+				m.Named[name] = v.(*Node)
+			}
+			matches = append(matches, m)
+		}
+		return true
+	})
+	return matches
+}
+
+// MatchNode reports whether n matches the pattern.
+//
+func (self *Pattern) MatchNode(n *Node) bool {
+	return self.p.MatchNode(n)
+}
+
+// readNode reads a node description: labels, @labels, /regex/ or __, any
+// of them after !.
+//
+func readNode(p *pattern.Parser) (func(n pattern.Node) bool, error) {
+	negate := false
+	if p.Peek() == '!' {
+		negate = true
+		p.Skip()
+	}
+	var match func(n *Node) bool
+	switch p.Peek() {
+	case '/':
+		body, err := p.Regex()
+		if err != nil {
+			return nil, err
+		}
+		re, err := regexp.Compile(body)
+		if err != nil {
+			return nil, p.Errorf("%v", err)
+		}
+		match = func(n *Node) bool { return re.MatchString(n.Label()) }
+	default:
+		category := false
+		if p.Peek() == '@' {
+			p.Skip()
+			category = true
+		}
+		label := p.While(func(r rune) bool { return !unicode.IsSpace(r) && !strings.ContainsRune("()<>$!&=/@", r) })
+		if label == "" {
+			return nil, p.Errorf("expected a node")
+		}
+		labels := strings.Split(label, "|")
+		match = func(n *Node) bool {
+			if label == "__" {
+				return true
+			}
+			l := n.Label()
+			if category {
+				l = n.Category()
+			}
+			for _, want := range labels {
+				if want == l {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return func(n pattern.Node) bool { return match(n.(*Node)) != negate }, nil
+}
+
+// readRelation reads one of <, >, <<, >>, $, $+, $-, $++, $--.
+//
+func readRelation(p *pattern.Parser) (func(n pattern.Node) []pattern.Node, error) {
+	r := p.Peek()
+	var op string
+	switch r {
+	case '<', '>':
+		p.Skip()
+		op = string(r)
+		if p.Peek() == r {
+			p.Skip()
+			op += string(r)
+		}
+	case '$':
+		p.Skip()
+		op = "$"
+		if s := p.Peek(); s == '+' || s == '-' {
+			p.Skip()
+			op += string(s)
+			if p.Peek() == s {
+				p.Skip()
+				op += string(s)
+			}
+		}
+	default:
+		return nil, p.Errorf("expected <, > or $")
+	}
+	return func(n pattern.Node) []pattern.Node { return candidates(op, n.(*Node)) }, nil
+}
+
+// candidates returns the nodes in the relation op with n.
+//
+func candidates(op string, n *Node) []pattern.Node {
+	var nodes []*Node
+	switch op {
+	case "<":
+		nodes = n.Children
+	case ">":
+		if n.Parent != nil {
+			nodes = []*Node{n.Parent}
+		}
+	case "<<":
+		for _, c := range n.Children {
+			c.Walk(func(m *Node) bool {
+				nodes = append(nodes, m)
+				return true
+			})
+		}
+	case ">>":
+		nodes = n.Ancestors()
+	default:
+		if n.Parent == nil {
+			return nil
+		}
+		sisters := n.Parent.Children
+		switch op {
+		case "$":
+			for _, s := range sisters {
+				if s != n {
+					nodes = append(nodes, s)
+				}
+			}
+		case "$+":
+			if n.Index+1 < len(sisters) {
+				nodes = []*Node{sisters[n.Index+1]}
+			}
+		case "$-":
+			if n.Index > 0 {
+				nodes = []*Node{sisters[n.Index-1]}
+			}
+		case "$++":
+			nodes = sisters[n.Index+1:]
+		case "$--":
+			nodes = sisters[:n.Index]
+		}
+	}
+	result := make([]pattern.Node, len(nodes))
+	for i, m := range nodes {
+		result[i] = m
+	}
+	return result
+}
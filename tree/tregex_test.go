@@ -0,0 +1,56 @@
+package tree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTregex(t *testing.T) {
+	tr, err := Parse("(ROOT (S (NP-SBJ (DT the) (NN price) (PP (IN of) (NP (NN oil)))) (VP (VBD rose) (NP-TMP (NN today))) (. .)))")
+	if err != nil { t.Fatal(err) }
+	root := New(tr)
+
+	texts := func(pattern, name string) []string {
+		var s []string
+		for _, m := range MustCompile(pattern).Match(root) {
+			n := m.Node
+			if name != "" {
+				n = m.Named[name]
+			}
+			s = append(s, n.Text())
+		}
+		return s
+	}
+
+	tests := []struct {
+		pattern, name, want string
+	}{
+		{"NP", "", "oil"},
+		{"@NP", "", "the price of oil|oil|today"},
+		{"/^NP/ < (PP < (IN < of))", "", "the price of oil"},
+		{"@NP=np << oil", "np", "the price of oil|oil"},
+		{"@NP !<< oil", "", "today"},
+		{"NN > @NP=np", "np", "the price of oil|oil|today"},
+		{"NN >> VP", "", "today"},
+		{"DT $+ NN=next", "next", "price"},
+		{"PP $- NN|NNS", "", "of oil"},
+		{"DT $++ PP", "", "the"},
+		{"PP $-- DT & $ NN", "", "of oil"},
+		{"VBD $ __=sister", "sister", "today"},
+		{"S < (VP < VBD=verb) < !VP=other", "other", "the price of oil|."},
+	}
+	for _, test := range tests {
+		if got := strings.Join(texts(test.pattern, test.name), "|"); got != test.want {
+			t.Errorf("%s: %q, want %q", test.pattern, got, test.want)
+		}
+	}
+
+	if !MustCompile("__ < VBD").MatchNode(root.Find("VP")[0]) {
+		t.Errorf("MatchNode")
+	}
+	for _, bad := range []string{"", "NP <", "(NP", "NP < /x", "NP=", "NP ? VP"} {
+		if _, err := Compile(bad); err == nil {
+			t.Errorf("%q: no error", bad)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+// Package viz renders annotations for visualization tools.
+//
+package viz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/genelet/corenlp-golang/graph"
+	"github.com/genelet/corenlp-golang/nlp"
+)
+
+// DependenciesToDOT renders the dependency graph of the sentence in the DOT
+// language of graphviz, e.g. for "dot -Tsvg". It takes the enhanced++
+// dependencies if the sentence has them, the closest variant otherwise, see
+// graph.Dependencies.
+//
+func DependenciesToDOT(sentence *nlp.Sentence) string {
+	g, _ := graph.Dependencies(sentence, graph.EnhancedPlusPlus)
+	return GraphToDOT(sentence, g)
+}
+
+// GraphToDOT renders the dependency graph g of the sentence in DOT. Every
+// token is a node labeled with its word and POS, laid out in sentence order,
+// and every relation an edge labeled with its name; the extra edges of the
+// enhanced graphs are dashed, and the copy nodes marked with primes.
+//
+func GraphToDOT(sentence *nlp.Sentence, g *nlp.DependencyGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+	b.WriteString("\tnode [shape=box];\n")
+	b.WriteString("\tROOT [label=\"ROOT\"];\n")
+
+	var order []string
+	for _, n := range g.GetNode() {
+		id := nodeID(n.GetIndex(), n.GetCopyAnnotation())
+		label := nodeLabel(sentence, n.GetIndex(), n.GetCopyAnnotation())
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", id, quote(label))
+		order = append(order, id)
+	}
+	if len(order) > 1 {
+		// invisible edges keep the tokens in sentence order
+		fmt.Fprintf(&b, "\t{ rank=same; %s [style=invis]; }\n", strings.Join(order, " -> "))
+	}
+
+	for _, root := range g.GetRoot() {
+		fmt.Fprintf(&b, "\tROOT -> %s [label=\"root\"];\n", nodeID(root, 0))
+	}
+	for _, e := range g.GetEdge() {
+		style := ""
+		if e.GetIsExtra() {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s%s];\n", nodeID(e.GetSource(), e.GetSourceCopy()),
+			nodeID(e.GetTarget(), e.GetTargetCopy()), quote(e.GetDep()), style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func nodeID(index, copies uint32) string {
+	if copies > 0 {
+		return fmt.Sprintf("t%d_%d", index, copies)
+	}
+	return "t" + strconv.Itoa(int(index))
+}
+
+// nodeLabel returns "word-index" over the POS of the token, index being 1-based.
+//
+func nodeLabel(sentence *nlp.Sentence, index, copies uint32) string {
+	if index == 0 || int(index) > len(sentence.GetToken()) {
+		return strconv.Itoa(int(index))
+	}
+	t := sentence.Token[index-1]
+	label := fmt.Sprintf("%s-%d%s", t.GetWord(), index, strings.Repeat("'", int(copies)))
+	if pos := t.GetPos(); pos != "" {
+		label += "\n" + pos
+	}
+	return label
+}
+
+// quote makes s a DOT string.
+//
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
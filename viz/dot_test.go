@@ -0,0 +1,31 @@
+package viz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/genelet/corenlp-golang/format"
+)
+
+func TestDependenciesToDOT(t *testing.T) {
+	data := "1\tJohn\tJohn\tNNP\tPERSON\t2\tnsubj\n2\tsaid\tsay\tVBD\tO\t0\tROOT\n3\t\"\t\"\t``\tO\t2\tpunct\n"
+	doc, err := format.Parse(format.CoNLL, []byte(data))
+	if err != nil { t.Fatal(err) }
+
+	out := DependenciesToDOT(doc.Sentence[0])
+	for _, want := range []string{
+		"digraph dependencies {\n",
+		"\tt1 [label=\"John-1\\nNNP\"];\n",
+		"\tt3 [label=\"\\\"-3\\n``\"];\n",
+		"\t{ rank=same; t1 -> t2 -> t3 [style=invis]; }\n",
+		"\tROOT -> t2 [label=\"root\"];\n",
+		"\tt2 -> t1 [label=\"nsubj\"];\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "}\n") {
+		t.Errorf("%s", out)
+	}
+}